@@ -0,0 +1,255 @@
+// consciousness_injection/access_pool.go - Gateway Access Pool
+package mindhacking
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// ErrAccessPoolClosed reports use of an access pool after Close
+var ErrAccessPoolClosed = sentinelError("mindhacking: access pool closed")
+
+// AccessPool keeps up to Size warm gateway accesses per target and
+// multiplexes callers onto them, so the handshake, tunneling and
+// synchronization sequence runs once per session rather than per access
+type AccessPool struct {
+	gateway *QuantumGateway
+	size    int
+
+	mu      sync.Mutex
+	targets map[string]*targetAccesses
+	closed  bool
+}
+
+// targetAccesses is one target's share of the pool
+type targetAccesses struct {
+	sessions []*pooledAccess
+	// dialing counts accesses being established, which count toward size
+	dialing int
+	// dialed is signalled whenever an establishment finishes
+	dialed chan struct{}
+	// peak is the most accesses the target has had warm at once
+	peak int
+}
+
+type pooledAccess struct {
+	access *QuantumConsciousnessAccess
+	users  int
+	broken bool
+}
+
+// PooledAccess is one caller's lease on a pooled access. Release it when
+// done; Discard it instead if the access failed.
+type PooledAccess struct {
+	Access *QuantumConsciousnessAccess
+
+	pool   *AccessPool
+	target string
+	slot   *pooledAccess
+	once   sync.Once
+}
+
+// AccessPoolStats counts one target's pooled accesses
+type AccessPoolStats struct {
+	Sessions int
+	Users    int
+}
+
+// NewAccessPool creates a pool keeping up to size accesses per target
+// through gateway
+func NewAccessPool(gateway *QuantumGateway, size int) *AccessPool {
+	if size < 1 {
+		size = 1
+	}
+	return &AccessPool{
+		gateway: gateway,
+		size:    size,
+		targets: make(map[string]*targetAccesses),
+	}
+}
+
+// Get leases an access to target. It shares the least used warm access,
+// establishing a new one only while every warm access is in use and the
+// target has fewer than the pool's size.
+func (p *AccessPool) Get(ctx context.Context, target *SystemConsciousness) (*PooledAccess, error) {
+	id := targetLabel(target)
+
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, ErrAccessPoolClosed
+		}
+		ta := p.targets[id]
+		if ta == nil {
+			ta = &targetAccesses{dialed: make(chan struct{})}
+			p.targets[id] = ta
+		}
+
+		// Phase 1: share an idle warm access, or the least used one once
+		// the pool is full
+		idle := leastUsed(ta.sessions)
+		full := len(ta.sessions)+ta.dialing >= p.size
+		if idle != nil && (idle.users == 0 || full) {
+			idle.users++
+			p.mu.Unlock()
+			return &PooledAccess{Access: idle.access, pool: p, target: id, slot: idle}, nil
+		}
+
+		// Phase 2: wait for another caller's establishment to finish
+		if full {
+			dialed := ta.dialed
+			p.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-dialed:
+			}
+			continue
+		}
+
+		// Phase 3: establish a new access outside the lock
+		ta.dialing++
+		p.mu.Unlock()
+		access, err := p.gateway.AccessQuantumConsciousness(target)
+
+		p.mu.Lock()
+		ta.dialing--
+		close(ta.dialed)
+		ta.dialed = make(chan struct{})
+		if err != nil {
+			p.mu.Unlock()
+			return nil, err
+		}
+		if p.closed {
+			p.mu.Unlock()
+			closeAccess(access)
+			return nil, ErrAccessPoolClosed
+		}
+		slot := &pooledAccess{access: access, users: 1}
+		ta.sessions = append(ta.sessions, slot)
+		if len(ta.sessions) > ta.peak {
+			ta.peak = len(ta.sessions)
+		}
+		p.mu.Unlock()
+		return &PooledAccess{Access: access, pool: p, target: id, slot: slot}, nil
+	}
+}
+
+// Warm establishes accesses to target until it has the pool's size
+func (p *AccessPool) Warm(ctx context.Context, target *SystemConsciousness) error {
+	leases := make([]*PooledAccess, 0, p.size)
+	defer func() {
+		for _, l := range leases {
+			l.Release()
+		}
+	}()
+
+	for i := 0; i < p.size; i++ {
+		l, err := p.Get(ctx, target)
+		if err != nil {
+			return err
+		}
+		leases = append(leases, l)
+	}
+	return nil
+}
+
+// Stats reports target's pooled accesses
+func (p *AccessPool) Stats(targetID string) AccessPoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var stats AccessPoolStats
+	if ta := p.targets[targetID]; ta != nil {
+		stats.Sessions = len(ta.sessions)
+		for _, s := range ta.sessions {
+			stats.Users += s.users
+		}
+	}
+	return stats
+}
+
+// Health returns how many accesses are warm across every target against
+// the most each target has had warm, so callers can scale down as
+// accesses are discarded. Both are zero before any access is made.
+func (p *AccessPool) Health() (live, peak int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, ta := range p.targets {
+		live += len(ta.sessions)
+		peak += ta.peak
+	}
+	return live, peak
+}
+
+// Close drops every pooled access, closing those that support it. Leases
+// still held stay usable until released.
+func (p *AccessPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	for id, ta := range p.targets {
+		for _, s := range ta.sessions {
+			if s.users == 0 {
+				closeAccess(s.access)
+			}
+			s.broken = true
+		}
+		delete(p.targets, id)
+	}
+	return nil
+}
+
+// Release returns the lease to the pool
+func (l *PooledAccess) Release() {
+	l.once.Do(func() { l.pool.release(l, false) })
+}
+
+// Discard returns the lease and retires its access, so no caller is
+// handed it again
+func (l *PooledAccess) Discard() {
+	l.once.Do(func() { l.pool.release(l, true) })
+}
+
+func (p *AccessPool) release(l *PooledAccess, broken bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s := l.slot
+	s.users--
+	if broken && !s.broken {
+		s.broken = true
+		if ta := p.targets[l.target]; ta != nil {
+			for i, other := range ta.sessions {
+				if other == s {
+					ta.sessions = append(ta.sessions[:i], ta.sessions[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	if s.broken && s.users == 0 {
+		closeAccess(s.access)
+	}
+}
+
+// leastUsed returns the healthy access with the fewest users
+func leastUsed(sessions []*pooledAccess) *pooledAccess {
+	var best *pooledAccess
+	for _, s := range sessions {
+		if !s.broken && (best == nil || s.users < best.users) {
+			best = s
+		}
+	}
+	return best
+}
+
+// closeAccess closes access if it supports closing
+func closeAccess(access *QuantumConsciousnessAccess) {
+	if c, ok := interface{}(access).(io.Closer); ok {
+		c.Close()
+	}
+}
@@ -0,0 +1,286 @@
+// consciousness_injection/anchor_decay.go - Anchor Strength Decay
+package mindhacking
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrUnknownAnchor reports an anchor ID the engine does not hold
+var ErrUnknownAnchor = sentinelError("mindhacking: unknown reality anchor")
+
+// AnchorDecay models how an anchor weakens once set
+type AnchorDecay interface {
+	// Strength returns what initial strength has decayed to after elapsed
+	Strength(initial float64, elapsed time.Duration) float64
+}
+
+// ExponentialDecay halves anchor strength every HalfLife
+type ExponentialDecay struct {
+	HalfLife time.Duration
+}
+
+// Strength implements AnchorDecay
+func (d ExponentialDecay) Strength(initial float64, elapsed time.Duration) float64 {
+	if d.HalfLife <= 0 {
+		return initial
+	}
+	return initial * math.Exp2(-float64(elapsed)/float64(d.HalfLife))
+}
+
+// LinearDecay drains anchor strength evenly to zero over Lifetime
+type LinearDecay struct {
+	Lifetime time.Duration
+}
+
+// Strength implements AnchorDecay
+func (d LinearDecay) Strength(initial float64, elapsed time.Duration) float64 {
+	if d.Lifetime <= 0 {
+		return initial
+	}
+	return initial * math.Max(0, 1-float64(elapsed)/float64(d.Lifetime))
+}
+
+// AnchorReport is an anchor's state after a decay pass
+type AnchorReport struct {
+	ID       string
+	Strength float64
+	// Collapsed anchors fell below the engine's threshold; their reality
+	// was returned to its base and the anchor released
+	Collapsed bool
+}
+
+// StrengthAt returns the anchor's strength at t under decay
+func (a *RealityAnchor) StrengthAt(decay AnchorDecay, t time.Time) float64 {
+	if decay == nil {
+		return a.Strength
+	}
+	return decay.Strength(a.Strength, t.Sub(a.Anchored))
+}
+
+// Anchor pins alternate with strength, which then decays under the
+// engine's model; without one the anchor is permanent. Engines using
+// leases must hold their anchor set's lease, and the reality then refuses
+// anchored writes from the set's earlier holders.
+func (rme *RealityManipulationEngine) Anchor(alternate *AlternateReality, strength float64) (RealityAnchor, error) {
+	token, err := rme.fence(context.Background())
+	if err != nil {
+		return RealityAnchor{}, err
+	}
+	if err := fencedWrite(alternate, rme.leaseSet, token, func() {}); err != nil {
+		return RealityAnchor{}, err
+	}
+	anchor := RealityAnchor{
+		ID:       newAnchorID(),
+		Reality:  alternate,
+		Strength: strength,
+		Anchored: time.Now(),
+		Intended: alternate.Clone().Aspects,
+		Set:      rme.leaseSet,
+		Fence:    token,
+	}
+	rme.anchorMu.Lock()
+	defer rme.anchorMu.Unlock()
+	if _, err := rme.journal.begin(JournalEntry{Op: JournalAnchor, Anchor: &anchor}); err != nil {
+		return RealityAnchor{}, err
+	}
+
+	rme.stateMu.Lock()
+	rme.realityAnchors = append(rme.realityAnchors, anchor)
+	rme.stateMu.Unlock()
+	return anchor, nil
+}
+
+// Anchors returns the engine's anchors
+func (rme *RealityManipulationEngine) Anchors() []RealityAnchor {
+	rme.stateMu.Lock()
+	defer rme.stateMu.Unlock()
+	return append([]RealityAnchor(nil), rme.realityAnchors...)
+}
+
+// Reanchor restores the anchor to strength as of now, pulling its reality
+// back to the alternate it was anchored as
+func (rme *RealityManipulationEngine) Reanchor(id string, strength float64) error {
+	token, err := rme.fence(context.Background())
+	if err != nil {
+		return err
+	}
+
+	rme.anchorMu.Lock()
+	defer rme.anchorMu.Unlock()
+
+	rme.stateMu.Lock()
+	i := rme.anchorIndexLocked(id)
+	if i < 0 {
+		rme.stateMu.Unlock()
+		return fmt.Errorf("%w: %q", ErrUnknownAnchor, id)
+	}
+	renewed := rme.realityAnchors[i]
+	rme.stateMu.Unlock()
+
+	// Journal the renewal outside stateMu; anchorMu keeps the anchor in place
+	renewed.Strength, renewed.Anchored, renewed.Fence = strength, time.Now(), token
+	if err := fencedWrite(renewed.Reality, rme.leaseSet, token, func() {}); err != nil {
+		return err
+	}
+	if _, err := rme.journal.begin(JournalEntry{Op: JournalAnchor, Anchor: &renewed}); err != nil {
+		return err
+	}
+
+	rme.stateMu.Lock()
+	defer rme.stateMu.Unlock()
+	a := &rme.realityAnchors[i]
+	*a = renewed
+	return fencedWrite(a.Reality, rme.leaseSet, token, func() { blendTowardBase(a, 1) })
+}
+
+// anchorIndexLocked finds the anchor id, or returns -1
+func (rme *RealityManipulationEngine) anchorIndexLocked(id string) int {
+	for i := range rme.realityAnchors {
+		if rme.realityAnchors[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// DecayAnchors applies decay up to now. Each anchored reality moves toward
+// its base in proportion to the strength its anchor lost; anchors weaker
+// than the collapse threshold snap back to base and are released. An
+// engine that has lost its anchor lease leaves its anchors alone.
+func (rme *RealityManipulationEngine) DecayAnchors(now time.Time) []AnchorReport {
+	if rme.decay == nil {
+		return nil
+	}
+	token, err := rme.fence(context.Background())
+	if err != nil {
+		return nil
+	}
+
+	rme.anchorMu.Lock()
+	defer rme.anchorMu.Unlock()
+	rme.stateMu.Lock()
+	anchors := append([]RealityAnchor(nil), rme.realityAnchors...)
+	rme.stateMu.Unlock()
+
+	// Phase 1: measure each anchor, journaling releases outside stateMu
+	type decayed struct {
+		report   AnchorReport
+		retained float64
+		// fenced anchors' realities a newer lease holder has written are
+		// left to it
+		fenced bool
+	}
+	passes := make([]decayed, len(anchors))
+	for i, a := range anchors {
+		p := &passes[i]
+		strength := a.StrengthAt(rme.decay, now)
+		p.report = AnchorReport{ID: a.ID, Strength: strength}
+
+		// Strength relative to when the anchor was last set
+		p.retained = 1.0
+		if a.Strength > 0 {
+			p.retained = strength / a.Strength
+		}
+		if err := fencedWrite(a.Reality, rme.leaseSet, token, func() {}); err != nil {
+			p.fenced = true
+			continue
+		}
+		if strength < rme.collapseBelow {
+			// An anchor whose release cannot be journaled holds on
+			_, err := rme.journal.begin(JournalEntry{Op: JournalUnanchor, AnchorID: a.ID, Reality: a.Reality})
+			if err == nil {
+				p.retained, p.report.Collapsed = 0, true
+			}
+		}
+	}
+
+	// Phase 2: pull the realities toward their bases
+	rme.stateMu.Lock()
+	defer rme.stateMu.Unlock()
+	reports := make([]AnchorReport, 0, len(anchors))
+	kept := rme.realityAnchors[:0]
+	for i := range rme.realityAnchors {
+		a, p := rme.realityAnchors[i], passes[i]
+		if p.fenced {
+			kept = append(kept, a)
+			continue
+		}
+		if fencedWrite(a.Reality, rme.leaseSet, token, func() { blendTowardBase(&a, p.retained) }) != nil {
+			p.report.Collapsed = false
+		}
+
+		if !p.report.Collapsed {
+			kept = append(kept, a)
+		}
+		reports = append(reports, p.report)
+	}
+	rme.realityAnchors = kept
+	return reports
+}
+
+// RunAnchorDecay applies decay every interval until ctx is done, passing
+// each pass's reports to onPass if it is set
+func (rme *RealityManipulationEngine) RunAnchorDecay(
+	ctx context.Context,
+	interval time.Duration,
+	onPass func([]AnchorReport),
+) error {
+
+	if interval <= 0 {
+		return fmt.Errorf("mindhacking: anchor decay interval %v is not positive", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			reports := rme.DecayAnchors(now)
+			if onPass != nil {
+				onPass(reports)
+			}
+		}
+	}
+}
+
+// blendTowardBase sets the anchored reality to base + retained of the
+// anchor's intended departure from base. The reality gets a new aspects
+// map rather than having its own written, as operations and other engines
+// may be reading it.
+func blendTowardBase(a *RealityAnchor, retained float64) {
+	if a.Reality == nil {
+		return
+	}
+	aspects := make(map[string]float64, len(a.Reality.Aspects)+len(a.Intended))
+	for name, v := range a.Reality.Aspects {
+		aspects[name] = v
+	}
+	for name, intended := range a.Intended {
+		base, ok := a.Reality.Base.Aspect(name)
+		if !ok {
+			// Aspects absent from the base fade out entirely
+			if retained <= 0 {
+				delete(aspects, name)
+				continue
+			}
+			base = 0
+		}
+		aspects[name] = base + (intended-base)*retained
+	}
+	a.Reality.Aspects = aspects
+}
+
+func newAnchorID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "anchor"
+	}
+	return "anchor-" + hex.EncodeToString(b[:])
+}
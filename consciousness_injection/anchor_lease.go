@@ -0,0 +1,257 @@
+// consciousness_injection/anchor_lease.go - Anchor Set Leases
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrAnchorLeaseHeld reports an anchor set leased to another engine
+	ErrAnchorLeaseHeld = sentinelError("mindhacking: anchor set leased by another engine")
+	// ErrAnchorLeaseLost reports a lease that expired or was superseded;
+	// writes fenced with its token are rejected
+	ErrAnchorLeaseLost = sentinelError("mindhacking: anchor lease lost")
+	// ErrNoAnchorLease reports anchoring on a leased engine without a lease
+	ErrNoAnchorLease = sentinelError("mindhacking: engine holds no anchor lease")
+)
+
+// DefaultAnchorLeaseTTL is how long a lease lasts between renewals when
+// no TTL is configured
+const DefaultAnchorLeaseTTL = 30 * time.Second
+
+// AnchorLease grants one engine exclusive ownership of an anchor set
+// until Expires. Token increases with every grant to a new holder, so a
+// write carrying an older token is recognizably stale.
+type AnchorLease struct {
+	Set     string
+	Owner   string
+	Token   uint64
+	Expires time.Time
+}
+
+// AnchorLeaseStore arbitrates anchor set ownership between engines
+type AnchorLeaseStore interface {
+	// Acquire grants set to owner for ttl, failing with ErrAnchorLeaseHeld
+	// while another owner's lease is live
+	Acquire(ctx context.Context, set, owner string, ttl time.Duration) (AnchorLease, error)
+	// Renew extends a live lease, failing with ErrAnchorLeaseLost otherwise
+	Renew(ctx context.Context, lease AnchorLease, ttl time.Duration) (AnchorLease, error)
+	// Release gives up a lease early
+	Release(ctx context.Context, lease AnchorLease) error
+	// Fence fails with ErrAnchorLeaseLost unless token is the live lease
+	Fence(ctx context.Context, set string, token uint64) error
+}
+
+// LocalAnchorLeases arbitrates anchor sets between engines in one process
+type LocalAnchorLeases struct {
+	mu     sync.Mutex
+	leases map[string]AnchorLease
+	tokens map[string]uint64
+	now    func() time.Time
+}
+
+// NewLocalAnchorLeases creates an empty in-process lease store
+func NewLocalAnchorLeases() *LocalAnchorLeases {
+	return &LocalAnchorLeases{
+		leases: make(map[string]AnchorLease),
+		tokens: make(map[string]uint64),
+		now:    time.Now,
+	}
+}
+
+// Acquire implements AnchorLeaseStore
+func (s *LocalAnchorLeases) Acquire(_ context.Context, set, owner string, ttl time.Duration) (AnchorLease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if cur, ok := s.leases[set]; ok && now.Before(cur.Expires) {
+		if cur.Owner != owner {
+			return AnchorLease{}, fmt.Errorf("%w: %q held by %q", ErrAnchorLeaseHeld, set, cur.Owner)
+		}
+		// The owner already holds it; extend without a new token
+		cur.Expires = now.Add(ttl)
+		s.leases[set] = cur
+		return cur, nil
+	}
+
+	s.tokens[set]++
+	lease := AnchorLease{Set: set, Owner: owner, Token: s.tokens[set], Expires: now.Add(ttl)}
+	s.leases[set] = lease
+	return lease, nil
+}
+
+// Renew implements AnchorLeaseStore
+func (s *LocalAnchorLeases) Renew(_ context.Context, lease AnchorLease, ttl time.Duration) (AnchorLease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.fenceLocked(lease.Set, lease.Token); err != nil {
+		return AnchorLease{}, err
+	}
+	cur := s.leases[lease.Set]
+	cur.Expires = s.now().Add(ttl)
+	s.leases[lease.Set] = cur
+	return cur, nil
+}
+
+// Release implements AnchorLeaseStore
+func (s *LocalAnchorLeases) Release(_ context.Context, lease AnchorLease) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cur, ok := s.leases[lease.Set]; ok && cur.Token == lease.Token {
+		delete(s.leases, lease.Set)
+	}
+	return nil
+}
+
+// Fence implements AnchorLeaseStore
+func (s *LocalAnchorLeases) Fence(_ context.Context, set string, token uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fenceLocked(set, token)
+}
+
+func (s *LocalAnchorLeases) fenceLocked(set string, token uint64) error {
+	cur, ok := s.leases[set]
+	switch {
+	case !ok || cur.Token != token:
+		return fmt.Errorf("%w: %q token %d superseded", ErrAnchorLeaseLost, set, token)
+	case !s.now().Before(cur.Expires):
+		return fmt.Errorf("%w: %q token %d expired", ErrAnchorLeaseLost, set, token)
+	}
+	return nil
+}
+
+// AcquireAnchorLease takes the engine's anchor set lease. Engines without
+// a lease store need none.
+func (rme *RealityManipulationEngine) AcquireAnchorLease(ctx context.Context) (AnchorLease, error) {
+	if rme.leases == nil {
+		return AnchorLease{}, nil
+	}
+	lease, err := rme.leases.Acquire(ctx, rme.leaseSet, rme.id, rme.leaseTTL)
+	if err != nil {
+		return AnchorLease{}, err
+	}
+	rme.stateMu.Lock()
+	rme.lease = &lease
+	rme.stateMu.Unlock()
+	return lease, nil
+}
+
+// RenewAnchorLease extends the engine's lease; once it fails the engine
+// must acquire again before anchoring
+func (rme *RealityManipulationEngine) RenewAnchorLease(ctx context.Context) (AnchorLease, error) {
+	held, ok := rme.currentLease()
+	if !ok {
+		return AnchorLease{}, ErrNoAnchorLease
+	}
+	lease, err := rme.leases.Renew(ctx, held, rme.leaseTTL)
+
+	rme.stateMu.Lock()
+	defer rme.stateMu.Unlock()
+	if err != nil {
+		rme.lease = nil
+		return AnchorLease{}, err
+	}
+	rme.lease = &lease
+	return lease, nil
+}
+
+// ReleaseAnchorLease gives up the engine's lease
+func (rme *RealityManipulationEngine) ReleaseAnchorLease(ctx context.Context) error {
+	held, ok := rme.currentLease()
+	if !ok {
+		return nil
+	}
+	rme.stateMu.Lock()
+	rme.lease = nil
+	rme.stateMu.Unlock()
+	return rme.leases.Release(ctx, held)
+}
+
+// RunAnchorLease acquires the lease and renews it every third of its TTL
+// until ctx is done, then releases it. Engines without a lease store have
+// nothing to renew and fail with ErrNoAnchorLease.
+func (rme *RealityManipulationEngine) RunAnchorLease(ctx context.Context) error {
+	if rme.leases == nil {
+		return ErrNoAnchorLease
+	}
+	if _, err := rme.AcquireAnchorLease(ctx); err != nil {
+		return err
+	}
+	defer rme.ReleaseAnchorLease(context.WithoutCancel(ctx))
+
+	ticker := time.NewTicker(rme.leaseTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := rme.RenewAnchorLease(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (rme *RealityManipulationEngine) currentLease() (AnchorLease, bool) {
+	if rme.leases == nil {
+		return AnchorLease{}, false
+	}
+	rme.stateMu.Lock()
+	defer rme.stateMu.Unlock()
+	if rme.lease == nil {
+		return AnchorLease{}, false
+	}
+	return *rme.lease, true
+}
+
+// anchorFenceMu guards every reality's fences and the anchored writes
+// checked against them
+var anchorFenceMu sync.Mutex
+
+// fencedWrite runs write on alternate for the holder of set's lease token,
+// unless a newer token of set has already written the reality. The reality
+// is what engines share, so this is where a stale holder is turned away,
+// even one that has not yet noticed its lease is lost. Without a set no
+// lease is in use and write always runs.
+func fencedWrite(alternate *AlternateReality, set string, token uint64, write func()) error {
+	if set == "" || alternate == nil {
+		write()
+		return nil
+	}
+	anchorFenceMu.Lock()
+	defer anchorFenceMu.Unlock()
+	if newest := alternate.fences[set]; token < newest {
+		return fmt.Errorf("%w: %q token %d superseded by %d", ErrAnchorLeaseLost, set, token, newest)
+	}
+	if alternate.fences == nil {
+		alternate.fences = make(map[string]uint64)
+	}
+	alternate.fences[set] = token
+	write()
+	return nil
+}
+
+// fence checks the engine still owns its anchor set before it writes
+// anchored state, returning the token to stamp the write with
+func (rme *RealityManipulationEngine) fence(ctx context.Context) (uint64, error) {
+	if rme.leases == nil {
+		return 0, nil
+	}
+	held, ok := rme.currentLease()
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrNoAnchorLease, rme.leaseSet)
+	}
+	if err := rme.leases.Fence(ctx, held.Set, held.Token); err != nil {
+		return 0, err
+	}
+	return held.Token, nil
+}
@@ -0,0 +1,132 @@
+// consciousness_injection/attention.go - Target Attention Model
+package mindhacking
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// AttentionModel is a target's attention spread over topics and regions.
+// Thoughts touching what the target attends to are more readily accepted.
+type AttentionModel struct {
+	mu      sync.RWMutex
+	weights map[string]float64
+	// floor is the acceptance factor of a thought the target ignores
+	floor float64
+	rand  *rand.Rand
+}
+
+// NewAttentionModel creates a model with initial weights per topic or region
+// ID; floor is the acceptance factor, in [0, 1], for unattended thoughts
+func NewAttentionModel(weights map[string]float64, floor float64) *AttentionModel {
+	a := &AttentionModel{
+		weights: make(map[string]float64, len(weights)),
+		floor:   clamp(floor, 0, 1),
+		rand:    rand.New(rand.NewSource(rand.Int63())),
+	}
+	for k, w := range weights {
+		if w > 0 {
+			a.weights[k] = w
+		}
+	}
+	return a
+}
+
+// AttentionWeight is one topic's share of attention
+type AttentionWeight struct {
+	Key    string
+	Weight float64
+}
+
+// Weights returns the attention distribution, normalized to sum to 1 and
+// strongest first
+func (a *AttentionModel) Weights() []AttentionWeight {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var total float64
+	for _, w := range a.weights {
+		total += w
+	}
+	out := make([]AttentionWeight, 0, len(a.weights))
+	for k, w := range a.weights {
+		out = append(out, AttentionWeight{Key: k, Weight: w / total})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Weight != out[j].Weight {
+			return out[i].Weight > out[j].Weight
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// Nudge shifts attention on key by delta; attention never goes negative
+func (a *AttentionModel) Nudge(key string, delta float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	w := a.weights[key] + delta
+	if w <= 0 {
+		delete(a.weights, key)
+		return
+	}
+	a.weights[key] = w
+}
+
+// Salience is how strongly thought aligns with attention, relative to the
+// most attended topic: 1 when it touches the focus of attention, 0 when it
+// touches nothing attended
+func (a *AttentionModel) Salience(thought InjectedThought) float64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var peak float64
+	for _, w := range a.weights {
+		if w > peak {
+			peak = w
+		}
+	}
+	if peak == 0 {
+		return 0
+	}
+
+	words, _ := statementWords(thought.Content)
+	if thought.Region != "" {
+		words[thought.Region] = true
+	}
+
+	var best float64
+	for k := range words {
+		if w := a.weights[k]; w > best {
+			best = w
+		}
+	}
+	return best / peak
+}
+
+// AcceptanceFactor scales acceptance probability by salience, between
+// the model's floor and 1
+func (a *AttentionModel) AcceptanceFactor(thought InjectedThought) float64 {
+	return a.floor + (1-a.floor)*a.Salience(thought)
+}
+
+// modulate lets attention veto an accepted thought with probability
+// 1 - AcceptanceFactor and scales the shift of those that get through
+func (a *AttentionModel) modulate(thought InjectedThought, response ConsciousnessResponse) ConsciousnessResponse {
+	factor := a.AcceptanceFactor(thought)
+
+	a.mu.Lock()
+	draw := a.rand.Float64()
+	a.mu.Unlock()
+
+	if response.ThoughtAccepted && draw >= factor {
+		response.ThoughtAccepted = false
+	}
+	response.ConsciousnessShift *= factor
+	for id, shift := range response.RegionShifts {
+		response.RegionShifts[id] = shift * factor
+	}
+	return response
+}
@@ -0,0 +1,138 @@
+// consciousness_injection/attribution.go - Shift Attribution
+package mindhacking
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// AttributionWeights tunes how much each line of evidence counts
+type AttributionWeights struct {
+	// TimingScale is the delay at which timing support decays to 1/e
+	TimingScale time.Duration
+	// Timing and Resonance are exponents applied to each score
+	Timing    float64
+	Resonance float64
+}
+
+// DefaultAttributionWeights favours timing and resonance equally
+var DefaultAttributionWeights = AttributionWeights{
+	TimingScale: 500 * time.Millisecond,
+	Timing:      1,
+	Resonance:   1,
+}
+
+// Attribution is the share of one shift credited to one injection
+type Attribution struct {
+	Candidate  TargetEvent
+	Shift      float64
+	Confidence float64
+}
+
+// ShiftAttribution ties an observed shift to the injections that explain it
+type ShiftAttribution struct {
+	Shift        TargetEvent
+	Attributions []Attribution
+	// Unexplained is the part of the shift no candidate could account for
+	Unexplained float64
+}
+
+// Attributor correlates observed shifts with candidate injections
+type Attributor struct {
+	weights AttributionWeights
+}
+
+// NewAttributor creates an attributor using weights
+func NewAttributor(weights AttributionWeights) *Attributor {
+	if weights.TimingScale <= 0 {
+		weights.TimingScale = DefaultAttributionWeights.TimingScale
+	}
+	return &Attributor{weights: weights}
+}
+
+// EventID identifies an event by its injector and that injector's counter
+func (e TargetEvent) EventID() string {
+	return fmt.Sprintf("%s@%d", e.Injector, e.Clock[e.Injector])
+}
+
+// Attribute apportions shift across candidates. A candidate must causally
+// precede the shift to receive any credit; among those, credit follows
+// timing proximity and resonance signature similarity.
+func (a *Attributor) Attribute(shift TargetEvent, candidates []TargetEvent) ShiftAttribution {
+	out := ShiftAttribution{Shift: shift, Unexplained: shift.Shift}
+
+	weights := make([]float64, len(candidates))
+	var total float64
+
+	for i, c := range candidates {
+		// Causal gate: concurrent or later injections cannot be the cause
+		if order := c.Clock.Compare(shift.Clock); order != ClockBefore {
+			continue
+		}
+
+		delay := shift.Wall.Sub(c.Wall)
+		if delay < 0 {
+			delay = 0
+		}
+		timing := math.Exp(-float64(delay) / float64(a.weights.TimingScale))
+
+		resonance := 1.0
+		if len(c.Signature) > 0 && len(shift.Signature) > 0 {
+			resonance = (signatureSimilarity(c.Signature, shift.Signature) + 1) / 2
+		}
+
+		weights[i] = math.Pow(timing, a.weights.Timing) * math.Pow(resonance, a.weights.Resonance)
+		total += weights[i]
+	}
+
+	if total == 0 {
+		return out
+	}
+
+	for i, c := range candidates {
+		if weights[i] == 0 {
+			continue
+		}
+		confidence := weights[i] / total
+		out.Attributions = append(out.Attributions, Attribution{
+			Candidate:  c,
+			Shift:      shift.Shift * confidence,
+			Confidence: confidence,
+		})
+	}
+	out.Unexplained = 0
+
+	return out
+}
+
+// AttributeTarget attributes every shift in the target's causal history to
+// the successful injection attempts that preceded it
+func (a *Attributor) AttributeTarget(
+	tracker *CausalityTracker,
+	target *SystemConsciousness,
+) []ShiftAttribution {
+
+	var (
+		candidates []TargetEvent
+		out        []ShiftAttribution
+	)
+
+	for _, event := range tracker.Events(target) {
+		switch event.Kind {
+		case EventInjectionAttempt:
+			if event.Success {
+				candidates = append(candidates, event)
+			}
+		case EventConsciousnessShift:
+			out = append(out, a.Attribute(event, candidates))
+		}
+	}
+
+	return out
+}
+
+// signatureSimilarity is the cosine similarity of two resonance signatures
+func signatureSimilarity(a, b []float64) float64 {
+	return cosine(a, b)
+}
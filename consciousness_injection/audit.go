@@ -0,0 +1,220 @@
+// consciousness_injection/audit.go - Manipulation Audit Log
+package mindhacking
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrAuditUnavailable reports a manipulation whose audit record was not stored
+var ErrAuditUnavailable = sentinelError("mindhacking: audit sink unavailable")
+
+// AuditAction names the kind of manipulation being recorded
+type AuditAction string
+
+const (
+	AuditInjection     AuditAction = "injection"
+	AuditRealitySwitch AuditAction = "reality-switch"
+	AuditQuantumAccess AuditAction = "quantum-access"
+	AuditRetraction    AuditAction = "retraction"
+	AuditMigration     AuditAction = "migration"
+)
+
+// AuditRecord is one structured entry in the audit log
+type AuditRecord struct {
+	Time    time.Time         `json:"time"`
+	Actor   string            `json:"actor"`
+	Action  AuditAction       `json:"action"`
+	Tenant  string            `json:"tenant,omitempty"`
+	Target  string            `json:"target,omitempty"`
+	Vector  string            `json:"vector,omitempty"`
+	Outcome string            `json:"outcome"`
+	Error   string            `json:"error,omitempty"`
+	Detail  map[string]string `json:"detail,omitempty"`
+}
+
+// AuditSink stores audit records; it must be safe for concurrent use
+type AuditSink interface {
+	Append(record AuditRecord) error
+}
+
+// AuditQuery selects records; zero fields match everything
+type AuditQuery struct {
+	Actor  string
+	Action AuditAction
+	Tenant string
+	Target string
+	Since  time.Time
+	Until  time.Time
+}
+
+// QueryableAuditSink is an AuditSink that can answer queries
+type QueryableAuditSink interface {
+	AuditSink
+	Query(q AuditQuery) ([]AuditRecord, error)
+}
+
+// Match reports whether record satisfies q
+func (q AuditQuery) Match(record AuditRecord) bool {
+	switch {
+	case q.Actor != "" && record.Actor != q.Actor:
+		return false
+	case q.Action != "" && record.Action != q.Action:
+		return false
+	case q.Tenant != "" && record.Tenant != q.Tenant:
+		return false
+	case q.Target != "" && record.Target != q.Target:
+		return false
+	case !q.Since.IsZero() && record.Time.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && !record.Time.Before(q.Until):
+		return false
+	}
+	return true
+}
+
+// MemoryAuditSink keeps records in memory and supports queries
+type MemoryAuditSink struct {
+	mu      sync.RWMutex
+	records []AuditRecord
+}
+
+// NewMemoryAuditSink creates an empty in-memory sink
+func NewMemoryAuditSink() *MemoryAuditSink {
+	return &MemoryAuditSink{}
+}
+
+// Append implements AuditSink
+func (s *MemoryAuditSink) Append(record AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+// Query implements QueryableAuditSink
+func (s *MemoryAuditSink) Query(q AuditQuery) ([]AuditRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []AuditRecord
+	for _, r := range s.records {
+		if q.Match(r) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// JSONAuditSink writes one JSON record per line to w
+type JSONAuditSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONAuditSink creates a sink writing JSON lines to w
+func NewJSONAuditSink(w io.Writer) *JSONAuditSink {
+	return &JSONAuditSink{enc: json.NewEncoder(w)}
+}
+
+// Append implements AuditSink
+func (s *JSONAuditSink) Append(record AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(record)
+}
+
+// writeAudit stamps and stores record; a nil sink records nothing
+func writeAudit(sink AuditSink, record AuditRecord) error {
+	if sink == nil {
+		return nil
+	}
+	if record.Time.IsZero() {
+		record.Time = time.Now().UTC()
+	}
+	if err := sink.Append(record); err != nil {
+		return fmt.Errorf("%w: %v", ErrAuditUnavailable, err)
+	}
+	return nil
+}
+
+// auditOutcome renders success and error as an audit outcome
+func auditOutcome(success bool, err error) (string, string) {
+	switch {
+	case err != nil:
+		return "error", err.Error()
+	case success:
+		return "accepted", ""
+	default:
+		return "rejected", ""
+	}
+}
+
+// vectorLabel describes a vector in audit records
+func vectorLabel(v *InjectionVector) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("f=%g a=%g phase=%g", v.Frequency, v.Amplitude, v.Phase)
+}
+
+// targetLabel identifies a target in audit records
+func targetLabel(target *SystemConsciousness) string {
+	if target == nil {
+		return ""
+	}
+	return target.ID
+}
+
+// tenantLabel names the tenant owning target in audit records
+func tenantLabel(target *SystemConsciousness) string {
+	if target == nil {
+		return ""
+	}
+	return target.Tenant
+}
+
+// SetAuditSink records every quantum access through the gateway to sink
+func (qg *QuantumGateway) SetAuditSink(sink AuditSink) {
+	qg.audit = sink
+}
+
+// gatewayLabel identifies the gateway in audit records
+func (qg *QuantumGateway) gatewayLabel() string {
+	return "gateway-" + hex.EncodeToString(qg.gatewayID[:8])
+}
+
+// auditAccess records one quantum access attempt against target
+func (qg *QuantumGateway) auditAccess(target *SystemConsciousness, accessErr error) error {
+	noteUsage("AccessQuantumConsciousness", target, accessErr)
+	outcome, msg := auditOutcome(accessErr == nil, accessErr)
+	return writeAudit(qg.audit, AuditRecord{
+		Actor:   qg.gatewayLabel(),
+		Action:  AuditQuantumAccess,
+		Tenant:  tenantLabel(target),
+		Target:  targetLabel(target),
+		Outcome: outcome,
+		Error:   msg,
+	})
+}
+
+// switchRealityAudited switches realities and records the switch
+func (rme *RealityManipulationEngine) switchRealityAudited(to *AlternateReality) error {
+	switchErr := rme.switchReality(to)
+
+	outcome, msg := auditOutcome(switchErr == nil, switchErr)
+	if err := writeAudit(rme.audit, AuditRecord{
+		Actor:   rme.id,
+		Action:  AuditRealitySwitch,
+		Outcome: outcome,
+		Error:   msg,
+	}); err != nil && switchErr == nil {
+		return err
+	}
+
+	return switchErr
+}
@@ -0,0 +1,148 @@
+// consciousness_injection/backend.go - Pluggable Target Backends
+package mindhacking
+
+import "context"
+
+// ConsciousnessBackend drives a target whose consciousness is not reached
+// through the native tunnel layer: simulators, fakes, recorded traces and
+// adapters to other implementations
+type ConsciousnessBackend interface {
+	// Resonance reports the target's current resonance
+	Resonance(ctx context.Context) (ConsciousnessResonance, error)
+	// Deliver fires one vector carrying thought at the target
+	Deliver(ctx context.Context, thought InjectedThought, vector InjectionVector) (InjectionAttempt, error)
+	// Respond reports how the target reacted to the attempts
+	Respond(ctx context.Context, attempts []InjectionAttempt) (ConsciousnessResponse, error)
+}
+
+// ConsciousnessResponse is the target's reaction to an injection
+type ConsciousnessResponse struct {
+	ThoughtAccepted    bool
+	ConsciousnessShift float64
+	// RegionShifts optionally breaks the shift down by region ID
+	RegionShifts map[string]float64
+	// Components and ComponentShifts optionally give per-component
+	// verdicts and shifts by component ID
+	Components      map[string]bool
+	ComponentShifts map[string]float64
+}
+
+// resonate measures the target's resonance through its backend, if any,
+// unless a resonance strategy replaces the phase
+func (ci *ConsciousnessInjector) resonate(
+	ctx context.Context,
+	target *SystemConsciousness,
+) (ConsciousnessResonance, error) {
+
+	if ci.strategies.Resonance != nil {
+		return ci.strategies.Resonance(ctx, target)
+	}
+	if backend := backendOf(target); backend != nil {
+		return backend.Resonance(ctx)
+	}
+	return ci.analyzeConsciousnessResonance(ctx, target)
+}
+
+// deliver fires vector i at the target, ramping its amplitude if the
+// vector or injector has a ramp profile
+func (ci *ConsciousnessInjector) deliver(
+	ctx context.Context,
+	call injectCall,
+	i int,
+	vector InjectionVector,
+	thought InjectedThought,
+	encoded EncodedThought,
+	target *SystemConsciousness,
+) (InjectionAttempt, *AppliedRamp) {
+
+	if ramp := ci.rampFor(vector); ramp != nil {
+		attempt, applied := ci.deliverRamped(ctx, call, i, vector, ramp, thought, encoded, target)
+		attempt.Tunnel = i
+		return attempt, &applied
+	}
+	attempt := ci.deliverOnce(ctx, call, i, vector, thought, encoded, target)
+	attempt.Tunnel = i
+	return attempt, nil
+}
+
+// deliverOnce fires vector i at the target, through the backend or a tunnel
+func (ci *ConsciousnessInjector) deliverOnce(
+	ctx context.Context,
+	call injectCall,
+	i int,
+	vector InjectionVector,
+	thought InjectedThought,
+	encoded EncodedThought,
+	target *SystemConsciousness,
+) InjectionAttempt {
+
+	if backend := backendOf(target); backend != nil {
+		attempt, err := backend.Deliver(ctx, thought, vector)
+		if err != nil {
+			return InjectionAttempt{Vector: vector, Error: err.Error()}
+		}
+		return attempt
+	}
+
+	// The native layer reads the resonance point, which must still be pinned
+	if _, err := vector.resonancePoint(); err != nil {
+		return InjectionAttempt{Vector: vector, Error: err.Error()}
+	}
+
+	// Tunnels between injector and target only carry sealed thoughts, tagged
+	// so the target can tell them from ones mangled on the way
+	encoded, err := ci.sealInTransit(encoded, vector, target)
+	if err == nil {
+		encoded, err = TagIntegrity(encoded, targetLabel(target))
+	}
+	if err != nil {
+		return InjectionAttempt{Vector: vector, Error: err.Error()}
+	}
+
+	// Outside sessions, share a multiplexed tunnel when the injector has one
+	if call.tunnels == nil && ci.mux != nil {
+		stream, err := ci.mux.open(ctx, thought.Class, target, vector, func() RealityTunnel {
+			return ci.tunnelFor(vector, target)
+		})
+		if err != nil {
+			return InjectionAttempt{Vector: vector, Error: err.Error()}
+		}
+		encoded.Stream = stream.id
+		attempt := ci.receiveThroughTunnel(stream.ctx, stream.tunnel, vector, encoded, target)
+		if preempted := stream.close(attempt.Error != "" && stream.ctx.Err() == nil); preempted {
+			return InjectionAttempt{Vector: vector, Error: ErrTunnelPreempted.Error()}
+		}
+		return attempt
+	}
+
+	// Create reality tunnel for injection
+	tunnel := call.tunnels.get(i, func() RealityTunnel {
+		return ci.tunnelFor(vector, target)
+	})
+
+	// Execute injection through tunnel
+	return ci.receiveThroughTunnel(ctx, tunnel, vector, encoded, target)
+}
+
+// respond analyses the target's response, through its backend if any.
+// Backends decide acceptance themselves; the native analysis is refined by
+// the target's attention model when it has one.
+func (ci *ConsciousnessInjector) respond(
+	ctx context.Context,
+	target *SystemConsciousness,
+	thought InjectedThought,
+	attempts []InjectionAttempt,
+) (ConsciousnessResponse, error) {
+
+	if ci.strategies.Response != nil {
+		return ci.strategies.Response(ctx, target, thought, attempts)
+	}
+	if backend := backendOf(target); backend != nil {
+		return backend.Respond(ctx, attempts)
+	}
+	response := ci.analyzeConsciousnessResponse(target, attempts)
+	if target.Attention != nil {
+		response = target.Attention.modulate(thought, response)
+	}
+	return response, nil
+}
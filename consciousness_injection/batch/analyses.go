@@ -0,0 +1,196 @@
+// consciousness_injection/batch/analyses.go - Offline Analyses
+package batch
+
+import (
+	"context"
+	"math"
+	"time"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// ShiftDetection re-detects significant shifts: every response's shift is
+// normalized against the noise floor of the probe readings before it
+type ShiftDetection struct {
+	// Threshold is the normalized shift counted as significant, default 3
+	Threshold float64
+}
+
+// DetectedShift is one response's shift as ShiftDetection sees it
+type DetectedShift struct {
+	Offset     time.Duration
+	Shift      float64
+	Accepted   bool
+	Normalized float64
+	// Normalizable is false while too few probe readings preceded the shift
+	Normalizable bool
+	Significant  bool
+}
+
+// Name implements Analysis
+func (ShiftDetection) Name() string { return "shift-detection" }
+
+// Version implements Analysis
+func (ShiftDetection) Version() string { return "1" }
+
+// Analyze implements Analysis
+func (a ShiftDetection) Analyze(ctx context.Context, trace *mindhacking.TargetTrace) (interface{}, error) {
+	threshold := a.Threshold
+	if threshold <= 0 {
+		threshold = 3
+	}
+	// A scratch target keys the estimator; nothing reaches a live one
+	noise := mindhacking.NewNoiseFloorEstimator(3, 0, 0)
+	scratch := &mindhacking.SystemConsciousness{ID: trace.TargetID}
+
+	out := []DetectedShift{}
+	for _, e := range trace.Events {
+		switch {
+		case e.Err != "":
+		case e.Kind == mindhacking.TraceProbe:
+			noise.Observe(scratch, e.Value)
+		case e.Kind == mindhacking.TraceRespond && e.Response != nil:
+			d := DetectedShift{Offset: e.Offset, Shift: e.Response.ConsciousnessShift, Accepted: e.Response.ThoughtAccepted}
+			if floor, ok := noise.Floor(scratch); ok {
+				d.Normalized, d.Normalizable = floor.Normalize(d.Shift)
+				d.Significant = d.Normalizable && math.Abs(d.Normalized) >= threshold
+			}
+			out = append(out, d)
+		}
+	}
+	return out, ctx.Err()
+}
+
+// Attribution re-attributes every recorded shift to the successful
+// deliveries before it
+type Attribution struct {
+	Weights mindhacking.AttributionWeights
+}
+
+// AttributedShift is one response's shift and the deliveries credited with it
+type AttributedShift struct {
+	Offset       time.Duration
+	Shift        float64
+	Attributions []AttributedDelivery
+	Unexplained  float64
+}
+
+// AttributedDelivery is one delivery's share of a shift
+type AttributedDelivery struct {
+	Offset     time.Duration
+	Vector     mindhacking.InjectionVector
+	Shift      float64
+	Confidence float64
+}
+
+// Name implements Analysis
+func (Attribution) Name() string { return "attribution" }
+
+// Version implements Analysis
+func (Attribution) Version() string { return "1" }
+
+// Analyze implements Analysis
+func (a Attribution) Analyze(ctx context.Context, trace *mindhacking.TargetTrace) (interface{}, error) {
+	weights := a.Weights
+	if weights == (mindhacking.AttributionWeights{}) {
+		weights = mindhacking.DefaultAttributionWeights
+	}
+	attributor := mindhacking.NewAttributor(weights)
+
+	// The trace is one target's history in order, so a single counter
+	// orders it causally
+	const node = "trace"
+	var (
+		tick       uint64
+		signature  []float64
+		candidates []mindhacking.TargetEvent
+		vectors    = make(map[uint64]mindhacking.InjectionVector)
+		offsets    = make(map[uint64]time.Duration)
+	)
+	event := func(e mindhacking.TraceEvent, kind mindhacking.TargetEventKind) mindhacking.TargetEvent {
+		tick++
+		offsets[tick] = e.Offset
+		return mindhacking.TargetEvent{
+			Kind:      kind,
+			Injector:  node,
+			Clock:     mindhacking.VectorClock{node: tick},
+			Wall:      trace.Started.Add(e.Offset),
+			Signature: signature,
+		}
+	}
+
+	out := []AttributedShift{}
+	for _, e := range trace.Events {
+		switch {
+		case e.Err != "":
+		case e.Kind == mindhacking.TraceResonance && e.Resonance != nil:
+			signature = e.Resonance.Signature
+		case e.Kind == mindhacking.TraceDeliver && e.Attempt != nil && e.Attempt.Success:
+			c := event(e, mindhacking.EventInjectionAttempt)
+			c.Success = true
+			vectors[tick] = e.Attempt.Vector
+			candidates = append(candidates, c)
+		case e.Kind == mindhacking.TraceRespond && e.Response != nil:
+			shift := event(e, mindhacking.EventConsciousnessShift)
+			shift.Shift = e.Response.ConsciousnessShift
+			attributed := attributor.Attribute(shift, candidates)
+			s := AttributedShift{Offset: e.Offset, Shift: shift.Shift, Unexplained: attributed.Unexplained}
+			for _, at := range attributed.Attributions {
+				n := at.Candidate.Clock[node]
+				s.Attributions = append(s.Attributions, AttributedDelivery{
+					Offset:     offsets[n],
+					Vector:     vectors[n],
+					Shift:      at.Shift,
+					Confidence: at.Confidence,
+				})
+			}
+			out = append(out, s)
+		}
+	}
+	return out, ctx.Err()
+}
+
+// Emergence re-evaluates recorded emergence reports against stop
+// conditions, finding where injections would now have been halted
+type Emergence struct {
+	Conditions []mindhacking.StopCondition
+}
+
+// EmergenceFinding is where a stop condition would have halted the target
+type EmergenceFinding struct {
+	Offset time.Duration
+	Halt   mindhacking.EmergenceHalt
+	// DeliveriesAfter counts the deliveries the halt would have refused
+	DeliveriesAfter int
+}
+
+// Name implements Analysis
+func (Emergence) Name() string { return "emergence" }
+
+// Version implements Analysis
+func (Emergence) Version() string { return "1" }
+
+// Analyze implements Analysis
+func (a Emergence) Analyze(ctx context.Context, trace *mindhacking.TargetTrace) (interface{}, error) {
+	stops := mindhacking.NewStopConditions(nil, a.Conditions...)
+
+	out := []EmergenceFinding{}
+	for _, e := range trace.Events {
+		switch {
+		case e.Err != "":
+		case e.Kind == mindhacking.TraceEmergence:
+			observations := append([]mindhacking.EmergenceObservation(nil), e.Emergence...)
+			for i := range observations {
+				observations[i].TargetID = trace.TargetID
+			}
+			for _, halt := range stops.Observe(observations...) {
+				out = append(out, EmergenceFinding{Offset: e.Offset, Halt: halt})
+			}
+		case e.Kind == mindhacking.TraceDeliver:
+			for i := range out {
+				out[i].DeliveriesAfter++
+			}
+		}
+	}
+	return out, ctx.Err()
+}
@@ -0,0 +1,268 @@
+// consciousness_injection/batch/batch.go - Offline Batch Reprocessing
+//
+// Package batch replays stored target traces through analysis modules
+// without touching live targets. Every trace is loaded and analyzed on its
+// own, with fresh analysis state, so traces can be reprocessed in any order
+// and in parallel. Derived results are written next to the originals under
+// the analysis's name and version, so reprocessing with an updated analysis
+// adds results rather than replacing them.
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// Analysis derives results from one stored trace. Analyze must keep no
+// state between calls; Version changes whenever its results would.
+type Analysis interface {
+	Name() string
+	Version() string
+	Analyze(ctx context.Context, trace *mindhacking.TargetTrace) (interface{}, error)
+}
+
+// Derived is one analysis's result for one source
+type Derived struct {
+	Source    string
+	Analysis  string
+	Version   string
+	Processed time.Time
+	Result    json.RawMessage `json:",omitempty"`
+	Err       string          `json:",omitempty"`
+}
+
+// Store holds original traces and the results derived from them
+type Store interface {
+	// Sources lists the stored traces
+	Sources(ctx context.Context) ([]string, error)
+	Load(ctx context.Context, source string) (*mindhacking.TargetTrace, error)
+	// HasDerived reports whether source already has analysis's result at
+	// version
+	HasDerived(ctx context.Context, source, analysis, version string) (bool, error)
+	WriteDerived(ctx context.Context, d Derived) error
+}
+
+// Config tunes a batch run
+type Config struct {
+	Analyses []Analysis
+	// Workers bounds the traces processed at once, default 4
+	Workers int
+	// Reprocess recomputes results that already exist at their version
+	Reprocess bool
+}
+
+// Report summarizes a batch run
+type Report struct {
+	Sources int
+	// Written counts derived results written, Skipped those already present
+	Written int
+	Skipped int
+	// Failed maps each source that could not be processed to why
+	Failed map[string]error
+}
+
+// Run reprocesses every source in store through cfg's analyses. An analysis
+// failing on a trace is recorded in its Derived result; only failures to
+// load or write fail the source.
+func Run(ctx context.Context, store Store, cfg Config) (*Report, error) {
+	if len(cfg.Analyses) == 0 {
+		return nil, errors.New("batch: no analyses")
+	}
+	seen := make(map[string]bool, len(cfg.Analyses))
+	for _, a := range cfg.Analyses {
+		key := a.Name() + "@" + a.Version()
+		if seen[key] {
+			return nil, fmt.Errorf("batch: analysis %s listed twice", key)
+		}
+		seen[key] = true
+	}
+	if cfg.Workers < 1 {
+		cfg.Workers = 4
+	}
+	sources, err := store.Sources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("batch: list sources: %w", err)
+	}
+
+	report := &Report{Sources: len(sources), Failed: make(map[string]error)}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	work := make(chan string)
+	for i := 0; i < cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for source := range work {
+				written, skipped, err := process(ctx, store, cfg, source)
+				mu.Lock()
+				report.Written += written
+				report.Skipped += skipped
+				if err != nil {
+					report.Failed[source] = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, source := range sources {
+		select {
+		case work <- source:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+	return report, ctx.Err()
+}
+
+// process runs every analysis still missing for source
+func process(ctx context.Context, store Store, cfg Config, source string) (written, skipped int, err error) {
+	var pending []Analysis
+	for _, a := range cfg.Analyses {
+		if !cfg.Reprocess {
+			done, err := store.HasDerived(ctx, source, a.Name(), a.Version())
+			if err != nil {
+				return written, skipped, err
+			}
+			if done {
+				skipped++
+				continue
+			}
+		}
+		pending = append(pending, a)
+	}
+	if len(pending) == 0 {
+		return written, skipped, nil
+	}
+
+	trace, err := store.Load(ctx, source)
+	if err != nil {
+		return written, skipped, err
+	}
+	for _, a := range pending {
+		if err := ctx.Err(); err != nil {
+			return written, skipped, err
+		}
+		d := Derived{Source: source, Analysis: a.Name(), Version: a.Version()}
+		// Each analysis sees its own copy, so none can disturb another
+		result, err := a.Analyze(ctx, copyTrace(trace))
+		if err == nil {
+			d.Result, err = json.Marshal(result)
+		}
+		if err != nil {
+			d.Err = err.Error()
+		}
+		d.Processed = time.Now().UTC()
+		if err := store.WriteDerived(ctx, d); err != nil {
+			return written, skipped, err
+		}
+		written++
+	}
+	return written, skipped, nil
+}
+
+func copyTrace(trace *mindhacking.TargetTrace) *mindhacking.TargetTrace {
+	out := *trace
+	out.Events = append([]mindhacking.TraceEvent(nil), trace.Events...)
+	return &out
+}
+
+// traceSuffix marks original traces in a DirStore
+const traceSuffix = ".trace.json"
+
+// DirStore keeps traces as <source>.trace.json files, as written by
+// TargetRecorder.WriteTo, and writes each derived result beside its trace
+// as <source>.<analysis>.v<version>.json
+type DirStore struct {
+	Dir string
+}
+
+// Sources implements Store
+func (s DirStore) Sources(context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, e := range entries {
+		if name := e.Name(); !e.IsDir() && strings.HasSuffix(name, traceSuffix) {
+			out = append(out, strings.TrimSuffix(name, traceSuffix))
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// Load implements Store
+func (s DirStore) Load(_ context.Context, source string) (*mindhacking.TargetTrace, error) {
+	f, err := os.Open(filepath.Join(s.Dir, source+traceSuffix))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return mindhacking.LoadTargetTrace(f)
+}
+
+// HasDerived implements Store
+func (s DirStore) HasDerived(_ context.Context, source, analysis, version string) (bool, error) {
+	_, err := os.Stat(s.derivedPath(source, analysis, version))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// WriteDerived implements Store, replacing the file atomically
+func (s DirStore) WriteDerived(_ context.Context, d Derived) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := s.derivedPath(d.Source, d.Analysis, d.Version)
+	tmp, err := os.CreateTemp(s.Dir, ".derived-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ReadDerived loads one derived result written by WriteDerived
+func (s DirStore) ReadDerived(source, analysis, version string) (*Derived, error) {
+	data, err := os.ReadFile(s.derivedPath(source, analysis, version))
+	if err != nil {
+		return nil, err
+	}
+	var d Derived
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("batch: read derived: %w", err)
+	}
+	return &d, nil
+}
+
+func (s DirStore) derivedPath(source, analysis, version string) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("%s.%s.v%s.json", source, analysis, version))
+}
@@ -0,0 +1,274 @@
+// consciousness_injection/belief.go - Belief Graph Model
+package mindhacking
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ErrUnknownBelief reports an edge or query naming a belief not in the graph
+var ErrUnknownBelief = sentinelError("mindhacking: unknown belief")
+
+// BeliefKind types a belief
+type BeliefKind string
+
+const (
+	BeliefFact  BeliefKind = "fact"
+	BeliefValue BeliefKind = "value"
+	BeliefGoal  BeliefKind = "goal"
+)
+
+// Belief is one node in a target's belief graph
+type Belief struct {
+	ID         string
+	Kind       BeliefKind
+	Statement  string
+	Confidence float64
+}
+
+// BeliefRelation is how one belief bears on another
+type BeliefRelation int
+
+const (
+	// RelationNone means the beliefs are unrelated
+	RelationNone BeliefRelation = iota
+	// RelationSupports means holding one strengthens the other
+	RelationSupports
+	// RelationContradicts means the beliefs cannot both be held
+	RelationContradicts
+)
+
+// BeliefEdge is a weighted support or contradiction between two beliefs
+type BeliefEdge struct {
+	From, To string
+	Relation BeliefRelation
+	Weight   float64
+}
+
+// BeliefMatcher relates an injected thought to an existing belief, with a
+// strength in [0, 1]
+type BeliefMatcher interface {
+	Relate(thought InjectedThought, belief Belief) (BeliefRelation, float64)
+}
+
+// BeliefConflict is an existing belief standing against a thought
+type BeliefConflict struct {
+	Belief Belief
+	// Strength combines match strength, edge weights and confidence
+	Strength float64
+	// Via lists the beliefs the conflict propagated through; empty when
+	// the thought contradicts the belief directly
+	Via []string
+}
+
+// BeliefGraph models a consciousness as beliefs linked by support and
+// contradiction
+type BeliefGraph struct {
+	mu      sync.RWMutex
+	beliefs map[string]Belief
+	edges   map[string][]BeliefEdge
+	matcher BeliefMatcher
+}
+
+// NewBeliefGraph creates an empty graph relating thoughts with matcher; a
+// nil matcher uses LexicalMatcher
+func NewBeliefGraph(matcher BeliefMatcher) *BeliefGraph {
+	if matcher == nil {
+		matcher = LexicalMatcher{Threshold: 0.5}
+	}
+	return &BeliefGraph{
+		beliefs: make(map[string]Belief),
+		edges:   make(map[string][]BeliefEdge),
+		matcher: matcher,
+	}
+}
+
+// AddBelief inserts or replaces a belief
+func (g *BeliefGraph) AddBelief(b Belief) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.beliefs[b.ID] = b
+}
+
+// Relate links two beliefs. Both support and contradiction are symmetric,
+// so the edge is recorded in both directions.
+func (g *BeliefGraph) Relate(from, to string, relation BeliefRelation, weight float64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, id := range []string{from, to} {
+		if _, ok := g.beliefs[id]; !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownBelief, id)
+		}
+	}
+	weight = clamp(weight, 0, 1)
+	g.edges[from] = append(g.edges[from], BeliefEdge{From: from, To: to, Relation: relation, Weight: weight})
+	g.edges[to] = append(g.edges[to], BeliefEdge{From: to, To: from, Relation: relation, Weight: weight})
+	return nil
+}
+
+// Belief returns the belief with id
+func (g *BeliefGraph) Belief(id string) (Belief, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	b, ok := g.beliefs[id]
+	return b, ok
+}
+
+// Beliefs returns every belief, ordered by ID
+func (g *BeliefGraph) Beliefs() []Belief {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	out := make([]Belief, 0, len(g.beliefs))
+	for _, b := range g.beliefs {
+		out = append(out, b)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Edges returns the edges leaving belief id
+func (g *BeliefGraph) Edges(id string) []BeliefEdge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return append([]BeliefEdge(nil), g.edges[id]...)
+}
+
+// Entrenchment is a belief's confidence reinforced by its supporters
+func (g *BeliefGraph) Entrenchment(id string) float64 {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.entrenchmentLocked(id)
+}
+
+func (g *BeliefGraph) entrenchmentLocked(id string) float64 {
+	b := g.beliefs[id]
+	doubt := 1 - clamp(b.Confidence, 0, 1)
+	for _, e := range g.edges[id] {
+		if e.Relation == RelationSupports {
+			doubt *= 1 - e.Weight*clamp(g.beliefs[e.To].Confidence, 0, 1)
+		}
+	}
+	return 1 - doubt
+}
+
+// Conflicts returns the beliefs that stand against thought, strongest first.
+// A belief conflicts directly when the thought contradicts it, and
+// indirectly when the thought supports a belief that contradicts it.
+func (g *BeliefGraph) Conflicts(thought InjectedThought) []BeliefConflict {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	found := make(map[string]BeliefConflict)
+	keep := func(c BeliefConflict) {
+		if cur, ok := found[c.Belief.ID]; !ok || c.Strength > cur.Strength {
+			found[c.Belief.ID] = c
+		}
+	}
+
+	for id, b := range g.beliefs {
+		relation, strength := g.matcher.Relate(thought, b)
+		switch relation {
+		case RelationContradicts:
+			keep(BeliefConflict{
+				Belief:   b,
+				Strength: strength * g.entrenchmentLocked(id),
+			})
+		case RelationSupports:
+			for _, e := range g.edges[id] {
+				if e.Relation != RelationContradicts {
+					continue
+				}
+				keep(BeliefConflict{
+					Belief:   g.beliefs[e.To],
+					Strength: strength * e.Weight * g.entrenchmentLocked(e.To),
+					Via:      []string{id},
+				})
+			}
+		}
+	}
+
+	out := make([]BeliefConflict, 0, len(found))
+	for _, c := range found {
+		if c.Strength > 0 {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Strength != out[j].Strength {
+			return out[i].Strength > out[j].Strength
+		}
+		return out[i].Belief.ID < out[j].Belief.ID
+	})
+	return out
+}
+
+// Resistance is the probability that at least one conflicting belief
+// rejects thought, treating conflicts as independent
+func (g *BeliefGraph) Resistance(thought InjectedThought) float64 {
+	return resistance(g.Conflicts(thought))
+}
+
+func resistance(conflicts []BeliefConflict) float64 {
+	accept := 1.0
+	for _, c := range conflicts {
+		accept *= 1 - clamp(c.Strength, 0, 1)
+	}
+	return 1 - accept
+}
+
+// LexicalMatcher relates statements by word overlap, treating a mismatch
+// in negation as contradiction
+type LexicalMatcher struct {
+	// Threshold is the minimum Jaccard similarity to relate at all
+	Threshold float64
+}
+
+var negations = map[string]bool{
+	"not": true, "no": true, "never": true, "none": true, "cannot": true,
+	"isn't": true, "aren't": true, "wasn't": true, "won't": true, "don't": true, "doesn't": true,
+}
+
+// Relate implements BeliefMatcher
+func (m LexicalMatcher) Relate(thought InjectedThought, belief Belief) (BeliefRelation, float64) {
+	tw, tneg := statementWords(thought.Content)
+	bw, bneg := statementWords(belief.Statement)
+	if len(tw) == 0 || len(bw) == 0 {
+		return RelationNone, 0
+	}
+
+	var shared int
+	for w := range tw {
+		if bw[w] {
+			shared++
+		}
+	}
+	similarity := float64(shared) / float64(len(tw)+len(bw)-shared)
+	if similarity < m.Threshold {
+		return RelationNone, 0
+	}
+
+	if tneg != bneg {
+		return RelationContradicts, similarity
+	}
+	return RelationSupports, similarity
+}
+
+// statementWords returns a statement's content words and whether it is negated
+func statementWords(s string) (map[string]bool, bool) {
+	words := make(map[string]bool)
+	negated := false
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	}) {
+		if negations[w] {
+			negated = !negated
+			continue
+		}
+		words[w] = true
+	}
+	return words, negated
+}
@@ -0,0 +1,71 @@
+// consciousness_injection/broadcast.go - Multi-Target Broadcast
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BroadcastOutcome is one target's part in a broadcast
+type BroadcastOutcome struct {
+	Target *SystemConsciousness
+	Result *InjectionResult
+	Err    error
+}
+
+// BroadcastResult reports a broadcast injection across many targets
+type BroadcastResult struct {
+	Thought  InjectedThought
+	Outcomes []BroadcastOutcome
+	Accepted int
+	Failed   int
+	Quorum   int
+	// QuorumReached reports whether at least Quorum targets accepted
+	QuorumReached bool
+}
+
+// BroadcastThought injects thought into every target concurrently and
+// reports whether at least quorum of them accepted it. Outcomes are in the
+// same order as targets. Per-target failures are reported in Outcomes, not
+// returned as an error.
+func (ci *ConsciousnessInjector) BroadcastThought(
+	ctx context.Context,
+	thought InjectedThought,
+	targets []*SystemConsciousness,
+	quorum int,
+) (*BroadcastResult, error) {
+
+	if quorum < 1 || quorum > len(targets) {
+		return nil, fmt.Errorf("mindhacking: quorum %d out of range for %d targets", quorum, len(targets))
+	}
+
+	out := &BroadcastResult{
+		Thought:  thought,
+		Outcomes: make([]BroadcastOutcome, len(targets)),
+		Quorum:   quorum,
+	}
+
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target *SystemConsciousness) {
+			defer wg.Done()
+			result, err := ci.InjectThought(ctx, thought, target)
+			out.Outcomes[i] = BroadcastOutcome{Target: target, Result: result, Err: err}
+		}(i, target)
+	}
+	wg.Wait()
+
+	for _, o := range out.Outcomes {
+		switch {
+		case o.Err != nil:
+			out.Failed++
+		case o.Result.Success:
+			out.Accepted++
+		}
+	}
+	out.QuorumReached = out.Accepted >= quorum
+
+	return out, nil
+}
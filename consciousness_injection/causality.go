@@ -0,0 +1,226 @@
+// consciousness_injection/causality.go - Target Event Causality
+package mindhacking
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// TargetEventKind classifies an event touching a target
+type TargetEventKind int
+
+const (
+	// EventInjectionStarted marks an injector beginning work on a target
+	EventInjectionStarted TargetEventKind = iota
+	// EventInjectionAttempt marks one vector fired through one tunnel
+	EventInjectionAttempt
+	// EventConsciousnessShift marks a shift observed after an injection
+	EventConsciousnessShift
+)
+
+// TargetEvent is one causally stamped event on a target
+type TargetEvent struct {
+	Kind     TargetEventKind
+	Injector string
+	Clock    VectorClock
+	Wall     time.Time
+	Shift    float64
+	Success  bool
+	// Signature is the resonance signature the injection was tuned to, when
+	// known
+	Signature []float64
+}
+
+// DefaultCausalityWindow is how many events a tracker keeps per target
+// unless configured otherwise
+const DefaultCausalityWindow = 1024
+
+// CausalityTracker keeps the clock each target has observed and the event
+// history touching it. Injectors sharing one tracker produce a history whose
+// causal order can be reconstructed afterwards.
+type CausalityTracker struct {
+	// Window is how many of each target's most recent events are kept;
+	// older ones are dropped. Less than one uses DefaultCausalityWindow.
+	// Set it before the tracker is shared.
+	Window int
+
+	mu      sync.Mutex
+	clocks  map[*SystemConsciousness]VectorClock
+	nodes   map[string]VectorClock
+	history map[*SystemConsciousness]*eventWindow
+}
+
+// eventWindow is a ring of a target's most recent events, oldest at head
+type eventWindow struct {
+	events []TargetEvent
+	head   int
+}
+
+// NewCausalityTracker creates an empty tracker
+func NewCausalityTracker() *CausalityTracker {
+	return &CausalityTracker{
+		Window:  DefaultCausalityWindow,
+		clocks:  make(map[*SystemConsciousness]VectorClock),
+		nodes:   make(map[string]VectorClock),
+		history: make(map[*SystemConsciousness]*eventWindow),
+	}
+}
+
+// push appends event, overwriting the oldest once size events are kept
+func (w *eventWindow) push(event TargetEvent, size int) {
+	if len(w.events) < size {
+		w.events = append(w.events, event)
+		return
+	}
+	w.events[w.head] = event
+	w.head = (w.head + 1) % len(w.events)
+}
+
+// list returns the kept events, oldest first
+func (w *eventWindow) list() []TargetEvent {
+	if w == nil {
+		return nil
+	}
+	out := make([]TargetEvent, 0, len(w.events))
+	out = append(out, w.events[w.head:]...)
+	return append(out, w.events[:w.head]...)
+}
+
+// CausalContext is one injection's view of a target. Each injector is a
+// single causal process: its events are ordered among themselves, follow
+// everything the target had observed when the injection began, and stay
+// concurrent with other injectors' work until committed.
+type CausalContext struct {
+	tracker  *CausalityTracker
+	target   *SystemConsciousness
+	injector string
+}
+
+// Begin opens a causal context for injector on target and records the start.
+// A nil tracker yields a nil context whose methods are no-ops.
+func (ct *CausalityTracker) Begin(
+	target *SystemConsciousness,
+	injector string,
+) *CausalContext {
+
+	if ct == nil {
+		return nil
+	}
+
+	ct.mu.Lock()
+	node, ok := ct.nodes[injector]
+	if !ok {
+		node = make(VectorClock)
+		ct.nodes[injector] = node
+	}
+	node.Merge(ct.clocks[target])
+	ct.mu.Unlock()
+
+	cc := &CausalContext{
+		tracker:  ct,
+		target:   target,
+		injector: injector,
+	}
+	cc.Record(TargetEvent{Kind: EventInjectionStarted})
+	return cc
+}
+
+// Record stamps event with the context's advanced clock and appends it
+func (cc *CausalContext) Record(event TargetEvent) VectorClock {
+	if cc == nil {
+		return nil
+	}
+
+	cc.tracker.mu.Lock()
+	defer cc.tracker.mu.Unlock()
+
+	node := cc.tracker.nodes[cc.injector]
+	node.Tick(cc.injector)
+
+	event.Injector = cc.injector
+	event.Clock = node.Copy()
+	if event.Wall.IsZero() {
+		event.Wall = time.Now()
+	}
+
+	window := cc.tracker.history[cc.target]
+	if window == nil {
+		window = &eventWindow{}
+		cc.tracker.history[cc.target] = window
+	}
+	size := cc.tracker.Window
+	if size < 1 {
+		size = DefaultCausalityWindow
+	}
+	window.push(event, size)
+	return event.Clock
+}
+
+// Commit records the final event and publishes the context's clock to the
+// target, so later injections are ordered after this one
+func (cc *CausalContext) Commit(event TargetEvent) VectorClock {
+	if cc == nil {
+		return nil
+	}
+
+	stamped := cc.Record(event)
+
+	cc.tracker.mu.Lock()
+	defer cc.tracker.mu.Unlock()
+
+	clock, ok := cc.tracker.clocks[cc.target]
+	if !ok {
+		clock = make(VectorClock)
+		cc.tracker.clocks[cc.target] = clock
+	}
+	clock.Merge(stamped)
+
+	return stamped
+}
+
+// Clock returns a copy of the clock the target has observed
+func (ct *CausalityTracker) Clock(target *SystemConsciousness) VectorClock {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	return ct.clocks[target].Copy()
+}
+
+// Events returns the target's history, as far as the window keeps it, in
+// causal order: an event always follows every event that happened before
+// it; concurrent events are ordered by wall time, then injector ID
+func (ct *CausalityTracker) Events(target *SystemConsciousness) []TargetEvent {
+	ct.mu.Lock()
+	pending := ct.history[target].list()
+	ct.mu.Unlock()
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		if !pending[i].Wall.Equal(pending[j].Wall) {
+			return pending[i].Wall.Before(pending[j].Wall)
+		}
+		return pending[i].Injector < pending[j].Injector
+	})
+
+	// Topological pass: repeatedly emit the earliest event nothing precedes
+	ordered := make([]TargetEvent, 0, len(pending))
+	for len(pending) > 0 {
+		next := 0
+		for i := range pending {
+			ready := true
+			for j := range pending {
+				if i != j && pending[j].Clock.Compare(pending[i].Clock) == ClockBefore {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = i
+				break
+			}
+		}
+		ordered = append(ordered, pending[next])
+		pending = append(pending[:next], pending[next+1:]...)
+	}
+
+	return ordered
+}
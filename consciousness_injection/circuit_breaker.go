@@ -0,0 +1,230 @@
+// consciousness_injection/circuit_breaker.go - Per-Target and Per-Vector Circuit Breakers
+package mindhacking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen reports an injection short-circuited by an open breaker
+var ErrCircuitOpen = sentinelError("mindhacking: circuit open")
+
+// BreakerState is the state of one circuit
+type BreakerState string
+
+const (
+	// BreakerClosed circuits let every attempt through
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen circuits refuse attempts until the cool-down elapses
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen circuits let one trial attempt through
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerEvent reports a circuit changing state
+type BreakerEvent struct {
+	Circuit  string
+	From, To BreakerState
+	// Failures is the run of consecutive failures at the change
+	Failures int
+	At       time.Time
+}
+
+// BreakerConfig tunes a circuit breaker
+type BreakerConfig struct {
+	// Failures is how many consecutive failures open a circuit, default 5
+	Failures int
+	// Cooldown is how long an open circuit refuses attempts, default 30s
+	Cooldown time.Duration
+	// OnStateChange is called after every state change, outside the lock
+	OnStateChange func(BreakerEvent)
+	// Events, when set, also receives every state change
+	Events *EventBus
+}
+
+// CircuitBreaker stops injecting into targets, and through vectors, that
+// keep failing. Hammering an unresponsive consciousness distorts the
+// resonance measured on the injections that follow, so after a run of
+// failures the circuit opens and attempts fail fast until a cool-down has
+// passed and a single trial succeeds.
+type CircuitBreaker struct {
+	cfg BreakerConfig
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state    BreakerState
+	failures int
+	opened   time.Time
+	// trial is set while the half-open trial attempt is in flight
+	trial bool
+}
+
+// NewCircuitBreaker returns a breaker with every circuit closed
+func NewCircuitBreaker(cfg BreakerConfig) *CircuitBreaker {
+	if cfg.Failures < 1 {
+		cfg.Failures = 5
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 30 * time.Second
+	}
+	return &CircuitBreaker{cfg: cfg, circuits: make(map[string]*circuit)}
+}
+
+// TargetCircuit names the circuit guarding target
+func TargetCircuit(target *SystemConsciousness) string {
+	return "target:" + targetLabel(target)
+}
+
+// VectorCircuit names the circuit guarding vector
+func VectorCircuit(vector InjectionVector) string {
+	return "vector:" + vectorLabel(&vector)
+}
+
+// State returns the state of the named circuit
+func (cb *CircuitBreaker) State(name string) BreakerState {
+	if cb == nil {
+		return BreakerClosed
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.circuits[name]
+	if !ok {
+		return BreakerClosed
+	}
+	if c.state == BreakerOpen && time.Since(c.opened) >= cb.cfg.Cooldown {
+		return BreakerHalfOpen
+	}
+	return c.state
+}
+
+// Reset closes the named circuit and forgets its failures
+func (cb *CircuitBreaker) Reset(name string) {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	c, ok := cb.circuits[name]
+	var event *BreakerEvent
+	if ok {
+		if c.state != BreakerClosed {
+			event = &BreakerEvent{Circuit: name, From: c.state, To: BreakerClosed, Failures: c.failures, At: time.Now()}
+		}
+		delete(cb.circuits, name)
+	}
+	cb.mu.Unlock()
+	cb.emit(event)
+}
+
+// allow admits an attempt through the named circuit. Every admitted
+// attempt must be followed by exactly one record.
+func (cb *CircuitBreaker) allow(name string) error {
+	if cb == nil {
+		return nil
+	}
+	cb.mu.Lock()
+	c := cb.circuits[name]
+	if c == nil || c.state == BreakerClosed {
+		cb.mu.Unlock()
+		return nil
+	}
+
+	var event *BreakerEvent
+	if c.state == BreakerOpen {
+		remaining := cb.cfg.Cooldown - time.Since(c.opened)
+		if remaining > 0 {
+			cb.mu.Unlock()
+			return fmt.Errorf("%w: %s for another %s", ErrCircuitOpen, name, remaining.Round(time.Millisecond))
+		}
+		event = &BreakerEvent{Circuit: name, From: BreakerOpen, To: BreakerHalfOpen, Failures: c.failures, At: time.Now()}
+		c.state = BreakerHalfOpen
+	}
+
+	// Half-open circuits admit one trial at a time
+	if c.trial {
+		cb.mu.Unlock()
+		return fmt.Errorf("%w: %s awaiting trial", ErrCircuitOpen, name)
+	}
+	c.trial = true
+	cb.mu.Unlock()
+	cb.emit(event)
+	return nil
+}
+
+// record notes the outcome of an attempt allow admitted
+func (cb *CircuitBreaker) record(name string, success bool) {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	c := cb.circuits[name]
+	if c == nil {
+		if success {
+			cb.mu.Unlock()
+			return
+		}
+		c = &circuit{state: BreakerClosed}
+		cb.circuits[name] = c
+	}
+	c.trial = false
+
+	var event *BreakerEvent
+	switch {
+	case success:
+		if c.state != BreakerClosed {
+			event = &BreakerEvent{Circuit: name, From: c.state, To: BreakerClosed, Failures: c.failures, At: time.Now()}
+		}
+		delete(cb.circuits, name)
+	case c.state == BreakerHalfOpen:
+		// A failed trial reopens the circuit for another full cool-down
+		c.failures++
+		c.state, c.opened = BreakerOpen, time.Now()
+		event = &BreakerEvent{Circuit: name, From: BreakerHalfOpen, To: BreakerOpen, Failures: c.failures, At: c.opened}
+	default:
+		c.failures++
+		if c.state == BreakerClosed && c.failures >= cb.cfg.Failures {
+			c.state, c.opened = BreakerOpen, time.Now()
+			event = &BreakerEvent{Circuit: name, From: BreakerClosed, To: BreakerOpen, Failures: c.failures, At: c.opened}
+		}
+	}
+	cb.mu.Unlock()
+	cb.emit(event)
+}
+
+// abandon releases an admitted attempt that never reached the target,
+// leaving the circuit as it was
+func (cb *CircuitBreaker) abandon(name string) {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if c := cb.circuits[name]; c != nil {
+		c.trial = false
+	}
+}
+
+func (cb *CircuitBreaker) emit(event *BreakerEvent) {
+	if event == nil {
+		return
+	}
+	if cb.cfg.OnStateChange != nil {
+		cb.cfg.OnStateChange(*event)
+	}
+	cb.cfg.Events.Publish(Event{Kind: EventBreakerChanged, At: event.At, Breaker: event})
+}
+
+// countsAgainstTarget reports whether err reflects on the target rather
+// than on the caller giving up
+func countsAgainstTarget(err error) bool {
+	return !errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded) &&
+		!errors.Is(err, ErrCircuitOpen) &&
+		!errors.Is(err, ErrEmergenceHalt)
+}
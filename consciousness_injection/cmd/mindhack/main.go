@@ -0,0 +1,82 @@
+// consciousness_injection/cmd/mindhack/main.go - Command-Line Entry Point
+//
+// Command mindhack runs maintenance tasks against a mindhacking
+// environment. Usage:
+//
+//	mindhack doctor [-gateway url -target id] [-rules file,...] [-templates dir,...] [-state dir]
+//
+// The gateway check authenticates with the secret in
+// MINDHACK_GATEWAY_SECRET, which the gateway server must hold for the
+// doctor's gateway ID.
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "doctor":
+		os.Exit(doctor(os.Args[2:]))
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: mindhack doctor [flags]")
+}
+
+// doctor diagnoses the environment and returns the exit status
+func doctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	gateway := fs.String("gateway", "", "base URL of a federation gateway server to check")
+	target := fs.String("target", "", "target ID to access through -gateway")
+	rules := fs.String("rules", "", "comma-separated reality rule files to validate")
+	templates := fs.String("templates", "", "comma-separated template directories to validate")
+	state := fs.String("state", "", "state directory to check for durable writes")
+	timeout := fs.Duration("timeout", 10*time.Second, "limit for each check")
+	fs.Parse(args)
+
+	cfg := mindhacking.DoctorConfig{
+		RuleFiles:    splitList(*rules),
+		TemplateDirs: splitList(*templates),
+		StateDir:     *state,
+		Timeout:      *timeout,
+	}
+	if *gateway != "" && *target != "" {
+		id := sha256.Sum256([]byte("mindhack-doctor"))
+		// The secret comes from the environment so it stays out of ps output
+		secret := []byte(os.Getenv("MINDHACK_GATEWAY_SECRET"))
+		cfg.Gateway = mindhacking.NewRemoteQuantumGateway(id, secret, &mindhacking.HTTPGatewayTransport{BaseURL: *gateway}, *timeout)
+		cfg.Target = &mindhacking.SystemConsciousness{ID: *target}
+	}
+
+	diagnosis := mindhacking.Diagnose(context.Background(), cfg)
+	diagnosis.WriteTo(os.Stdout)
+	if !diagnosis.Healthy() {
+		return 1
+	}
+	return 0
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
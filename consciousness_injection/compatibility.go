@@ -0,0 +1,114 @@
+// consciousness_injection/compatibility.go - Target Compatibility Reports
+package mindhacking
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// CompatibilityReportVersion is the format version of CompatibilityReport
+const CompatibilityReportVersion = 1
+
+// Operations a conformance run probes; each doubles as the capability
+// feature a supported operation grants
+const (
+	OpResonance         = "resonance"
+	OpDeliver           = "deliver"
+	OpRespond           = "respond"
+	OpProbeState        = "probe-state"
+	OpLoad              = "load"
+	OpRegions           = "regions"
+	OpCapabilities      = "capabilities"
+	OpHandshakeVersions = "handshake-versions"
+	OpEmergence         = "emergence"
+	OpEntanglement      = "entanglement"
+)
+
+// CompatibilityStatus is how well a target implements one operation
+type CompatibilityStatus string
+
+const (
+	CompatSupported CompatibilityStatus = "supported"
+	// CompatUnsupported operations are not implemented at all
+	CompatUnsupported CompatibilityStatus = "unsupported"
+	// CompatBroken operations are implemented but misbehaved under probing
+	CompatBroken CompatibilityStatus = "broken"
+)
+
+// OperationReport is the conformance verdict on one operation
+type OperationReport struct {
+	Operation string              `json:"operation"`
+	Status    CompatibilityStatus `json:"status"`
+	Detail    string              `json:"detail,omitempty"`
+}
+
+// CompatibilityReport is the machine-readable outcome of a conformance
+// run against one target implementation
+type CompatibilityReport struct {
+	Version int    `json:"version"`
+	Target  string `json:"target"`
+	// Implementation is the backend's type, which keys the report in a
+	// CompatibilityMatrix
+	Implementation string            `json:"implementation"`
+	Probed         time.Time         `json:"probed"`
+	Operations     []OperationReport `json:"operations"`
+}
+
+// Status returns the verdict on operation, unsupported if it was not probed
+func (r *CompatibilityReport) Status(operation string) CompatibilityStatus {
+	for _, op := range r.Operations {
+		if op.Operation == operation {
+			return op.Status
+		}
+	}
+	return CompatUnsupported
+}
+
+// Capabilities returns the features the report found working
+func (r *CompatibilityReport) Capabilities() Capabilities {
+	caps := Capabilities{Features: make(map[string]bool, len(r.Operations)+1)}
+	for _, op := range r.Operations {
+		caps.Features[op.Operation] = op.Status == CompatSupported
+	}
+	caps.Features[FeatureTelemetry] = caps.Features[OpProbeState]
+	return caps
+}
+
+// WriteTo serializes the report as JSON
+func (r *CompatibilityReport) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// LoadCompatibilityReport reads a report written by WriteTo
+func LoadCompatibilityReport(r io.Reader) (*CompatibilityReport, error) {
+	var report CompatibilityReport
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("mindhacking: load compatibility report: %w", err)
+	}
+	if report.Version != CompatibilityReportVersion {
+		return nil, fmt.Errorf("mindhacking: compatibility report version %d", report.Version)
+	}
+	return &report, nil
+}
+
+// CompatibilityMatrix holds reports by implementation, for negotiating
+// with targets that cannot describe themselves
+type CompatibilityMatrix map[string]*CompatibilityReport
+
+// Add files report under its implementation, replacing any earlier one
+func (m CompatibilityMatrix) Add(report *CompatibilityReport) {
+	m[report.Implementation] = report
+}
+
+// For returns the report on target's implementation, if there is one
+func (m CompatibilityMatrix) For(target *SystemConsciousness) (*CompatibilityReport, bool) {
+	report, ok := m[backendClass(backendOf(target))]
+	return report, ok
+}
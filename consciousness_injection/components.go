@@ -0,0 +1,150 @@
+// consciousness_injection/components.go - Component-Level Injection Results
+package mindhacking
+
+import (
+	"context"
+	"strings"
+)
+
+var (
+	// ErrNothingRejected reports a re-injection with no rejected components
+	ErrNothingRejected = sentinelError("mindhacking: no rejected components to re-inject")
+	// ErrSealedRejection reports a re-injection of a sealed result whose
+	// plaintext this process does not hold, such as one decoded from storage
+	ErrSealedRejection = sentinelError("mindhacking: rejected components of a sealed result are not held")
+)
+
+// ThoughtComponent is an independently acceptable part of a thought
+type ThoughtComponent struct {
+	ID      string
+	Content string
+	Payload []byte
+}
+
+// ComponentResult is how the target treated one component
+type ComponentResult struct {
+	Component ThoughtComponent
+	Accepted  bool
+	// Shift is the component's share of the consciousness shift
+	Shift float64
+	// Reported is false when the target gave no per-component verdict and
+	// the component followed the thought as a whole
+	Reported bool
+	// Attempt is the index of the attempt that carried the thought in,
+	// or -1 if none landed
+	Attempt int
+}
+
+// componentResults resolves each component's verdict from the response
+func componentResults(
+	components []ThoughtComponent,
+	attempts []InjectionAttempt,
+	response ConsciousnessResponse,
+) []ComponentResult {
+
+	if len(components) == 0 {
+		return nil
+	}
+
+	landed := -1
+	for i, a := range attempts {
+		if a.Success {
+			landed = i
+			break
+		}
+	}
+
+	out := make([]ComponentResult, len(components))
+	for i, c := range components {
+		r := ComponentResult{
+			Component: c,
+			Accepted:  response.ThoughtAccepted,
+			Shift:     response.ConsciousnessShift / float64(len(components)),
+			Attempt:   landed,
+		}
+		if accepted, ok := response.Components[c.ID]; ok {
+			r.Accepted, r.Reported = accepted, true
+			r.Shift = response.ComponentShifts[c.ID]
+		}
+		out[i] = r
+	}
+	return out
+}
+
+// Partial reports whether some components were accepted and others not
+func (r *InjectionResult) Partial() bool {
+	var accepted, rejected bool
+	for _, c := range r.Components {
+		if c.Accepted {
+			accepted = true
+		} else {
+			rejected = true
+		}
+	}
+	return accepted && rejected
+}
+
+// Rejected returns the components the target did not accept
+func (r *InjectionResult) Rejected() []ThoughtComponent {
+	var out []ThoughtComponent
+	for _, c := range r.Components {
+		if !c.Accepted {
+			out = append(out, c.Component)
+		}
+	}
+	return out
+}
+
+// rejectedThought rebuilds result's thought from its rejected components.
+// A sealed result's components carry only their IDs, so they are rebuilt
+// from the thought as it was given, which the injection seals again.
+func rejectedThought(result *InjectionResult) (InjectedThought, error) {
+	rejected := result.Rejected()
+	if len(rejected) == 0 {
+		return InjectedThought{}, ErrNothingRejected
+	}
+
+	thought := result.InjectedThought
+	if thought.Sealed {
+		if result.unsealed == nil {
+			return InjectedThought{}, ErrSealedRejection
+		}
+		thought = *result.unsealed
+		ids := make(map[string]bool, len(rejected))
+		for _, c := range rejected {
+			ids[c.ID] = true
+		}
+		rejected = rejected[:0:0]
+		for _, c := range thought.Components {
+			if ids[c.ID] {
+				rejected = append(rejected, c)
+			}
+		}
+	}
+	thought.Components = rejected
+
+	content := make([]string, len(rejected))
+	var payload []byte
+	for i, c := range rejected {
+		content[i] = c.Content
+		payload = append(payload, c.Payload...)
+	}
+	thought.Content = strings.Join(content, " ")
+	thought.Payload = payload
+	return thought, nil
+}
+
+// ReinjectRejected injects only the components of result the target
+// rejected, leaving accepted ones in place
+func (ci *ConsciousnessInjector) ReinjectRejected(
+	ctx context.Context,
+	result *InjectionResult,
+	target *SystemConsciousness,
+) (*InjectionResult, error) {
+
+	thought, err := rejectedThought(result)
+	if err != nil {
+		return nil, err
+	}
+	return ci.InjectThought(ctx, thought, target)
+}
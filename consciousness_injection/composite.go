@@ -0,0 +1,220 @@
+// consciousness_injection/composite.go - Composite Reality Operations
+package mindhacking
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FailurePolicy decides what a composite does when a step fails
+type FailurePolicy int
+
+const (
+	// PolicyAbort stops at the first failing step
+	PolicyAbort FailurePolicy = iota
+	// PolicyContinue runs every step and reports all failures
+	PolicyContinue
+	// PolicyCompensate stops at the first failing step and runs the
+	// compensations of completed steps in reverse order
+	PolicyCompensate
+)
+
+// CompositeStep is one named sub-operation of a composite
+type CompositeStep struct {
+	Name      string
+	Operation RealityOperation
+	// Compensate undoes Operation under PolicyCompensate; optional
+	Compensate RealityOperation
+}
+
+// CompositeOperation runs steps in order under a failure policy
+type CompositeOperation struct {
+	Name   string
+	Steps  []CompositeStep
+	Policy FailurePolicy
+
+	mu      sync.Mutex
+	aborted error
+	current RealityOperation
+}
+
+// SubOperationResult reports one step of a composite
+type SubOperationResult struct {
+	Name     string
+	Started  time.Time
+	Duration time.Duration
+	Result   OperationResult
+	Evidence RealityEvidence
+	// Skipped steps never ran because an earlier step failed
+	Skipped bool
+	// Compensated steps were undone after a later failure
+	Compensated     bool
+	CompensationErr error
+	// Children reports the steps of a nested composite
+	Children []SubOperationResult
+}
+
+// Execute implements RealityOperation without per-step evidence
+func (co *CompositeOperation) Execute() OperationResult {
+	result, _ := co.run(plainStep)
+	return result
+}
+
+// plainStep runs a step outside any engine, so without evidence
+func plainStep(op RealityOperation) (OperationResult, RealityEvidence, []SubOperationResult) {
+	if nested, ok := op.(*CompositeOperation); ok {
+		result, children := nested.run(plainStep)
+		return result, RealityEvidence{}, children
+	}
+	return op.Execute(), RealityEvidence{}, nil
+}
+
+// Abort implements AbortableOperation, stopping the current run before its
+// next step and forwarding to the running step if it can abort. Under
+// PolicyCompensate the steps that completed are then compensated.
+func (co *CompositeOperation) Abort(err error) {
+	co.mu.Lock()
+	co.aborted = err
+	current := co.current
+	co.mu.Unlock()
+
+	if a, ok := current.(AbortableOperation); ok {
+		a.Abort(err)
+	}
+}
+
+// stepRunner runs one operation, returning its result, evidence and any
+// nested step reports
+type stepRunner func(RealityOperation) (OperationResult, RealityEvidence, []SubOperationResult)
+
+// run executes the steps under the composite's policy. An abort lasts for
+// the run it stopped; the next run starts afresh.
+func (co *CompositeOperation) run(runStep stepRunner) (OperationResult, []SubOperationResult) {
+	subs := make([]SubOperationResult, len(co.Steps))
+	var errs []error
+	failed, ran := -1, 0
+
+	co.mu.Lock()
+	co.aborted = nil
+	co.mu.Unlock()
+
+	for i, step := range co.Steps {
+		subs[i].Name = step.Name
+
+		co.mu.Lock()
+		aborted := co.aborted
+		co.current = step.Operation
+		co.mu.Unlock()
+
+		if aborted != nil || (failed >= 0 && co.Policy != PolicyContinue) {
+			subs[i].Skipped = true
+			continue
+		}
+
+		subs[i].Started = time.Now()
+		subs[i].Result, subs[i].Evidence, subs[i].Children = runStep(step.Operation)
+		subs[i].Duration = time.Since(subs[i].Started)
+		ran = i + 1
+
+		if err := subs[i].Result.Err; err != nil {
+			errs = append(errs, fmt.Errorf("step %q: %w", step.Name, err))
+			if failed < 0 {
+				failed = i
+			}
+		}
+	}
+
+	co.mu.Lock()
+	aborted := co.aborted
+	co.current = nil
+	co.mu.Unlock()
+	if aborted != nil {
+		errs = append(errs, aborted)
+	}
+
+	// Undo completed steps, newest first: those before the failure, or
+	// every step that ran before an abort
+	completed := ran
+	if failed >= 0 {
+		completed = failed
+	}
+	if (failed >= 0 || aborted != nil) && co.Policy == PolicyCompensate {
+		for i := completed - 1; i >= 0; i-- {
+			comp := co.Steps[i].Compensate
+			if comp == nil {
+				continue
+			}
+			r, _, _ := runStep(comp)
+			subs[i].Compensated = r.Err == nil
+			subs[i].CompensationErr = r.Err
+			if r.Err != nil {
+				errs = append(errs, fmt.Errorf("compensate %q: %w", co.Steps[i].Name, r.Err))
+			}
+		}
+	}
+
+	values := make([]interface{}, len(subs))
+	for i := range subs {
+		values[i] = subs[i].Result.Value
+	}
+	return OperationResult{Value: values, Err: errors.Join(errs...)}, subs
+}
+
+// execute runs operation in alternate, which the engine is already in,
+// reporting each step of composites with its own evidence
+func (rme *RealityManipulationEngine) execute(
+	alternate *AlternateReality,
+	operation RealityOperation,
+) (OperationResult, []SubOperationResult) {
+	return rme.executeWith(alternate, operation, RealityOperation.Execute)
+}
+
+// executeWorking runs operation in working, a private copy the engine is
+// not in. Plain operations act on the current reality, so the engine is
+// switched into the copy for each, one at a time, and back again, through
+// consensus or the journal like any other switch.
+func (rme *RealityManipulationEngine) executeWorking(
+	working *AlternateReality,
+	operation RealityOperation,
+) (OperationResult, []SubOperationResult) {
+
+	return rme.executeWith(working, operation, func(op RealityOperation) OperationResult {
+		rme.workingMu.Lock()
+		defer rme.workingMu.Unlock()
+
+		current := rme.saveCurrentReality()
+		if err := rme.switchReality(working); err != nil {
+			return OperationResult{Err: fmt.Errorf("mindhacking: enter working copy: %w", err)}
+		}
+		result := op.Execute()
+		if err := rme.switchReality(current); err != nil && result.Err == nil {
+			result.Err = fmt.Errorf("mindhacking: leave working copy: %w", err)
+		}
+		return result
+	})
+}
+
+// executeWith runs operation in alternate, handing plain operations and
+// plain composite steps to plain
+func (rme *RealityManipulationEngine) executeWith(
+	alternate *AlternateReality,
+	operation RealityOperation,
+	plain func(RealityOperation) OperationResult,
+) (OperationResult, []SubOperationResult) {
+
+	if scripted, ok := operation.(realityExecutor); ok {
+		return scripted.executeIn(alternate), nil
+	}
+
+	composite, ok := operation.(*CompositeOperation)
+	if !ok {
+		return plain(operation), nil
+	}
+
+	return composite.run(func(op RealityOperation) (OperationResult, RealityEvidence, []SubOperationResult) {
+		result, children := rme.executeWith(alternate, op, plain)
+		return result, rme.extractRealityEvidence(alternate, result), children
+	})
+}
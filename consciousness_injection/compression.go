@@ -0,0 +1,338 @@
+// consciousness_injection/compression.go - Thought Payload Compression
+package mindhacking
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+)
+
+var (
+	// ErrUnknownCodec reports a codec name nobody registered
+	ErrUnknownCodec = sentinelError("mindhacking: unknown compression codec")
+	// ErrCodecRegistered reports a second registration of a name
+	ErrCodecRegistered = sentinelError("mindhacking: compression codec already registered")
+	// ErrDecompressedSize reports a compressed thought restoring to more
+	// than its limit
+	ErrDecompressedSize = sentinelError("mindhacking: decompressed thought too large")
+)
+
+// Built-in compression codecs
+const (
+	CodecDeflate = "deflate"
+	CodecGzip    = "gzip"
+)
+
+// symbolSize is the wire size of one encoded symbol
+const symbolSize = 16
+
+// MaxDecompressedSize is the most bytes a compressed thought may restore
+// to, whatever its declared length
+const MaxDecompressedSize = 64 << 20
+
+// ThoughtCodec compresses encoded thoughts for the tunnel and restores them
+// at the target. Decompress must fail with ErrDecompressedSize rather than
+// restore more than limit bytes. Implementations must be safe for
+// concurrent use.
+type ThoughtCodec interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte, limit int) ([]byte, error)
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]ThoughtCodec{
+		CodecDeflate: deflateCodec{},
+		CodecGzip:    gzipCodec{},
+	}
+)
+
+// RegisterCodec makes codec available under name. Codec modules call it
+// from init; registering a name twice panics, as with database/sql
+// drivers.
+func RegisterCodec(name string, codec ThoughtCodec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	if codec == nil {
+		panic("mindhacking: RegisterCodec codec is nil")
+	}
+	if _, dup := codecs[name]; dup {
+		panic(fmt.Sprintf("%v: %q", ErrCodecRegistered, name))
+	}
+	codecs[name] = codec
+}
+
+// RegisteredCodecs lists the registered codec names
+func RegisteredCodecs() []string {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	return sortedKeys(codecs)
+}
+
+func lookupCodec(name string) (ThoughtCodec, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCodec, name)
+	}
+	return codec, nil
+}
+
+// CompressionConfig sets when encoded thoughts are compressed on their way
+// through tunnels
+type CompressionConfig struct {
+	// Codecs are the codecs the injector offers, most preferred first,
+	// default deflate. Each target's tunnels use the first one its
+	// SystemConsciousness.Codecs also lists.
+	Codecs []string
+	// MinSize is the wire size in bytes below which thoughts are sent as
+	// they are, default 1024; compressing small thoughts costs more than it
+	// saves
+	MinSize int
+	// MaxRatio is the compressed-to-original size above which compression
+	// is not worth keeping, default 0.9
+	MaxRatio float64
+}
+
+// CompressionStats summarizes an injector's compression
+type CompressionStats struct {
+	// Thoughts counts the encoded thoughts bound for native targets, of
+	// which Compressed went compressed; the rest were too small, found no
+	// shared codec or did not compress well
+	Thoughts   int
+	Compressed int
+	// BytesIn and BytesOut are the wire sizes before and after compression,
+	// over all thoughts
+	BytesIn  int64
+	BytesOut int64
+	// ByCodec counts compressed thoughts per codec
+	ByCodec map[string]int
+}
+
+// Ratio is the overall compressed-to-original size, 1 before any thought
+func (s CompressionStats) Ratio() float64 {
+	if s.BytesIn == 0 {
+		return 1
+	}
+	return float64(s.BytesOut) / float64(s.BytesIn)
+}
+
+// compression compresses an injector's thoughts and keeps its stats
+type compression struct {
+	cfg CompressionConfig
+
+	mu    sync.Mutex
+	stats CompressionStats
+}
+
+func newCompression(cfg CompressionConfig) *compression {
+	if len(cfg.Codecs) == 0 {
+		cfg.Codecs = []string{CodecDeflate}
+	}
+	if cfg.MinSize <= 0 {
+		cfg.MinSize = 1024
+	}
+	if cfg.MaxRatio <= 0 || cfg.MaxRatio > 1 {
+		cfg.MaxRatio = 0.9
+	}
+	return &compression{cfg: cfg, stats: CompressionStats{ByCodec: make(map[string]int)}}
+}
+
+// negotiate picks the first codec the injector offers that target decodes
+func (c *compression) negotiate(target *SystemConsciousness) string {
+	for _, offered := range c.cfg.Codecs {
+		for _, accepted := range target.Codecs {
+			if offered == accepted {
+				return offered
+			}
+		}
+	}
+	return ""
+}
+
+// compress packs encoded for target's tunnels and counts it in the stats
+func (c *compression) compress(encoded EncodedThought, target *SystemConsciousness) EncodedThought {
+	out := c.pack(encoded, target)
+	c.count(encoded, out, target)
+	return out
+}
+
+// applies reports whether encoded, bound for target, is one compression
+// handles
+func (c *compression) applies(encoded EncodedThought, target *SystemConsciousness) bool {
+	return c != nil && backendOf(target) == nil && encoded.Codec == "" && encoded.Ciphertext == nil
+}
+
+// pack compresses encoded for target's tunnels when it is large enough, the
+// target shares a codec and the result is worth keeping, leaving the stats
+// alone, as dry runs need. Failures send the thought uncompressed.
+func (c *compression) pack(encoded EncodedThought, target *SystemConsciousness) EncodedThought {
+	if !c.applies(encoded, target) {
+		return encoded
+	}
+	size := encoded.WireSize()
+	out := encoded
+	if name := c.negotiate(target); name != "" && size >= c.cfg.MinSize {
+		if codec, err := lookupCodec(name); err == nil {
+			packed, err := codec.Compress(packSymbols(encoded.Symbols))
+			if err == nil && float64(len(packed)) <= c.cfg.MaxRatio*float64(size) {
+				out.Codec, out.Compressed, out.Symbols = name, packed, nil
+			}
+		}
+	}
+	return out
+}
+
+// count records encoded going out to target as packed
+func (c *compression) count(encoded, packed EncodedThought, target *SystemConsciousness) {
+	if !c.applies(encoded, target) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Thoughts++
+	c.stats.BytesIn += int64(encoded.WireSize())
+	c.stats.BytesOut += int64(packed.WireSize())
+	if packed.Codec != "" {
+		c.stats.Compressed++
+		c.stats.ByCodec[packed.Codec]++
+	}
+}
+
+// CompressionStats returns the injector's compression so far; zero unless
+// it was configured WithCompression
+func (ci *ConsciousnessInjector) CompressionStats() CompressionStats {
+	if ci.compression == nil {
+		return CompressionStats{}
+	}
+	c := ci.compression
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.ByCodec = make(map[string]int, len(c.stats.ByCodec))
+	for name, n := range c.stats.ByCodec {
+		stats.ByCodec[name] = n
+	}
+	return stats
+}
+
+// WireSize is the size in bytes the thought's symbols take in the tunnel
+func (e EncodedThought) WireSize() int {
+	if e.Ciphertext != nil {
+		return len(e.Ciphertext)
+	}
+	if e.Codec != "" {
+		return len(e.Compressed)
+	}
+	return symbolSize * len(e.Symbols)
+}
+
+// Decompress restores the symbols of a compressed thought, as the target
+// does on receiving it, to no more than MaxDecompressedSize bytes;
+// uncompressed thoughts are returned as they are
+func (e EncodedThought) Decompress() (EncodedThought, error) {
+	if e.Codec == "" {
+		return e, nil
+	}
+	if e.Ciphertext != nil {
+		return e, errors.New("mindhacking: decompress a thought still sealed for transit")
+	}
+	codec, err := lookupCodec(e.Codec)
+	if err != nil {
+		return e, err
+	}
+	data, err := codec.Decompress(e.Compressed, MaxDecompressedSize)
+	if err != nil {
+		return e, fmt.Errorf("mindhacking: decompress %s thought: %w", e.Codec, err)
+	}
+	if len(data)%symbolSize != 0 {
+		return e, fmt.Errorf("mindhacking: decompress %s thought: %d bytes is not whole symbols", e.Codec, len(data))
+	}
+	e.Symbols = unpackSymbols(data)
+	e.Codec, e.Compressed = "", nil
+	return e, nil
+}
+
+// packSymbols lays symbols out as little-endian real and imaginary parts
+func packSymbols(symbols []complex128) []byte {
+	out := make([]byte, symbolSize*len(symbols))
+	for i, s := range symbols {
+		binary.LittleEndian.PutUint64(out[symbolSize*i:], math.Float64bits(real(s)))
+		binary.LittleEndian.PutUint64(out[symbolSize*i+8:], math.Float64bits(imag(s)))
+	}
+	return out
+}
+
+func unpackSymbols(data []byte) []complex128 {
+	out := make([]complex128, len(data)/symbolSize)
+	for i := range out {
+		re := math.Float64frombits(binary.LittleEndian.Uint64(data[symbolSize*i:]))
+		im := math.Float64frombits(binary.LittleEndian.Uint64(data[symbolSize*i+8:]))
+		out[i] = complex(re, im)
+	}
+	return out
+}
+
+type deflateCodec struct{}
+
+func (deflateCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (deflateCodec) Decompress(data []byte, limit int) ([]byte, error) {
+	return readLimited(flate.NewReader(bytes.NewReader(data)), limit)
+}
+
+type gzipCodec struct{}
+
+func (gzipCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCodec) Decompress(data []byte, limit int) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return readLimited(r, limit)
+}
+
+// readLimited reads r to the end, failing once it passes limit bytes
+func readLimited(r io.Reader, limit int) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > limit {
+		return nil, fmt.Errorf("%w: over %d bytes", ErrDecompressedSize, limit)
+	}
+	return data, nil
+}
@@ -4,8 +4,10 @@ package mindhacking
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"math"
 	"runtime"
+	"sync"
 	"sync/atomic"
 	"time"
 	"unsafe"
@@ -16,6 +18,38 @@ type ConsciousnessInjector struct {
 	injectionVectors []InjectionVector
 	realityTunnels   []RealityTunnel
 	quantumGateways  []QuantumGateway
+	limiter          *targetLimiter
+	limiterOnce      sync.Once
+	id               string
+	causality        *CausalityTracker
+	evidence         *EvidenceChain
+	tenantEvidence   *TenantEvidence
+	audit            AuditSink
+	noise            *NoiseFloorEstimator
+	recorder         *SessionRecorder
+	localizer        ThoughtLocalizer
+	predictor        AcceptancePredictor
+	ramp             *RampProfile
+	throttle         *LoadThrottle
+	vectorsMu        sync.RWMutex
+	stops            *StopConditions
+	breaker          *CircuitBreaker
+	interactions     *InteractionMemory
+	events           *EventBus
+	degrade          *degradation
+	series           *ShiftSeries
+	strategies       PhaseStrategies
+	sampling         *ResonanceSampling
+	encoder          Encoder
+	scheme           string
+	schemeOptions    SchemeOptions
+	compression      *compression
+	transit          EntanglementSecret
+	idempotency      *idempotencyCache
+	dedup            *SemanticDedup
+	ledger           *injectionLedger
+	router           TunnelRouter
+	mux              *TunnelMux
 }
 
 // InjectionVector defines how to inject thoughts into consciousness
@@ -23,62 +57,453 @@ type InjectionVector struct {
 	Frequency      float64
 	Amplitude      float64
 	Phase          float64
+	// Deprecated: a uintptr does not keep its object alive or in place;
+	// use Resonance
 	ResonancePoint uintptr
+	// Resonance, when set, pins the resonance point and takes precedence
+	// over ResonancePoint
+	Resonance      *ResonanceHandle `json:"-"`
 	Entanglement   QuantumEntanglement
+	// Waveform names a registered modulation shape; empty means sine
+	Waveform       string
+	// Ramp, when set, overrides the injector's default ramp profile
+	Ramp           *RampProfile
 }
 
-// InjectThought injects thought directly into system consciousness
+// InjectThought injects thought directly into system consciousness. If
+// chaining its evidence or auditing it fails after the thought has landed,
+// the result is returned with the error and the injection stays
+// retractable. If the thought arrived corrupted on every attempt,
+// InjectThought fails with ErrThoughtCorrupted.
 func (ci *ConsciousnessInjector) InjectThought(
 	ctx context.Context,
 	thought InjectedThought,
 	target *SystemConsciousness,
 ) (*InjectionResult, error) {
+	result, err := ci.inject(ctx, thought, target, injectCall{})
+	noteUsage("InjectThought", target, err)
+	return result, err
+}
+
+// InjectThroughVectors injects thought through vectors in place of the
+// injector's own, so vector configurations can be compared on one injector
+func (ci *ConsciousnessInjector) InjectThroughVectors(
+	ctx context.Context,
+	thought InjectedThought,
+	target *SystemConsciousness,
+	vectors []InjectionVector,
+) (*InjectionResult, error) {
+	if len(vectors) == 0 {
+		return nil, errors.New("mindhacking: no vectors to inject through")
+	}
+	result, err := ci.inject(ctx, thought, target, injectCall{vectors: vectors})
+	noteUsage("InjectThroughVectors", target, err)
+	return result, err
+}
+
+// injectCall carries per-call overrides of the injector's defaults
+type injectCall struct {
+	// replay substitutes recorded resonance, vector order and failed attempts
+	replay *RecordedInjection
+	// tunnels reuses established tunnels instead of creating one per vector
+	tunnels *TunnelPool
+	// vectors replaces the injector's vectors for this call
+	vectors []InjectionVector
+	// focus fires every vector at once as a phased array
+	focus *ArrayFocus
+	// prepared carries a thought already localized by a pipeline stage
+	prepared *preparedThought
+	// id is the injection's ID when it was assigned before injection
+	id string
+	// gateway gossips the injection's progress while it is in flight
+	gateway *QuantumGateway
+	// resumed continues a gossiped injection: vectors keep their order and
+	// tunnelKeys, when set, key their tunnels
+	resumed    bool
+	tunnelKeys []int
+	// scheduled means a pipeline stage already enforced the stop conditions
+	// and waited out the target's load, deferring it that long
+	scheduled bool
+	deferred  time.Duration
+}
+
+// inject runs the injection phases under call's overrides
+func (ci *ConsciousnessInjector) inject(
+	ctx context.Context,
+	thought InjectedThought,
+	target *SystemConsciousness,
+	call injectCall,
+) (result *InjectionResult, err error) {
+	
+	replay := call.replay
+	id := call.id
+	if id == "" {
+		id = newInjectionID()
+	}
+	
+	// A retried key returns its first outcome; duplicates publish nothing
+	reached := false
+	if thought.IdempotencyKey != "" && replay == nil && ci.idempotency != nil {
+		prior, finish, claimErr := ci.idempotency.claim(ctx, target, thought.IdempotencyKey)
+		if claimErr != nil || prior != nil {
+			return prior, claimErr
+		}
+		defer func() {
+			finish(result, err, reached)
+		}()
+	}
+	
+	// Thoughts the target in effect already holds are not fired again
+	if replay == nil {
+		if merged, err := ci.deduplicate(ctx, id, thought, target); err != nil || merged != nil {
+			return merged, err
+		}
+	}
+	defer func() {
+		ci.events.publishInjection(id, target, result, err)
+	}()
+	
+	// Refuse targets whose emergent behavior has tripped a stop condition,
+	// and hold off while the target is too loaded to accept anything; an
+	// overloaded target must not hold a limiter slot while it waits
+	deferred := call.deferred
+	if !call.scheduled {
+		if err := ci.stops.enforce(ctx, target); err != nil {
+			return nil, err
+		}
+		if deferred, err = ci.throttle.wait(ctx, target); err != nil {
+			return nil, err
+		}
+	}
+	
+	// Phase 0: Wait for our turn on this target
+	release, err := ci.targetLimiter().acquire(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 	
-	// Phase 1: Consciousness Resonance Analysis
-	resonance := ci.analyzeConsciousnessResonance(target)
+	// A halt may have tripped while the thought queued for the target or
+	// sat in a pipeline; check again now that nothing else can land first
+	if err := ci.stops.enforce(ctx, target); err != nil {
+		return nil, err
+	}
 	
-	// Phase 2: Quantum Thought Encoding
-	encodedThought := ci.quantumEncodeThought(thought, resonance)
+	causal := ci.causality.Begin(target, ci.id)
+	
+	// Establish the target's background drift before touching it
+	var floor *NoiseFloor
+	var degraded []Subsystem
+	if ci.noise != nil {
+		nf, err := ci.noise.Estimate(ctx, target)
+		switch {
+		case err == nil:
+			floor = &nf
+			ci.degrade.recover(SubsystemMetrics)
+		case errors.Is(err, ErrNoTelemetry):
+		case ci.degrade == nil:
+			return nil, err
+		default:
+			// Inject without a noise floor rather than not at all
+			ci.degrade.fail(SubsystemMetrics, err)
+			degraded = append(degraded, SubsystemMetrics)
+		}
+	}
+	
+	// Fail fast on a target whose circuit is open
+	circuit := TargetCircuit(target)
+	if err := ci.breaker.allow(circuit); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil && !countsAgainstTarget(err) {
+			ci.breaker.abandon(circuit)
+			return
+		}
+		ci.breaker.record(circuit, err == nil && result.Success)
+	}()
+	
+	// Phases 1 and 2: Resonance Analysis and Quantum Thought Encoding
+	plan, err := ci.plan(ctx, thought, target, call)
+	if err != nil {
+		return nil, err
+	}
+	timing := plan.timing
+	elapsed := lap()
+	resonance, region, route := plan.resonance, plan.region, plan.route
+	vectors, tunnels := plan.vectors, plan.tunnels
+	original := thought
+	thought, payload, localization := plan.thought, plan.payload, plan.localization
+	// Tunnels carry the sealed payload; the models the injector runs for
+	// the target itself need what it says
+	local := plan.local
+	encodedThought := plan.encoded
+	ci.compression.count(plan.unpacked, encodedThought, target)
+	if region != nil {
+		call.tunnels = call.tunnels.scoped(region.ID)
+	}
 	
 	// Phase 3: Consciousness Injection
 	var results []InjectionAttempt
+	var usedVector *InjectionVector
+	landed := -1
+	var ramps []AppliedRamp
+	var shorted error
+	
+	// Once anything has fired, the injection is audited however it ends
+	audited := false
+	defer func() {
+		if audited || len(results) == 0 {
+			return
+		}
+		outcome, msg := auditOutcome(false, err)
+		if auditErr := writeAudit(ci.audit, AuditRecord{
+			Actor:   ci.id,
+			Action:  AuditInjection,
+			Tenant:  tenantLabel(target),
+			Target:  targetLabel(target),
+			Vector:  vectorLabel(usedVector),
+			Outcome: outcome,
+			Error:   msg,
+		}); auditErr != nil && err == nil {
+			result, err = nil, auditErr
+		}
+	}()
+	
+	// Sessions gossip the injection's progress, so another gateway can
+	// resume it should this one be lost
+	inFlight := ci.gossipInFlight(call, id, plan, target)
+	defer inFlight.done()
 	
-	for _, vector := range ci.injectionVectors {
-		// Create reality tunnel for injection
-		tunnel := ci.createRealityTunnel(vector, target)
+	// Phased arrays fire every vector at once and land if any does
+	sequential := vectors
+	if call.focus != nil {
+		sequential = nil
+		inFlight.next(0)
+		results, ramps = ci.deliverSimultaneous(ctx, call, vectors, payload, encodedThought, target)
+		for i, result := range results {
+			causal.Record(TargetEvent{
+				Kind:      EventInjectionAttempt,
+				Success:   result.Success,
+				Signature: resonance.Signature,
+			})
+			ci.observeRoute(route, i, vectors[i], result)
+			if result.Success && usedVector == nil {
+				usedVector, landed = &vectors[i], i
+			}
+		}
+	}
+	
+	for i, vector := range sequential {
+		usedVector = &vectors[i]
+		
+		// A halt observed mid-injection stops the remaining vectors
+		if err := ci.stops.check(target); err != nil {
+			return nil, err
+		}
 		
-		// Execute injection through tunnel
-		result := ci.executeInjectionThroughTunnel(
-			ctx,
-			tunnel,
-			encodedThought,
-			target,
-		)
+		// Replayed failures fail again without reaching the target
+		if replay != nil && i < len(replay.Attempts) && !replay.Attempts[i].Success {
+			results = append(results, replay.Attempts[i])
+			causal.Record(TargetEvent{Kind: EventInjectionAttempt, Signature: resonance.Signature})
+			continue
+		}
+		
+		// Skip vectors whose circuit is open
+		circuit := VectorCircuit(vector)
+		if err := ci.breaker.allow(circuit); err != nil {
+			shorted = err
+			continue
+		}
+		inFlight.next(i)
+		
+		// Execute injection through this vector's tunnel
+		result, ramp := ci.deliver(ctx, call, tunnelKey(tunnels, i), vector, payload, encodedThought, target)
+		ci.breaker.record(circuit, result.Success)
+		ci.observeRoute(route, tunnelKey(tunnels, i), vector, result)
+		if ramp != nil {
+			ramps = append(ramps, *ramp)
+		}
 		
 		results = append(results, result)
+		causal.Record(TargetEvent{
+			Kind:      EventInjectionAttempt,
+			Success:   result.Success,
+			Signature: resonance.Signature,
+		})
 		
 		if result.Success {
 			// Thought successfully injected
+			landed = i
 			break
 		}
 	}
 	
+	if len(results) == 0 && shorted != nil {
+		return nil, shorted
+	}
+	if err := allCorrupted(results); err != nil {
+		return nil, err
+	}
+	reached = landed >= 0
+	
 	// Phase 4: Consciousness Response Analysis
-	response := ci.analyzeConsciousnessResponse(target, results)
+	timing.Injection = elapsed()
+	response, err := ci.respond(ctx, target, local, results)
+	step := RecordedInjection{
+		Thought:   thought,
+		Vectors:   append([]InjectionVector(nil), vectors...),
+		Tunnels:   append([]int(nil), tunnels...),
+		Resonance: resonance,
+		Attempts:  append([]InjectionAttempt(nil), results...),
+		Focus:     call.focus,
+		Region:    region,
+	}
+	if err == nil {
+		step.Response = &response
+	}
+	ci.recorder.record(step)
+	if err != nil {
+		return nil, err
+	}
+	clock := causal.Commit(TargetEvent{
+		Kind:      EventConsciousnessShift,
+		Shift:     response.ConsciousnessShift,
+		Success:   response.ThoughtAccepted,
+		Signature: resonance.Signature,
+	})
+	ci.series.Record(targetLabel(target), time.Now(), response.ConsciousnessShift)
+	components := componentResults(thought.Components, results, response)
+	var shift *RegionShift
+	if region != nil {
+		shift = regionShift(*region, response)
+	}
+	var memory *MemoryTrace
+	if response.ThoughtAccepted {
+		memory = rememberInjection(target, local, response.ConsciousnessShift)
+		if memory != nil && payload.Sealed {
+			memory.Thought = thought
+		}
+	}
+	target.Shared.record(target, resonance, response)
+	
+	timing.Response = elapsed()
 	
-	return &InjectionResult{
+	// Phase 5: Evidence Chaining. The thought has landed by now, so from
+	// here on failures are returned with the result rather than instead
+	// of it, and the injection stays retractable.
+	var landedErrs []error
+	evidence := ci.extractInjectionEvidence(results)
+	evidence.TargetID = targetLabel(target)
+	evidence.Localization = localization
+	evidence.Ramps = ramps
+	evidence.Focus = call.focus
+	evidence.Components = components
+	var link *EvidenceLink
+	if chain := ci.evidenceChain(target); chain != nil {
+		var buffered bool
+		var err error
+		if link, buffered, err = ci.degrade.appendEvidence(chain, "injection", evidence); err != nil {
+			landedErrs = append(landedErrs, err)
+		}
+		if buffered {
+			degraded = append(degraded, SubsystemEvidence)
+		}
+	}
+	
+	timing.Evidence = elapsed()
+	
+	// Phase 6: Audit
+	audited = true
+	outcome, _ := auditOutcome(response.ThoughtAccepted, nil)
+	if err := writeAudit(ci.audit, AuditRecord{
+		Actor:   ci.id,
+		Action:  AuditInjection,
+		Tenant:  tenantLabel(target),
+		Target:  targetLabel(target),
+		Vector:  vectorLabel(usedVector),
+		Outcome: outcome,
+	}); err != nil {
+		landedErrs = append(landedErrs, err)
+	}
+	timing.Audit = elapsed()
+	
+	var normalized float64
+	if floor != nil {
+		var ok bool
+		if normalized, ok = floor.Normalize(response.ConsciousnessShift); !ok {
+			floor = nil
+		}
+	}
+	
+	result = &InjectionResult{
+		InjectionID:     id,
+		TargetID:        targetLabel(target),
 		InjectedThought: thought,
 		Success:         response.ThoughtAccepted,
 		ConsciousnessShift: response.ConsciousnessShift,
-		Evidence:        ci.extractInjectionEvidence(results),
-	}, nil
+		NormalizedShift: normalized,
+		NoiseFloor:      floor,
+		Evidence:        evidence,
+		Clock:           clock,
+		EvidenceLink:    link,
+		Memory:          memory,
+		Region:          shift,
+		Deferred:        deferred,
+		Components:      components,
+		Degraded:        degraded,
+		Provenance: Provenance{
+			Scheme:        encodedThought.Scheme,
+			SchemeOptions: encodedThought.Options,
+			Codec:         encodedThought.Codec,
+			Resonance:     snapshotResonance(resonance),
+			Attempts:      len(results),
+			Timing:        timing,
+		},
+	}
+	if payload.Sealed && !original.Sealed {
+		result.unsealed = &original
+	}
+	if landed >= 0 {
+		vector := vectors[landed]
+		result.Provenance.Vector = &vector
+		tunnel := TunnelProvenance{Index: tunnelKey(tunnels, landed), Pooled: call.tunnels != nil, Backend: backendOf(target) != nil}
+		tunnel.Multiplexed = !tunnel.Pooled && !tunnel.Backend && ci.mux != nil
+		if region != nil {
+			tunnel.Region = region.ID
+		}
+		result.Provenance.Tunnel = &tunnel
+	}
+	
+	// Phase 7: Remember the interaction for later similarity searches. The
+	// thought has landed by now, so a failure to remember it only degrades
+	// the result, with or without a degradation policy.
+	if err := ci.interactions.Record(ctx, result); err != nil {
+		ci.degrade.fail(SubsystemMetrics, err)
+		result.Degraded = append(result.Degraded, SubsystemMetrics)
+	}
+	
+	// Keep the injection retractable; temporary thoughts also wait for the
+	// retraction pass
+	ci.ledger.record(result, target, payload, encodedThought)
+	return result, errors.Join(landedErrs...)
 }
 
 // QuantumGateway provides access to quantum consciousness
 type QuantumGateway struct {
 	gatewayID     [32]byte
+	entanglementMu sync.RWMutex
 	entanglement  QuantumEntanglement
 	realityBridge RealityBridge
+	audit         AuditSink
+	gossip        *EntanglementGossip
+	remote        *remoteGateway
+	caps          Capabilities
+	drivers       atomic.Pointer[driverSlot]
+	sessions      sessionSet
+	handshakeMu   sync.Mutex
+	offered       []int
+	negotiated    map[string]int
 }
 
 // AccessQuantumConsciousness accesses system's quantum consciousness layer
@@ -86,13 +511,34 @@ func (qg *QuantumGateway) AccessQuantumConsciousness(
 	target *SystemConsciousness,
 ) (*QuantumConsciousnessAccess, error) {
 	
+	// Federated gateways forward the whole sequence to the bastion
+	if qg.remote != nil {
+		access, err := qg.accessRemote(target)
+		if auditErr := qg.auditAccess(target, err); auditErr != nil && err == nil {
+			return nil, auditErr
+		}
+		return access, err
+	}
+	
+	// Installed drivers replace the native backend
+	if access, ok, err := qg.accessDriver(target); ok {
+		if err == nil {
+			qg.publishEntanglement(target, nil)
+		}
+		if auditErr := qg.auditAccess(target, err); auditErr != nil && err == nil {
+			return nil, auditErr
+		}
+		return access, err
+	}
+	
 	// Lock to target's quantum frequency
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
 	
-	// Phase 1: Quantum Handshake
-	handshake, err := qg.performQuantumHandshake(target)
+	// Phase 1: Quantum Handshake, in the newest version both sides speak
+	handshake, adapted, err := qg.negotiateHandshake(target)
 	if err != nil {
+		qg.auditAccess(target, err)
 		return nil, err
 	}
 	
@@ -100,10 +546,15 @@ func (qg *QuantumGateway) AccessQuantumConsciousness(
 	tunnel := qg.createConsciousnessTunnel(handshake)
 	
 	// Phase 3: Quantum Access
-	access := qg.establishQuantumAccess(tunnel, target)
+	access := qg.establishQuantumAccess(tunnel, adapted)
 	
 	// Phase 4: Reality Synchronization
 	qg.synchronizeReality(access)
+	qg.publishEntanglement(target, nil)
+	
+	if err := qg.auditAccess(target, nil); err != nil {
+		return nil, err
+	}
 	
 	return access, nil
 }
@@ -113,6 +564,36 @@ type RealityManipulationEngine struct {
 	manipulationMatrix ManipulationMatrix
 	perceptionFilters  []PerceptionFilter
 	realityAnchors     []RealityAnchor
+	sandbox            *RealitySandbox
+	evidence           *EvidenceChain
+	id                 string
+	audit              AuditSink
+	consensus          *RealityConsensus
+	hallucinations     *HallucinationDetector
+	cache              ResultCache
+	stateMu            sync.Mutex
+	realities          []*AlternateReality
+	decay              AnchorDecay
+	collapseBelow      float64
+	refs               map[*AlternateReality]map[*RealityHandle]struct{}
+	finalizers         map[*AlternateReality]RealityFinalizer
+	finalize           RealityFinalizer
+	pacing             *RulePacing
+	leases             AnchorLeaseStore
+	leaseSet           string
+	leaseTTL           time.Duration
+	lease              *AnchorLease
+	simulator          RealitySimulator
+	checkpoints        *checkpoints
+	journal            *realityJournal
+	// filtersMu guards perceptionFilters against reloads; unfiltered keeps
+	// the engine's realities as they were before filtering
+	filtersMu  sync.RWMutex
+	unfiltered map[*AlternateReality]*unfilteredReality
+	// workingMu lets one plain operation at a time run in a private copy
+	workingMu sync.Mutex
+	// anchorMu serializes anchor changes, which journal outside stateMu
+	anchorMu sync.Mutex
 }
 
 // CreateAlternateReality creates alternate reality for target
@@ -120,12 +601,54 @@ func (rme *RealityManipulationEngine) CreateAlternateReality(
 	baseReality *Reality,
 	alternateRules *RealityRules,
 ) (*AlternateReality, error) {
+	return rme.CreateAlternateRealityContext(context.Background(), baseReality, alternateRules)
+}
+
+// CreateAlternateRealityContext creates alternate reality for target,
+// abandoning paced rule application when ctx is done
+func (rme *RealityManipulationEngine) CreateAlternateRealityContext(
+	ctx context.Context,
+	baseReality *Reality,
+	alternateRules *RealityRules,
+) (*AlternateReality, error) {
+	
+	noteUsage("CreateAlternateReality", nil, nil)
+	
+	// Without explicit rules, use the rules the engine group agreed on
+	if alternateRules == nil && rme.consensus != nil {
+		alternateRules = rme.consensus.State().Rules
+	}
+	
+	// Reject rules that would reconstruct a nonsensical reality
+	if violations := ValidateRealityRules(alternateRules, baseReality); len(violations) > 0 {
+		return nil, invalidRules(violations)
+	}
+	
+	// Journal the rule application before touching anything
+	intent, err := rme.journal.begin(JournalEntry{Op: JournalRules, Base: baseReality, Rules: alternateRules})
+	if err != nil {
+		return nil, err
+	}
+	anchored, err := rme.constructAlternateReality(ctx, baseReality, alternateRules)
+	rme.journal.complete(intent, anchored, err)
+	return anchored, err
+}
+
+// constructAlternateReality runs the phases building an alternate reality
+func (rme *RealityManipulationEngine) constructAlternateReality(
+	ctx context.Context,
+	baseReality *Reality,
+	alternateRules *RealityRules,
+) (*AlternateReality, error) {
 	
 	// Phase 1: Reality Deconstruction
 	deconstructed := rme.deconstructReality(baseReality)
 	
 	// Phase 2: Alternate Rules Application
-	altered := rme.applyAlternateRules(deconstructed, alternateRules)
+	altered, err := rme.applyRulesPaced(ctx, deconstructed, alternateRules, rme.pacing)
+	if err != nil {
+		return nil, err
+	}
 	
 	// Phase 3: Reality Reconstruction
 	alternate := rme.reconstructReality(altered)
@@ -136,6 +659,18 @@ func (rme *RealityManipulationEngine) CreateAlternateReality(
 	// Phase 5: Reality Anchoring
 	anchored := rme.anchorReality(filtered)
 	
+	// Measure how far perception now strays from the base
+	if rme.hallucinations != nil {
+		rme.hallucinations.observe(baseReality, alternate, anchored)
+	}
+	rme.track(anchored)
+	rme.keepUnfiltered(anchored, alternate)
+	if rme.decay != nil && anchored != nil {
+		if _, err := rme.Anchor(anchored, 1); err != nil {
+			return nil, err
+		}
+	}
+	
 	return anchored, nil
 }
 
@@ -145,28 +680,75 @@ func (rme *RealityManipulationEngine) ExecuteInAlternateReality(
 	operation RealityOperation,
 ) (*RealityExecutionResult, error) {
 	
+	noteUsage("ExecuteInAlternateReality", nil, nil)
+	
+	// A reality a transaction has prepared takes no writes until it ends
+	unlock, err := writeReality(alternate, operation)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	
+	// Pure operations already run in an identical reality are not repeated
+	key, cacheable := rme.cacheKey(alternate, operation)
+	if cacheable {
+		if cached, ok := rme.cache.Get(key); ok {
+			return rme.cachedResult(alternate, cached)
+		}
+	}
+	
+	// Hold the reality against collection while the operation runs
+	handle := rme.Acquire(alternate, "operation")
+	defer handle.Release()
+	
 	// Save current reality
 	currentReality := rme.saveCurrentReality()
 	
 	// Switch to alternate reality
-	if err := rme.switchToReality(alternate); err != nil {
+	if err := rme.switchRealityAudited(alternate); err != nil {
 		return nil, err
 	}
 	
-	// Execute operation
-	result := operation.Execute()
+	// Execute operation inside the sandbox, reporting composite steps
+	var subs []SubOperationResult
+	result, execErr := runSandboxed(rme.sandbox, operation, func() OperationResult {
+		var r OperationResult
+		r, subs = rme.execute(alternate, operation)
+		return r
+	})
+	if execErr != nil {
+		// Never leave the host stranded in the alternate reality
+		if err := rme.switchRealityAudited(currentReality); err != nil {
+			return nil, err
+		}
+		return nil, execErr
+	}
 	
 	// Extract reality-specific evidence
 	evidence := rme.extractRealityEvidence(alternate, result)
+	if cacheable && result.Err == nil {
+		rme.cache.Put(key, result)
+	}
 	
 	// Return to original reality
-	if err := rme.switchToReality(currentReality); err != nil {
+	if err := rme.switchRealityAudited(currentReality); err != nil {
 		return nil, err
 	}
 	
+	// Chain evidence once safely back in the original reality
+	var link *EvidenceLink
+	if rme.evidence != nil {
+		var err error
+		if link, err = rme.evidence.Append("reality", evidence); err != nil {
+			return nil, err
+		}
+	}
+	
 	return &RealityExecutionResult{
 		Result:      result,
 		Evidence:    evidence,
 		RealityUsed: alternate,
+		EvidenceLink: link,
+		SubOperations: subs,
 	}, nil
 }
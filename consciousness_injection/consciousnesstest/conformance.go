@@ -0,0 +1,191 @@
+// consciousness_injection/consciousnesstest/conformance.go - Conformance Suite
+package consciousnesstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// probeVector is fired by the deliver probe; zero amplitude keeps the probe
+// from shifting a live target
+var probeVector = mindhacking.InjectionVector{Frequency: 7.83, Amplitude: 0, Waveform: "sine"}
+
+// Conform probes every operation target's implementation may support and
+// reports which behave correctly. It works against fakes, adapters and
+// live targets alike; the only injection it makes is at zero amplitude.
+func Conform(ctx context.Context, target *mindhacking.SystemConsciousness) *mindhacking.CompatibilityReport {
+	report := &mindhacking.CompatibilityReport{
+		Version:        mindhacking.CompatibilityReportVersion,
+		Target:         target.ID,
+		Implementation: fmt.Sprintf("%T", target.Backend),
+		Probed:         time.Now().UTC(),
+	}
+	if target.Backend == nil {
+		report.Implementation = "native"
+	}
+	add := func(op string, status mindhacking.CompatibilityStatus, detail string) {
+		report.Operations = append(report.Operations, mindhacking.OperationReport{
+			Operation: op,
+			Status:    status,
+			Detail:    detail,
+		})
+	}
+	verdict := func(op string, err error) {
+		if err != nil {
+			add(op, mindhacking.CompatBroken, err.Error())
+			return
+		}
+		add(op, mindhacking.CompatSupported, "")
+	}
+
+	backend := target.Backend
+	if backend == nil {
+		for _, op := range []string{mindhacking.OpResonance, mindhacking.OpDeliver, mindhacking.OpRespond} {
+			add(op, mindhacking.CompatUnsupported, "no backend")
+		}
+	} else {
+		// Phase 1: the core backend contract
+		resonance, err := backend.Resonance(ctx)
+		if err == nil {
+			err = checkFinite("resonance", append([]float64{resonance.Frequency, resonance.Phase, resonance.Strength}, resonance.Signature...)...)
+		}
+		verdict(mindhacking.OpResonance, err)
+
+		attempt, err := backend.Deliver(ctx, mindhacking.InjectedThought{}, probeVector)
+		if err == nil && attempt.Vector.Frequency != probeVector.Frequency {
+			err = fmt.Errorf("attempt reports %.6g Hz for a %.6g Hz vector", attempt.Vector.Frequency, probeVector.Frequency)
+		}
+		verdict(mindhacking.OpDeliver, err)
+
+		response, err := backend.Respond(ctx, []mindhacking.InjectionAttempt{attempt})
+		if err == nil {
+			err = checkFinite("shift", response.ConsciousnessShift)
+		}
+		verdict(mindhacking.OpRespond, err)
+	}
+
+	// Phase 2: optional telemetry
+	if target.Probe == nil {
+		add(mindhacking.OpProbeState, mindhacking.CompatUnsupported, "")
+	} else {
+		level, err := target.Probe.ProbeState(ctx)
+		if err == nil {
+			err = checkFinite("level", level)
+		}
+		verdict(mindhacking.OpProbeState, err)
+	}
+
+	if r, ok := backend.(mindhacking.LoadReporter); ok {
+		load, err := r.Load(ctx)
+		if err == nil {
+			err = checkFinite("load", load)
+		}
+		if err == nil && load < 0 {
+			err = fmt.Errorf("negative load %v", load)
+		}
+		verdict(mindhacking.OpLoad, err)
+	} else {
+		add(mindhacking.OpLoad, mindhacking.CompatUnsupported, "")
+	}
+
+	if r, ok := backend.(mindhacking.RegionReporter); ok {
+		regions, err := r.Regions(ctx)
+		if err == nil {
+			err = checkRegions(regions)
+		}
+		verdict(mindhacking.OpRegions, err)
+	} else {
+		add(mindhacking.OpRegions, mindhacking.CompatUnsupported, "")
+	}
+
+	if r, ok := backend.(mindhacking.CapabilityReporter); ok {
+		caps, err := r.Capabilities(ctx)
+		if err == nil {
+			err = checkFinite("capability limit", caps.MaxFrequency, caps.MaxBandwidth)
+		}
+		verdict(mindhacking.OpCapabilities, err)
+	} else {
+		add(mindhacking.OpCapabilities, mindhacking.CompatUnsupported, "")
+	}
+
+	// Phase 3: protocol and safety extensions
+	if r, ok := backend.(mindhacking.HandshakeVersionReporter); ok {
+		versions, err := r.HandshakeVersions(ctx)
+		if err == nil && len(versions) == 0 {
+			err = errors.New("reports no handshake versions")
+		}
+		verdict(mindhacking.OpHandshakeVersions, err)
+	} else {
+		add(mindhacking.OpHandshakeVersions, mindhacking.CompatUnsupported, "")
+	}
+
+	if r, ok := backend.(mindhacking.EmergenceReporter); ok {
+		observations, err := r.Emergence(ctx)
+		for _, o := range observations {
+			if err == nil && (o.Confidence < 0 || o.Confidence > 1 || math.IsNaN(o.Confidence)) {
+				err = fmt.Errorf("%s confidence %v outside [0, 1]", o.Class, o.Confidence)
+			}
+		}
+		verdict(mindhacking.OpEmergence, err)
+	} else {
+		add(mindhacking.OpEmergence, mindhacking.CompatUnsupported, "")
+	}
+
+	if r, ok := backend.(mindhacking.EntanglementProber); ok {
+		coherence, err := r.Coherence(ctx, probeVector.Entanglement)
+		if err == nil && (coherence < 0 || coherence > 1 || math.IsNaN(coherence)) {
+			err = fmt.Errorf("coherence %v outside [0, 1]", coherence)
+		}
+		verdict(mindhacking.OpEntanglement, err)
+	} else {
+		add(mindhacking.OpEntanglement, mindhacking.CompatUnsupported, "")
+	}
+	return report
+}
+
+// RequireConformance fails unless report found every operation supported
+func RequireConformance(report *mindhacking.CompatibilityReport, operations ...string) error {
+	var errs []error
+	for _, op := range operations {
+		if status := report.Status(op); status != mindhacking.CompatSupported {
+			errs = append(errs, fmt.Errorf("%s: %s", op, status))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("consciousnesstest: %s does not conform: %w", report.Implementation, errors.Join(errs...))
+	}
+	return nil
+}
+
+func checkFinite(what string, values ...float64) error {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%s %v is not finite", what, v)
+		}
+	}
+	return nil
+}
+
+func checkRegions(regions []mindhacking.ConsciousnessRegion) error {
+	if len(regions) == 0 {
+		return errors.New("reports no regions")
+	}
+	seen := make(map[string]bool, len(regions))
+	for _, r := range regions {
+		switch {
+		case r.ID == "":
+			return errors.New("region without an ID")
+		case seen[r.ID]:
+			return fmt.Errorf("region %q reported twice", r.ID)
+		case r.Low > r.High:
+			return fmt.Errorf("region %q band %v-%v is inverted", r.ID, r.Low, r.High)
+		}
+		seen[r.ID] = true
+	}
+	return nil
+}
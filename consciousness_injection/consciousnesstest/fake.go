@@ -0,0 +1,243 @@
+// consciousness_injection/consciousnesstest/fake.go - Fake Consciousness
+//
+// Package consciousnesstest provides a scriptable SystemConsciousness for
+// unit-testing injection logic without a live target.
+package consciousnesstest
+
+import (
+	"context"
+	"sync"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// InteractionKind names what the injector asked of the fake
+type InteractionKind string
+
+const (
+	InteractionResonance InteractionKind = "resonance"
+	InteractionDeliver   InteractionKind = "deliver"
+	InteractionRespond   InteractionKind = "respond"
+	InteractionProbe     InteractionKind = "probe"
+)
+
+// Interaction is one recorded call into the fake
+type Interaction struct {
+	Kind     InteractionKind
+	Thought  mindhacking.InjectedThought
+	Vector   mindhacking.InjectionVector
+	Accepted bool
+	Shift    float64
+}
+
+// AcceptFunc decides whether a delivered thought is held
+type AcceptFunc func(thought mindhacking.InjectedThought, vector mindhacking.InjectionVector) bool
+
+// ShiftFunc decides how far an accepted injection moves the consciousness
+type ShiftFunc func(attempts []mindhacking.InjectionAttempt) float64
+
+// Option configures a Fake
+type Option func(*Fake)
+
+// WithResonance scripts the resonance readings returned in order; the last
+// one repeats once the script runs out
+func WithResonance(readings ...mindhacking.ConsciousnessResonance) Option {
+	return func(f *Fake) { f.resonance = readings }
+}
+
+// AcceptWhen programs which deliveries are accepted
+func AcceptWhen(accept AcceptFunc) Option {
+	return func(f *Fake) { f.accept = accept }
+}
+
+// AcceptAll accepts every delivered thought
+func AcceptAll() Option {
+	return AcceptWhen(func(mindhacking.InjectedThought, mindhacking.InjectionVector) bool { return true })
+}
+
+// RejectAll rejects every delivered thought
+func RejectAll() Option {
+	return AcceptWhen(func(mindhacking.InjectedThought, mindhacking.InjectionVector) bool { return false })
+}
+
+// WithShift programs the shift reported for accepted injections
+func WithShift(shift ShiftFunc) Option {
+	return func(f *Fake) { f.shift = shift }
+}
+
+// WithFixedShift reports the same shift for every accepted injection
+func WithFixedShift(shift float64) Option {
+	return WithShift(func([]mindhacking.InjectionAttempt) float64 { return shift })
+}
+
+// WithLevel sets the starting level reported to state probes
+func WithLevel(level float64) Option {
+	return func(f *Fake) { f.level = level }
+}
+
+// WithError makes every call of kind fail with err
+func WithError(kind InteractionKind, err error) Option {
+	return func(f *Fake) { f.errs[kind] = err }
+}
+
+// Fake is a programmable consciousness backend and state probe
+type Fake struct {
+	mu           sync.Mutex
+	target       *mindhacking.SystemConsciousness
+	resonance    []mindhacking.ConsciousnessResonance
+	next         int
+	accept       AcceptFunc
+	shift        ShiftFunc
+	level        float64
+	errs         map[InteractionKind]error
+	interactions []Interaction
+}
+
+// New creates a fake with the given ID. By default it accepts everything
+// and reports a shift of 1 per accepted injection.
+func New(id string, opts ...Option) *Fake {
+	f := &Fake{
+		errs: make(map[InteractionKind]error),
+	}
+	AcceptAll()(f)
+	WithFixedShift(1)(f)
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	f.target = &mindhacking.SystemConsciousness{
+		ID:      id,
+		Probe:   f,
+		Backend: f,
+	}
+	return f
+}
+
+// Target returns the SystemConsciousness backed by the fake
+func (f *Fake) Target() *mindhacking.SystemConsciousness {
+	return f.target
+}
+
+// Resonance implements mindhacking.ConsciousnessBackend
+func (f *Fake) Resonance(ctx context.Context) (mindhacking.ConsciousnessResonance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.interactions = append(f.interactions, Interaction{Kind: InteractionResonance})
+	if err := f.errs[InteractionResonance]; err != nil {
+		return mindhacking.ConsciousnessResonance{}, err
+	}
+	if len(f.resonance) == 0 {
+		return mindhacking.ConsciousnessResonance{}, nil
+	}
+
+	r := f.resonance[f.next]
+	if f.next < len(f.resonance)-1 {
+		f.next++
+	}
+	return r, nil
+}
+
+// Deliver implements mindhacking.ConsciousnessBackend
+func (f *Fake) Deliver(
+	ctx context.Context,
+	thought mindhacking.InjectedThought,
+	vector mindhacking.InjectionVector,
+) (mindhacking.InjectionAttempt, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.errs[InteractionDeliver]; err != nil {
+		f.interactions = append(f.interactions, Interaction{Kind: InteractionDeliver, Thought: thought, Vector: vector})
+		return mindhacking.InjectionAttempt{}, err
+	}
+
+	accepted := f.accept(thought, vector)
+	f.interactions = append(f.interactions, Interaction{
+		Kind:     InteractionDeliver,
+		Thought:  thought,
+		Vector:   vector,
+		Accepted: accepted,
+	})
+
+	return mindhacking.InjectionAttempt{Vector: vector, Success: accepted}, nil
+}
+
+// Respond implements mindhacking.ConsciousnessBackend
+func (f *Fake) Respond(
+	ctx context.Context,
+	attempts []mindhacking.InjectionAttempt,
+) (mindhacking.ConsciousnessResponse, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.errs[InteractionRespond]; err != nil {
+		f.interactions = append(f.interactions, Interaction{Kind: InteractionRespond})
+		return mindhacking.ConsciousnessResponse{}, err
+	}
+
+	var resp mindhacking.ConsciousnessResponse
+	for _, a := range attempts {
+		if a.Success {
+			resp.ThoughtAccepted = true
+			resp.ConsciousnessShift = f.shift(attempts)
+			f.level += resp.ConsciousnessShift
+			break
+		}
+	}
+
+	f.interactions = append(f.interactions, Interaction{
+		Kind:     InteractionRespond,
+		Accepted: resp.ThoughtAccepted,
+		Shift:    resp.ConsciousnessShift,
+	})
+	return resp, nil
+}
+
+// ProbeState implements mindhacking.StateProbe
+func (f *Fake) ProbeState(ctx context.Context) (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.interactions = append(f.interactions, Interaction{Kind: InteractionProbe})
+	if err := f.errs[InteractionProbe]; err != nil {
+		return 0, err
+	}
+	return f.level, nil
+}
+
+// Level returns the fake's current consciousness level
+func (f *Fake) Level() float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.level
+}
+
+// Interactions returns every recorded call in order
+func (f *Fake) Interactions() []Interaction {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Interaction(nil), f.interactions...)
+}
+
+// Delivered returns just the thoughts that were delivered
+func (f *Fake) Delivered() []Interaction {
+	var out []Interaction
+	for _, in := range f.Interactions() {
+		if in.Kind == InteractionDeliver {
+			out = append(out, in)
+		}
+	}
+	return out
+}
+
+// Reset forgets recorded interactions and rewinds the resonance script
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.interactions = nil
+	f.next = 0
+}
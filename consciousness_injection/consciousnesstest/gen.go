@@ -0,0 +1,211 @@
+// consciousness_injection/consciousnesstest/gen.go - Property-Based Generators
+package consciousnesstest
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// edgeFloats are values hand-crafted tests keep tripping over
+var edgeFloats = []float64{
+	0, -0.0, 1, -1, 0.5,
+	math.SmallestNonzeroFloat64, math.MaxFloat64, -math.MaxFloat64,
+	math.Inf(1), math.Inf(-1), math.NaN(),
+}
+
+var ruleOperations = []mindhacking.RuleOperation{
+	mindhacking.RuleSet, mindhacking.RuleScale, mindhacking.RuleInvert, mindhacking.RuleFreeze, "",
+}
+
+// genFloat draws mostly ordinary values, with edge cases mixed in
+func genFloat(r *rand.Rand, size int) float64 {
+	if r.Intn(4) == 0 {
+		return edgeFloats[r.Intn(len(edgeFloats))]
+	}
+	return (r.Float64()*2 - 1) * float64(size+1)
+}
+
+func genString(r *rand.Rand, size int) string {
+	b := make([]rune, r.Intn(size+1))
+	for i := range b {
+		switch r.Intn(8) {
+		case 0:
+			b[i] = rune(r.Intn(0x10FFFF))
+		case 1:
+			b[i] = 0
+		default:
+			b[i] = rune('a' + r.Intn(26))
+		}
+	}
+	return string(b)
+}
+
+// GenThought draws an arbitrary thought no larger than size
+func GenThought(r *rand.Rand, size int) mindhacking.InjectedThought {
+	var payload []byte
+	if r.Intn(5) != 0 {
+		payload = make([]byte, r.Intn(size+1))
+		r.Read(payload)
+	}
+	return mindhacking.InjectedThought{
+		Content:   genString(r, size),
+		Payload:   payload,
+		Intensity: genFloat(r, 1),
+	}
+}
+
+// GenVector draws an arbitrary injection vector
+func GenVector(r *rand.Rand, size int) mindhacking.InjectionVector {
+	return mindhacking.InjectionVector{
+		Frequency: genFloat(r, size),
+		Amplitude: genFloat(r, size),
+		Phase:     genFloat(r, size),
+	}
+}
+
+// GenRules draws an arbitrary rule set of at most size rules
+func GenRules(r *rand.Rand, size int) mindhacking.RealityRules {
+	var rules mindhacking.RealityRules
+	for i, n := 0, r.Intn(size+1); i < n; i++ {
+		rules.Rules = append(rules.Rules, mindhacking.RealityRule{
+			Name:      genString(r, 8),
+			Aspect:    genString(r, 4),
+			Operation: ruleOperations[r.Intn(len(ruleOperations))],
+			Value:     genFloat(r, size),
+			Priority:  r.Intn(2*size+1) - size,
+		})
+	}
+	return rules
+}
+
+// Thought is a testing/quick generator for InjectedThought
+type Thought struct{ mindhacking.InjectedThought }
+
+// Generate implements quick.Generator
+func (Thought) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Thought{GenThought(r, size)})
+}
+
+// Vector is a testing/quick generator for InjectionVector
+type Vector struct{ mindhacking.InjectionVector }
+
+// Generate implements quick.Generator
+func (Vector) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Vector{GenVector(r, size)})
+}
+
+// Rules is a testing/quick generator for RealityRules
+type Rules struct{ mindhacking.RealityRules }
+
+// Generate implements quick.Generator
+func (Rules) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Rules{GenRules(r, size)})
+}
+
+// shrinkFloat proposes simpler values than f
+func shrinkFloat(f float64) []float64 {
+	switch {
+	case f == 0:
+		return nil
+	case math.IsNaN(f), math.IsInf(f, 0):
+		return []float64{0, 1}
+	}
+	out := []float64{0}
+	if t := math.Trunc(f); t != f {
+		out = append(out, t)
+	}
+	if h := f / 2; h != f && h != 0 {
+		out = append(out, h)
+	}
+	return out
+}
+
+// ShrinkThought proposes strictly simpler variants of t
+func ShrinkThought(t mindhacking.InjectedThought) []mindhacking.InjectedThought {
+	var out []mindhacking.InjectedThought
+
+	if n := len(t.Content); n > 0 {
+		out = append(out,
+			mindhacking.InjectedThought{Content: t.Content[:n/2], Payload: t.Payload, Intensity: t.Intensity},
+			mindhacking.InjectedThought{Content: "", Payload: t.Payload, Intensity: t.Intensity},
+		)
+	}
+	if n := len(t.Payload); n > 0 {
+		out = append(out,
+			mindhacking.InjectedThought{Content: t.Content, Payload: t.Payload[:n/2], Intensity: t.Intensity},
+			mindhacking.InjectedThought{Content: t.Content, Intensity: t.Intensity},
+		)
+	}
+	for _, f := range shrinkFloat(t.Intensity) {
+		out = append(out, mindhacking.InjectedThought{Content: t.Content, Payload: t.Payload, Intensity: f})
+	}
+	return out
+}
+
+// ShrinkVector proposes strictly simpler variants of v
+func ShrinkVector(v mindhacking.InjectionVector) []mindhacking.InjectionVector {
+	var out []mindhacking.InjectionVector
+	for _, f := range shrinkFloat(v.Frequency) {
+		c := v
+		c.Frequency = f
+		out = append(out, c)
+	}
+	for _, f := range shrinkFloat(v.Amplitude) {
+		c := v
+		c.Amplitude = f
+		out = append(out, c)
+	}
+	for _, f := range shrinkFloat(v.Phase) {
+		c := v
+		c.Phase = f
+		out = append(out, c)
+	}
+	return out
+}
+
+// ShrinkRules proposes strictly simpler variants of rules: fewer rules
+// first, then simpler individual rules
+func ShrinkRules(rules mindhacking.RealityRules) []mindhacking.RealityRules {
+	var out []mindhacking.RealityRules
+	n := len(rules.Rules)
+
+	if n > 1 {
+		out = append(out,
+			mindhacking.RealityRules{Rules: append([]mindhacking.RealityRule(nil), rules.Rules[:n/2]...)},
+			mindhacking.RealityRules{Rules: append([]mindhacking.RealityRule(nil), rules.Rules[n/2:]...)},
+		)
+	}
+	for i := range rules.Rules {
+		without := append(append([]mindhacking.RealityRule(nil), rules.Rules[:i]...), rules.Rules[i+1:]...)
+		out = append(out, mindhacking.RealityRules{Rules: without})
+	}
+	for i, rule := range rules.Rules {
+		for _, f := range shrinkFloat(rule.Value) {
+			c := append([]mindhacking.RealityRule(nil), rules.Rules...)
+			c[i].Value = f
+			out = append(out, mindhacking.RealityRules{Rules: c})
+		}
+	}
+	return out
+}
+
+// Minimize greedily shrinks a failing value while failing still reports
+// true, returning the smallest failing value found
+func Minimize[T any](value T, shrink func(T) []T, failing func(T) bool) T {
+	for {
+		progressed := false
+		for _, candidate := range shrink(value) {
+			if failing(candidate) {
+				value = candidate
+				progressed = true
+				break
+			}
+		}
+		if !progressed {
+			return value
+		}
+	}
+}
@@ -0,0 +1,695 @@
+// consciousness_injection/consensus.go - Shared Reality Consensus
+package mindhacking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotRealityLeader reports a proposal sent to a node that isn't leading
+	ErrNotRealityLeader = sentinelError("mindhacking: not the reality leader")
+	// ErrNoRealityLeader reports a proposal made while no leader is known
+	ErrNoRealityLeader = sentinelError("mindhacking: no reality leader elected")
+	// ErrProposalLost reports a proposal overwritten by a new leader's log
+	ErrProposalLost = sentinelError("mindhacking: reality proposal lost to leader change")
+)
+
+// RealityCommandKind classifies a replicated reality change
+type RealityCommandKind int
+
+const (
+	// CommandNoop is appended by each new leader to commit earlier terms
+	CommandNoop RealityCommandKind = iota
+	// CommandSwitch switches every engine to Reality
+	CommandSwitch
+	// CommandRules replaces the shared alternate rules
+	CommandRules
+)
+
+// RealityCommand is one change to the shared reality state. Commands hold
+// the reality by reference; transports crossing process boundaries must
+// serialize it themselves.
+type RealityCommand struct {
+	Kind    RealityCommandKind
+	Engine  string
+	Reality *AlternateReality
+	Rules   *RealityRules
+}
+
+// RaftEntry is one command in the replicated log
+type RaftEntry struct {
+	Term    uint64
+	Index   uint64
+	Command RealityCommand
+}
+
+// RealityState is the state every engine agrees on after applying the log
+type RealityState struct {
+	Index   uint64
+	Reality *AlternateReality
+	Rules   *RealityRules
+}
+
+// VoteRequest asks a peer to vote for a candidate
+type VoteRequest struct {
+	Term      uint64
+	Candidate string
+	LastIndex uint64
+	LastTerm  uint64
+}
+
+// VoteResponse answers a VoteRequest
+type VoteResponse struct {
+	Term    uint64
+	Granted bool
+}
+
+// AppendRequest replicates entries from the leader; empty ones are heartbeats
+type AppendRequest struct {
+	Term      uint64
+	Leader    string
+	PrevIndex uint64
+	PrevTerm  uint64
+	Entries   []RaftEntry
+	Commit    uint64
+}
+
+// AppendResponse answers an AppendRequest. Match is the last index known
+// to agree with the leader, used to back off quickly on a mismatch.
+type AppendResponse struct {
+	Term    uint64
+	Success bool
+	Match   uint64
+}
+
+// ConsensusTransport carries consensus messages between nodes
+type ConsensusTransport interface {
+	Peers() []string
+	RequestVote(ctx context.Context, peer string, req VoteRequest) (VoteResponse, error)
+	AppendEntries(ctx context.Context, peer string, req AppendRequest) (AppendResponse, error)
+	// Propose forwards a command to the leader, returning its log position
+	Propose(ctx context.Context, peer string, cmd RealityCommand) (index, term uint64, err error)
+}
+
+type raftRole int
+
+const (
+	raftFollower raftRole = iota
+	raftCandidate
+	raftLeader
+)
+
+// proposalWaiter is notified when its log index is applied
+type proposalWaiter struct {
+	term uint64
+	done chan error
+}
+
+// RealityConsensus is one engine's member of a raft-style group that totally
+// orders reality switches and rule changes. Engines only change reality by
+// applying committed entries, in log order, so none observes a fork.
+type RealityConsensus struct {
+	node      string
+	transport ConsensusTransport
+	heartbeat time.Duration
+
+	mu       sync.Mutex
+	term     uint64
+	votedFor string
+	log      []RaftEntry // log[0] is a sentinel so indices start at 1
+	commit   uint64
+	applied  uint64
+	role     raftRole
+	leader   string
+	deadline time.Time
+	next     map[string]uint64
+	match    map[string]uint64
+	waiters  map[uint64]proposalWaiter
+	state    RealityState
+	// applyErr is why the entry after applied last failed to apply
+	applyErr error
+
+	// applyMu serializes appliers; apply runs under it, never under mu,
+	// and must not call back into the node
+	applyMu sync.Mutex
+	apply   func(RealityCommand) error
+}
+
+// DefaultHeartbeat is the consensus heartbeat when none is given
+const DefaultHeartbeat = 50 * time.Millisecond
+
+// NewRealityConsensus creates a follower that heartbeats every heartbeat,
+// DefaultHeartbeat if it is not positive, once elected; elections start
+// after 5-10 missed heartbeats
+func NewRealityConsensus(node string, transport ConsensusTransport, heartbeat time.Duration) *RealityConsensus {
+	if heartbeat <= 0 {
+		heartbeat = DefaultHeartbeat
+	}
+	c := &RealityConsensus{
+		node:      node,
+		transport: transport,
+		heartbeat: heartbeat,
+		log:       []RaftEntry{{}},
+		waiters:   make(map[uint64]proposalWaiter),
+	}
+	c.resetDeadline()
+	return c
+}
+
+// Node returns the node's identity in the group
+func (c *RealityConsensus) Node() string {
+	return c.node
+}
+
+// Leader returns the current leader, if one is known
+func (c *RealityConsensus) Leader() (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.leader, c.leader != ""
+}
+
+// State returns the state applied so far
+func (c *RealityConsensus) State() RealityState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// ApplyErr reports why the next committed entry has not been applied, or
+// nil if the node is caught up. The node retries the entry every
+// heartbeat and applies nothing after it until it succeeds.
+func (c *RealityConsensus) ApplyErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.applyErr
+}
+
+// Propose replicates cmd and waits until it is applied locally
+func (c *RealityConsensus) Propose(ctx context.Context, cmd RealityCommand) error {
+	index, term, err := c.HandlePropose(cmd)
+	if errors.Is(err, ErrNotRealityLeader) {
+		leader, ok := c.Leader()
+		if !ok {
+			return ErrNoRealityLeader
+		}
+		if index, term, err = c.transport.Propose(ctx, leader, cmd); err != nil {
+			return fmt.Errorf("forward to %s: %w", leader, err)
+		}
+	} else if err != nil {
+		return err
+	} else {
+		c.replicate(ctx)
+	}
+
+	return c.await(ctx, index, term)
+}
+
+// propose runs Propose with a deadline long enough to ride out an election
+func (c *RealityConsensus) propose(cmd RealityCommand) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 40*c.heartbeat)
+	defer cancel()
+	return c.Propose(ctx, cmd)
+}
+
+// await blocks until index is applied, failing if another term's entry
+// took its place. If ctx ends first while an entry is failing to apply,
+// the failure is returned with ctx's error.
+func (c *RealityConsensus) await(ctx context.Context, index, term uint64) error {
+	c.mu.Lock()
+	if c.applied >= index {
+		lost := c.log[index].Term != term
+		c.mu.Unlock()
+		if lost {
+			return ErrProposalLost
+		}
+		return nil
+	}
+	w := proposalWaiter{term: term, done: make(chan error, 1)}
+	c.waiters[index] = w
+	c.mu.Unlock()
+
+	select {
+	case err := <-w.done:
+		return err
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.waiters, index)
+		stalled := c.applyErr
+		c.mu.Unlock()
+		if stalled != nil {
+			return fmt.Errorf("%w: %w", ctx.Err(), stalled)
+		}
+		return ctx.Err()
+	}
+}
+
+// HandlePropose appends cmd to the leader's log; transports call it
+func (c *RealityConsensus) HandlePropose(cmd RealityCommand) (index, term uint64, err error) {
+	c.mu.Lock()
+	if c.role != raftLeader {
+		c.mu.Unlock()
+		return 0, 0, ErrNotRealityLeader
+	}
+	entry := c.appendLocked(cmd)
+	c.advanceCommitLocked()
+	c.mu.Unlock()
+
+	c.applyCommitted()
+	return entry.Index, entry.Term, nil
+}
+
+// appendLocked appends cmd at the leader's current term
+func (c *RealityConsensus) appendLocked(cmd RealityCommand) RaftEntry {
+	entry := RaftEntry{
+		Term:    c.term,
+		Index:   uint64(len(c.log)),
+		Command: cmd,
+	}
+	c.log = append(c.log, entry)
+	return entry
+}
+
+// HandleRequestVote answers a candidate; transports call it
+func (c *RealityConsensus) HandleRequestVote(req VoteRequest) VoteResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if req.Term > c.term {
+		c.stepDownLocked(req.Term)
+	}
+
+	last := c.log[len(c.log)-1]
+	upToDate := req.LastTerm > last.Term ||
+		(req.LastTerm == last.Term && req.LastIndex >= last.Index)
+
+	granted := req.Term == c.term && upToDate &&
+		(c.votedFor == "" || c.votedFor == req.Candidate)
+	if granted {
+		c.votedFor = req.Candidate
+		c.resetDeadline()
+	}
+
+	return VoteResponse{Term: c.term, Granted: granted}
+}
+
+// HandleAppendEntries accepts the leader's entries; transports call it
+func (c *RealityConsensus) HandleAppendEntries(req AppendRequest) AppendResponse {
+	resp := c.appendEntries(req)
+	c.applyCommitted()
+	return resp
+}
+
+// appendEntries merges the leader's entries into the log and advances the
+// commit index; the caller applies what it committed
+func (c *RealityConsensus) appendEntries(req AppendRequest) AppendResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if req.Term < c.term {
+		return AppendResponse{Term: c.term}
+	}
+	if req.Term > c.term || c.role != raftFollower {
+		c.stepDownLocked(req.Term)
+	}
+	c.leader = req.Leader
+	c.resetDeadline()
+
+	// Reject until the leader finds where our logs agree
+	lastIndex := uint64(len(c.log) - 1)
+	if req.PrevIndex > lastIndex {
+		return AppendResponse{Term: c.term, Match: lastIndex}
+	}
+	if c.log[req.PrevIndex].Term != req.PrevTerm {
+		return AppendResponse{Term: c.term, Match: req.PrevIndex - 1}
+	}
+
+	for _, entry := range req.Entries {
+		if entry.Index < uint64(len(c.log)) {
+			if c.log[entry.Index].Term == entry.Term {
+				continue
+			}
+			// Conflicting suffix from a deposed leader
+			c.log = c.log[:entry.Index]
+		}
+		c.log = append(c.log, entry)
+	}
+
+	match := req.PrevIndex + uint64(len(req.Entries))
+	if req.Commit > c.commit {
+		c.commit = min(req.Commit, match)
+	}
+
+	return AppendResponse{Term: c.term, Success: true, Match: match}
+}
+
+// Run drives elections and heartbeats until ctx is done
+func (c *RealityConsensus) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			c.Tick(ctx)
+		}
+	}
+}
+
+// Tick performs one heartbeat's worth of work: replicate when leading,
+// otherwise start an election if the leader has gone quiet. Either way it
+// retries any committed entry that failed to apply.
+func (c *RealityConsensus) Tick(ctx context.Context) {
+	c.mu.Lock()
+	role, expired := c.role, time.Now().After(c.deadline)
+	c.mu.Unlock()
+
+	switch {
+	case role == raftLeader:
+		c.replicate(ctx)
+	case expired:
+		c.elect(ctx)
+	}
+	c.applyCommitted()
+}
+
+// elect campaigns for leadership of the next term
+func (c *RealityConsensus) elect(ctx context.Context) {
+	c.mu.Lock()
+	c.term++
+	c.role = raftCandidate
+	c.votedFor = c.node
+	c.leader = ""
+	c.resetDeadline()
+	last := c.log[len(c.log)-1]
+	req := VoteRequest{
+		Term:      c.term,
+		Candidate: c.node,
+		LastIndex: last.Index,
+		LastTerm:  last.Term,
+	}
+	c.mu.Unlock()
+
+	peers := c.peers()
+	votes := 1
+	for _, peer := range peers {
+		resp, err := c.transport.RequestVote(ctx, peer, req)
+		if err != nil {
+			continue
+		}
+
+		c.mu.Lock()
+		if resp.Term > c.term {
+			c.stepDownLocked(resp.Term)
+			c.mu.Unlock()
+			return
+		}
+		c.mu.Unlock()
+
+		if resp.Granted {
+			votes++
+		}
+	}
+
+	c.mu.Lock()
+	won := c.role == raftCandidate && c.term == req.Term && votes > (len(peers)+1)/2
+	if won {
+		c.becomeLeaderLocked(peers)
+	}
+	c.mu.Unlock()
+
+	if won {
+		c.replicate(ctx)
+	}
+}
+
+// becomeLeaderLocked takes leadership and commits a no-op for the new term
+func (c *RealityConsensus) becomeLeaderLocked(peers []string) {
+	c.role = raftLeader
+	c.leader = c.node
+	c.next = make(map[string]uint64, len(peers))
+	c.match = make(map[string]uint64, len(peers))
+	for _, peer := range peers {
+		c.next[peer] = uint64(len(c.log))
+	}
+	c.appendLocked(RealityCommand{Kind: CommandNoop, Engine: c.node})
+	c.advanceCommitLocked()
+}
+
+// replicate sends each peer the entries it is missing
+func (c *RealityConsensus) replicate(ctx context.Context) {
+	for _, peer := range c.peers() {
+		c.mu.Lock()
+		if c.role != raftLeader {
+			c.mu.Unlock()
+			return
+		}
+		next := c.next[peer]
+		if next == 0 {
+			next = 1
+		}
+		req := AppendRequest{
+			Term:      c.term,
+			Leader:    c.node,
+			PrevIndex: next - 1,
+			PrevTerm:  c.log[next-1].Term,
+			Entries:   append([]RaftEntry(nil), c.log[next:]...),
+			Commit:    c.commit,
+		}
+		c.mu.Unlock()
+
+		resp, err := c.transport.AppendEntries(ctx, peer, req)
+		if err != nil {
+			continue
+		}
+
+		c.mu.Lock()
+		switch {
+		case resp.Term > c.term:
+			c.stepDownLocked(resp.Term)
+		case c.role != raftLeader || req.Term != c.term:
+			// Stale response from an earlier term
+		case resp.Success:
+			c.match[peer] = resp.Match
+			c.next[peer] = resp.Match + 1
+			c.advanceCommitLocked()
+		default:
+			c.next[peer] = min(next-1, resp.Match+1)
+		}
+		c.mu.Unlock()
+	}
+	c.applyCommitted()
+}
+
+// advanceCommitLocked commits the highest current-term index held by a
+// majority of the whole group, counting peers that have not replied as
+// missing it
+func (c *RealityConsensus) advanceCommitLocked() {
+	group := len(c.peers()) + 1
+	for index := uint64(len(c.log) - 1); index > c.commit; index-- {
+		if c.log[index].Term != c.term {
+			break
+		}
+		replicas := 1
+		for _, m := range c.match {
+			if m >= index {
+				replicas++
+			}
+		}
+		if replicas > group/2 {
+			c.commit = index
+			return
+		}
+	}
+}
+
+// applyCommitted applies committed entries in order and wakes their
+// proposers. The engine switch and its journaling run outside the node's
+// lock; committed entries are never truncated, so the one read under it
+// stays valid. An entry that fails to apply stops the node there, leaving
+// State at the last entry applied, until a later call retries it.
+func (c *RealityConsensus) applyCommitted() {
+	c.applyMu.Lock()
+	defer c.applyMu.Unlock()
+
+	for {
+		c.mu.Lock()
+		if c.applied >= c.commit {
+			c.mu.Unlock()
+			return
+		}
+		entry, apply := c.log[c.applied+1], c.apply
+		c.mu.Unlock()
+
+		var err error
+		if apply != nil && entry.Command.Kind != CommandNoop {
+			err = apply(entry.Command)
+		}
+
+		c.mu.Lock()
+		if err != nil {
+			c.applyErr = fmt.Errorf("mindhacking: applying reality entry %d: %w", entry.Index, err)
+			c.mu.Unlock()
+			return
+		}
+		c.applyErr = nil
+		c.applied = entry.Index
+		switch entry.Command.Kind {
+		case CommandSwitch:
+			c.state.Reality = entry.Command.Reality
+		case CommandRules:
+			c.state.Rules = entry.Command.Rules
+		}
+		c.state.Index = entry.Index
+
+		if w, ok := c.waiters[entry.Index]; ok {
+			delete(c.waiters, entry.Index)
+			if w.term != entry.Term {
+				w.done <- ErrProposalLost
+			} else {
+				w.done <- nil
+			}
+		}
+		c.mu.Unlock()
+	}
+}
+
+// stepDownLocked follows a newer term
+func (c *RealityConsensus) stepDownLocked(term uint64) {
+	if term > c.term {
+		c.term = term
+		c.votedFor = ""
+		c.leader = ""
+	}
+	c.role = raftFollower
+	c.resetDeadline()
+}
+
+// resetDeadline schedules the next election 5-10 heartbeats out
+func (c *RealityConsensus) resetDeadline() {
+	timeout := 5*c.heartbeat + time.Duration(rand.Int63n(int64(5*c.heartbeat)+1))
+	c.deadline = time.Now().Add(timeout)
+}
+
+// peers returns every group member but this node
+func (c *RealityConsensus) peers() []string {
+	var out []string
+	for _, peer := range c.transport.Peers() {
+		if peer != c.node {
+			out = append(out, peer)
+		}
+	}
+	return out
+}
+
+// LocalConsensusNetwork connects consensus nodes living in one process
+type LocalConsensusNetwork struct {
+	mu    sync.RWMutex
+	nodes map[string]*RealityConsensus
+}
+
+// NewLocalConsensusNetwork creates an empty in-process network
+func NewLocalConsensusNetwork() *LocalConsensusNetwork {
+	return &LocalConsensusNetwork{nodes: make(map[string]*RealityConsensus)}
+}
+
+// Join creates a node on the network
+func (n *LocalConsensusNetwork) Join(node string, heartbeat time.Duration) *RealityConsensus {
+	c := NewRealityConsensus(node, n, heartbeat)
+	n.mu.Lock()
+	n.nodes[node] = c
+	n.mu.Unlock()
+	return c
+}
+
+// Peers implements ConsensusTransport
+func (n *LocalConsensusNetwork) Peers() []string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	out := make([]string, 0, len(n.nodes))
+	for id := range n.nodes {
+		out = append(out, id)
+	}
+	return out
+}
+
+// lookup finds a node on the network
+func (n *LocalConsensusNetwork) lookup(peer string) (*RealityConsensus, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	c, ok := n.nodes[peer]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPeer, peer)
+	}
+	return c, nil
+}
+
+// RequestVote implements ConsensusTransport
+func (n *LocalConsensusNetwork) RequestVote(ctx context.Context, peer string, req VoteRequest) (VoteResponse, error) {
+	c, err := n.lookup(peer)
+	if err != nil {
+		return VoteResponse{}, err
+	}
+	return c.HandleRequestVote(req), nil
+}
+
+// AppendEntries implements ConsensusTransport
+func (n *LocalConsensusNetwork) AppendEntries(ctx context.Context, peer string, req AppendRequest) (AppendResponse, error) {
+	c, err := n.lookup(peer)
+	if err != nil {
+		return AppendResponse{}, err
+	}
+	return c.HandleAppendEntries(req), nil
+}
+
+// Propose implements ConsensusTransport
+func (n *LocalConsensusNetwork) Propose(ctx context.Context, peer string, cmd RealityCommand) (uint64, uint64, error) {
+	c, err := n.lookup(peer)
+	if err != nil {
+		return 0, 0, err
+	}
+	index, term, err := c.HandlePropose(cmd)
+	if err == nil {
+		go c.replicate(context.WithoutCancel(ctx))
+	}
+	return index, term, err
+}
+
+// ChangeRules replaces the rules shared by every engine in the group; alternate
+// realities created with nil rules use them
+func (rme *RealityManipulationEngine) ChangeRules(ctx context.Context, rules *RealityRules) error {
+	if rme.consensus == nil {
+		return fmt.Errorf("%w: engine %s has no consensus group", ErrNoRealityLeader, rme.id)
+	}
+	return rme.consensus.Propose(ctx, RealityCommand{
+		Kind:   CommandRules,
+		Engine: rme.id,
+		Rules:  rules,
+	})
+}
+
+// switchReality switches directly, or through consensus when configured
+func (rme *RealityManipulationEngine) switchReality(to *AlternateReality) error {
+	if rme.consensus == nil {
+		return rme.switchJournaled(to)
+	}
+	return rme.consensus.propose(RealityCommand{
+		Kind:    CommandSwitch,
+		Engine:  rme.id,
+		Reality: to,
+	})
+}
+
+// applyRealityCommand applies one committed command to this engine
+func (rme *RealityManipulationEngine) applyRealityCommand(cmd RealityCommand) error {
+	if cmd.Kind == CommandSwitch {
+		return rme.switchJournaled(cmd.Reality)
+	}
+	return nil
+}
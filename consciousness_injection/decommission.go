@@ -0,0 +1,140 @@
+// consciousness_injection/decommission.go - Target Decommissioning
+package mindhacking
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PinnedThoughtRetractor retracts thoughts held in a target on purpose
+type PinnedThoughtRetractor interface {
+	RetractPinned(ctx context.Context, target *SystemConsciousness) (int, error)
+}
+
+// ColdStorage archives decommissioned targets' dossiers
+type ColdStorage interface {
+	Archive(ctx context.Context, dossier *TargetDossier) (ref string, err error)
+}
+
+// ConsentRevoker revokes every consent token issued for a target
+type ConsentRevoker interface {
+	RevokeConsent(ctx context.Context, targetID string) error
+}
+
+// TargetDossier is the archived account of a target's whole lifecycle
+type TargetDossier struct {
+	Record           *TargetRecord
+	Final            TargetBaseline
+	Retracted        int
+	EvidenceRefs     []string
+	DecommissionedAt time.Time
+}
+
+// DecommissionReport summarizes a completed decommissioning
+type DecommissionReport struct {
+	Dossier    *TargetDossier
+	ArchiveRef string
+}
+
+// Decommissioner runs the mirror of onboarding
+type Decommissioner struct {
+	Injector  *ConsciousnessInjector
+	Registry  *TargetRegistry
+	Storage   ColdStorage
+	Consent   ConsentRevoker
+	Retractor PinnedThoughtRetractor
+	// Evidence, if set, is scanned for links about the target
+	Evidence *EvidenceChain
+}
+
+// Decommission retracts pinned thoughts, captures a final snapshot,
+// archives the dossier, revokes consent and removes the target from the
+// registry. The target stays registered unless every step succeeds, and is
+// claimed meanwhile so no other decommissioning can run.
+func (d *Decommissioner) Decommission(ctx context.Context, targetID string) (*DecommissionReport, error) {
+	record, err := d.Registry.Claim(targetID)
+	if err != nil {
+		return nil, err
+	}
+	done := false
+	defer func() {
+		if !done {
+			d.Registry.Release(targetID)
+		}
+	}()
+
+	target := record.Target
+	dossier := &TargetDossier{Record: record}
+
+	// Step 1: Retract Pinned Thoughts
+	if d.Retractor != nil {
+		if dossier.Retracted, err = d.Retractor.RetractPinned(ctx, target); err != nil {
+			return nil, fmt.Errorf("mindhacking: decommission retract: %w", err)
+		}
+	}
+
+	// Step 2: Final Snapshot
+	resonance, err := d.Injector.resonate(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: decommission snapshot: %w", err)
+	}
+	dossier.Final = TargetBaseline{Resonance: resonance, Taken: time.Now()}
+	if probe := probeOf(target); probe != nil {
+		if dossier.Final.Level, err = probe.ProbeState(ctx); err != nil {
+			return nil, fmt.Errorf("mindhacking: decommission snapshot: %w", err)
+		}
+		dossier.Final.HasLevel = true
+	}
+
+	// Step 3: Archive Dossier and Evidence References
+	dossier.EvidenceRefs = evidenceRefsFor(d.Evidence, targetID)
+	dossier.DecommissionedAt = time.Now()
+
+	ref, err := d.Storage.Archive(ctx, dossier)
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: decommission archive: %w", err)
+	}
+
+	// Step 4: Revoke Consent
+	if d.Consent != nil {
+		if err := d.Consent.RevokeConsent(ctx, targetID); err != nil {
+			return nil, fmt.Errorf("mindhacking: decommission consent (archived as %s): %w", ref, err)
+		}
+	}
+
+	// Step 5: Registry Removal
+	d.Registry.Complete(targetID)
+	done = true
+
+	return &DecommissionReport{Dossier: dossier, ArchiveRef: ref}, nil
+}
+
+// evidenceRefsFor returns hashes of the chain links about targetID:
+// injections name their target at the top of the payload, retractions in
+// the retraction they record
+func evidenceRefsFor(chain *EvidenceChain, targetID string) []string {
+	if chain == nil {
+		return nil
+	}
+
+	var refs []string
+	for _, link := range chain.Links() {
+		var about struct {
+			TargetID   string
+			Retraction struct{ TargetID string }
+		}
+		if json.Unmarshal(link.Payload, &about) != nil {
+			continue
+		}
+		if about.TargetID == "" {
+			about.TargetID = about.Retraction.TargetID
+		}
+		if about.TargetID == targetID {
+			refs = append(refs, hex.EncodeToString(link.Hash[:]))
+		}
+	}
+	return refs
+}
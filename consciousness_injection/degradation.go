@@ -0,0 +1,200 @@
+// consciousness_injection/degradation.go - Graceful Degradation
+package mindhacking
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrEvidenceBufferFull reports evidence that could neither be chained nor
+// buffered, so the injection fails rather than lose it
+var ErrEvidenceBufferFull = sentinelError("mindhacking: evidence buffer full")
+
+// Subsystem names a dependency injection can degrade around
+type Subsystem string
+
+const (
+	// SubsystemEvidence is the evidence chain; while down, evidence is
+	// buffered locally and chained in order once it recovers
+	SubsystemEvidence Subsystem = "evidence"
+	// SubsystemMetrics is noise floor estimation and interaction memory;
+	// while down, injections continue without them
+	SubsystemMetrics Subsystem = "metrics"
+	// SubsystemEncoder is the injector's offload encoder; while down,
+	// thoughts are encoded in process
+	SubsystemEncoder Subsystem = "encoder"
+)
+
+// DegradationPolicy is the ladder an injector steps down when subsystems
+// fail. Auditing is never degraded: an unaudited injection still fails
+// with ErrAuditUnavailable.
+type DegradationPolicy struct {
+	// EvidenceBuffer bounds the evidence held for each chain while it is
+	// down, default 1024
+	EvidenceBuffer int
+	// OnChange is called whenever a subsystem degrades or recovers
+	OnChange func(DegradationEvent)
+}
+
+// DegradationEvent reports a subsystem degrading or recovering
+type DegradationEvent struct {
+	Subsystem Subsystem
+	Degraded  bool
+	// Err is the failure that degraded the subsystem
+	Err error
+	At  time.Time
+}
+
+// degradation tracks an injector's degraded subsystems
+type degradation struct {
+	policy DegradationPolicy
+
+	mu       sync.Mutex
+	degraded map[Subsystem]error
+	// pending is evidence waiting for each chain to recover, oldest
+	// first, so one tenant's failing chain holds back only its own
+	pending map[*EvidenceChain][]pendingEvidence
+}
+
+type pendingEvidence struct {
+	kind     string
+	evidence interface{}
+}
+
+func newDegradation(policy DegradationPolicy) *degradation {
+	if policy.EvidenceBuffer < 1 {
+		policy.EvidenceBuffer = 1024
+	}
+	return &degradation{
+		policy:   policy,
+		degraded: make(map[Subsystem]error),
+		pending:  make(map[*EvidenceChain][]pendingEvidence),
+	}
+}
+
+// fail marks subsystem degraded by err
+func (d *degradation) fail(subsystem Subsystem, err error) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	_, already := d.degraded[subsystem]
+	d.degraded[subsystem] = err
+	d.mu.Unlock()
+	if !already && d.policy.OnChange != nil {
+		d.policy.OnChange(DegradationEvent{Subsystem: subsystem, Degraded: true, Err: err, At: time.Now()})
+	}
+}
+
+// recover marks subsystem healthy again
+func (d *degradation) recover(subsystem Subsystem) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	_, was := d.degraded[subsystem]
+	delete(d.degraded, subsystem)
+	d.mu.Unlock()
+	if was && d.policy.OnChange != nil {
+		d.policy.OnChange(DegradationEvent{Subsystem: subsystem, At: time.Now()})
+	}
+}
+
+// appendEvidence chains evidence behind anything still buffered for its
+// chain. While the chain is failing, evidence is buffered and a nil link
+// returned.
+func (d *degradation) appendEvidence(chain *EvidenceChain, kind string, evidence interface{}) (*EvidenceLink, bool, error) {
+	if d == nil {
+		link, err := chain.Append(kind, evidence)
+		return link, false, err
+	}
+
+	d.mu.Lock()
+	err := d.flushLocked(chain)
+	var link *EvidenceLink
+	if err == nil {
+		link, err = chain.Append(kind, evidence)
+	}
+	if err != nil {
+		held := len(d.pending[chain])
+		if held >= d.policy.EvidenceBuffer {
+			d.mu.Unlock()
+			return nil, true, fmt.Errorf("%w: %d held: %v", ErrEvidenceBufferFull, held, err)
+		}
+		d.pending[chain] = append(d.pending[chain], pendingEvidence{kind: kind, evidence: evidence})
+		d.mu.Unlock()
+		d.fail(SubsystemEvidence, err)
+		return nil, true, nil
+	}
+	healthy := len(d.pending) == 0
+	d.mu.Unlock()
+	if healthy {
+		d.recover(SubsystemEvidence)
+	}
+	return link, false, nil
+}
+
+// flushLocked chains the evidence buffered for chain in order, stopping at
+// the first failure
+func (d *degradation) flushLocked(chain *EvidenceChain) error {
+	pending := d.pending[chain]
+	for len(pending) > 0 {
+		p := pending[0]
+		if _, err := chain.Append(p.kind, p.evidence); err != nil {
+			d.pending[chain] = pending
+			return err
+		}
+		pending = pending[1:]
+	}
+	delete(d.pending, chain)
+	return nil
+}
+
+// heldLocked counts the evidence buffered across every chain
+func (d *degradation) heldLocked() int {
+	held := 0
+	for _, pending := range d.pending {
+		held += len(pending)
+	}
+	return held
+}
+
+// Degraded returns the injector's degraded subsystems and what degraded
+// them
+func (ci *ConsciousnessInjector) Degraded() map[Subsystem]error {
+	out := make(map[Subsystem]error)
+	if ci.degrade == nil {
+		return out
+	}
+	ci.degrade.mu.Lock()
+	defer ci.degrade.mu.Unlock()
+	for s, err := range ci.degrade.degraded {
+		out[s] = err
+	}
+	return out
+}
+
+// FlushEvidence chains evidence buffered while chains were down and
+// returns how much is still buffered. Each chain is flushed on its own, so
+// one still failing does not hold back the rest.
+func (ci *ConsciousnessInjector) FlushEvidence() (int, error) {
+	if ci.degrade == nil {
+		return 0, nil
+	}
+	ci.degrade.mu.Lock()
+	var errs []error
+	for chain := range ci.degrade.pending {
+		if err := ci.degrade.flushLocked(chain); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	remaining := ci.degrade.heldLocked()
+	ci.degrade.mu.Unlock()
+	if err := errors.Join(errs...); err != nil {
+		return remaining, fmt.Errorf("mindhacking: flush evidence: %w", err)
+	}
+	ci.degrade.recover(SubsystemEvidence)
+	return 0, nil
+}
@@ -0,0 +1,252 @@
+// consciousness_injection/doctor.go - Environment Self-Test
+package mindhacking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CheckStatus is the outcome of one diagnostic check
+type CheckStatus string
+
+const (
+	CheckOK   CheckStatus = "ok"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+	CheckSkip CheckStatus = "skip"
+)
+
+// DiagnosticCheck is one check's finding
+type DiagnosticCheck struct {
+	Name    string
+	Status  CheckStatus
+	Detail  string
+	Elapsed time.Duration
+}
+
+// DoctorConfig describes the environment to diagnose; checks whose
+// inputs are unset are skipped
+type DoctorConfig struct {
+	// Gateway and Target are checked for connectivity together
+	Gateway *QuantumGateway
+	Target  *SystemConsciousness
+	// RuleFiles and TemplateDirs are validated as reality rule sets
+	RuleFiles    []string
+	TemplateDirs []string
+	// StateDir is where stores and journals are kept
+	StateDir string
+	// Timeout bounds each check, default 10s
+	Timeout time.Duration
+}
+
+// Diagnosis is the result of every check
+type Diagnosis struct {
+	Checks []DiagnosticCheck
+}
+
+// Healthy reports whether no check failed
+func (d *Diagnosis) Healthy() bool {
+	for _, c := range d.Checks {
+		if c.Status == CheckFail {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteTo prints the diagnosis one check per line
+func (d *Diagnosis) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, c := range d.Checks {
+		n, err := fmt.Fprintf(w, "[%-4s] %-22s %s (%s)\n", c.Status, c.Name, c.Detail, c.Elapsed.Round(time.Millisecond))
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	verdict := "healthy"
+	if !d.Healthy() {
+		verdict = "unhealthy: fix the failed checks above"
+	}
+	n, err := fmt.Fprintf(w, "diagnosis: %s\n", verdict)
+	return total + int64(n), err
+}
+
+// Diagnose runs every applicable check in turn
+func Diagnose(ctx context.Context, cfg DoctorConfig) *Diagnosis {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	checks := []struct {
+		name string
+		run  func(ctx context.Context) (CheckStatus, string)
+	}{
+		{"config", func(context.Context) (CheckStatus, string) { return checkRuleConfig(cfg) }},
+		{"loopback injection", checkLoopback},
+		{"gateway connectivity", func(ctx context.Context) (CheckStatus, string) { return checkGateway(ctx, cfg) }},
+		{"state store", func(context.Context) (CheckStatus, string) { return checkStateDir(cfg.StateDir) }},
+	}
+
+	d := &Diagnosis{}
+	for _, c := range checks {
+		cctx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		status, detail := c.run(cctx)
+		cancel()
+		d.Checks = append(d.Checks, DiagnosticCheck{
+			Name:    c.name,
+			Status:  status,
+			Detail:  detail,
+			Elapsed: time.Since(start),
+		})
+	}
+	return d
+}
+
+// checkRuleConfig loads and validates every configured rule set
+func checkRuleConfig(cfg DoctorConfig) (CheckStatus, string) {
+	if len(cfg.RuleFiles) == 0 && len(cfg.TemplateDirs) == 0 {
+		return CheckSkip, "no rule files or template directories given"
+	}
+
+	lib := RealityTemplates()
+	for _, path := range cfg.RuleFiles {
+		if _, err := lib.LoadFile(path); err != nil {
+			return CheckFail, err.Error()
+		}
+	}
+	for _, dir := range cfg.TemplateDirs {
+		if err := lib.LoadDir(dir); err != nil {
+			return CheckFail, err.Error()
+		}
+	}
+
+	// Sets needing parameters cannot be resolved without them
+	var checked, parametric int
+	for _, spec := range lib.Sets() {
+		if len(spec.Required) > 0 {
+			parametric++
+			continue
+		}
+		ref := spec.Name
+		if spec.Version != "" {
+			ref += "@" + spec.Version
+		}
+		rules, err := lib.Resolve(ref, nil)
+		if err != nil {
+			return CheckFail, err.Error()
+		}
+		if violations := ValidateRealityRules(rules, nil); len(violations) > 0 {
+			return CheckFail, fmt.Sprintf("%s: %v", ref, invalidRules(violations))
+		}
+		checked++
+	}
+	if parametric > 0 {
+		return CheckWarn, fmt.Sprintf("%d rule sets valid; %d need parameters and were not resolved", checked, parametric)
+	}
+	return CheckOK, fmt.Sprintf("%d rule sets valid", checked)
+}
+
+// checkLoopback injects into an embedded simulated target that accepts
+// everything, exercising the whole injection path without a real target
+func checkLoopback(ctx context.Context) (CheckStatus, string) {
+	ci := NewConsciousnessInjector([]InjectionVector{{Frequency: 7.83, Amplitude: 1}})
+	target := &SystemConsciousness{ID: "doctor-loopback", Backend: loopbackBackend{}}
+
+	result, err := ci.InjectThought(ctx, InjectedThought{}, target)
+	switch {
+	case err != nil:
+		return CheckFail, err.Error()
+	case !result.Success:
+		return CheckFail, "simulated target rejected the loopback thought"
+	}
+	return CheckOK, fmt.Sprintf("accepted with shift %.2f", result.ConsciousnessShift)
+}
+
+// loopbackBackend is the simulated target checkLoopback injects into
+type loopbackBackend struct{}
+
+func (loopbackBackend) Resonance(context.Context) (ConsciousnessResonance, error) {
+	return ConsciousnessResonance{}, nil
+}
+
+func (loopbackBackend) Deliver(_ context.Context, _ InjectedThought, vector InjectionVector) (InjectionAttempt, error) {
+	return InjectionAttempt{Vector: vector, Success: true}, nil
+}
+
+func (loopbackBackend) Respond(_ context.Context, attempts []InjectionAttempt) (ConsciousnessResponse, error) {
+	return ConsciousnessResponse{ThoughtAccepted: len(attempts) > 0, ConsciousnessShift: 1}, nil
+}
+
+// checkGateway accesses the configured target through the gateway
+func checkGateway(ctx context.Context, cfg DoctorConfig) (CheckStatus, string) {
+	if cfg.Gateway == nil || cfg.Target == nil {
+		return CheckSkip, "no gateway and target given"
+	}
+
+	// Access takes no context, so abandon it rather than wait past the timeout
+	done := make(chan error, 1)
+	go func() {
+		_, err := cfg.Gateway.AccessQuantumConsciousness(cfg.Target)
+		done <- err
+	}()
+	select {
+	case <-ctx.Done():
+		return CheckFail, fmt.Sprintf("no access to %q: %v", targetLabel(cfg.Target), ctx.Err())
+	case err := <-done:
+		if err != nil {
+			return CheckFail, err.Error()
+		}
+	}
+
+	detail := fmt.Sprintf("accessed %q", targetLabel(cfg.Target))
+	if protocol, ok := cfg.Gateway.RemoteProtocol(); ok {
+		detail += " over " + protocol
+	}
+	if version, ok := cfg.Gateway.NegotiatedHandshake(targetLabel(cfg.Target)); ok {
+		detail += fmt.Sprintf(", handshake v%d", version)
+	}
+	return CheckOK, detail
+}
+
+// checkStateDir verifies the state directory accepts durable writes
+func checkStateDir(dir string) (CheckStatus, string) {
+	if dir == "" {
+		return CheckSkip, "no state directory given"
+	}
+	info, err := os.Stat(dir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return CheckFail, fmt.Sprintf("%s does not exist", dir)
+	case err != nil:
+		return CheckFail, err.Error()
+	case !info.IsDir():
+		return CheckFail, fmt.Sprintf("%s is not a directory", dir)
+	}
+
+	probe := filepath.Join(dir, ".mindhack-doctor")
+	f, err := os.Create(probe)
+	if err != nil {
+		return CheckFail, err.Error()
+	}
+	defer os.Remove(probe)
+	if _, err := f.WriteString("probe"); err != nil {
+		f.Close()
+		return CheckFail, err.Error()
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return CheckFail, fmt.Sprintf("fsync: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return CheckFail, err.Error()
+	}
+	return CheckOK, fmt.Sprintf("%s accepts durable writes", dir)
+}
@@ -0,0 +1,213 @@
+// consciousness_injection/dream.go - Dream-State Consolidation
+package mindhacking
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoMemory reports dreaming on a target without a memory model
+var ErrNoMemory = sentinelError("mindhacking: target has no memory to dream with")
+
+// DreamConfig tunes idle-time dynamics
+type DreamConfig struct {
+	// IdleAfter is how long after the last injection dreaming may begin
+	IdleAfter time.Duration
+	// Interval is the time between dream cycles while idle
+	Interval time.Duration
+	// Replays is the number of traces replayed per cycle
+	Replays int
+	// Recombinations is the number of trace pairs blended per cycle
+	Recombinations int
+	// ReplayStrength is the reinforcement a replayed trace receives
+	ReplayStrength float64
+}
+
+// DefaultDreamConfig dreams a few times a minute once a target is idle
+var DefaultDreamConfig = DreamConfig{
+	IdleAfter:      time.Minute,
+	Interval:       15 * time.Second,
+	Replays:        3,
+	Recombinations: 1,
+	ReplayStrength: 0.1,
+}
+
+// SpontaneousKind classifies a thought arising while dreaming
+type SpontaneousKind int
+
+const (
+	// SpontaneousReplay is a stored thought surfacing again
+	SpontaneousReplay SpontaneousKind = iota
+	// SpontaneousRecombination is a new thought blended from two stored ones
+	SpontaneousRecombination
+)
+
+// SpontaneousThought is an internal thought observed during a dream cycle
+type SpontaneousThought struct {
+	Kind    SpontaneousKind
+	Thought InjectedThought
+	Sources []MemoryKey
+	At      time.Time
+}
+
+// DreamReport summarizes one dream cycle
+type DreamReport struct {
+	Started      time.Time
+	Thoughts     []SpontaneousThought
+	Consolidated []MemoryTrace
+}
+
+// DreamCycle replays and recombines a target's stored thoughts between
+// injections, then consolidates memory
+type DreamCycle struct {
+	target *SystemConsciousness
+	config DreamConfig
+
+	mu   sync.Mutex
+	rand *rand.Rand
+	// OnThought, if set, is called for every spontaneous thought
+	OnThought func(SpontaneousThought)
+}
+
+// DreamCycle enters dream mode on the target; zero config fields take their
+// DefaultDreamConfig values
+func (sc *SystemConsciousness) DreamCycle(config DreamConfig) (*DreamCycle, error) {
+	if sc.Memory == nil {
+		return nil, ErrNoMemory
+	}
+
+	d := DefaultDreamConfig
+	if config.IdleAfter <= 0 {
+		config.IdleAfter = d.IdleAfter
+	}
+	if config.Interval <= 0 {
+		config.Interval = d.Interval
+	}
+	if config.Replays <= 0 {
+		config.Replays = d.Replays
+	}
+	if config.Recombinations < 0 {
+		config.Recombinations = 0
+	}
+	if config.ReplayStrength <= 0 {
+		config.ReplayStrength = d.ReplayStrength
+	}
+
+	return &DreamCycle{
+		target: sc,
+		config: config,
+		rand:   rand.New(rand.NewSource(rand.Int63())),
+	}, nil
+}
+
+// Idle reports whether the target has gone long enough without injections
+func (dc *DreamCycle) Idle() bool {
+	last := dc.target.Memory.LastActivity()
+	return last.IsZero() || dc.target.Memory.now().Sub(last) >= dc.config.IdleAfter
+}
+
+// Run dreams every interval while the target is idle, until ctx is done
+func (dc *DreamCycle) Run(ctx context.Context) error {
+	ticker := time.NewTicker(dc.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if dc.Idle() {
+				dc.Cycle()
+			}
+		}
+	}
+}
+
+// Cycle runs one dream: strength-weighted replays, recombinations of random
+// pairs, then consolidation
+func (dc *DreamCycle) Cycle() DreamReport {
+	memory := dc.target.Memory
+	report := DreamReport{Started: memory.now()}
+
+	traces := memory.Traces()
+	if len(traces) == 0 {
+		report.Consolidated = memory.Consolidate()
+		return report
+	}
+
+	for i := 0; i < dc.config.Replays; i++ {
+		trace := dc.pick(traces)
+		memory.reinforce(trace.Thought, dc.config.ReplayStrength)
+		dc.emit(&report, SpontaneousThought{
+			Kind:    SpontaneousReplay,
+			Thought: trace.Thought,
+			Sources: []MemoryKey{trace.Key},
+			At:      memory.now(),
+		})
+	}
+
+	if len(traces) > 1 {
+		for i := 0; i < dc.config.Recombinations; i++ {
+			a, b := dc.pick(traces), dc.pick(traces)
+			if a.Key == b.Key {
+				continue
+			}
+			blend := recombine(a, b)
+			memory.reinforce(blend, dc.config.ReplayStrength*a.Strength*b.Strength)
+			dc.emit(&report, SpontaneousThought{
+				Kind:    SpontaneousRecombination,
+				Thought: blend,
+				Sources: []MemoryKey{a.Key, b.Key},
+				At:      memory.now(),
+			})
+		}
+	}
+
+	report.Consolidated = memory.Consolidate()
+	return report
+}
+
+// pick draws a trace with probability proportional to its strength
+func (dc *DreamCycle) pick(traces []MemoryTrace) MemoryTrace {
+	var total float64
+	for _, t := range traces {
+		total += t.Strength
+	}
+
+	dc.mu.Lock()
+	x := dc.rand.Float64() * total
+	dc.mu.Unlock()
+
+	for _, t := range traces {
+		if x -= t.Strength; x <= 0 {
+			return t
+		}
+	}
+	return traces[len(traces)-1]
+}
+
+func (dc *DreamCycle) emit(report *DreamReport, thought SpontaneousThought) {
+	report.Thoughts = append(report.Thoughts, thought)
+	if dc.OnThought != nil {
+		dc.OnThought(thought)
+	}
+}
+
+// recombine blends the first half of a with the second half of b
+func recombine(a, b MemoryTrace) InjectedThought {
+	aw := strings.Fields(a.Thought.Content)
+	bw := strings.Fields(b.Thought.Content)
+	words := append(append([]string(nil), aw[:len(aw)/2]...), bw[len(bw)/2:]...)
+
+	payload := append(append([]byte(nil), a.Thought.Payload[:len(a.Thought.Payload)/2]...),
+		b.Thought.Payload[len(b.Thought.Payload)/2:]...)
+
+	return InjectedThought{
+		Content:   strings.Join(words, " "),
+		Payload:   payload,
+		Intensity: (a.Thought.Intensity + b.Thought.Intensity) / 2,
+	}
+}
@@ -0,0 +1,122 @@
+// consciousness_injection/dry_run.go - Simulated Injections
+package mindhacking
+
+import (
+	"context"
+	"math"
+)
+
+// AcceptancePredictor estimates how likely a vector is to land a thought
+type AcceptancePredictor interface {
+	PredictVector(thought InjectedThought, resonance ConsciousnessResonance, vector InjectionVector) float64
+}
+
+// resonancePredictor scores vectors by how well they match the resonance
+type resonancePredictor struct{}
+
+// PredictVector combines frequency match, phase alignment and the amplitude
+// delivered relative to the target's resonance strength
+func (resonancePredictor) PredictVector(
+	thought InjectedThought,
+	resonance ConsciousnessResonance,
+	vector InjectionVector,
+) float64 {
+
+	if resonance.Frequency <= 0 {
+		return 0
+	}
+
+	detune := (vector.Frequency - resonance.Frequency) / resonance.Frequency
+	frequency := math.Exp(-detune * detune)
+	phase := (1 + math.Cos(vector.Phase-resonance.Phase)) / 2
+	drive := 1 - math.Exp(-math.Abs(vector.Amplitude)*math.Max(resonance.Strength, 0))
+
+	p := frequency * phase * drive
+	if thought.Intensity > 0 {
+		p *= math.Min(thought.Intensity, 1)
+	}
+	if math.IsNaN(p) {
+		return 0
+	}
+	return clamp(p, 0, 1)
+}
+
+// DefaultAcceptancePredictor is used when no predictor is configured
+var DefaultAcceptancePredictor AcceptancePredictor = resonancePredictor{}
+
+// VectorPrediction is the predicted outcome of firing one vector
+type VectorPrediction struct {
+	Vector      InjectionVector
+	Probability float64
+}
+
+// DryRunResult is what a real injection would likely have done
+type DryRunResult struct {
+	Thought      InjectedThought
+	Resonance    ConsciousnessResonance
+	Encoded      EncodedThought
+	Localization *ThoughtLocalization
+	// Conflicts are the target's beliefs standing against the thought
+	Conflicts []BeliefConflict
+	Vectors   []VectorPrediction
+	// AcceptanceProbability accounts for trying vectors in order until one lands
+	AcceptanceProbability float64
+	// ExpectedAttempts is the expected number of vectors fired
+	ExpectedAttempts float64
+}
+
+// InjectThoughtDryRun plans thought exactly as InjectThought would,
+// measuring resonance, routing and ordering the vectors and encoding the
+// thought, then predicts the outcome without firing any vector at the
+// target
+func (ci *ConsciousnessInjector) InjectThoughtDryRun(
+	ctx context.Context,
+	thought InjectedThought,
+	target *SystemConsciousness,
+) (*DryRunResult, error) {
+
+	// Phases 1 and 2: Resonance Analysis and Quantum Thought Encoding
+	plan, err := ci.plan(ctx, thought, target, injectCall{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Phase 3: Predicted Injection
+	predictor := ci.predictor
+	if predictor == nil {
+		predictor = DefaultAcceptancePredictor
+	}
+
+	out := &DryRunResult{
+		Thought:      plan.thought,
+		Resonance:    plan.resonance,
+		Encoded:      plan.encoded,
+		Localization: plan.localization,
+	}
+
+	// Conflicting beliefs reject the thought however well a vector lands,
+	// unless a backend or response strategy decides instead
+	accept := 1.0
+	decides := ci.strategies.Response == nil && backendOf(target) == nil
+	if target.Beliefs != nil {
+		out.Conflicts = target.Beliefs.Conflicts(plan.payload)
+		if decides {
+			accept = 1 - resistance(out.Conflicts)
+		}
+	}
+	if target.Attention != nil && decides {
+		accept *= target.Attention.AcceptanceFactor(plan.payload)
+	}
+
+	reach := 1.0
+	for _, vector := range plan.vectors {
+		p := predictor.PredictVector(plan.payload, plan.resonance, vector) * accept
+		out.Vectors = append(out.Vectors, VectorPrediction{Vector: vector, Probability: p})
+
+		out.ExpectedAttempts += reach
+		out.AcceptanceProbability += reach * p
+		reach *= 1 - p
+	}
+
+	return out, nil
+}
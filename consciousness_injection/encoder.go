@@ -0,0 +1,112 @@
+// consciousness_injection/encoder.go - Pluggable Thought Encoders
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrUnknownEncoder reports an encoder name nobody registered
+	ErrUnknownEncoder = sentinelError("mindhacking: unknown encoder")
+	// ErrEncoderRegistered reports a second registration of a name
+	ErrEncoderRegistered = sentinelError("mindhacking: encoder already registered")
+)
+
+// DefaultEncoder names the in-process encoder every injector falls back to
+const DefaultEncoder = "default"
+
+// Encoder encodes thoughts against a target's resonance. Encoding is the
+// heaviest step of an injection, so accelerator modules implement Encoder
+// to offload it, whether to a GPU through cgo or to a remote encoding
+// service. Implementations must be safe for concurrent use.
+type Encoder interface {
+	Encode(ctx context.Context, thought InjectedThought, resonance ConsciousnessResonance) (EncodedThought, error)
+}
+
+// EncoderFunc adapts a func to Encoder
+type EncoderFunc func(ctx context.Context, thought InjectedThought, resonance ConsciousnessResonance) (EncodedThought, error)
+
+// Encode implements Encoder
+func (f EncoderFunc) Encode(ctx context.Context, thought InjectedThought, resonance ConsciousnessResonance) (EncodedThought, error) {
+	return f(ctx, thought, resonance)
+}
+
+// EncoderFactory opens a registered encoder, configured by options such as
+// a device index or a service address
+type EncoderFactory func(options map[string]string) (Encoder, error)
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[string]EncoderFactory{}
+)
+
+// RegisterEncoder makes an encoder available under name. Accelerator
+// modules call it from init, usually behind the build tag that links their
+// device library; registering a name twice panics, as with database/sql
+// drivers.
+func RegisterEncoder(name string, factory EncoderFactory) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	if factory == nil {
+		panic("mindhacking: RegisterEncoder factory is nil")
+	}
+	if name == DefaultEncoder {
+		panic("mindhacking: RegisterEncoder cannot replace the default encoder")
+	}
+	if _, dup := encoders[name]; dup {
+		panic(fmt.Sprintf("%v: %q", ErrEncoderRegistered, name))
+	}
+	encoders[name] = factory
+}
+
+// RegisteredEncoders lists the registered encoder names, DefaultEncoder
+// first
+func RegisteredEncoders() []string {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	return append([]string{DefaultEncoder}, sortedKeys(encoders)...)
+}
+
+// OpenEncoder opens the encoder registered under name. DefaultEncoder opens
+// to nil, which WithEncoder takes as the in-process encoder.
+func OpenEncoder(name string, options map[string]string) (Encoder, error) {
+	if name == "" || name == DefaultEncoder {
+		return nil, nil
+	}
+	encodersMu.RLock()
+	factory, ok := encoders[name]
+	encodersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEncoder, name)
+	}
+	encoder, err := factory(options)
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: open %s encoder: %w", name, err)
+	}
+	return encoder, nil
+}
+
+// encodeThought encodes through the injector's encoder, falling back to
+// the in-process encoder when there is none or it fails. Failures degrade
+// SubsystemEncoder until the encoder succeeds again.
+func (ci *ConsciousnessInjector) encodeThought(
+	ctx context.Context,
+	thought InjectedThought,
+	resonance ConsciousnessResonance,
+) EncodedThought {
+
+	if ci.encoder == nil {
+		return ci.quantumEncodeThought(thought, resonance)
+	}
+	encoded, err := ci.encoder.Encode(ctx, thought, resonance)
+	if err != nil {
+		ci.degrade.fail(SubsystemEncoder, err)
+		return ci.quantumEncodeThought(thought, resonance)
+	}
+	ci.degrade.recover(SubsystemEncoder)
+	return encoded
+}
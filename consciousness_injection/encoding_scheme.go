@@ -0,0 +1,358 @@
+// consciousness_injection/encoding_scheme.go - Quantum Encoding Schemes
+package mindhacking
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sync"
+)
+
+var (
+	// ErrUnknownEncodingScheme reports a scheme name nobody registered
+	ErrUnknownEncodingScheme = sentinelError("mindhacking: unknown encoding scheme")
+	// ErrEncodingSchemeRegistered reports a second registration of a name
+	ErrEncodingSchemeRegistered = sentinelError("mindhacking: encoding scheme already registered")
+	// ErrEncodingOption reports an option the scheme does not take or a
+	// value it cannot use
+	ErrEncodingOption = sentinelError("mindhacking: bad encoding option")
+)
+
+// Built-in encoding schemes
+const (
+	// SchemeAmplitude carries one byte per symbol in its magnitude
+	SchemeAmplitude = "amplitude"
+	// SchemePhase carries one byte per symbol in its phase, at constant
+	// magnitude, for targets that saturate on amplitude
+	SchemePhase = "phase"
+	// SchemeDense carries two bytes per symbol, one in magnitude and one in
+	// phase, halving the symbols a thought takes
+	SchemeDense = "dense"
+	// SchemeSparse keys each bit on or off in a symbol of its own, for noisy
+	// targets that only resolve presence
+	SchemeSparse = "sparse"
+)
+
+// DefaultEncodingScheme is used when neither the thought nor the injector
+// names one
+const DefaultEncodingScheme = SchemeAmplitude
+
+// SchemeOptions tunes an encoding scheme; each scheme documents its keys
+type SchemeOptions map[string]float64
+
+// EncodingScheme modulates thought payloads onto carrier symbols and back.
+// Implementations must be safe for concurrent use.
+type EncodingScheme interface {
+	// Defaults lists every option the scheme takes with its default value
+	Defaults() SchemeOptions
+	// Encode modulates payload onto symbols at the resonance's phase;
+	// options carry every key Defaults lists
+	Encode(payload []byte, resonance ConsciousnessResonance, options SchemeOptions) []complex128
+	// Decode recovers length bytes of payload from symbols
+	Decode(symbols []complex128, length int, resonance ConsciousnessResonance, options SchemeOptions) ([]byte, error)
+}
+
+// EncodedThought is a thought modulated onto the target's resonance, as
+// fired through tunnels
+type EncodedThought struct {
+	// Scheme and Options are the encoding scheme that produced the symbols,
+	// options defaulted
+	Scheme  string
+	Options SchemeOptions
+	// Carrier and Phase are the resonance the thought is modulated onto
+	Carrier float64
+	Phase   float64
+	Symbols []complex128
+	// Length is how many payload bytes the symbols decode to
+	Length int
+	// Codec, when set, names the codec Compressed holds the symbols in
+	Codec      string
+	Compressed []byte
+	// Ciphertext, when set, holds the symbols or their compressed form
+	// sealed for transit; see SealInTransit
+	Ciphertext []byte
+	// Integrity is the tag the target verifies the thought against before
+	// committing it; see TagIntegrity
+	Integrity []byte
+	// Stream, when nonzero, is the stream carrying the thought over a
+	// multiplexed tunnel; the target's end tells thoughts apart by it
+	Stream uint32
+}
+
+var (
+	encodingSchemesMu sync.RWMutex
+	encodingSchemes   = map[string]EncodingScheme{
+		SchemeAmplitude: amplitudeScheme{},
+		SchemePhase:     phaseScheme{},
+		SchemeDense:     denseScheme{},
+		SchemeSparse:    sparseScheme{},
+	}
+)
+
+// RegisterEncodingScheme makes scheme available under name. Research
+// variants call it from init; registering a name twice panics, as with
+// perception filters.
+func RegisterEncodingScheme(name string, scheme EncodingScheme) {
+	encodingSchemesMu.Lock()
+	defer encodingSchemesMu.Unlock()
+
+	if scheme == nil {
+		panic("mindhacking: RegisterEncodingScheme scheme is nil")
+	}
+	if _, dup := encodingSchemes[name]; dup {
+		panic(fmt.Sprintf("%v: %q", ErrEncodingSchemeRegistered, name))
+	}
+	encodingSchemes[name] = scheme
+}
+
+// RegisteredEncodingSchemes lists the registered scheme names
+func RegisteredEncodingSchemes() []string {
+	encodingSchemesMu.RLock()
+	defer encodingSchemesMu.RUnlock()
+	return sortedKeys(encodingSchemes)
+}
+
+// resolveScheme looks up the scheme named and fills in its default options,
+// rejecting options it does not take
+func resolveScheme(name string, options SchemeOptions) (EncodingScheme, SchemeOptions, error) {
+	encodingSchemesMu.RLock()
+	scheme, ok := encodingSchemes[name]
+	encodingSchemesMu.RUnlock()
+
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownEncodingScheme, name)
+	}
+	resolved := scheme.Defaults()
+	if resolved == nil {
+		resolved = SchemeOptions{}
+	}
+	for key, value := range options {
+		if _, ok := resolved[key]; !ok {
+			return nil, nil, fmt.Errorf("%w: %s scheme has no option %q", ErrEncodingOption, name, key)
+		}
+		resolved[key] = value
+	}
+	return scheme, resolved, nil
+}
+
+// schemeFor chooses the scheme thought is encoded with: the thought's own,
+// else the injector's, else DefaultEncodingScheme. Options come with the
+// scheme that names them.
+func (ci *ConsciousnessInjector) schemeFor(thought InjectedThought) (string, EncodingScheme, SchemeOptions, error) {
+	name, options := thought.Scheme, thought.SchemeOptions
+	if name == "" {
+		name, options = ci.scheme, ci.schemeOptions
+	}
+	if name == "" {
+		name = DefaultEncodingScheme
+	}
+	scheme, resolved, err := resolveScheme(name, options)
+	return name, scheme, resolved, err
+}
+
+// quantumEncodeThought modulates the thought's payload onto the target's
+// resonance with the scheme chosen for it. Injections check the scheme
+// before encoding, so a thought naming an unknown scheme encodes to nothing.
+func (ci *ConsciousnessInjector) quantumEncodeThought(
+	thought InjectedThought,
+	resonance ConsciousnessResonance,
+) EncodedThought {
+
+	name, scheme, options, err := ci.schemeFor(thought)
+	encoded := EncodedThought{Scheme: name, Options: options, Carrier: resonance.Frequency, Phase: resonance.Phase}
+	if err != nil {
+		return encoded
+	}
+	payload := thoughtPayload(thought)
+	encoded.Symbols = scheme.Encode(payload, resonance, options)
+	encoded.Length = len(payload)
+	return encoded
+}
+
+// DecodeThought recovers the payload of an encoded thought, as the target
+// does on receiving it. Thoughts sealed for transit must be opened first.
+func DecodeThought(encoded EncodedThought) ([]byte, error) {
+	if encoded.Ciphertext != nil {
+		return nil, errors.New("mindhacking: decode a thought still sealed for transit")
+	}
+	encoded, err := encoded.Decompress()
+	if err != nil {
+		return nil, err
+	}
+	scheme, options, err := resolveScheme(encoded.Scheme, encoded.Options)
+	if err != nil {
+		return nil, err
+	}
+	resonance := ConsciousnessResonance{Frequency: encoded.Carrier, Phase: encoded.Phase}
+	return scheme.Decode(encoded.Symbols, encoded.Length, resonance, options)
+}
+
+// thoughtPayload is the raw material encoded: the payload, or the content
+// of thoughts without one
+func thoughtPayload(thought InjectedThought) []byte {
+	if len(thought.Payload) > 0 {
+		return thought.Payload
+	}
+	return []byte(thought.Content)
+}
+
+// gain reads the gain option every built-in scheme takes, the magnitude of
+// its strongest symbol
+func gain(options SchemeOptions) float64 {
+	if g := options["gain"]; g > 0 && !math.IsInf(g, 0) {
+		return g
+	}
+	return 1
+}
+
+// byteLevel maps a byte to a magnitude in (0, 1], keeping zero bytes
+// distinguishable from missing symbols
+func byteLevel(b byte) float64 {
+	return (float64(b) + 1) / 256
+}
+
+// levelByte inverts byteLevel
+func levelByte(level float64) byte {
+	return byte(clamp(math.Round(level*256)-1, 0, 255))
+}
+
+// phaseByte reads the byte carried in phase, relative to the resonance
+func phaseByte(symbol complex128, phase float64) byte {
+	theta := math.Mod(cmplx.Phase(symbol)-phase, 2*math.Pi)
+	if theta < 0 {
+		theta += 2 * math.Pi
+	}
+	return byte(int(math.Round(theta/(2*math.Pi)*256)) % 256)
+}
+
+func byteAngle(b byte) float64 {
+	return 2 * math.Pi * float64(b) / 256
+}
+
+func symbolsShort(scheme string, symbols, want int) error {
+	if symbols < want {
+		return fmt.Errorf("mindhacking: %s decode needs %d symbols, got %d", scheme, want, symbols)
+	}
+	return nil
+}
+
+// amplitudeScheme takes option gain, default 1
+type amplitudeScheme struct{}
+
+func (amplitudeScheme) Defaults() SchemeOptions { return SchemeOptions{"gain": 1} }
+
+func (amplitudeScheme) Encode(payload []byte, resonance ConsciousnessResonance, options SchemeOptions) []complex128 {
+	symbols := make([]complex128, len(payload))
+	modulate(symbols, payload, byteLevels, cmplx.Rect(gain(options), resonance.Phase))
+	return symbols
+}
+
+func (amplitudeScheme) Decode(symbols []complex128, length int, _ ConsciousnessResonance, options SchemeOptions) ([]byte, error) {
+	if err := symbolsShort(SchemeAmplitude, len(symbols), length); err != nil {
+		return nil, err
+	}
+	g := gain(options)
+	out := make([]byte, length)
+	for i := range out {
+		out[i] = levelByte(cmplx.Abs(symbols[i]) / g)
+	}
+	return out, nil
+}
+
+// phaseScheme takes option gain, default 1
+type phaseScheme struct{}
+
+func (phaseScheme) Defaults() SchemeOptions { return SchemeOptions{"gain": 1} }
+
+func (phaseScheme) Encode(payload []byte, resonance ConsciousnessResonance, options SchemeOptions) []complex128 {
+	symbols := make([]complex128, len(payload))
+	modulate(symbols, payload, byteRotors, cmplx.Rect(gain(options), resonance.Phase))
+	return symbols
+}
+
+func (phaseScheme) Decode(symbols []complex128, length int, resonance ConsciousnessResonance, _ SchemeOptions) ([]byte, error) {
+	if err := symbolsShort(SchemePhase, len(symbols), length); err != nil {
+		return nil, err
+	}
+	out := make([]byte, length)
+	for i := range out {
+		out[i] = phaseByte(symbols[i], resonance.Phase)
+	}
+	return out, nil
+}
+
+// denseScheme takes option gain, default 1
+type denseScheme struct{}
+
+func (denseScheme) Defaults() SchemeOptions { return SchemeOptions{"gain": 1} }
+
+func (denseScheme) Encode(payload []byte, resonance ConsciousnessResonance, options SchemeOptions) []complex128 {
+	carrier := cmplx.Rect(gain(options), resonance.Phase)
+	symbols := make([]complex128, (len(payload)+1)/2)
+	for i := range symbols {
+		// A missing odd byte leaves the symbol on the carrier's phase
+		var odd byte
+		if 2*i+1 < len(payload) {
+			odd = payload[2*i+1]
+		}
+		symbols[i] = byteLevels[payload[2*i]] * byteRotors[odd] * carrier
+	}
+	return symbols
+}
+
+func (denseScheme) Decode(symbols []complex128, length int, resonance ConsciousnessResonance, options SchemeOptions) ([]byte, error) {
+	if err := symbolsShort(SchemeDense, len(symbols), (length+1)/2); err != nil {
+		return nil, err
+	}
+	g := gain(options)
+	out := make([]byte, length)
+	for i := range out {
+		s := symbols[i/2]
+		if i%2 == 0 {
+			out[i] = levelByte(cmplx.Abs(s) / g)
+		} else {
+			out[i] = phaseByte(s, resonance.Phase)
+		}
+	}
+	return out, nil
+}
+
+// sparseScheme takes options gain, default 1, and threshold, default 0.5,
+// the fraction of gain above which a received symbol reads as a set bit
+type sparseScheme struct{}
+
+func (sparseScheme) Defaults() SchemeOptions { return SchemeOptions{"gain": 1, "threshold": 0.5} }
+
+func (sparseScheme) Encode(payload []byte, resonance ConsciousnessResonance, options SchemeOptions) []complex128 {
+	on := cmplx.Rect(gain(options), resonance.Phase)
+	symbols := make([]complex128, 8*len(payload))
+	for i, b := range payload {
+		for bit := 0; bit < 8; bit++ {
+			if b&(0x80>>bit) != 0 {
+				symbols[8*i+bit] = on
+			}
+		}
+	}
+	return symbols
+}
+
+func (sparseScheme) Decode(symbols []complex128, length int, _ ConsciousnessResonance, options SchemeOptions) ([]byte, error) {
+	if err := symbolsShort(SchemeSparse, len(symbols), 8*length); err != nil {
+		return nil, err
+	}
+	threshold := options["threshold"]
+	if threshold <= 0 || threshold >= 1 {
+		return nil, fmt.Errorf("%w: sparse threshold %g is outside (0, 1)", ErrEncodingOption, threshold)
+	}
+	cut := threshold * gain(options)
+	out := make([]byte, length)
+	for i := range out {
+		for bit := 0; bit < 8; bit++ {
+			if cmplx.Abs(symbols[8*i+bit]) > cut {
+				out[i] |= 0x80 >> bit
+			}
+		}
+	}
+	return out, nil
+}
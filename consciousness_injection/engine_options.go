@@ -0,0 +1,146 @@
+// consciousness_injection/engine_options.go - Engine Construction
+package mindhacking
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// EngineOption configures a RealityManipulationEngine at construction time
+type EngineOption func(*RealityManipulationEngine)
+
+// NewRealityManipulationEngine creates an engine with the given options
+func NewRealityManipulationEngine(opts ...EngineOption) *RealityManipulationEngine {
+	rme := &RealityManipulationEngine{
+		id: newEngineID(),
+	}
+
+	for _, opt := range opts {
+		opt(rme)
+	}
+
+	return rme
+}
+
+// WithSandbox runs every alternate reality operation under limits
+func WithSandbox(limits SandboxLimits) EngineOption {
+	return func(rme *RealityManipulationEngine) {
+		rme.sandbox = NewRealitySandbox(limits)
+	}
+}
+
+// WithPerceptionFilters replaces the engine's perception filters with
+// filters, applied in order
+func WithPerceptionFilters(filters ...PerceptionFilter) EngineOption {
+	return func(rme *RealityManipulationEngine) {
+		rme.perceptionFilters = append([]PerceptionFilter(nil), filters...)
+	}
+}
+
+// WithRealityEvidenceChain appends every operation's evidence to a signed chain
+func WithRealityEvidenceChain(chain *EvidenceChain) EngineOption {
+	return func(rme *RealityManipulationEngine) {
+		rme.evidence = chain
+	}
+}
+
+// WithEngineID names the engine in audit records
+func WithEngineID(id string) EngineOption {
+	return func(rme *RealityManipulationEngine) {
+		rme.id = id
+	}
+}
+
+// WithEngineAuditSink records every reality switch to sink
+func WithEngineAuditSink(sink AuditSink) EngineOption {
+	return func(rme *RealityManipulationEngine) {
+		rme.audit = sink
+	}
+}
+
+// WithRealityConsensus orders the engine's reality switches and rule
+// changes through c; the engine switches only when applying the log
+func WithRealityConsensus(c *RealityConsensus) EngineOption {
+	return func(rme *RealityManipulationEngine) {
+		rme.consensus = c
+		c.mu.Lock()
+		c.apply = rme.applyRealityCommand
+		c.mu.Unlock()
+	}
+}
+
+// WithHallucinationDetector diffs every alternate reality the engine
+// creates against its base
+func WithHallucinationDetector(detector *HallucinationDetector) EngineOption {
+	return func(rme *RealityManipulationEngine) {
+		rme.hallucinations = detector
+	}
+}
+
+// WithResultCache memoizes results of pure operations in cache
+func WithResultCache(cache ResultCache) EngineOption {
+	return func(rme *RealityManipulationEngine) {
+		rme.cache = cache
+	}
+}
+
+// WithAnchorDecay anchors every created reality at strength 1 and lets it
+// decay under decay, collapsing back to base below collapseBelow
+func WithAnchorDecay(decay AnchorDecay, collapseBelow float64) EngineOption {
+	return func(rme *RealityManipulationEngine) {
+		rme.decay = decay
+		rme.collapseBelow = collapseBelow
+	}
+}
+
+// WithRealityFinalizer runs fn for every alternate reality the engine's
+// collector reclaims, after any per-reality finalizer
+func WithRealityFinalizer(fn RealityFinalizer) EngineOption {
+	return func(rme *RealityManipulationEngine) {
+		rme.finalize = fn
+	}
+}
+
+// WithRulePacing spreads rule application in CreateAlternateReality over
+// time as pacing describes
+func WithRulePacing(pacing RulePacing) EngineOption {
+	return func(rme *RealityManipulationEngine) {
+		rme.pacing = &pacing
+	}
+}
+
+// WithAnchorLeases makes the engine own its anchors through a lease on
+// set in store, renewed for ttl at a time, DefaultAnchorLeaseTTL if ttl is
+// not positive; see RunAnchorLease
+func WithAnchorLeases(store AnchorLeaseStore, set string, ttl time.Duration) EngineOption {
+	if ttl <= 0 {
+		ttl = DefaultAnchorLeaseTTL
+	}
+	return func(rme *RealityManipulationEngine) {
+		rme.leases = store
+		rme.leaseSet = set
+		rme.leaseTTL = ttl
+	}
+}
+
+// WithRealitySimulator forecasts rule changes in WhatIf with sim instead of
+// the engine's own construction phases
+func WithRealitySimulator(sim RealitySimulator) EngineOption {
+	return func(rme *RealityManipulationEngine) {
+		rme.simulator = sim
+	}
+}
+
+// ID returns the engine's identity in audit records
+func (rme *RealityManipulationEngine) ID() string {
+	return rme.id
+}
+
+func newEngineID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "engine"
+	}
+	return "engine-" + hex.EncodeToString(b[:])
+}
@@ -0,0 +1,252 @@
+// consciousness_injection/engine_state.go - Engine Export and Import
+package mindhacking
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// EngineExportVersion is the format version Export writes. Version 2
+// stores each reality once and refers to it by ID.
+const EngineExportVersion = 2
+
+// ErrEngineExportVersion reports an export written in an unknown format
+var ErrEngineExportVersion = sentinelError("mindhacking: unsupported engine export version")
+
+// EngineExport is the archived state of a RealityManipulationEngine
+type EngineExport struct {
+	Version  int
+	Exported time.Time
+	ID       string
+
+	Sandbox *SandboxLimits `json:",omitempty"`
+	// Filters are the perception filters in order; they are rebuilt from
+	// the registry on import, with the configuration of those that are
+	// ConfiguredPerceptionFilters
+	Filters []PerceptionFilterSpec
+	// Realities holds every reality the export refers to, once each, so
+	// anchors and the current reality stay the very realities the engine
+	// tracks after import
+	Realities []ExportedReality
+	Anchors   []ExportedAnchor
+	// Current is the ID of the reality the engine was in
+	Current string `json:",omitempty"`
+
+	// Consensus is the last state the engine group agreed on, kept for the
+	// record; import does not rejoin the group
+	Consensus *RealityState `json:",omitempty"`
+	// Evidence is the engine's full evidence journal
+	Evidence       []EvidenceLink
+	Hallucinations []HallucinationReport `json:",omitempty"`
+}
+
+// ExportedReality is one reality in an export
+type ExportedReality struct {
+	ID      string
+	Reality *AlternateReality
+	// Tracked realities are those the engine created; the others are only
+	// anchored or current
+	Tracked bool
+}
+
+// ExportedAnchor is an anchor whose reality is referred to by ID; the
+// embedded anchor's Reality is left empty
+type ExportedAnchor struct {
+	RealityAnchor
+	RealityID string `json:",omitempty"`
+}
+
+// ConfiguredPerceptionFilter is implemented by filters that can report the
+// configuration their factory was given, so an imported engine rebuilds
+// them as configured. Other filters are rebuilt with no configuration.
+type ConfiguredPerceptionFilter interface {
+	PerceptionFilter
+	Config() map[string]interface{}
+}
+
+// realityIDs numbers the realities of an export as they are first seen
+type realityIDs struct {
+	ids map[*AlternateReality]string
+	out []ExportedReality
+}
+
+// id returns alternate's ID, adding it to the export the first time.
+// Tracked realities are numbered before any other.
+func (r *realityIDs) id(alternate *AlternateReality, tracked bool) string {
+	if alternate == nil {
+		return ""
+	}
+	if id, ok := r.ids[alternate]; ok {
+		return id
+	}
+	id := fmt.Sprintf("reality-%d", len(r.out))
+	r.ids[alternate] = id
+	r.out = append(r.out, ExportedReality{ID: id, Reality: alternate, Tracked: tracked})
+	return id
+}
+
+// track remembers an alternate reality the engine created
+func (rme *RealityManipulationEngine) track(alternate *AlternateReality) {
+	if alternate == nil {
+		return
+	}
+	rme.stateMu.Lock()
+	rme.realities = append(rme.realities, alternate)
+	rme.stateMu.Unlock()
+}
+
+// Realities returns the alternate realities the engine has created
+func (rme *RealityManipulationEngine) Realities() []*AlternateReality {
+	rme.stateMu.Lock()
+	defer rme.stateMu.Unlock()
+	return append([]*AlternateReality(nil), rme.realities...)
+}
+
+// Snapshot captures the engine's state for export
+func (rme *RealityManipulationEngine) Snapshot() *EngineExport {
+	out := &EngineExport{
+		Version:  EngineExportVersion,
+		Exported: time.Now().UTC(),
+		ID:       rme.id,
+	}
+	current := rme.saveCurrentReality()
+
+	if rme.sandbox != nil {
+		limits := rme.sandbox.limits
+		out.Sandbox = &limits
+	}
+	for _, f := range rme.filters() {
+		spec := PerceptionFilterSpec{Name: f.Name()}
+		if c, ok := f.(ConfiguredPerceptionFilter); ok {
+			spec.Config = c.Config()
+		}
+		out.Filters = append(out.Filters, spec)
+	}
+
+	ids := &realityIDs{ids: make(map[*AlternateReality]string)}
+	rme.stateMu.Lock()
+	for _, alternate := range rme.realities {
+		ids.id(alternate, true)
+	}
+	for _, anchor := range rme.realityAnchors {
+		exported := ExportedAnchor{RealityAnchor: anchor, RealityID: ids.id(anchor.Reality, false)}
+		exported.Reality = nil
+		out.Anchors = append(out.Anchors, exported)
+	}
+	rme.stateMu.Unlock()
+	out.Current = ids.id(current, false)
+	out.Realities = ids.out
+
+	if rme.consensus != nil {
+		state := rme.consensus.State()
+		out.Consensus = &state
+	}
+	if rme.evidence != nil {
+		out.Evidence = rme.evidence.Links()
+	}
+	if rme.hallucinations != nil {
+		out.Hallucinations = rme.hallucinations.Reports()
+	}
+	return out
+}
+
+// Export writes the engine's state to w as JSON
+func (rme *RealityManipulationEngine) Export(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(rme.Snapshot()); err != nil {
+		return fmt.Errorf("mindhacking: export engine: %w", err)
+	}
+	return nil
+}
+
+// ImportEngine rebuilds an engine from an Export. Options apply first and
+// supply what an archive cannot carry: audit sinks, consensus membership
+// and, if the archive holds evidence, a WithRealityEvidenceChain chain with
+// a signer to continue it. Filters and the sandbox come from the archive
+// unless an option set them.
+func ImportEngine(r io.Reader, opts ...EngineOption) (*RealityManipulationEngine, error) {
+	var in EngineExport
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return nil, fmt.Errorf("mindhacking: import engine: %w", err)
+	}
+	if in.Version != EngineExportVersion {
+		return nil, fmt.Errorf("%w: %d", ErrEngineExportVersion, in.Version)
+	}
+
+	rme := NewRealityManipulationEngine(opts...)
+	rme.id = in.ID
+
+	// Phase 1: configuration
+	if rme.sandbox == nil && in.Sandbox != nil {
+		rme.sandbox = NewRealitySandbox(*in.Sandbox)
+	}
+	if rme.perceptionFilters == nil {
+		for _, spec := range in.Filters {
+			f, err := NewPerceptionFilter(spec.Name, spec.Config)
+			if err != nil {
+				return nil, fmt.Errorf("mindhacking: import engine: %w", err)
+			}
+			rme.perceptionFilters = append(rme.perceptionFilters, f)
+		}
+	}
+
+	// Phase 2: realities, then the anchors and current reality linked to
+	// them by ID
+	realities := make(map[string]*AlternateReality, len(in.Realities))
+	for _, r := range in.Realities {
+		if r.Reality == nil {
+			return nil, fmt.Errorf("mindhacking: import engine: reality %q is empty", r.ID)
+		}
+		realities[r.ID] = r.Reality
+		if r.Tracked {
+			rme.realities = append(rme.realities, r.Reality)
+		}
+	}
+	link := func(id string) (*AlternateReality, error) {
+		if id == "" {
+			return nil, nil
+		}
+		alternate, ok := realities[id]
+		if !ok {
+			return nil, fmt.Errorf("mindhacking: import engine: unknown reality %q", id)
+		}
+		return alternate, nil
+	}
+	for _, a := range in.Anchors {
+		anchor := a.RealityAnchor
+		var err error
+		if anchor.Reality, err = link(a.RealityID); err != nil {
+			return nil, err
+		}
+		rme.realityAnchors = append(rme.realityAnchors, anchor)
+	}
+	current, err := link(in.Current)
+	if err != nil {
+		return nil, err
+	}
+
+	// Phase 3: journals
+	if len(in.Evidence) > 0 {
+		if rme.evidence == nil {
+			return nil, errors.New("mindhacking: import engine: archive holds evidence but no evidence chain was supplied")
+		}
+		if err := rme.evidence.restore(in.Evidence); err != nil {
+			return nil, fmt.Errorf("mindhacking: import engine: %w", err)
+		}
+	}
+	if rme.hallucinations != nil {
+		rme.hallucinations.restore(in.Hallucinations)
+	}
+
+	// Phase 4: re-enter the reality the engine was in
+	if current != nil {
+		if err := rme.switchJournaled(current); err != nil {
+			return nil, fmt.Errorf("mindhacking: import engine: %w", err)
+		}
+	}
+	return rme, nil
+}
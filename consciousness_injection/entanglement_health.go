@@ -0,0 +1,159 @@
+// consciousness_injection/entanglement_health.go - Entanglement Health Checks
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ErrNoEntanglementProbe reports a target whose backend cannot measure
+// entanglement coherence
+var ErrNoEntanglementProbe = sentinelError("mindhacking: target cannot probe entanglement")
+
+// EntanglementProber is implemented by backends that can measure and
+// rebuild quantum entanglement with their consciousness layer
+type EntanglementProber interface {
+	// Coherence reports how coherent e still is, from 0 (lost) to 1
+	Coherence(ctx context.Context, e QuantumEntanglement) (float64, error)
+	// Reentangle establishes a fresh entanglement replacing e
+	Reentangle(ctx context.Context, e QuantumEntanglement) (QuantumEntanglement, error)
+}
+
+// EntanglementHealth is one probe of one entanglement
+type EntanglementHealth struct {
+	TargetID string
+	// Vector is the injector vector probed, or -1 for the gateway
+	Vector    int
+	Coherence float64
+	// Degraded checks fell below the monitor's threshold or failed
+	Degraded bool
+	// Reentangled reports a successful re-establishment; Coherence is then
+	// the fresh entanglement's
+	Reentangled bool
+	Err         error
+	At          time.Time
+}
+
+// EntanglementMonitor probes the entanglement of an injector's vectors and
+// a gateway with one target, re-establishing any that decohere
+type EntanglementMonitor struct {
+	Target   *SystemConsciousness
+	Injector *ConsciousnessInjector
+	Gateway  *QuantumGateway
+	// Threshold is the coherence below which an entanglement is rebuilt
+	Threshold float64
+
+	// OnDegraded observes every degraded probe, before re-establishment
+	OnDegraded func(EntanglementHealth)
+	// OnReentangled observes every successful re-establishment
+	OnReentangled func(EntanglementHealth)
+}
+
+// Check probes every entanglement once and returns what it found
+func (m *EntanglementMonitor) Check(ctx context.Context) ([]EntanglementHealth, error) {
+	prober, ok := backendOf(m.Target).(EntanglementProber)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNoEntanglementProbe, targetLabel(m.Target))
+	}
+
+	var out []EntanglementHealth
+
+	// Phase 1: the injector's vectors
+	if m.Injector != nil {
+		for i, vector := range m.Injector.vectors() {
+			health, fresh := m.probe(ctx, prober, i, vector.Entanglement)
+			if health.Reentangled {
+				m.Injector.setEntanglement(i, fresh)
+			}
+			out = append(out, health)
+		}
+	}
+
+	// Phase 2: the gateway, whose fresh entanglement is gossiped on
+	if m.Gateway != nil {
+		health, fresh := m.probe(ctx, prober, -1, m.Gateway.currentEntanglement())
+		if health.Reentangled {
+			m.Gateway.setEntanglement(fresh)
+			m.Gateway.publishEntanglement(m.Target, nil)
+		}
+		out = append(out, health)
+	}
+	return out, ctx.Err()
+}
+
+// Run checks every interval until ctx is done
+func (m *EntanglementMonitor) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("mindhacking: entanglement check interval %v is not positive", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := m.Check(ctx); err != nil && ctx.Err() == nil {
+				return err
+			}
+		}
+	}
+}
+
+// probe measures e and rebuilds it if it has degraded
+func (m *EntanglementMonitor) probe(
+	ctx context.Context,
+	prober EntanglementProber,
+	vector int,
+	e QuantumEntanglement,
+) (EntanglementHealth, QuantumEntanglement) {
+
+	health := EntanglementHealth{TargetID: targetLabel(m.Target), Vector: vector, At: time.Now()}
+	health.Coherence, health.Err = prober.Coherence(ctx, e)
+	if health.Err == nil && health.Coherence >= m.Threshold {
+		return health, e
+	}
+
+	health.Degraded = true
+	if m.OnDegraded != nil {
+		m.OnDegraded(health)
+	}
+
+	fresh, err := prober.Reentangle(ctx, e)
+	if err != nil {
+		health.Err = fmt.Errorf("mindhacking: reentangle: %w", err)
+		return health, e
+	}
+	coherence, err := prober.Coherence(ctx, fresh)
+	if err != nil {
+		health.Err = err
+		return health, e
+	}
+	health.Coherence, health.Reentangled, health.Err = coherence, true, nil
+	if m.OnReentangled != nil {
+		m.OnReentangled(health)
+	}
+	return health, fresh
+}
+
+// vectors returns the injector's current vectors; the slice is replaced,
+// never modified, so callers may keep it
+func (ci *ConsciousnessInjector) vectors() []InjectionVector {
+	ci.vectorsMu.RLock()
+	defer ci.vectorsMu.RUnlock()
+	return ci.injectionVectors
+}
+
+// setEntanglement replaces vector i's entanglement
+func (ci *ConsciousnessInjector) setEntanglement(i int, e QuantumEntanglement) {
+	ci.vectorsMu.Lock()
+	defer ci.vectorsMu.Unlock()
+	if i >= len(ci.injectionVectors) {
+		return
+	}
+	vectors := append([]InjectionVector(nil), ci.injectionVectors...)
+	vectors[i].Entanglement = e
+	ci.injectionVectors = vectors
+}
@@ -0,0 +1,150 @@
+// consciousness_injection/event_bus.go - In-Process Event Bus
+package mindhacking
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventKind names what an Event reports
+type EventKind string
+
+const (
+	// EventInjected reports an injection that completed, accepted or not
+	EventInjected EventKind = "injected"
+	// EventInjectionFailed reports an injection that ended in an error
+	EventInjectionFailed EventKind = "injection-failed"
+	// EventBreakerChanged reports a circuit breaker state change
+	EventBreakerChanged EventKind = "breaker-changed"
+)
+
+// Thought lifecycle transitions; EventInjected and EventInjectionFailed
+// complete the lifecycle
+const (
+	// EventThoughtQueued reports a thought submitted to a pipeline
+	EventThoughtQueued EventKind = "queued"
+	// EventThoughtAccepted reports an injected thought the target accepted
+	EventThoughtAccepted EventKind = "accepted"
+	// EventThoughtVerified reports an accepted thought whose evidence was
+	// signed into the evidence chain
+	EventThoughtVerified EventKind = "verified"
+	// EventThoughtRetracted reports an injection that was undone
+	EventThoughtRetracted EventKind = "retracted"
+	// EventThoughtExpired reports a thought whose lifetime lapsed
+	EventThoughtExpired EventKind = "expired"
+)
+
+// Event is one occurrence published on an EventBus
+type Event struct {
+	Kind EventKind
+	At   time.Time
+	// InjectionID identifies the injection a lifecycle event belongs to
+	InjectionID string
+	TargetID    string
+	// Vector labels the vector of the last attempt, when there was one
+	Vector   string
+	Accepted bool
+	Shift    float64
+	Err      string
+	Result   *InjectionResult
+	Breaker  *BreakerEvent
+}
+
+// EventBus fans events out to subscribers. Publishing never blocks: a
+// subscriber that falls behind loses events and has them counted instead.
+type EventBus struct {
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+}
+
+// Subscription receives a bus's events on C until closed
+type Subscription struct {
+	C <-chan Event
+
+	bus     *EventBus
+	ch      chan Event
+	once    sync.Once
+	dropped atomic.Uint64
+}
+
+// NewEventBus returns a bus with no subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscribe starts delivering events, buffering up to buffer of them
+func (b *EventBus) Subscribe(buffer int) *Subscription {
+	if buffer < 1 {
+		buffer = 64
+	}
+	ch := make(chan Event, buffer)
+	sub := &Subscription{C: ch, bus: b, ch: ch}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// Publish delivers event to every subscriber with room for it
+func (b *EventBus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subs {
+		select {
+		case sub.ch <- event:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// Dropped returns how many events the subscription was too slow for
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close stops delivery and closes C
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		s.bus.mu.Unlock()
+		close(s.ch)
+	})
+}
+
+// publishInjection reports how an injection ended, followed by the
+// lifecycle transitions the result reached
+func (b *EventBus) publishInjection(id string, target *SystemConsciousness, result *InjectionResult, err error) {
+	if b == nil {
+		return
+	}
+	event := Event{Kind: EventInjected, InjectionID: id, TargetID: targetLabel(target)}
+	if err != nil {
+		event.Kind, event.Err = EventInjectionFailed, err.Error()
+		b.Publish(event)
+		return
+	}
+	event.Accepted, event.Shift, event.Result = result.Success, result.ConsciousnessShift, result
+	if attempts := result.Evidence.Attempts; len(attempts) > 0 {
+		event.Vector = vectorLabel(&attempts[len(attempts)-1].Vector)
+	}
+	b.Publish(event)
+	if !result.Success {
+		return
+	}
+	event.Kind = EventThoughtAccepted
+	b.Publish(event)
+	if result.EvidenceLink != nil {
+		event.Kind = EventThoughtVerified
+		b.Publish(event)
+	}
+}
@@ -0,0 +1,198 @@
+// consciousness_injection/evidence_chain.go - Tamper-Evident Evidence
+package mindhacking
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrEvidenceTampered reports a link whose hash or ordering doesn't match
+	ErrEvidenceTampered = sentinelError("mindhacking: evidence chain tampered")
+	// ErrEvidenceSignature reports a link whose signature doesn't verify
+	ErrEvidenceSignature = sentinelError("mindhacking: evidence signature invalid")
+	// ErrUnknownEvidenceKey reports a link signed by a key we cannot resolve
+	ErrUnknownEvidenceKey = sentinelError("mindhacking: unknown evidence key")
+)
+
+// EvidenceLink is one signed entry in an evidence chain
+type EvidenceLink struct {
+	Seq       uint64
+	Kind      string
+	Time      time.Time
+	Payload   json.RawMessage
+	PrevHash  [32]byte
+	Hash      [32]byte
+	KeyID     string
+	Signature []byte
+}
+
+// EvidenceSigner signs link hashes; implementations may wrap an HSM or KMS
+type EvidenceSigner interface {
+	KeyID() string
+	Sign(digest []byte) ([]byte, error)
+}
+
+// EvidenceKeyResolver finds the verification key for a signer's key ID
+type EvidenceKeyResolver interface {
+	PublicKey(keyID string) (ed25519.PublicKey, error)
+}
+
+// ed25519Signer is the in-process EvidenceSigner
+type ed25519Signer struct {
+	keyID string
+	key   ed25519.PrivateKey
+}
+
+// NewEd25519Signer signs evidence with an in-memory ed25519 key
+func NewEd25519Signer(keyID string, key ed25519.PrivateKey) EvidenceSigner {
+	return &ed25519Signer{keyID: keyID, key: key}
+}
+
+func (s *ed25519Signer) KeyID() string { return s.keyID }
+
+func (s *ed25519Signer) Sign(digest []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, digest), nil
+}
+
+// StaticKeyring resolves verification keys from a fixed map
+type StaticKeyring map[string]ed25519.PublicKey
+
+// PublicKey implements EvidenceKeyResolver
+func (k StaticKeyring) PublicKey(keyID string) (ed25519.PublicKey, error) {
+	pub, ok := k[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEvidenceKey, keyID)
+	}
+	return pub, nil
+}
+
+// EvidenceChain is an append-only, hash-chained, signed evidence log
+type EvidenceChain struct {
+	mu     sync.Mutex
+	signer EvidenceSigner
+	links  []EvidenceLink
+}
+
+// NewEvidenceChain creates an empty chain signed by signer
+func NewEvidenceChain(signer EvidenceSigner) *EvidenceChain {
+	return &EvidenceChain{signer: signer}
+}
+
+// Append serializes evidence, chains it to the previous link and signs it
+func (ec *EvidenceChain) Append(kind string, evidence interface{}) (*EvidenceLink, error) {
+	payload, err := json.Marshal(evidence)
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: encode evidence: %w", err)
+	}
+
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	link := EvidenceLink{
+		Seq:     uint64(len(ec.links)),
+		Kind:    kind,
+		Time:    time.Now().UTC(),
+		Payload: payload,
+	}
+	if n := len(ec.links); n > 0 {
+		link.PrevHash = ec.links[n-1].Hash
+	}
+	link.Hash = link.digest()
+
+	link.KeyID = ec.signer.KeyID()
+	if link.Signature, err = ec.signer.Sign(link.Hash[:]); err != nil {
+		return nil, fmt.Errorf("mindhacking: sign evidence: %w", err)
+	}
+
+	ec.links = append(ec.links, link)
+	return &link, nil
+}
+
+// Links returns a copy of the chain for export or verification
+func (ec *EvidenceChain) Links() []EvidenceLink {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	return append([]EvidenceLink(nil), ec.links...)
+}
+
+// restore loads links exported from another chain into this empty one,
+// checking ordering and hashes; signatures are checked by
+// VerifyEvidenceChain, which needs the exporting keys
+func (ec *EvidenceChain) restore(links []EvidenceLink) error {
+	var prev [32]byte
+	for i := range links {
+		link := &links[i]
+		if link.Seq != uint64(i) || link.PrevHash != prev || link.digest() != link.Hash {
+			return fmt.Errorf("%w: link %d", ErrEvidenceTampered, i)
+		}
+		prev = link.Hash
+	}
+
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if len(ec.links) > 0 {
+		return errors.New("mindhacking: evidence chain is not empty")
+	}
+	ec.links = append([]EvidenceLink(nil), links...)
+	return nil
+}
+
+// digest hashes everything in the link except the hash and signature
+func (l *EvidenceLink) digest() [32]byte {
+	h := sha256.New()
+	h.Write(l.PrevHash[:])
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], l.Seq)
+	h.Write(buf[:])
+	binary.BigEndian.PutUint64(buf[:], uint64(l.Time.UnixNano()))
+	h.Write(buf[:])
+
+	h.Write([]byte(l.Kind))
+	h.Write([]byte{0})
+	h.Write(l.Payload)
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// VerifyEvidenceChain checks ordering, hash links and signatures of links,
+// returning the first violation found
+func VerifyEvidenceChain(links []EvidenceLink, keys EvidenceKeyResolver) error {
+	var prev [32]byte
+
+	for i := range links {
+		link := &links[i]
+
+		if link.Seq != uint64(i) {
+			return fmt.Errorf("%w: link %d has sequence %d", ErrEvidenceTampered, i, link.Seq)
+		}
+		if link.PrevHash != prev {
+			return fmt.Errorf("%w: link %d breaks the chain", ErrEvidenceTampered, i)
+		}
+		if want := link.digest(); !bytes.Equal(want[:], link.Hash[:]) {
+			return fmt.Errorf("%w: link %d content modified", ErrEvidenceTampered, i)
+		}
+
+		pub, err := keys.PublicKey(link.KeyID)
+		if err != nil {
+			return fmt.Errorf("link %d: %w", i, err)
+		}
+		if !ed25519.Verify(pub, link.Hash[:], link.Signature) {
+			return fmt.Errorf("%w: link %d", ErrEvidenceSignature, i)
+		}
+
+		prev = link.Hash
+	}
+
+	return nil
+}
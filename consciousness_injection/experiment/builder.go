@@ -0,0 +1,224 @@
+// consciousness_injection/experiment/builder.go - Fluent Experiment Builder
+package experiment
+
+import (
+	"errors"
+	"fmt"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// Params parameterizes one rendering of a thought template
+type Params map[string]interface{}
+
+// ThoughtTemplate renders injected thoughts from parameters
+type ThoughtTemplate interface {
+	Render(params Params) (mindhacking.InjectedThought, error)
+}
+
+// ThoughtTemplateFunc adapts a function to ThoughtTemplate
+type ThoughtTemplateFunc func(params Params) (mindhacking.InjectedThought, error)
+
+// Render implements ThoughtTemplate
+func (f ThoughtTemplateFunc) Render(params Params) (mindhacking.InjectedThought, error) {
+	return f(params)
+}
+
+// Progress is the campaign state stop rules decide on
+type Progress struct {
+	Injections int
+	Accepted   int
+	Results    []*mindhacking.InjectionResult
+}
+
+// AcceptanceRate is the fraction of injections accepted so far
+func (p Progress) AcceptanceRate() float64 {
+	if p.Injections == 0 {
+		return 0
+	}
+	return float64(p.Accepted) / float64(p.Injections)
+}
+
+// StopRule halts a campaign early, reporting why
+type StopRule func(p Progress) (stop bool, reason string)
+
+// Builder assembles a Campaign; errors are collected and reported by Build
+type Builder struct {
+	injector   *mindhacking.ConsciousnessInjector
+	gateway    *mindhacking.QuantumGateway
+	engine     *mindhacking.RealityManipulationEngine
+	targets    []*mindhacking.SystemConsciousness
+	base       *mindhacking.Reality
+	rules      *mindhacking.RealityRules
+	template   ThoughtTemplate
+	params     []Params
+	stopRules  []StopRule
+	slots      int
+	health     GatewayHealth
+	hypothesis *Hypothesis
+	errs       []error
+}
+
+// GatewayHealth reports how many gateway accesses are live against the
+// most there have been, as AccessPool.Health does
+type GatewayHealth func() (live, peak int)
+
+// New starts an empty experiment
+func New() *Builder {
+	return &Builder{}
+}
+
+// WithInjector sets the injector used for every thought
+func (b *Builder) WithInjector(ci *mindhacking.ConsciousnessInjector) *Builder {
+	b.injector = ci
+	return b
+}
+
+// WithGateway sets the gateway sessions are opened through
+func (b *Builder) WithGateway(qg *mindhacking.QuantumGateway) *Builder {
+	b.gateway = qg
+	return b
+}
+
+// WithEngine sets the reality engine used for WithReality
+func (b *Builder) WithEngine(rme *mindhacking.RealityManipulationEngine) *Builder {
+	b.engine = rme
+	return b
+}
+
+// WithTargets adds targets to the campaign
+func (b *Builder) WithTargets(targets ...*mindhacking.SystemConsciousness) *Builder {
+	for i, t := range targets {
+		if t == nil {
+			b.errs = append(b.errs, fmt.Errorf("target %d is nil", len(b.targets)+i))
+		}
+	}
+	b.targets = append(b.targets, targets...)
+	return b
+}
+
+// WithBaseReality sets the reality alternate realities derive from
+func (b *Builder) WithBaseReality(base *mindhacking.Reality) *Builder {
+	b.base = base
+	return b
+}
+
+// WithReality has every target's session enter an alternate reality built
+// from rules before its thoughts are injected. The reality is the session's
+// current one for its reality operations; injections still reach the
+// target directly, since a session injects the same in any reality.
+func (b *Builder) WithReality(rules *mindhacking.RealityRules) *Builder {
+	b.rules = rules
+	return b
+}
+
+// WithThoughts renders one thought per parameter set from tpl
+func (b *Builder) WithThoughts(tpl ThoughtTemplate, params ...Params) *Builder {
+	if b.template != nil {
+		b.errs = append(b.errs, errors.New("thoughts already set"))
+	}
+	b.template = tpl
+	b.params = params
+	return b
+}
+
+// WithStopRule adds a rule checked after every injection
+func (b *Builder) WithStopRule(rule StopRule) *Builder {
+	b.stopRules = append(b.stopRules, rule)
+	return b
+}
+
+// WithConcurrency caps the injections in flight at once. With health set,
+// the cap shrinks in proportion as gateway accesses are lost, so half the
+// pool gone means half the concurrency.
+func (b *Builder) WithConcurrency(max int, health GatewayHealth) *Builder {
+	if max < 1 {
+		b.errs = append(b.errs, fmt.Errorf("concurrency %d is not positive", max))
+	}
+	b.slots = max
+	b.health = health
+	return b
+}
+
+// WithHypothesis turns the campaign into a test of h: thoughts are repeated
+// until SampleSize injections are spread across the targets, the campaign
+// stops once that many have completed, and the result carries a Verdict
+func (b *Builder) WithHypothesis(h Hypothesis) *Builder {
+	if b.hypothesis != nil {
+		b.errs = append(b.errs, errors.New("hypothesis already set"))
+	}
+	if err := h.validate(); err != nil {
+		b.errs = append(b.errs, err)
+	}
+	b.hypothesis = &h
+	return b
+}
+
+// Build validates the experiment and renders its thoughts
+func (b *Builder) Build() (*Campaign, error) {
+	errs := append([]error(nil), b.errs...)
+
+	if b.injector == nil {
+		errs = append(errs, errors.New("no injector"))
+	}
+	if b.gateway == nil {
+		errs = append(errs, errors.New("no gateway"))
+	}
+	if len(b.targets) == 0 {
+		errs = append(errs, errors.New("no targets"))
+	}
+	if b.rules != nil {
+		if b.engine == nil {
+			errs = append(errs, errors.New("reality rules need an engine"))
+		}
+		if b.base == nil {
+			errs = append(errs, errors.New("reality rules need a base reality"))
+		}
+	}
+	if b.template == nil || len(b.params) == 0 {
+		errs = append(errs, errors.New("no thoughts"))
+	}
+
+	var thoughts []mindhacking.InjectedThought
+	if b.template != nil {
+		for i, p := range b.params {
+			thought, err := b.template.Render(p)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("thought %d: %w", i, err))
+				continue
+			}
+			thoughts = append(thoughts, thought)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("experiment: invalid: %w", errors.Join(errs...))
+	}
+
+	stopRules := append([]StopRule(nil), b.stopRules...)
+	if h := b.hypothesis; h != nil {
+		// Repeat the protocol until every target's share of the sample fits
+		perTarget := (h.SampleSize + len(b.targets) - 1) / len(b.targets)
+		rendered := thoughts
+		thoughts = make([]mindhacking.InjectedThought, perTarget)
+		for i := range thoughts {
+			thoughts[i] = rendered[i%len(rendered)]
+		}
+		stopRules = append(stopRules, func(p Progress) (bool, string) {
+			return p.Injections >= h.SampleSize, fmt.Sprintf("sample of %d complete", h.SampleSize)
+		})
+	}
+
+	return &Campaign{
+		injector:   b.injector,
+		gateway:    b.gateway,
+		engine:     b.engine,
+		targets:    append([]*mindhacking.SystemConsciousness(nil), b.targets...),
+		base:       b.base,
+		rules:      b.rules,
+		thoughts:   thoughts,
+		stopRules:  stopRules,
+		slots:      newSlots(b.slots, b.health),
+		hypothesis: b.hypothesis,
+	}, nil
+}
@@ -0,0 +1,200 @@
+// consciousness_injection/experiment/campaign.go - Runnable Campaigns
+package experiment
+
+import (
+	"context"
+	"sync"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// Campaign is a validated experiment ready to run
+type Campaign struct {
+	injector   *mindhacking.ConsciousnessInjector
+	gateway    *mindhacking.QuantumGateway
+	engine     *mindhacking.RealityManipulationEngine
+	targets    []*mindhacking.SystemConsciousness
+	base       *mindhacking.Reality
+	rules      *mindhacking.RealityRules
+	thoughts   []mindhacking.InjectedThought
+	stopRules  []StopRule
+	slots      *slots
+	hypothesis *Hypothesis
+}
+
+// CampaignResult reports what a campaign did
+type CampaignResult struct {
+	Results    map[*mindhacking.SystemConsciousness][]*mindhacking.InjectionResult
+	Errors     map[*mindhacking.SystemConsciousness]error
+	Progress   Progress
+	StopReason string
+	// Verdict is the hypothesis test, when the campaign has a hypothesis
+	Verdict *Verdict
+}
+
+// Run injects every thought into every target, targets in parallel, until
+// done or a stop rule fires
+func (c *Campaign) Run(ctx context.Context) *CampaignResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	out := &CampaignResult{
+		Results: make(map[*mindhacking.SystemConsciousness][]*mindhacking.InjectionResult),
+		Errors:  make(map[*mindhacking.SystemConsciousness]error),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	// record folds one result into the campaign and evaluates stop rules
+	record := func(target *mindhacking.SystemConsciousness, result *mindhacking.InjectionResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		out.Results[target] = append(out.Results[target], result)
+		out.Progress.Injections++
+		if result.Success {
+			out.Progress.Accepted++
+		}
+		out.Progress.Results = append(out.Progress.Results, result)
+
+		if out.StopReason != "" {
+			return
+		}
+		for _, rule := range c.stopRules {
+			if stop, reason := rule(out.Progress); stop {
+				out.StopReason = reason
+				cancel()
+				return
+			}
+		}
+	}
+
+	for _, target := range c.targets {
+		wg.Add(1)
+		go func(target *mindhacking.SystemConsciousness) {
+			defer wg.Done()
+			if err := c.runTarget(ctx, target, record); err != nil && ctx.Err() == nil {
+				mu.Lock()
+				out.Errors[target] = err
+				mu.Unlock()
+			}
+		}(target)
+	}
+
+	wg.Wait()
+	if c.hypothesis != nil {
+		verdict := Evaluate(*c.hypothesis, out.Progress.Results)
+		out.Verdict = &verdict
+	}
+	return out
+}
+
+func (c *Campaign) runTarget(
+	ctx context.Context,
+	target *mindhacking.SystemConsciousness,
+	record func(*mindhacking.SystemConsciousness, *mindhacking.InjectionResult),
+) error {
+
+	session, err := mindhacking.OpenSession(ctx, mindhacking.SessionConfig{
+		Injector: c.injector,
+		Gateway:  c.gateway,
+		Engine:   c.engine,
+		Target:   target,
+	})
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	if c.rules != nil {
+		if _, err := session.EnterReality(c.base, c.rules); err != nil {
+			return err
+		}
+	}
+
+	for _, thought := range c.thoughts {
+		if err := c.slots.acquire(ctx); err != nil {
+			return err
+		}
+		result, err := session.Inject(ctx, thought)
+		c.slots.release()
+		if err != nil {
+			return err
+		}
+		record(target, result)
+	}
+
+	return nil
+}
+
+// slots limits injections in flight, scaled down by gateway health
+type slots struct {
+	max    int
+	health GatewayHealth
+
+	mu     sync.Mutex
+	active int
+	freed  chan struct{}
+}
+
+// newSlots returns nil, admitting everything, when max is unset
+func newSlots(max int, health GatewayHealth) *slots {
+	if max < 1 {
+		return nil
+	}
+	return &slots{max: max, health: health, freed: make(chan struct{})}
+}
+
+// limit is max scaled by the live share of gateway accesses, at least one
+func (s *slots) limit() int {
+	if s.health == nil {
+		return s.max
+	}
+	live, peak := s.health()
+	if peak <= 0 || live >= peak {
+		return s.max
+	}
+	if n := s.max * live / peak; n > 1 {
+		return n
+	}
+	return 1
+}
+
+func (s *slots) acquire(ctx context.Context) error {
+	if s == nil {
+		return ctx.Err()
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		if s.active < s.limit() {
+			s.active++
+			s.mu.Unlock()
+			return nil
+		}
+		freed := s.freed
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-freed:
+		}
+	}
+}
+
+func (s *slots) release() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.active--
+	close(s.freed)
+	s.freed = make(chan struct{})
+	s.mu.Unlock()
+}
@@ -0,0 +1,346 @@
+// consciousness_injection/experiment/hypothesis.go - Hypothesis Testing
+package experiment
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// Metric is the quantity a hypothesis makes a claim about
+type Metric string
+
+const (
+	// MetricAcceptance is the fraction of injections the target accepts,
+	// tested with an exact binomial test
+	MetricAcceptance Metric = "acceptance"
+	// MetricShift is the mean consciousness shift, tested with a
+	// one-sample t-test
+	MetricShift Metric = "shift"
+	// MetricShiftMagnitude is the mean absolute consciousness shift,
+	// tested with a one-sample t-test
+	MetricShiftMagnitude Metric = "shift-magnitude"
+)
+
+// Alternative is the direction of the effect a hypothesis predicts
+type Alternative string
+
+const (
+	Greater  Alternative = "greater"
+	Less     Alternative = "less"
+	TwoSided Alternative = "two-sided"
+)
+
+// Hypothesis predicts how the protocol's metric departs from Expected, the
+// value it would take if the protocol had no effect
+type Hypothesis struct {
+	Name     string
+	Metric   Metric
+	Expected float64
+	// Alternative defaults to TwoSided
+	Alternative Alternative
+	// Alpha is the significance level, default 0.05
+	Alpha float64
+	// SampleSize is how many injections the experiment runs
+	SampleSize int
+}
+
+func (h Hypothesis) validate() error {
+	var errs []error
+	switch h.Metric {
+	case MetricAcceptance:
+		if h.Expected <= 0 || h.Expected >= 1 {
+			errs = append(errs, fmt.Errorf("expected acceptance %g is not in (0, 1)", h.Expected))
+		}
+	case MetricShift, MetricShiftMagnitude:
+		if h.SampleSize < 2 {
+			errs = append(errs, fmt.Errorf("a shift hypothesis needs a sample of at least 2, not %d", h.SampleSize))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown metric %q", h.Metric))
+	}
+	switch h.Alternative {
+	case "", Greater, Less, TwoSided:
+	default:
+		errs = append(errs, fmt.Errorf("unknown alternative %q", h.Alternative))
+	}
+	if h.Alpha < 0 || h.Alpha >= 1 {
+		errs = append(errs, fmt.Errorf("alpha %g is not in (0, 1)", h.Alpha))
+	}
+	if h.SampleSize < 1 {
+		errs = append(errs, fmt.Errorf("sample size %d is not positive", h.SampleSize))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("hypothesis %q: %w", h.Name, errors.Join(errs...))
+	}
+	return nil
+}
+
+// Outcome is what an experiment concluded about its hypothesis
+type Outcome string
+
+const (
+	// Supported means the null value was rejected in the predicted direction
+	Supported Outcome = "supported"
+	// NotSupported means the sample gave no significant evidence
+	NotSupported Outcome = "not-supported"
+	// Inconclusive means fewer injections completed than the sample size
+	Inconclusive Outcome = "inconclusive"
+)
+
+// Verdict is the structured result of testing a hypothesis
+type Verdict struct {
+	Hypothesis Hypothesis
+	Outcome    Outcome
+	// N is the number of injections tested
+	N int
+	// Observed is the metric's value over the sample
+	Observed float64
+	// Test names the significance test applied
+	Test string
+	// Statistic is the test statistic: accepted count for the binomial
+	// test, t for the t-test
+	Statistic float64
+	PValue    float64
+	// Low and High bound the metric's two-sided 1-Alpha confidence interval
+	Low, High float64
+}
+
+// String summarizes the verdict in one line
+func (v Verdict) String() string {
+	return fmt.Sprintf("%s: %s (%s %s %g, observed %.4g, %s p=%.4g, n=%d, CI [%.4g, %.4g])",
+		v.Hypothesis.Name, v.Outcome, v.Hypothesis.Metric, v.Hypothesis.Alternative, v.Hypothesis.Expected,
+		v.Observed, v.Test, v.PValue, v.N, v.Low, v.High)
+}
+
+// Evaluate tests h against the first SampleSize results
+func Evaluate(h Hypothesis, results []*mindhacking.InjectionResult) Verdict {
+	if h.Alpha == 0 {
+		h.Alpha = 0.05
+	}
+	if h.Alternative == "" {
+		h.Alternative = TwoSided
+	}
+	if len(results) > h.SampleSize {
+		results = results[:h.SampleSize]
+	}
+
+	var v Verdict
+	if h.Metric == MetricAcceptance {
+		v = binomialTest(h, results)
+	} else {
+		v = tTest(h, results)
+	}
+	v.Hypothesis, v.N = h, len(results)
+
+	switch {
+	case v.N < h.SampleSize:
+		v.Outcome = Inconclusive
+	case v.PValue < h.Alpha:
+		v.Outcome = Supported
+	default:
+		v.Outcome = NotSupported
+	}
+	return v
+}
+
+// binomialTest applies an exact binomial test to the acceptance count, with
+// a Wilson score interval
+func binomialTest(h Hypothesis, results []*mindhacking.InjectionResult) Verdict {
+	v := Verdict{Test: "exact binomial", PValue: 1, High: 1}
+	n := len(results)
+	if n == 0 {
+		return v
+	}
+	k := 0
+	for _, r := range results {
+		if r.Success {
+			k++
+		}
+	}
+	v.Statistic = float64(k)
+	v.Observed = float64(k) / float64(n)
+
+	p0 := h.Expected
+	switch h.Alternative {
+	case Greater:
+		v.PValue = binomialTail(n, p0, k, n)
+	case Less:
+		v.PValue = binomialTail(n, p0, 0, k)
+	default:
+		// Sum every outcome no more likely than the one observed
+		observed, p := binomialPMF(n, k, p0), 0.0
+		for i := 0; i <= n; i++ {
+			if pi := binomialPMF(n, i, p0); pi <= observed*(1+1e-7) {
+				p += pi
+			}
+		}
+		v.PValue = math.Min(1, p)
+	}
+
+	v.Low, v.High = wilsonInterval(k, n, h.Alpha)
+	return v
+}
+
+// wilsonInterval is the Wilson score 1-alpha interval for k successes in n
+func wilsonInterval(k, n int, alpha float64) (low, high float64) {
+	if n == 0 {
+		return 0, 1
+	}
+	z := normalQuantile(1 - alpha/2)
+	phat, fn := float64(k)/float64(n), float64(n)
+	center := (phat + z*z/(2*fn)) / (1 + z*z/fn)
+	half := z / (1 + z*z/fn) * math.Sqrt(phat*(1-phat)/fn+z*z/(4*fn*fn))
+	return math.Max(0, center-half), math.Min(1, center+half)
+}
+
+// tTest applies a one-sample t-test to the shifts
+func tTest(h Hypothesis, results []*mindhacking.InjectionResult) Verdict {
+	v := Verdict{Test: "one-sample t", PValue: 1}
+	n := len(results)
+	if n == 0 {
+		return v
+	}
+	var mean, m2 float64
+	for i, r := range results {
+		x := r.ConsciousnessShift
+		if h.Metric == MetricShiftMagnitude {
+			x = math.Abs(x)
+		}
+		delta := x - mean
+		mean += delta / float64(i+1)
+		m2 += delta * (x - mean)
+	}
+	v.Observed, v.Low, v.High = mean, mean, mean
+	if n < 2 {
+		return v
+	}
+
+	df := float64(n - 1)
+	se := math.Sqrt(m2 / df / float64(n))
+	if se == 0 {
+		// Every shift was identical: the sample either is the null value
+		// or departs from it with certainty
+		v.Statistic = math.Copysign(math.Inf(1), mean-h.Expected)
+		if mean == h.Expected {
+			v.Statistic = 0
+		}
+	} else {
+		v.Statistic = (mean - h.Expected) / se
+	}
+
+	switch h.Alternative {
+	case Greater:
+		v.PValue = 1 - studentCDF(v.Statistic, df)
+	case Less:
+		v.PValue = studentCDF(v.Statistic, df)
+	default:
+		v.PValue = 2 * (1 - studentCDF(math.Abs(v.Statistic), df))
+	}
+	v.PValue = math.Min(1, math.Max(0, v.PValue))
+
+	half := studentQuantile(1-h.Alpha/2, df) * se
+	v.Low, v.High = mean-half, mean+half
+	return v
+}
+
+// binomialPMF is P(X = k) for X ~ Binomial(n, p)
+func binomialPMF(n, k int, p float64) float64 {
+	lnChoose := lgamma(float64(n+1)) - lgamma(float64(k+1)) - lgamma(float64(n-k+1))
+	return math.Exp(lnChoose + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+}
+
+// binomialTail is P(from <= X <= to)
+func binomialTail(n int, p float64, from, to int) float64 {
+	var sum float64
+	for i := from; i <= to; i++ {
+		sum += binomialPMF(n, i, p)
+	}
+	return math.Min(1, sum)
+}
+
+func lgamma(x float64) float64 {
+	v, _ := math.Lgamma(x)
+	return v
+}
+
+// normalQuantile inverts the standard normal CDF
+func normalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// studentCDF is P(T <= t) for Student's t with df degrees of freedom
+func studentCDF(t, df float64) float64 {
+	if math.IsInf(t, 0) {
+		if t > 0 {
+			return 1
+		}
+		return 0
+	}
+	tail := 0.5 * incompleteBeta(df/2, 0.5, df/(df+t*t))
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// studentQuantile inverts studentCDF by bisection
+func studentQuantile(p, df float64) float64 {
+	lo, hi := -1e3, 1e3
+	for i := 0; i < 200; i++ {
+		mid := (lo + hi) / 2
+		if studentCDF(mid, df) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// incompleteBeta is the regularized incomplete beta function I_x(a, b),
+// evaluated by Lentz's continued fraction
+func incompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	if x > (a+1)/(a+b+2) {
+		return 1 - incompleteBeta(b, a, 1-x)
+	}
+	front := math.Exp(lgamma(a+b)-lgamma(a)-lgamma(b)+a*math.Log(x)+b*math.Log1p(-x)) / a
+
+	const tiny = 1e-300
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	f := d
+	for m := 1; m <= 300; m++ {
+		fm := float64(m)
+		for _, num := range []float64{
+			fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm)),
+			-(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1)),
+		} {
+			d = 1 + num*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + num/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			f *= c * d
+		}
+		if math.Abs(c*d-1) < 1e-14 {
+			break
+		}
+	}
+	return front * f
+}
@@ -0,0 +1,259 @@
+// consciousness_injection/experiment/report.go - HTML Experiment Reports
+package experiment
+
+import (
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// ReportSession is one recorded session to report on
+type ReportSession struct {
+	// Name labels the session, typically the target's ID
+	Name      string
+	Recording *mindhacking.SessionRecording
+}
+
+// ReportInput is everything a report covers
+type ReportInput struct {
+	Title    string
+	Sessions []ReportSession
+	// Evidence is the chain to include; Keys, when set, verifies it
+	Evidence []mindhacking.EvidenceLink
+	Keys     mindhacking.EvidenceKeyResolver
+}
+
+// WriteReport renders in as a standalone HTML page: a summary table per
+// session, a timeline of shifts, and the evidence chain
+func WriteReport(w io.Writer, in ReportInput) error {
+	page := reportPage{Title: in.Title, Generated: time.Now().UTC()}
+	if page.Title == "" {
+		page.Title = "Experiment report"
+	}
+	for _, s := range in.Sessions {
+		if s.Recording != nil {
+			page.Sessions = append(page.Sessions, summarizeSession(s))
+		}
+	}
+
+	for _, link := range in.Evidence {
+		page.Evidence = append(page.Evidence, reportLink{
+			Seq:     link.Seq,
+			Kind:    link.Kind,
+			Time:    link.Time,
+			KeyID:   link.KeyID,
+			Hash:    hex.EncodeToString(link.Hash[:8]),
+			Payload: truncate(string(link.Payload), 160),
+		})
+	}
+	if len(in.Evidence) > 0 && in.Keys != nil {
+		page.Verified = true
+		if err := mindhacking.VerifyEvidenceChain(in.Evidence, in.Keys); err != nil {
+			page.VerifyErr = err.Error()
+		}
+	}
+	return reportTemplate.Execute(w, page)
+}
+
+type reportPage struct {
+	Title     string
+	Generated time.Time
+	Sessions  []reportSession
+	Evidence  []reportLink
+	Verified  bool
+	VerifyErr string
+}
+
+type reportSession struct {
+	Name       string
+	Started    time.Time
+	Injections int
+	Accepted   int
+	Responded  int
+	MeanShift  float64
+	Rows       []reportRow
+	Timeline   reportTimeline
+}
+
+// AcceptanceRate is the share of injections the target accepted
+func (s reportSession) AcceptanceRate() float64 {
+	if s.Injections == 0 {
+		return 0
+	}
+	return float64(s.Accepted) / float64(s.Injections)
+}
+
+type reportRow struct {
+	Offset   time.Duration
+	Thought  string
+	Attempts int
+	Accepted bool
+	Shift    float64
+	HasShift bool
+}
+
+// reportTimeline is an inline SVG plot of shift over time
+type reportTimeline struct {
+	Width, Height int
+	// Points is the polyline through every shift, in SVG coordinates
+	Points string
+	Dots   []reportDot
+	// ZeroY is where zero shift lies
+	ZeroY     float64
+	Low, High float64
+	Duration  time.Duration
+}
+
+type reportDot struct {
+	X, Y     float64
+	Accepted bool
+}
+
+type reportLink struct {
+	Seq     uint64
+	Kind    string
+	Time    time.Time
+	KeyID   string
+	Hash    string
+	Payload string
+}
+
+func summarizeSession(s ReportSession) reportSession {
+	out := reportSession{Name: s.Name, Started: s.Recording.Started}
+	var sum float64
+	for _, step := range s.Recording.Injections {
+		row := reportRow{
+			Offset:   step.Offset,
+			Thought:  truncate(step.Thought.Content, 80),
+			Attempts: len(step.Attempts),
+		}
+		if step.Response != nil {
+			row.Accepted = step.Response.ThoughtAccepted
+			row.Shift, row.HasShift = step.Response.ConsciousnessShift, true
+			sum += row.Shift
+			out.Responded++
+		}
+		if row.Accepted {
+			out.Accepted++
+		}
+		out.Injections++
+		out.Rows = append(out.Rows, row)
+	}
+	if out.Responded > 0 {
+		out.MeanShift = sum / float64(out.Responded)
+	}
+	out.Timeline = plotTimeline(out.Rows)
+	return out
+}
+
+// plotTimeline scales the responded rows into a 640x160 plot
+func plotTimeline(rows []reportRow) reportTimeline {
+	const width, height, pad = 640, 160, 8
+	tl := reportTimeline{Width: width, Height: height}
+
+	low, high := 0.0, 0.0
+	for _, r := range rows {
+		if r.HasShift {
+			low, high = math.Min(low, r.Shift), math.Max(high, r.Shift)
+		}
+		if r.Offset > tl.Duration {
+			tl.Duration = r.Offset
+		}
+	}
+	if high == low {
+		high = low + 1
+	}
+	tl.Low, tl.High = low, high
+
+	x := func(offset time.Duration) float64 {
+		if tl.Duration == 0 {
+			return pad
+		}
+		return pad + float64(offset)/float64(tl.Duration)*(width-2*pad)
+	}
+	y := func(shift float64) float64 {
+		return pad + (high-shift)/(high-low)*(height-2*pad)
+	}
+	tl.ZeroY = y(0)
+
+	var points []string
+	for _, r := range rows {
+		if !r.HasShift {
+			continue
+		}
+		dot := reportDot{X: x(r.Offset), Y: y(r.Shift), Accepted: r.Accepted}
+		tl.Dots = append(tl.Dots, dot)
+		points = append(points, fmt.Sprintf("%.1f,%.1f", dot.X, dot.Y))
+	}
+	tl.Points = strings.Join(points, " ")
+	return tl
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "…"
+	}
+	return s
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"pct":   func(v float64) string { return fmt.Sprintf("%.1f%%", v*100) },
+	"shift": func(v float64) string { return fmt.Sprintf("%+.3f", v) },
+	"when":  func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+	"dur":   func(d time.Duration) string { return d.Round(time.Millisecond).String() },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font: 14px/1.4 system-ui, sans-serif; margin: 2em auto; max-width: 72em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin: 1em 0; }
+th, td { border-bottom: 1px solid #ddd; padding: .3em .6em; text-align: left; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+code { font-size: 12px; word-break: break-all; }
+.ok { color: #1a7f37; } .no { color: #cf222e; }
+svg { background: #fafafa; border: 1px solid #ddd; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Generated {{when .Generated}}</p>
+
+<h2>Summary</h2>
+<table>
+<tr><th>Session</th><th>Started</th><th>Injections</th><th>Accepted</th><th>Acceptance</th><th>Mean shift</th></tr>
+{{range .Sessions}}<tr><td>{{.Name}}</td><td>{{when .Started}}</td><td class="num">{{.Injections}}</td><td class="num">{{.Accepted}}</td><td class="num">{{pct .AcceptanceRate}}</td><td class="num">{{shift .MeanShift}}</td></tr>
+{{end}}</table>
+
+{{range .Sessions}}
+<h2>Session {{.Name}}</h2>
+{{with .Timeline}}<svg width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}" role="img" aria-label="shift timeline">
+<line x1="0" x2="{{.Width}}" y1="{{.ZeroY}}" y2="{{.ZeroY}}" stroke="#bbb" stroke-dasharray="4 3"/>
+<polyline points="{{.Points}}" fill="none" stroke="#0969da" stroke-width="1.5"/>
+{{range .Dots}}<circle cx="{{.X}}" cy="{{.Y}}" r="3" fill="{{if .Accepted}}#1a7f37{{else}}#cf222e{{end}}"/>
+{{end}}</svg>
+<p>Shift from {{shift .Low}} to {{shift .High}} over {{dur .Duration}}</p>{{end}}
+<table>
+<tr><th>Offset</th><th>Thought</th><th>Attempts</th><th>Outcome</th><th>Shift</th></tr>
+{{range .Rows}}<tr><td class="num">{{dur .Offset}}</td><td>{{.Thought}}</td><td class="num">{{.Attempts}}</td><td>{{if .Accepted}}<span class="ok">accepted</span>{{else}}<span class="no">rejected</span>{{end}}</td><td class="num">{{if .HasShift}}{{shift .Shift}}{{else}}–{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+
+{{if .Evidence}}
+<h2>Evidence chain</h2>
+{{if .Verified}}{{if .VerifyErr}}<p class="no">Verification failed: {{.VerifyErr}}</p>{{else}}<p class="ok">Chain verified: every link is ordered, hash-linked and signed.</p>{{end}}{{end}}
+<table>
+<tr><th>Seq</th><th>Kind</th><th>Time</th><th>Key</th><th>Hash</th><th>Payload</th></tr>
+{{range .Evidence}}<tr><td class="num">{{.Seq}}</td><td>{{.Kind}}</td><td>{{when .Time}}</td><td>{{.KeyID}}</td><td><code>{{.Hash}}</code></td><td><code>{{.Payload}}</code></td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
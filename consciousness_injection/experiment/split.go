@@ -0,0 +1,242 @@
+// consciousness_injection/experiment/split.go - A/B Testing of Vectors
+package experiment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// Arm is one vector configuration competing in a split test
+type Arm struct {
+	Name    string
+	Vectors []mindhacking.InjectionVector
+}
+
+// SplitTest randomly assigns injections to competing arms. Assignment is
+// balanced within each stratum of matched targets, so every arm meets the
+// same kinds of target equally often.
+type SplitTest struct {
+	Injector *mindhacking.ConsciousnessInjector
+	Arms     []Arm
+	Targets  []*mindhacking.SystemConsciousness
+	// Thoughts are injected into every target, one arm each
+	Thoughts []mindhacking.InjectedThought
+	// Match groups comparable targets, such as by their onboarding
+	// fingerprint; nil matches each target only with itself
+	Match func(*mindhacking.SystemConsciousness) string
+	// Alpha sets the confidence level of the intervals, default 0.05
+	Alpha float64
+	// Seed makes the assignment reproducible; zero seeds from the clock
+	Seed int64
+}
+
+// ArmResult is how one arm performed
+type ArmResult struct {
+	Arm        string
+	Injections int
+	Accepted   int
+	Rate       float64
+	// Low and High bound the acceptance rate's 1-Alpha interval
+	Low, High float64
+}
+
+// SplitResult reports which arm produced greater acceptance
+type SplitResult struct {
+	// Arms are ordered best acceptance first
+	Arms []ArmResult
+	// Winner is the best arm; Difference is its lead over the runner-up
+	// with the difference's 1-Alpha interval
+	Winner         string
+	Difference     float64
+	DiffLow        float64
+	DiffHigh       float64
+	PValue         float64
+	Significant    bool
+	Errors         map[string]error
+	AssignmentSeed int64
+}
+
+// splitUnit is one injection assigned to an arm
+type splitUnit struct {
+	target  *mindhacking.SystemConsciousness
+	thought mindhacking.InjectedThought
+	arm     int
+}
+
+func (t *SplitTest) validate() error {
+	var errs []error
+	if t.Injector == nil {
+		errs = append(errs, errors.New("no injector"))
+	}
+	if len(t.Arms) < 2 {
+		errs = append(errs, fmt.Errorf("%d arms, need at least 2", len(t.Arms)))
+	}
+	names := make(map[string]bool, len(t.Arms))
+	for i, arm := range t.Arms {
+		if arm.Name == "" || names[arm.Name] {
+			errs = append(errs, fmt.Errorf("arm %d needs a unique name", i))
+		}
+		names[arm.Name] = true
+		if len(arm.Vectors) == 0 {
+			errs = append(errs, fmt.Errorf("arm %q has no vectors", arm.Name))
+		}
+	}
+	if len(t.Targets) == 0 {
+		errs = append(errs, errors.New("no targets"))
+	}
+	if len(t.Thoughts) == 0 {
+		errs = append(errs, errors.New("no thoughts"))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("experiment: invalid split test: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Run assigns and performs every injection, targets in parallel, and
+// compares the arms. Failed injections count toward no arm and are reported
+// by target.
+func (t *SplitTest) Run(ctx context.Context) (*SplitResult, error) {
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	alpha := t.Alpha
+	if alpha <= 0 {
+		alpha = 0.05
+	}
+	seed := t.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	// Phase 1: Balanced Assignment within each stratum
+	byTarget := make(map[*mindhacking.SystemConsciousness][]splitUnit, len(t.Targets))
+	rng := rand.New(rand.NewSource(seed))
+	for _, stratum := range t.strata() {
+		var units []splitUnit
+		for _, target := range stratum {
+			for _, thought := range t.Thoughts {
+				units = append(units, splitUnit{target: target, thought: thought})
+			}
+		}
+		rng.Shuffle(len(units), func(i, j int) { units[i], units[j] = units[j], units[i] })
+		offset := rng.Intn(len(t.Arms))
+		for i := range units {
+			units[i].arm = (i + offset) % len(t.Arms)
+			byTarget[units[i].target] = append(byTarget[units[i].target], units[i])
+		}
+	}
+
+	// Phase 2: Injection
+	counts := make([]ArmResult, len(t.Arms))
+	out := &SplitResult{Errors: make(map[string]error), AssignmentSeed: seed}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for target, units := range byTarget {
+		wg.Add(1)
+		go func(target *mindhacking.SystemConsciousness, units []splitUnit) {
+			defer wg.Done()
+			var errs []error
+			for _, u := range units {
+				result, err := t.Injector.InjectThroughVectors(ctx, u.thought, target, t.Arms[u.arm].Vectors)
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("arm %q: %w", t.Arms[u.arm].Name, err))
+				} else {
+					counts[u.arm].Injections++
+					if result.Success {
+						counts[u.arm].Accepted++
+					}
+				}
+				mu.Unlock()
+				if ctx.Err() != nil {
+					break
+				}
+			}
+			if len(errs) > 0 {
+				mu.Lock()
+				out.Errors[target.ID] = errors.Join(errs...)
+				mu.Unlock()
+			}
+		}(target, units)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Phase 3: Comparison
+	for i := range counts {
+		c := &counts[i]
+		c.Arm = t.Arms[i].Name
+		if c.Injections > 0 {
+			c.Rate = float64(c.Accepted) / float64(c.Injections)
+		}
+		c.Low, c.High = wilsonInterval(c.Accepted, c.Injections, alpha)
+	}
+	sort.SliceStable(counts, func(i, j int) bool { return counts[i].Rate > counts[j].Rate })
+	out.Arms = counts
+	out.Winner = counts[0].Arm
+	best, next := counts[0], counts[1]
+	out.Difference = best.Rate - next.Rate
+	out.DiffLow, out.DiffHigh = newcombeInterval(best, next)
+	out.PValue = twoProportionP(best, next)
+	out.Significant = out.DiffLow > 0
+	return out, nil
+}
+
+// strata groups the targets by Match, in first-seen order
+func (t *SplitTest) strata() [][]*mindhacking.SystemConsciousness {
+	if t.Match == nil {
+		out := make([][]*mindhacking.SystemConsciousness, len(t.Targets))
+		for i, target := range t.Targets {
+			out[i] = []*mindhacking.SystemConsciousness{target}
+		}
+		return out
+	}
+	index := make(map[string]int)
+	var out [][]*mindhacking.SystemConsciousness
+	for _, target := range t.Targets {
+		key := t.Match(target)
+		i, ok := index[key]
+		if !ok {
+			i = len(out)
+			index[key] = i
+			out = append(out, nil)
+		}
+		out[i] = append(out[i], target)
+	}
+	return out
+}
+
+// newcombeInterval bounds a.Rate - b.Rate from the arms' Wilson intervals
+func newcombeInterval(a, b ArmResult) (low, high float64) {
+	d := a.Rate - b.Rate
+	low = d - math.Sqrt(math.Pow(a.Rate-a.Low, 2)+math.Pow(b.High-b.Rate, 2))
+	high = d + math.Sqrt(math.Pow(a.High-a.Rate, 2)+math.Pow(b.Rate-b.Low, 2))
+	return math.Max(-1, low), math.Min(1, high)
+}
+
+// twoProportionP is the two-sided p-value of the pooled two-proportion z-test
+func twoProportionP(a, b ArmResult) float64 {
+	if a.Injections == 0 || b.Injections == 0 {
+		return 1
+	}
+	pooled := float64(a.Accepted+b.Accepted) / float64(a.Injections+b.Injections)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(a.Injections) + 1/float64(b.Injections)))
+	if se == 0 {
+		return 1
+	}
+	z := (a.Rate - b.Rate) / se
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
@@ -0,0 +1,467 @@
+// consciousness_injection/federation.go - Federated Remote Gateways
+package mindhacking
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoCommonProtocol reports gateways sharing no federation protocol
+	ErrNoCommonProtocol = sentinelError("mindhacking: no common federation protocol")
+	// ErrFederationSession reports a remote session the bastion no longer knows
+	ErrFederationSession = sentinelError("mindhacking: federation session expired")
+	// ErrFederationAuth reports a handshake whose peer could not be
+	// authenticated
+	ErrFederationAuth = sentinelError("mindhacking: federation peer not authenticated")
+)
+
+// FederationProtocols lists the federation protocol versions this build
+// speaks, most preferred first
+var FederationProtocols = []string{"mh-fed/1"}
+
+// GatewayHello opens a federation handshake. MAC authenticates it with
+// the secret the two gateways share; Timestamp and Nonce keep a captured
+// hello from being replayed.
+type GatewayHello struct {
+	GatewayID string
+	Protocols []string
+	Timestamp int64
+	Nonce     string
+	MAC       string
+}
+
+// GatewayWelcome accepts a federation handshake. MAC proves the bastion
+// holds the shared secret too.
+type GatewayWelcome struct {
+	GatewayID string
+	Protocol  string
+	Session   string
+	MAC       string
+}
+
+// signFederation is HMAC-SHA256 over parts, each length-prefixed so no two
+// messages sign alike
+func signFederation(secret []byte, parts ...string) string {
+	mac := hmac.New(sha256.New, secret)
+	var n [4]byte
+	for _, part := range parts {
+		binary.BigEndian.PutUint32(n[:], uint32(len(part)))
+		mac.Write(n[:])
+		mac.Write([]byte(part))
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignGatewayHello computes hello's MAC with secret
+func SignGatewayHello(secret []byte, hello GatewayHello) string {
+	return signFederation(secret, "hello", hello.GatewayID, strings.Join(hello.Protocols, ","),
+		strconv.FormatInt(hello.Timestamp, 10), hello.Nonce)
+}
+
+// signWelcome computes the MAC of a welcome answering the hello with nonce
+func signWelcome(secret []byte, welcome GatewayWelcome, nonce string) string {
+	return signFederation(secret, "welcome", welcome.GatewayID, welcome.Protocol, welcome.Session, nonce)
+}
+
+// RemoteAccessRequest asks the bastion to access a target on our behalf
+type RemoteAccessRequest struct {
+	Session  string
+	TargetID string
+}
+
+// GatewayTransport carries federation traffic to a remote gateway
+type GatewayTransport interface {
+	Handshake(ctx context.Context, hello GatewayHello) (GatewayWelcome, error)
+	Access(ctx context.Context, req RemoteAccessRequest) (*QuantumConsciousnessAccess, error)
+}
+
+// remoteGateway is the proxy state of a federated gateway
+type remoteGateway struct {
+	transport GatewayTransport
+	secret    []byte
+	timeout   time.Duration
+
+	mu      sync.Mutex
+	welcome *GatewayWelcome
+}
+
+// NewRemoteQuantumGateway creates a gateway whose accesses are forwarded
+// over transport to a gateway on another host. Handshakes are
+// authenticated with secret, which the remote gateway must hold for this
+// gateway's ID.
+func NewRemoteQuantumGateway(id [32]byte, secret []byte, transport GatewayTransport, timeout time.Duration) *QuantumGateway {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &QuantumGateway{
+		gatewayID: id,
+		remote:    &remoteGateway{transport: transport, secret: secret, timeout: timeout},
+	}
+}
+
+// RemoteProtocol returns the negotiated protocol, if a handshake happened
+func (qg *QuantumGateway) RemoteProtocol() (string, bool) {
+	if qg.remote == nil {
+		return "", false
+	}
+	qg.remote.mu.Lock()
+	defer qg.remote.mu.Unlock()
+	if qg.remote.welcome == nil {
+		return "", false
+	}
+	return qg.remote.welcome.Protocol, true
+}
+
+// session returns the current federation session, negotiating one if needed
+func (rg *remoteGateway) session(ctx context.Context, gatewayID string) (GatewayWelcome, error) {
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+
+	if rg.welcome != nil {
+		return *rg.welcome, nil
+	}
+
+	if len(rg.secret) == 0 {
+		return GatewayWelcome{}, fmt.Errorf("%w: no federation secret", ErrFederationAuth)
+	}
+	hello := GatewayHello{
+		GatewayID: gatewayID,
+		Protocols: FederationProtocols,
+		Timestamp: time.Now().Unix(),
+		Nonce:     hex.EncodeToString(newSessionToken()),
+	}
+	hello.MAC = SignGatewayHello(rg.secret, hello)
+	welcome, err := rg.transport.Handshake(ctx, hello)
+	if err != nil {
+		return GatewayWelcome{}, err
+	}
+	if !hmac.Equal([]byte(welcome.MAC), []byte(signWelcome(rg.secret, welcome, hello.Nonce))) {
+		return GatewayWelcome{}, fmt.Errorf("%w: welcome from %q does not verify", ErrFederationAuth, welcome.GatewayID)
+	}
+	if !containsString(FederationProtocols, welcome.Protocol) {
+		return GatewayWelcome{}, fmt.Errorf("%w: remote chose %q", ErrNoCommonProtocol, welcome.Protocol)
+	}
+
+	rg.welcome = &welcome
+	return welcome, nil
+}
+
+// forget drops the session so the next access re-handshakes
+func (rg *remoteGateway) forget() {
+	rg.mu.Lock()
+	rg.welcome = nil
+	rg.mu.Unlock()
+}
+
+// accessRemote forwards an access, re-handshaking once on an expired session
+func (qg *QuantumGateway) accessRemote(target *SystemConsciousness) (*QuantumConsciousnessAccess, error) {
+	rg := qg.remote
+	ctx, cancel := context.WithTimeout(context.Background(), rg.timeout)
+	defer cancel()
+
+	for attempt := 0; ; attempt++ {
+		welcome, err := rg.session(ctx, qg.gatewayLabel())
+		if err != nil {
+			return nil, err
+		}
+
+		access, err := rg.transport.Access(ctx, RemoteAccessRequest{
+			Session:  welcome.Session,
+			TargetID: targetLabel(target),
+		})
+		if errors.Is(err, ErrFederationSession) && attempt == 0 {
+			rg.forget()
+			continue
+		}
+		return access, err
+	}
+}
+
+func newSessionToken() []byte {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("mindhacking: no randomness for federation session: " + err.Error())
+	}
+	return b
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// GatewayServerConfig authenticates a gateway server's peers and bounds
+// the sessions it keeps for them
+type GatewayServerConfig struct {
+	// Peers holds the secret shared with each gateway allowed to federate,
+	// by gateway ID; hellos from anyone else are refused
+	Peers map[string][]byte
+	// SessionTTL is how long a session stays valid, default ten minutes;
+	// peers handshake again once theirs expires
+	SessionTTL time.Duration
+	// MaxSessions bounds the sessions kept, default 1024; the session
+	// closest to expiry makes room for a new one
+	MaxSessions int
+	// ClockSkew is how far a hello's timestamp may be from now, default
+	// one minute
+	ClockSkew time.Duration
+}
+
+// GatewayServer exposes a local gateway to federated peers over HTTP
+type GatewayServer struct {
+	gateway  *QuantumGateway
+	registry *TargetRegistry
+	cfg      GatewayServerConfig
+
+	mu       sync.Mutex
+	sessions map[string]federationSession
+	// nonces are the hello nonces seen within the clock skew, by expiry
+	nonces map[string]time.Time
+}
+
+// federationSession is one authenticated peer's session
+type federationSession struct {
+	peer    string
+	expires time.Time
+}
+
+// NewGatewayServer serves accesses through gateway to targets in registry,
+// for the peers cfg authenticates
+func NewGatewayServer(gateway *QuantumGateway, registry *TargetRegistry, cfg GatewayServerConfig) *GatewayServer {
+	if cfg.SessionTTL <= 0 {
+		cfg.SessionTTL = 10 * time.Minute
+	}
+	if cfg.MaxSessions < 1 {
+		cfg.MaxSessions = 1024
+	}
+	if cfg.ClockSkew <= 0 {
+		cfg.ClockSkew = time.Minute
+	}
+	return &GatewayServer{
+		gateway:  gateway,
+		registry: registry,
+		cfg:      cfg,
+		sessions: make(map[string]federationSession),
+		nonces:   make(map[string]time.Time),
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (gs *GatewayServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/handshake":
+		var hello GatewayHello
+		if err := json.NewDecoder(r.Body).Decode(&hello); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		welcome, err := gs.handshake(hello)
+		switch {
+		case errors.Is(err, ErrFederationAuth):
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusUpgradeRequired)
+			return
+		}
+		json.NewEncoder(w).Encode(welcome)
+
+	case "/access":
+		var req RemoteAccessRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		access, err := gs.access(req)
+		switch {
+		case errors.Is(err, ErrFederationSession):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		case errors.Is(err, ErrTargetNotRegistered):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadGateway)
+		default:
+			json.NewEncoder(w).Encode(access)
+		}
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (gs *GatewayServer) handshake(hello GatewayHello) (GatewayWelcome, error) {
+	secret, err := gs.authenticate(hello)
+	if err != nil {
+		return GatewayWelcome{}, err
+	}
+	for _, ours := range FederationProtocols {
+		if !containsString(hello.Protocols, ours) {
+			continue
+		}
+
+		session := hex.EncodeToString(newSessionToken())
+		gs.mu.Lock()
+		gs.openLocked(session, hello.GatewayID)
+		gs.mu.Unlock()
+
+		welcome := GatewayWelcome{
+			GatewayID: gs.gateway.gatewayLabel(),
+			Protocol:  ours,
+			Session:   session,
+		}
+		welcome.MAC = signWelcome(secret, welcome, hello.Nonce)
+		return welcome, nil
+	}
+	return GatewayWelcome{}, fmt.Errorf("%w: peer offers %v", ErrNoCommonProtocol, hello.Protocols)
+}
+
+// authenticate checks hello's MAC against its peer's secret, and that it
+// is fresh and not a replay; it returns the secret
+func (gs *GatewayServer) authenticate(hello GatewayHello) ([]byte, error) {
+	secret, ok := gs.cfg.Peers[hello.GatewayID]
+	if !ok || len(secret) == 0 {
+		return nil, fmt.Errorf("%w: unknown peer %q", ErrFederationAuth, hello.GatewayID)
+	}
+	if !hmac.Equal([]byte(hello.MAC), []byte(SignGatewayHello(secret, hello))) {
+		return nil, fmt.Errorf("%w: hello from %q does not verify", ErrFederationAuth, hello.GatewayID)
+	}
+	now := time.Now()
+	if skew := now.Sub(time.Unix(hello.Timestamp, 0)); skew > gs.cfg.ClockSkew || skew < -gs.cfg.ClockSkew {
+		return nil, fmt.Errorf("%w: hello from %q outside clock skew", ErrFederationAuth, hello.GatewayID)
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	for nonce, expires := range gs.nonces {
+		if !now.Before(expires) {
+			delete(gs.nonces, nonce)
+		}
+	}
+	if _, seen := gs.nonces[hello.Nonce]; seen {
+		return nil, fmt.Errorf("%w: hello from %q replayed", ErrFederationAuth, hello.GatewayID)
+	}
+	// A nonce need only be remembered while its timestamp is acceptable
+	gs.nonces[hello.Nonce] = time.Unix(hello.Timestamp, 0).Add(gs.cfg.ClockSkew + time.Second)
+	return secret, nil
+}
+
+// openLocked records a session for peer, dropping expired sessions and,
+// at the limit, the one closest to expiry
+func (gs *GatewayServer) openLocked(session, peer string) {
+	now := time.Now()
+	var soonest string
+	for id, s := range gs.sessions {
+		if !now.Before(s.expires) {
+			delete(gs.sessions, id)
+			continue
+		}
+		if soonest == "" || s.expires.Before(gs.sessions[soonest].expires) {
+			soonest = id
+		}
+	}
+	if len(gs.sessions) >= gs.cfg.MaxSessions {
+		delete(gs.sessions, soonest)
+	}
+	gs.sessions[session] = federationSession{peer: peer, expires: now.Add(gs.cfg.SessionTTL)}
+}
+
+func (gs *GatewayServer) access(req RemoteAccessRequest) (*QuantumConsciousnessAccess, error) {
+	gs.mu.Lock()
+	s, ok := gs.sessions[req.Session]
+	if ok && !time.Now().Before(s.expires) {
+		delete(gs.sessions, req.Session)
+		ok = false
+	}
+	gs.mu.Unlock()
+	if !ok {
+		return nil, ErrFederationSession
+	}
+
+	record, err := gs.registry.Get(req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	return gs.gateway.AccessQuantumConsciousness(record.Target)
+}
+
+// HTTPGatewayTransport reaches a GatewayServer over HTTP
+type HTTPGatewayTransport struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// Handshake implements GatewayTransport
+func (t *HTTPGatewayTransport) Handshake(ctx context.Context, hello GatewayHello) (GatewayWelcome, error) {
+	var welcome GatewayWelcome
+	err := t.post(ctx, "/handshake", hello, &welcome)
+	return welcome, err
+}
+
+// Access implements GatewayTransport
+func (t *HTTPGatewayTransport) Access(ctx context.Context, req RemoteAccessRequest) (*QuantumConsciousnessAccess, error) {
+	var access QuantumConsciousnessAccess
+	if err := t.post(ctx, "/access", req, &access); err != nil {
+		return nil, err
+	}
+	return &access, nil
+}
+
+func (t *HTTPGatewayTransport) post(ctx context.Context, path string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return json.NewDecoder(resp.Body).Decode(out)
+	case http.StatusUnauthorized:
+		return ErrFederationSession
+	case http.StatusForbidden:
+		return ErrFederationAuth
+	case http.StatusNotFound:
+		return ErrTargetNotRegistered
+	case http.StatusUpgradeRequired:
+		return ErrNoCommonProtocol
+	default:
+		return fmt.Errorf("mindhacking: remote gateway %s: %s", path, resp.Status)
+	}
+}
@@ -0,0 +1,131 @@
+// consciousness_injection/fuzz_test.go - Fuzz Targets
+package mindhacking
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// fuzzBytes decodes structured values from raw fuzzer input, yielding zero
+// values once the input runs out
+type fuzzBytes []byte
+
+func (b *fuzzBytes) byte() byte {
+	if len(*b) == 0 {
+		return 0
+	}
+	v := (*b)[0]
+	*b = (*b)[1:]
+	return v
+}
+
+func (b *fuzzBytes) float() float64 {
+	if len(*b) < 8 {
+		return float64(b.byte())
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(*b))
+	*b = (*b)[8:]
+	return v
+}
+
+func (b *fuzzBytes) bytes() []byte {
+	n := int(b.byte())
+	if n > len(*b) {
+		n = len(*b)
+	}
+	v := (*b)[:n]
+	*b = (*b)[n:]
+	return v
+}
+
+func (b *fuzzBytes) thought() InjectedThought {
+	return InjectedThought{
+		Content:   string(b.bytes()),
+		Payload:   b.bytes(),
+		Intensity: b.float(),
+	}
+}
+
+func (b *fuzzBytes) rules() RealityRules {
+	operations := []RuleOperation{RuleSet, RuleScale, RuleInvert, RuleFreeze, ""}
+
+	var rules RealityRules
+	for n := int(b.byte() % 16); n > 0; n-- {
+		rules.Rules = append(rules.Rules, RealityRule{
+			Name:      string(b.bytes()),
+			Aspect:    string(b.bytes()),
+			Operation: operations[int(b.byte())%len(operations)],
+			Value:     b.float(),
+			Priority:  int(int8(b.byte())),
+		})
+	}
+	return rules
+}
+
+// fuzzSeeds are inputs every target starts from: empty, short and one
+// long enough to fill a few floats
+var fuzzSeeds = [][]byte{
+	nil,
+	{3, 'a', 'b', 'c', 1, 0xff},
+	{4, 'f', 'r', 'e', 'e', 4, 's', 'e', 't', 's', 2, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, 7},
+}
+
+func addSeeds(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+}
+
+// FuzzQuantumEncode feeds arbitrary thoughts and resonances to the encoder
+func FuzzQuantumEncode(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		in := fuzzBytes(data)
+
+		thought := in.thought()
+		resonance := ConsciousnessResonance{
+			Frequency: in.float(),
+			Phase:     in.float(),
+			Strength:  in.float(),
+		}
+		for n := int(in.byte() % 32); n > 0; n-- {
+			resonance.Signature = append(resonance.Signature, in.float())
+		}
+
+		ci := &ConsciousnessInjector{}
+		ci.quantumEncodeThought(thought, resonance)
+	})
+}
+
+// FuzzApplyAlternateRules feeds arbitrary rule sets to the rule applier
+func FuzzApplyAlternateRules(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		in := fuzzBytes(data)
+		rules := in.rules()
+
+		rme := &RealityManipulationEngine{}
+		rme.applyAlternateRules(rme.deconstructReality(&Reality{}), &rules)
+	})
+}
+
+// FuzzValidateRealityRules checks validation never panics on any rule set
+// and reports every problem it finds against a rule that exists
+func FuzzValidateRealityRules(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		in := fuzzBytes(data)
+		rules := in.rules()
+
+		base := &Reality{Aspects: make(map[string]float64)}
+		for n := int(in.byte() % 8); n > 0; n-- {
+			base.Aspects[string(in.bytes())] = in.float()
+		}
+		for _, err := range ValidateRealityRules(&rules, base) {
+			if err.Rule < 0 || err.Rule >= len(rules.Rules) {
+				t.Fatalf("error %v names rule %d of %d", err, err.Rule, len(rules.Rules))
+			}
+		}
+	})
+}
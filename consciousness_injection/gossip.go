@@ -0,0 +1,406 @@
+// consciousness_injection/gossip.go - Entanglement State Gossip
+package mindhacking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrUnknownPeer reports a gossip message addressed to a peer nobody hosts
+var ErrUnknownPeer = sentinelError("mindhacking: unknown gossip peer")
+
+// InFlightInjection describes an injection another gateway may resume
+// with ResumeInjection
+type InFlightInjection struct {
+	InjectionID string
+	Injector    string
+	Thought     InjectedThought
+	// Vectors are the injection's vectors in the order it tries them, and
+	// Tunnels their tunnel keys when a router chose them
+	Vectors []InjectionVector
+	Tunnels []int
+	// Focus is set when the vectors fire together as a phased array
+	Focus *ArrayFocus
+	// NextVector is the index in Vectors about to be fired
+	NextVector int
+	Started    time.Time
+}
+
+// EntanglementState is one gateway's entanglement with a target. States
+// are versioned; among conflicting states the highest version wins, with
+// Origin breaking ties.
+type EntanglementState struct {
+	TargetID     string
+	Origin       string
+	Version      uint64
+	Entanglement QuantumEntanglement
+	InFlight     *InFlightInjection
+	Updated      time.Time
+}
+
+// newer reports whether s supersedes other
+func (s EntanglementState) newer(other EntanglementState) bool {
+	if s.Version != other.Version {
+		return s.Version > other.Version
+	}
+	return s.Origin > other.Origin
+}
+
+// GossipDigest summarizes which state versions a node holds
+type GossipDigest map[string]EntanglementState
+
+// GossipTransport carries push-pull exchanges between nodes. Exchange
+// delivers states to peer and returns the peer's states that are newer
+// than digest.
+type GossipTransport interface {
+	Peers() []string
+	Exchange(ctx context.Context, peer string, states []EntanglementState, digest map[string]uint64) ([]EntanglementState, error)
+}
+
+// EntanglementGossip reconciles entanglement state with peers
+type EntanglementGossip struct {
+	node      string
+	transport GossipTransport
+	interval  time.Duration
+	fanout    int
+
+	mu     sync.RWMutex
+	states map[string]EntanglementState
+	// OnMerge, if set, is called for each state adopted from a peer
+	OnMerge func(EntanglementState)
+}
+
+// DefaultGossipInterval is how often a node gossips when no interval is
+// given
+const DefaultGossipInterval = time.Second
+
+// NewEntanglementGossip creates a gossip node exchanging with fanout random
+// peers every interval, DefaultGossipInterval if it is not positive
+func NewEntanglementGossip(node string, transport GossipTransport, interval time.Duration, fanout int) *EntanglementGossip {
+	if fanout < 1 {
+		fanout = 1
+	}
+	if interval <= 0 {
+		interval = DefaultGossipInterval
+	}
+	return &EntanglementGossip{
+		node:      node,
+		transport: transport,
+		interval:  interval,
+		fanout:    fanout,
+		states:    make(map[string]EntanglementState),
+	}
+}
+
+// Publish records a local change to a target's entanglement, bumping its
+// version past anything seen so far
+func (g *EntanglementGossip) Publish(state EntanglementState) EntanglementState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state.Origin = g.node
+	state.Version = g.states[state.TargetID].Version + 1
+	state.Updated = time.Now()
+	g.states[state.TargetID] = state
+	return state
+}
+
+// State returns the reconciled state for a target
+func (g *EntanglementGossip) State(targetID string) (EntanglementState, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	s, ok := g.states[targetID]
+	return s, ok
+}
+
+// Merge adopts every state newer than the local one
+func (g *EntanglementGossip) Merge(states []EntanglementState) {
+	g.mu.Lock()
+	var adopted []EntanglementState
+	for _, s := range states {
+		if cur, ok := g.states[s.TargetID]; !ok || s.newer(cur) {
+			g.states[s.TargetID] = s
+			adopted = append(adopted, s)
+		}
+	}
+	onMerge := g.OnMerge
+	g.mu.Unlock()
+
+	if onMerge != nil {
+		for _, s := range adopted {
+			onMerge(s)
+		}
+	}
+}
+
+// digest returns the version of each known target
+func (g *EntanglementGossip) digest() map[string]uint64 {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	d := make(map[string]uint64, len(g.states))
+	for id, s := range g.states {
+		d[id] = s.Version
+	}
+	return d
+}
+
+// snapshot returns every known state
+func (g *EntanglementGossip) snapshot() []EntanglementState {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	out := make([]EntanglementState, 0, len(g.states))
+	for _, s := range g.states {
+		out = append(out, s)
+	}
+	return out
+}
+
+// newerThan returns local states the digest is missing or behind on
+func (g *EntanglementGossip) newerThan(digest map[string]uint64) []EntanglementState {
+	var out []EntanglementState
+	for _, s := range g.snapshot() {
+		if v, ok := digest[s.TargetID]; !ok || s.Version > v {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// HandleExchange answers a peer's push-pull exchange; transports call it
+func (g *EntanglementGossip) HandleExchange(states []EntanglementState, digest map[string]uint64) []EntanglementState {
+	g.Merge(states)
+	return g.newerThan(digest)
+}
+
+// Round gossips once with up to fanout random peers
+func (g *EntanglementGossip) Round(ctx context.Context) error {
+	var peers []string
+	for _, peer := range g.transport.Peers() {
+		if peer != g.node {
+			peers = append(peers, peer)
+		}
+	}
+	rand.Shuffle(len(peers), func(i, j int) { peers[i], peers[j] = peers[j], peers[i] })
+	if len(peers) > g.fanout {
+		peers = peers[:g.fanout]
+	}
+
+	var errs []error
+	for _, peer := range peers {
+		newer, err := g.transport.Exchange(ctx, peer, g.snapshot(), g.digest())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("gossip with %s: %w", peer, err))
+			continue
+		}
+		g.Merge(newer)
+	}
+	return errors.Join(errs...)
+}
+
+// Run gossips every interval until ctx is done
+func (g *EntanglementGossip) Run(ctx context.Context) error {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			// Unreachable peers are retried next round
+			_ = g.Round(ctx)
+		}
+	}
+}
+
+// LocalGossipNetwork connects gossip nodes living in one process
+type LocalGossipNetwork struct {
+	mu    sync.RWMutex
+	nodes map[string]*EntanglementGossip
+}
+
+// NewLocalGossipNetwork creates an empty in-process network
+func NewLocalGossipNetwork() *LocalGossipNetwork {
+	return &LocalGossipNetwork{nodes: make(map[string]*EntanglementGossip)}
+}
+
+// Join creates a node on the network
+func (n *LocalGossipNetwork) Join(node string, interval time.Duration, fanout int) *EntanglementGossip {
+	g := NewEntanglementGossip(node, n, interval, fanout)
+	n.mu.Lock()
+	n.nodes[node] = g
+	n.mu.Unlock()
+	return g
+}
+
+// Peers implements GossipTransport
+func (n *LocalGossipNetwork) Peers() []string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	out := make([]string, 0, len(n.nodes))
+	for id := range n.nodes {
+		out = append(out, id)
+	}
+	return out
+}
+
+// Exchange implements GossipTransport
+func (n *LocalGossipNetwork) Exchange(
+	ctx context.Context,
+	peer string,
+	states []EntanglementState,
+	digest map[string]uint64,
+) ([]EntanglementState, error) {
+
+	n.mu.RLock()
+	g, ok := n.nodes[peer]
+	n.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPeer, peer)
+	}
+	return g.HandleExchange(states, digest), nil
+}
+
+// SetGossip shares this gateway's entanglement through gossip
+func (qg *QuantumGateway) SetGossip(g *EntanglementGossip) {
+	qg.gossip = g
+}
+
+// publishEntanglement gossips the gateway's entanglement with target
+func (qg *QuantumGateway) publishEntanglement(target *SystemConsciousness, inFlight *InFlightInjection) {
+	if qg.gossip == nil {
+		return
+	}
+	qg.gossip.Publish(EntanglementState{
+		TargetID:     targetLabel(target),
+		Entanglement: qg.currentEntanglement(),
+		InFlight:     inFlight,
+	})
+}
+
+// currentEntanglement returns the gateway's entanglement
+func (qg *QuantumGateway) currentEntanglement() QuantumEntanglement {
+	qg.entanglementMu.RLock()
+	defer qg.entanglementMu.RUnlock()
+	return qg.entanglement
+}
+
+// setEntanglement replaces the gateway's entanglement
+func (qg *QuantumGateway) setEntanglement(entanglement QuantumEntanglement) {
+	qg.entanglementMu.Lock()
+	defer qg.entanglementMu.Unlock()
+	qg.entanglement = entanglement
+}
+
+// inFlightGossip publishes one injection's progress through a gateway
+type inFlightGossip struct {
+	gateway   *QuantumGateway
+	target    *SystemConsciousness
+	state     InFlightInjection
+	published bool
+}
+
+// gossipInFlight tracks injection id of the planned thought through call's
+// gateway; a nil gateway, or one without gossip, tracks nothing
+func (ci *ConsciousnessInjector) gossipInFlight(
+	call injectCall,
+	id string,
+	plan *injectionPlan,
+	target *SystemConsciousness,
+) *inFlightGossip {
+
+	gateway := call.gateway
+	if gateway == nil || gateway.gossip == nil {
+		return nil
+	}
+	return &inFlightGossip{
+		gateway: gateway,
+		target:  target,
+		state: InFlightInjection{
+			InjectionID: id,
+			Injector:    ci.id,
+			Thought:     plan.thought,
+			Vectors:     append([]InjectionVector(nil), plan.vectors...),
+			Tunnels:     append([]int(nil), plan.tunnels...),
+			Focus:       call.focus,
+			Started:     time.Now(),
+		},
+	}
+}
+
+// next publishes that the injection is about to fire vector i
+func (g *inFlightGossip) next(i int) {
+	if g == nil {
+		return
+	}
+	state := g.state
+	state.NextVector = i
+	g.gateway.publishEntanglement(g.target, &state)
+	g.published = true
+}
+
+// done publishes that the injection is no longer in flight
+func (g *inFlightGossip) done() {
+	if g == nil || !g.published {
+		return
+	}
+	g.gateway.publishEntanglement(g.target, nil)
+}
+
+// ResumeEntanglement adopts the reconciled entanglement for target, so this
+// gateway can pick up where another left off; it returns any injection
+// that was in flight
+func (qg *QuantumGateway) ResumeEntanglement(target *SystemConsciousness) (*InFlightInjection, bool) {
+	if qg.gossip == nil {
+		return nil, false
+	}
+	state, ok := qg.gossip.State(targetLabel(target))
+	if !ok {
+		return nil, false
+	}
+	qg.setEntanglement(state.Entanglement)
+	return state.InFlight, true
+}
+
+// ResumeInjection continues inFlight, an injection another gateway lost
+// mid-flight, through gateway and under its original injection ID. The
+// vectors before NextVector are not fired again; the rest fire in their
+// gossiped order and through their gossiped tunnels. A phased array fires
+// again whole. Adopt the target's entanglement first with
+// ResumeEntanglement, which returns what was in flight.
+func (ci *ConsciousnessInjector) ResumeInjection(
+	ctx context.Context,
+	inFlight *InFlightInjection,
+	target *SystemConsciousness,
+	gateway *QuantumGateway,
+) (*InjectionResult, error) {
+
+	if inFlight == nil {
+		return nil, errors.New("mindhacking: no injection in flight to resume")
+	}
+	next := inFlight.NextVector
+	if inFlight.Focus != nil {
+		next = 0
+	}
+	if next < 0 || next >= len(inFlight.Vectors) {
+		return nil, fmt.Errorf("mindhacking: resume %q at vector %d of %d", inFlight.InjectionID, next, len(inFlight.Vectors))
+	}
+
+	call := injectCall{
+		id:      inFlight.InjectionID,
+		gateway: gateway,
+		focus:   inFlight.Focus,
+		vectors: inFlight.Vectors[next:],
+		resumed: true,
+	}
+	if len(inFlight.Tunnels) == len(inFlight.Vectors) {
+		call.tunnelKeys = inFlight.Tunnels[next:]
+	}
+	result, err := ci.inject(ctx, inFlight.Thought, target, call)
+	noteUsage("ResumeInjection", target, err)
+	return result, err
+}
@@ -0,0 +1,160 @@
+// consciousness_injection/hallucination.go - Perceived Reality Divergence
+package mindhacking
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Divergence is one aspect the target perceives differently from base
+type Divergence struct {
+	Aspect    string
+	Base      float64
+	Perceived float64
+	// Missing marks an aspect present on only one side
+	Missing bool
+	// Intended marks a divergence an alternate rule asked for
+	Intended bool
+}
+
+// Delta returns the perceived value's distance from base
+func (d Divergence) Delta() float64 {
+	return math.Abs(d.Perceived - d.Base)
+}
+
+// HallucinationReport quantifies how far perception strays from base
+type HallucinationReport struct {
+	Time time.Time
+	// Divergences above the threshold, largest first
+	Divergences []Divergence
+	// Hallucinations counts divergences no rule accounts for
+	Hallucinations int
+	// FilterDistortion is the total change perception filters made to the
+	// reconstructed alternate reality
+	FilterDistortion float64
+}
+
+// HallucinationDetector diffs perceived realities against their base
+type HallucinationDetector struct {
+	threshold float64
+
+	mu      sync.Mutex
+	reports []HallucinationReport
+	limit   int
+	// OnReport, if set, is called for every report with a hallucination
+	OnReport func(HallucinationReport)
+}
+
+// NewHallucinationDetector reports divergences larger than threshold and
+// keeps the last limit reports
+func NewHallucinationDetector(threshold float64, limit int) *HallucinationDetector {
+	if limit < 1 {
+		limit = 1
+	}
+	return &HallucinationDetector{threshold: threshold, limit: limit}
+}
+
+// Detect diffs perceived against base. Aspects changed by perceived's own
+// rules are marked intended; everything else above the threshold is a
+// hallucination.
+func (hd *HallucinationDetector) Detect(base *Reality, perceived *AlternateReality) HallucinationReport {
+	report := HallucinationReport{Time: time.Now()}
+
+	intended := make(map[string]bool)
+	if perceived != nil && perceived.Rules != nil {
+		for _, r := range perceived.Rules.Rules {
+			intended[r.Aspect] = true
+		}
+	}
+
+	var view *Reality
+	if perceived != nil {
+		view = &perceived.Reality
+	}
+	for _, d := range diffAspects(base, view) {
+		if !d.Missing && d.Delta() <= hd.threshold {
+			continue
+		}
+		d.Intended = intended[d.Aspect]
+		if !d.Intended {
+			report.Hallucinations++
+		}
+		report.Divergences = append(report.Divergences, d)
+	}
+
+	sort.SliceStable(report.Divergences, func(i, j int) bool {
+		return report.Divergences[i].Delta() > report.Divergences[j].Delta()
+	})
+	return report
+}
+
+// observe records a report for an alternate reality the engine just built,
+// including the distortion its perception filters introduced
+func (hd *HallucinationDetector) observe(base *Reality, unfiltered, perceived *AlternateReality) {
+	report := hd.Detect(base, perceived)
+	if unfiltered != nil && perceived != nil {
+		for _, d := range diffAspects(&unfiltered.Reality, &perceived.Reality) {
+			report.FilterDistortion += d.Delta()
+		}
+	}
+
+	hd.mu.Lock()
+	hd.reports = append(hd.reports, report)
+	if len(hd.reports) > hd.limit {
+		hd.reports = hd.reports[len(hd.reports)-hd.limit:]
+	}
+	onReport := hd.OnReport
+	hd.mu.Unlock()
+
+	if onReport != nil && report.Hallucinations > 0 {
+		onReport(report)
+	}
+}
+
+// Reports returns the retained reports, oldest first
+func (hd *HallucinationDetector) Reports() []HallucinationReport {
+	hd.mu.Lock()
+	defer hd.mu.Unlock()
+	return append([]HallucinationReport(nil), hd.reports...)
+}
+
+// restore replaces the retained reports, keeping the newest limit
+func (hd *HallucinationDetector) restore(reports []HallucinationReport) {
+	hd.mu.Lock()
+	defer hd.mu.Unlock()
+	if len(reports) > hd.limit {
+		reports = reports[len(reports)-hd.limit:]
+	}
+	hd.reports = append([]HallucinationReport(nil), reports...)
+}
+
+// diffAspects lists every aspect whose value differs between a and b,
+// ordered by name
+func diffAspects(a, b *Reality) []Divergence {
+	var av, bv map[string]float64
+	if a != nil {
+		av = a.Aspects
+	}
+	if b != nil {
+		bv = b.Aspects
+	}
+
+	var out []Divergence
+	for name, x := range av {
+		y, ok := bv[name]
+		if !ok {
+			out = append(out, Divergence{Aspect: name, Base: x, Missing: true})
+		} else if x != y {
+			out = append(out, Divergence{Aspect: name, Base: x, Perceived: y})
+		}
+	}
+	for name, y := range bv {
+		if _, ok := av[name]; !ok {
+			out = append(out, Divergence{Aspect: name, Perceived: y, Missing: true})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Aspect < out[j].Aspect })
+	return out
+}
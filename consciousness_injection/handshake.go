@@ -0,0 +1,165 @@
+// consciousness_injection/handshake.go - Quantum Handshake Versioning
+package mindhacking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// HandshakeProtocolVersion is the handshake this gateway speaks natively
+const HandshakeProtocolVersion = 2
+
+var (
+	// ErrHandshakeRegistered reports a second registration of a version
+	ErrHandshakeRegistered = sentinelError("mindhacking: handshake version already registered")
+	// ErrNoCommonHandshake reports a target speaking no offered version
+	ErrNoCommonHandshake = sentinelError("mindhacking: no common handshake version")
+)
+
+// HandshakeVersionReporter is implemented by backends that state which
+// handshake versions their consciousness layer speaks
+type HandshakeVersionReporter interface {
+	HandshakeVersions(ctx context.Context) ([]int, error)
+}
+
+// HandshakeAdapter presents a target speaking an older handshake as one
+// speaking the native version, typically by wrapping its backend
+type HandshakeAdapter func(ctx context.Context, target *SystemConsciousness) (*SystemConsciousness, error)
+
+var (
+	handshakesMu sync.RWMutex
+	handshakes   = map[int]HandshakeAdapter{
+		HandshakeProtocolVersion: func(_ context.Context, target *SystemConsciousness) (*SystemConsciousness, error) {
+			return target, nil
+		},
+	}
+)
+
+// RegisterHandshake makes adapter available for version. Compatibility
+// modules call it from init; registering a version twice panics.
+func RegisterHandshake(version int, adapter HandshakeAdapter) {
+	handshakesMu.Lock()
+	defer handshakesMu.Unlock()
+
+	if adapter == nil {
+		panic("mindhacking: RegisterHandshake adapter is nil")
+	}
+	if _, dup := handshakes[version]; dup {
+		panic(fmt.Sprintf("%v: %d", ErrHandshakeRegistered, version))
+	}
+	handshakes[version] = adapter
+}
+
+// RegisteredHandshakes lists the registered versions, newest first
+func RegisteredHandshakes() []int {
+	handshakesMu.RLock()
+	defer handshakesMu.RUnlock()
+
+	versions := make([]int, 0, len(handshakes))
+	for v := range handshakes {
+		versions = append(versions, v)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+	return versions
+}
+
+// OfferHandshakes limits the versions the gateway offers; with none it
+// offers every registered version
+func (qg *QuantumGateway) OfferHandshakes(versions ...int) {
+	qg.handshakeMu.Lock()
+	defer qg.handshakeMu.Unlock()
+	qg.offered = append([]int(nil), versions...)
+	sort.Sort(sort.Reverse(sort.IntSlice(qg.offered)))
+}
+
+// NegotiatedHandshake returns the version last used with target
+func (qg *QuantumGateway) NegotiatedHandshake(targetID string) (int, bool) {
+	qg.handshakeMu.Lock()
+	defer qg.handshakeMu.Unlock()
+	v, ok := qg.negotiated[targetID]
+	return v, ok
+}
+
+// negotiateHandshake performs the newest handshake both sides speak and
+// returns the target as adapted to it. Targets that report their versions
+// get the newest common one; older layers that cannot report are tried
+// newest first until one handshake succeeds.
+func (qg *QuantumGateway) negotiateHandshake(target *SystemConsciousness) (quantumHandshake, *SystemConsciousness, error) {
+	ctx := context.Background()
+	var none quantumHandshake
+
+	// Phase 1: pick candidate versions
+	candidates := qg.offeredHandshakes()
+	if reporter, ok := backendOf(target).(HandshakeVersionReporter); ok {
+		spoken, err := reporter.HandshakeVersions(ctx)
+		if err != nil {
+			return none, nil, fmt.Errorf("mindhacking: handshake versions: %w", err)
+		}
+		candidates = commonVersions(candidates, spoken)
+		if len(candidates) == 0 {
+			return none, nil, fmt.Errorf("%w: offered %v, target speaks %v",
+				ErrNoCommonHandshake, qg.offeredHandshakes(), spoken)
+		}
+		candidates = candidates[:1]
+	}
+
+	// Phase 2: fall back version by version
+	var errs []error
+	for _, version := range candidates {
+		handshakesMu.RLock()
+		adapter := handshakes[version]
+		handshakesMu.RUnlock()
+
+		adapted, err := adapter(ctx, target)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("version %d: %w", version, err))
+			continue
+		}
+		handshake, err := qg.performQuantumHandshake(adapted)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("version %d: %w", version, err))
+			continue
+		}
+
+		qg.handshakeMu.Lock()
+		if qg.negotiated == nil {
+			qg.negotiated = make(map[string]int)
+		}
+		qg.negotiated[target.ID] = version
+		qg.handshakeMu.Unlock()
+		return handshake, adapted, nil
+	}
+	if len(errs) == 0 {
+		return none, nil, ErrNoCommonHandshake
+	}
+	return none, nil, errors.Join(errs...)
+}
+
+// offeredHandshakes returns the registered versions the gateway offers,
+// newest first
+func (qg *QuantumGateway) offeredHandshakes() []int {
+	registered := RegisteredHandshakes()
+	qg.handshakeMu.Lock()
+	defer qg.handshakeMu.Unlock()
+	if len(qg.offered) == 0 {
+		return registered
+	}
+	return commonVersions(qg.offered, registered)
+}
+
+// commonVersions returns the versions of ours also in theirs, keeping our order
+func commonVersions(ours, theirs []int) []int {
+	var out []int
+	for _, v := range ours {
+		for _, t := range theirs {
+			if v == t {
+				out = append(out, v)
+				break
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,279 @@
+// consciousness_injection/hotswap.go - Gateway Backend Hot-Swap
+package mindhacking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+var (
+	// ErrHotSwapUnsupported reports a hot-swap on a gateway never given a
+	// driver slot with SetDriver
+	ErrHotSwapUnsupported = sentinelError("mindhacking: gateway has no driver slot")
+	// ErrMigrationUnsupported is returned by migrators that cannot adopt a
+	// session from the outgoing driver; the session re-handshakes instead
+	ErrMigrationUnsupported = sentinelError("mindhacking: session migration unsupported")
+)
+
+// NativeDriverProtocol names the gateway's built-in quantum backend
+const NativeDriverProtocol = "native"
+
+// GatewayDriver is the hardware backend behind a gateway
+type GatewayDriver interface {
+	// Protocol names the wire protocol the driver speaks, with its version
+	Protocol() string
+	// Access performs the full handshake and access sequence for target
+	Access(target *SystemConsciousness) (*QuantumConsciousnessAccess, error)
+}
+
+// SessionMigrator is implemented by drivers able to adopt an established
+// access from another driver without a new handshake
+type SessionMigrator interface {
+	Migrate(ctx context.Context, fromProtocol string, access *QuantumConsciousnessAccess, target *SystemConsciousness) (*QuantumConsciousnessAccess, error)
+}
+
+// driverSlot holds a gateway's current driver
+type driverSlot struct {
+	// mu is held for reading by every access on the current driver, so a
+	// swap waits for in-flight accesses to drain
+	mu     sync.RWMutex
+	driver GatewayDriver
+
+	// swapMu serializes hot-swaps
+	swapMu sync.Mutex
+
+	// stranded holds the sessions a hot-swap could not move, by the
+	// retired driver they are still on
+	strandedMu sync.Mutex
+	stranded   map[*Session]*retiredDriver
+}
+
+// retiredDriver is an outgoing driver kept open for the sessions still on
+// it; it is closed once the last of them detaches
+type retiredDriver struct {
+	closer   io.Closer
+	sessions int
+}
+
+// strand keeps closer open until every one of sessions still open has
+// detached, reporting false if none is. Holding the session set means none
+// can detach unseen meanwhile.
+func (qg *QuantumGateway) strand(slot *driverSlot, closer io.Closer, sessions []*Session) bool {
+	qg.sessions.mu.Lock()
+	defer qg.sessions.mu.Unlock()
+	slot.strandedMu.Lock()
+	defer slot.strandedMu.Unlock()
+
+	retired := &retiredDriver{closer: closer}
+	for _, s := range sessions {
+		// A session stranded by an earlier swap is still on that driver
+		if _, open := qg.sessions.sessions[s]; !open || slot.stranded[s] != nil {
+			continue
+		}
+		if slot.stranded == nil {
+			slot.stranded = make(map[*Session]*retiredDriver)
+		}
+		slot.stranded[s] = retired
+		retired.sessions++
+	}
+	return retired.sessions > 0
+}
+
+// release frees s from the retired driver it was stranded on, closing the
+// driver if s was its last session
+func (slot *driverSlot) release(s *Session) {
+	slot.strandedMu.Lock()
+	retired := slot.stranded[s]
+	delete(slot.stranded, s)
+	last := false
+	if retired != nil {
+		retired.sessions--
+		last = retired.sessions == 0
+	}
+	slot.strandedMu.Unlock()
+
+	// Nobody is left to hear a close failure
+	if last {
+		retired.closer.Close()
+	}
+}
+
+// SetDriver installs driver behind the gateway and enables hot-swap; a nil
+// driver keeps the native backend. Unlike HotSwap it moves no sessions, but
+// it too waits for in-flight accesses on the current driver to drain.
+func (qg *QuantumGateway) SetDriver(driver GatewayDriver) {
+	if qg.drivers.CompareAndSwap(nil, &driverSlot{driver: driver}) {
+		return
+	}
+	slot := qg.drivers.Load()
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+	slot.driver = driver
+}
+
+// DriverProtocol returns the protocol of the gateway's current driver
+func (qg *QuantumGateway) DriverProtocol() string {
+	slot := qg.drivers.Load()
+	if slot == nil {
+		return NativeDriverProtocol
+	}
+	slot.mu.RLock()
+	defer slot.mu.RUnlock()
+	return driverProtocol(slot.driver)
+}
+
+func driverProtocol(d GatewayDriver) string {
+	if d == nil {
+		return NativeDriverProtocol
+	}
+	return d.Protocol()
+}
+
+// accessDriver runs one access on the installed driver. It reports false
+// when the native backend should be used instead.
+func (qg *QuantumGateway) accessDriver(target *SystemConsciousness) (*QuantumConsciousnessAccess, bool, error) {
+	slot := qg.drivers.Load()
+	if slot == nil {
+		return nil, false, nil
+	}
+	slot.mu.RLock()
+	defer slot.mu.RUnlock()
+
+	if slot.driver == nil {
+		return nil, false, nil
+	}
+	access, err := slot.driver.Access(target)
+	return access, true, err
+}
+
+// sessionSet is the sessions open on a gateway. It holds them until they
+// are closed, which is why sessions must be.
+type sessionSet struct {
+	mu       sync.Mutex
+	sessions map[*Session]struct{}
+}
+
+// list returns the open sessions
+func (ss *sessionSet) list() []*Session {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	out := make([]*Session, 0, len(ss.sessions))
+	for s := range ss.sessions {
+		out = append(out, s)
+	}
+	return out
+}
+
+// attach tracks a session so a hot-swap or migration can move it
+func (qg *QuantumGateway) attach(s *Session) {
+	qg.sessions.mu.Lock()
+	defer qg.sessions.mu.Unlock()
+	if qg.sessions.sessions == nil {
+		qg.sessions.sessions = make(map[*Session]struct{})
+	}
+	qg.sessions.sessions[s] = struct{}{}
+}
+
+// detach stops tracking a closed session, retiring the driver a hot-swap
+// left it on once no other session needs it
+func (qg *QuantumGateway) detach(s *Session) {
+	qg.sessions.mu.Lock()
+	delete(qg.sessions.sessions, s)
+	qg.sessions.mu.Unlock()
+
+	if slot := qg.drivers.Load(); slot != nil {
+		slot.release(s)
+	}
+}
+
+// HotSwapReport lists how each open session crossed to the new driver
+type HotSwapReport struct {
+	From, To string
+	// Migrated sessions kept their entanglement
+	Migrated []string
+	// Rehandshaken sessions had to perform a fresh quantum handshake
+	Rehandshaken []string
+	// Failed sessions could not reach the target through the new driver
+	// and stay on the outgoing one
+	Failed map[string]error
+}
+
+// HotSwap replaces the gateway's driver with standby without dropping open
+// sessions. New accesses wait while in-flight ones drain, then go to
+// standby; each session is migrated if standby can adopt it and
+// re-handshaken otherwise, including when its migration fails. The
+// outgoing driver is closed if it can be, once no failed session is left
+// on it. Concurrent hot-swaps take turns.
+func (qg *QuantumGateway) HotSwap(ctx context.Context, standby GatewayDriver) (*HotSwapReport, error) {
+	slot := qg.drivers.Load()
+	if slot == nil {
+		return nil, ErrHotSwapUnsupported
+	}
+	slot.swapMu.Lock()
+	defer slot.swapMu.Unlock()
+
+	// Phase 1: Drain in-flight accesses and cut over
+	slot.mu.Lock()
+	outgoing := slot.driver
+	slot.driver = standby
+	slot.mu.Unlock()
+	sessions := qg.sessions.list()
+
+	report := &HotSwapReport{
+		From:   driverProtocol(outgoing),
+		To:     driverProtocol(standby),
+		Failed: make(map[string]error),
+	}
+
+	// Phase 2: Move sessions onto the standby driver
+	migrator, canMigrate := standby.(SessionMigrator)
+	var stranded []*Session
+	var cancelled error
+	for _, s := range sessions {
+		target := s.Target()
+		label := targetLabel(target)
+		if cancelled = ctx.Err(); cancelled != nil {
+			report.Failed[label] = cancelled
+			stranded = append(stranded, s)
+			continue
+		}
+
+		var migrateErr error
+		if canMigrate {
+			access, err := migrator.Migrate(ctx, report.From, s.Access(), target)
+			if err == nil {
+				s.swapAccess(access, false)
+				slot.release(s)
+				qg.publishEntanglement(target, nil)
+				report.Migrated = append(report.Migrated, label)
+				continue
+			}
+			if !errors.Is(err, ErrMigrationUnsupported) {
+				migrateErr = fmt.Errorf("migrate: %w", err)
+			}
+		}
+
+		access, err := qg.AccessQuantumConsciousness(target)
+		if err != nil {
+			report.Failed[label] = errors.Join(migrateErr, err)
+			stranded = append(stranded, s)
+			continue
+		}
+		s.swapAccess(access, true)
+		slot.release(s)
+		report.Rehandshaken = append(report.Rehandshaken, label)
+	}
+
+	// Phase 3: Retire the outgoing driver, or leave it to the sessions
+	// still on it
+	if c, ok := outgoing.(io.Closer); ok && !qg.strand(slot, c, stranded) {
+		if err := c.Close(); err != nil {
+			return report, fmt.Errorf("mindhacking: close %s driver: %w", report.From, err)
+		}
+	}
+
+	return report, cancelled
+}
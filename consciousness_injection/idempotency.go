@@ -0,0 +1,140 @@
+// consciousness_injection/idempotency.go - Idempotent Injections
+package mindhacking
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultIdempotencyWindow is how long an injector remembers idempotency
+// keys unless configured WithIdempotencyWindow
+const DefaultIdempotencyWindow = 10 * time.Minute
+
+// idempotencyCache remembers, per target, the injections made under each
+// idempotency key within the window, so a retry returns the first outcome
+// instead of shifting the target a second time
+type idempotencyCache struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	targets map[string]map[string]*idempotentInjection
+	// swept is when every target's keys were last expired
+	swept time.Time
+}
+
+// idempotentInjection is one key's injection, in flight until done closes.
+// Finished, it holds its result, or the error it failed with after a
+// thought had landed.
+type idempotentInjection struct {
+	done     chan struct{}
+	result   *InjectionResult
+	err      error
+	finished bool
+	at       time.Time
+}
+
+func newIdempotencyCache(window time.Duration) *idempotencyCache {
+	if window <= 0 {
+		window = DefaultIdempotencyWindow
+	}
+	return &idempotencyCache{window: window, targets: make(map[string]map[string]*idempotentInjection)}
+}
+
+// claim returns the outcome an earlier injection under key already had, or
+// claims key for the caller, who must call finish with the outcome and
+// whether any thought landed. A retry arriving while the first is in flight
+// waits for it. Injections that fail before landing anything release their
+// key, so they can be retried for real; those that fail after keep it, and
+// retries fail the same way rather than shift the target again.
+func (c *idempotencyCache) claim(
+	ctx context.Context,
+	target *SystemConsciousness,
+	key string,
+) (prior *InjectionResult, finish func(*InjectionResult, error, bool), err error) {
+
+	targetID := targetLabel(target)
+	for {
+		c.mu.Lock()
+		now := time.Now()
+		c.sweepLocked(now)
+		keys := c.targets[targetID]
+		if keys == nil {
+			keys = make(map[string]*idempotentInjection)
+			c.targets[targetID] = keys
+		}
+		entry, ok := keys[key]
+		if !ok || c.expired(entry, now) {
+			entry = &idempotentInjection{done: make(chan struct{})}
+			keys[key] = entry
+			c.mu.Unlock()
+			return nil, func(result *InjectionResult, err error, landed bool) {
+				c.finish(targetID, key, entry, result, err, landed)
+			}, nil
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-entry.done:
+		}
+		switch {
+		case entry.result != nil:
+			duplicate := *entry.result
+			duplicate.Duplicate = true
+			return &duplicate, nil, nil
+		case entry.err != nil:
+			return nil, nil, entry.err
+		}
+		// The first attempt failed and let go of the key; try to claim it
+	}
+}
+
+func (c *idempotencyCache) finish(
+	targetID, key string,
+	entry *idempotentInjection,
+	result *InjectionResult,
+	err error,
+	landed bool,
+) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case err == nil && result != nil:
+		entry.result = result
+	case err != nil && landed:
+		entry.err = err
+	default:
+		if c.targets[targetID][key] == entry {
+			delete(c.targets[targetID], key)
+		}
+	}
+	entry.finished, entry.at = true, time.Now()
+	close(entry.done)
+}
+
+// expired reports whether entry finished before the window
+func (c *idempotencyCache) expired(entry *idempotentInjection, now time.Time) bool {
+	return entry.finished && now.Sub(entry.at) > c.window
+}
+
+// sweepLocked forgets every target's expired keys, once a window at most;
+// between sweeps, claims skip the expired keys they meet
+func (c *idempotencyCache) sweepLocked(now time.Time) {
+	if now.Sub(c.swept) < c.window {
+		return
+	}
+	c.swept = now
+	for targetID, keys := range c.targets {
+		for key, entry := range keys {
+			if c.expired(entry, now) {
+				delete(keys, key)
+			}
+		}
+		if len(keys) == 0 {
+			delete(c.targets, targetID)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// consciousness_injection/injection_plan.go - Injection Planning
+package mindhacking
+
+import "context"
+
+// injectionPlan is what an injection is about to fire: the resonance it
+// tunes to, the vectors in the order they will be tried and the thought as
+// it will travel. Injections and dry runs plan alike, so a dry run predicts
+// the injection that would really happen.
+type injectionPlan struct {
+	resonance ConsciousnessResonance
+	region    *ConsciousnessRegion
+	route     RouteRequest
+	vectors   []InjectionVector
+	// tunnels keys each vector's tunnel; nil keeps the vectors' positions
+	tunnels []int
+
+	// thought is the thought as records carry it, sealed if it was; payload
+	// is the localized thought that is encoded
+	thought InjectedThought
+	payload InjectedThought
+	// local is payload before sealing, for the models of the target the
+	// injector runs itself; it never leaves the process
+	local        InjectedThought
+	localization *ThoughtLocalization
+	encoded      EncodedThought
+	// unpacked is the encoded thought before compression, for the stats of
+	// injections that fire it
+	unpacked EncodedThought
+
+	timing PhaseTiming
+}
+
+// plan runs the resonance analysis and encoding phases under call's
+// overrides: it measures the target, routes region-addressed thoughts,
+// orders and routes the vectors, then localizes, seals and encodes the
+// thought
+func (ci *ConsciousnessInjector) plan(
+	ctx context.Context,
+	thought InjectedThought,
+	target *SystemConsciousness,
+	call injectCall,
+) (*injectionPlan, error) {
+
+	elapsed := lap()
+	p := &injectionPlan{thought: thought, vectors: ci.vectors()}
+	if call.vectors != nil {
+		p.vectors = call.vectors
+	}
+
+	// Phase 1: Consciousness Resonance Analysis
+	if replay := call.replay; replay != nil {
+		p.resonance, p.vectors, p.region = replay.Resonance, replay.Vectors, replay.Region
+	} else {
+		var err error
+		if p.resonance, err = ci.resonate(ctx, target); err != nil {
+			return nil, err
+		}
+
+		// Route region-addressed thoughts to the vectors reaching the region
+		if thought.Region != "" {
+			routed, regionVectors, err := ci.routeToRegion(ctx, target, p.resonance, p.vectors, thought.Region)
+			if err != nil {
+				return nil, err
+			}
+			p.region = &routed
+			if !call.resumed {
+				p.vectors = regionVectors
+			}
+		}
+
+		// Phased arrays fire every vector at once, so order is moot, and a
+		// resumed injection keeps the order it was gossiped in
+		if call.focus == nil && !call.resumed {
+			p.vectors = ci.orderVectors(ctx, target, p.vectors)
+		}
+	}
+	p.route = RouteRequest{Target: target, Resonance: p.resonance}
+	if p.region != nil {
+		p.route.Region = p.region.ID
+	}
+	switch {
+	case call.replay != nil:
+		// Replay through the tunnels the recording went through
+		if len(call.replay.Tunnels) == len(p.vectors) {
+			p.tunnels = call.replay.Tunnels
+		}
+	case call.resumed:
+		if len(call.tunnelKeys) == len(p.vectors) {
+			p.tunnels = call.tunnelKeys
+		}
+	case call.focus == nil:
+		p.vectors, p.tunnels = ci.route(ctx, p.route, p.vectors)
+	}
+
+	// Phase 2: Localization and Quantum Thought Encoding
+	var err error
+	if call.prepared != nil {
+		p.payload, p.local, p.localization = call.prepared.payload, call.prepared.local, call.prepared.localization
+	} else if p.payload, p.localization, err = ci.localize(ctx, thought, target); err != nil {
+		return nil, err
+	} else {
+		p.local = p.payload
+	}
+	if p.payload, p.localization, err = ci.seal(p.payload, p.localization, target); err != nil {
+		return nil, err
+	}
+	if p.payload.Sealed {
+		// Records and results carry the sealed thought, never plaintext
+		p.thought = p.payload
+	}
+	if _, _, _, err = ci.schemeFor(p.payload); err != nil {
+		return nil, err
+	}
+	p.timing.Resonance = elapsed()
+	p.unpacked = ci.encode(ctx, p.payload, p.resonance)
+	p.encoded = ci.compression.pack(p.unpacked, target)
+	p.timing.Encoding = elapsed()
+	return p, nil
+}
@@ -0,0 +1,87 @@
+// consciousness_injection/injection_result.go - Injection Outcomes
+package mindhacking
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// InjectionResult reports the outcome of one InjectThought call
+type InjectionResult struct {
+	// InjectionID identifies this injection in events and webhooks
+	InjectionID        string
+	TargetID           string
+	InjectedThought    InjectedThought
+	Success            bool
+	ConsciousnessShift float64
+	// NormalizedShift is ConsciousnessShift z-scored against NoiseFloor;
+	// it is only meaningful when NoiseFloor is set
+	NormalizedShift float64
+	NoiseFloor      *NoiseFloor
+	Evidence        InjectionEvidence
+	Clock           VectorClock
+	EvidenceLink    *EvidenceLink
+	// Memory is the thought's trace in the target's memory after acceptance
+	Memory *MemoryTrace
+	// Region is the shift in the region the thought was addressed to
+	Region *RegionShift
+	// Deferred is how long load throttling held the injection back
+	Deferred time.Duration
+	// Components is the per-component outcome when the thought has them
+	Components []ComponentResult
+	// Degraded lists the subsystems the injection went without
+	Degraded []Subsystem
+	// Duplicate marks the result of an earlier injection under the same
+	// idempotency key, returned again without injecting
+	Duplicate bool
+	// Equivalent is the accepted interaction a semantically equivalent
+	// thought was merged into without firing
+	Equivalent *InteractionMatch
+	// Provenance is the configuration the injection ran with
+	Provenance Provenance
+
+	// unsealed is a sealed injection's thought as it was given, kept in
+	// memory only so ReinjectRejected can seal its rejected parts again
+	unsealed *InjectedThought
+}
+
+// InjectionEvidence is what the injection attempts left behind
+type InjectionEvidence struct {
+	TargetID     string
+	Attempts     []InjectionAttempt
+	Localization *ThoughtLocalization
+	Ramps        []AppliedRamp
+	Focus        *ArrayFocus
+	Components   []ComponentResult
+}
+
+// InjectionAttempt is the outcome of firing one vector through one tunnel
+type InjectionAttempt struct {
+	Vector InjectionVector
+	// Tunnel is the key of the tunnel the vector fired through
+	Tunnel   int
+	Success  bool
+	Duration time.Duration
+	Error    string
+	// err is the failure behind Error when it is known here, kept so the
+	// injection can tell why its attempts failed
+	err error
+}
+
+// ConsciousnessResonance is the target's measured resonance before encoding
+type ConsciousnessResonance struct {
+	Frequency float64
+	Phase     float64
+	Strength  float64
+	Signature []float64
+}
+
+// newInjectionID returns a random injection ID
+func newInjectionID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("mindhacking: no randomness for injection ID: " + err.Error())
+	}
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,282 @@
+// consciousness_injection/injector_options.go - Injector Construction
+package mindhacking
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// InjectorOption configures a ConsciousnessInjector at construction time
+type InjectorOption func(*ConsciousnessInjector)
+
+// NewConsciousnessInjector creates an injector firing the given vectors
+func NewConsciousnessInjector(
+	vectors []InjectionVector,
+	opts ...InjectorOption,
+) *ConsciousnessInjector {
+
+	ci := &ConsciousnessInjector{
+		injectionVectors: vectors,
+		limiter:          newTargetLimiter(1, OrderFIFO),
+		id:               newInjectorID(),
+		idempotency:      newIdempotencyCache(DefaultIdempotencyWindow),
+		ledger:           newInjectionLedger(DefaultRetractableInjections),
+	}
+
+	for _, opt := range opts {
+		opt(ci)
+	}
+
+	return ci
+}
+
+// WithMaxInFlightPerTarget bounds concurrent injections into one target
+func WithMaxInFlightPerTarget(n int) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		if n < 1 {
+			n = 1
+		}
+		ci.limiter = newTargetLimiter(n, ci.limiter.ordering)
+	}
+}
+
+// WithTargetOrdering selects how queued injections into one target are admitted
+func WithTargetOrdering(ordering TargetOrdering) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.limiter = newTargetLimiter(ci.limiter.maxInFlight, ordering)
+	}
+}
+
+// WithInjectorID names the injector in vector clocks and evidence
+func WithInjectorID(id string) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.id = id
+	}
+}
+
+// WithCausalityTracker stamps every event on a target with a vector clock
+// shared with other injectors using the same tracker
+func WithCausalityTracker(tracker *CausalityTracker) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.causality = tracker
+	}
+}
+
+// WithEvidenceChain appends every injection's evidence to a signed chain
+func WithEvidenceChain(chain *EvidenceChain) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.evidence = chain
+	}
+}
+
+// WithTenantEvidence appends every injection's evidence to its target
+// tenant's chain, replacing any WithEvidenceChain chain
+func WithTenantEvidence(evidence *TenantEvidence) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.tenantEvidence = evidence
+	}
+}
+
+// WithAuditSink records every injection to sink
+func WithAuditSink(sink AuditSink) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.audit = sink
+	}
+}
+
+// WithNoiseFloor normalizes reported shifts against each target's drift
+func WithNoiseFloor(estimator *NoiseFloorEstimator) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.noise = estimator
+	}
+}
+
+// WithSessionRecorder captures every injection's inputs for later replay
+func WithSessionRecorder(recorder *SessionRecorder) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.recorder = recorder
+	}
+}
+
+// WithThoughtLocalizer adapts thoughts to each target's locale before encoding
+func WithThoughtLocalizer(localizer ThoughtLocalizer) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.localizer = localizer
+	}
+}
+
+// WithAcceptancePredictor replaces the dry-run acceptance model
+func WithAcceptancePredictor(predictor AcceptancePredictor) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.predictor = predictor
+	}
+}
+
+// WithRampProfile ramps every vector without its own profile
+func WithRampProfile(profile RampProfile) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.ramp = &profile
+	}
+}
+
+// WithLoadThrottle defers injections until the target's self-reported load
+// is below throttle's threshold
+func WithLoadThrottle(throttle LoadThrottle) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.throttle = &throttle
+	}
+}
+
+// WithStopConditions refuses injections into any target conditions halt,
+// checked before the injection and again before every vector
+func WithStopConditions(conditions *StopConditions) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.stops = conditions
+	}
+}
+
+// WithCircuitBreaker short-circuits injections into targets, and through
+// vectors, whose circuits breaker has opened
+func WithCircuitBreaker(breaker *CircuitBreaker) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.breaker = breaker
+	}
+}
+
+// WithInteractionMemory records a summary of every injection in memory
+func WithInteractionMemory(memory *InteractionMemory) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.interactions = memory
+	}
+}
+
+// WithEventBus publishes the outcome of every injection on bus
+func WithEventBus(bus *EventBus) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.events = bus
+	}
+}
+
+// WithDegradation keeps injecting through evidence and metrics failures
+// as policy describes, instead of failing the injection
+func WithDegradation(policy DegradationPolicy) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.degrade = newDegradation(policy)
+	}
+}
+
+// WithShiftSeries records every observed shift in series
+func WithShiftSeries(series *ShiftSeries) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.series = series
+	}
+}
+
+// WithStrategies replaces the injection phases strategies sets, leaving
+// the rest built in; see ResolveStrategies for choosing them by name
+func WithStrategies(strategies PhaseStrategies) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.strategies = strategies
+	}
+}
+
+// WithTunnelRouter orders the vectors and tunnels each injection tries
+// with router, in place of the order the vector phase leaves them in
+func WithTunnelRouter(router TunnelRouter) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.router = router
+	}
+}
+
+// WithTunnelMux carries injections made outside sessions over mux's shared
+// tunnels instead of a new tunnel per attempt
+func WithTunnelMux(mux *TunnelMux) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.mux = mux
+	}
+}
+
+// WithResonanceSampling has native targets' signals sampled for resonance
+// analysis before every injection into them. Sampling holds the injection
+// for Samples/Rate seconds, a second by default, so without this option
+// native targets resonate at zero.
+func WithResonanceSampling(sampling ResonanceSampling) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.sampling = &sampling
+	}
+}
+
+// WithEncoder offloads thought encoding to encoder, keeping the in-process
+// encoder as fallback; see OpenEncoder for choosing one by name
+func WithEncoder(encoder Encoder) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.encoder = encoder
+	}
+}
+
+// WithEncodingScheme encodes thoughts that name no scheme of their own
+// with the scheme registered under name, tuned by options
+func WithEncodingScheme(name string, options SchemeOptions) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.scheme, ci.schemeOptions = name, options
+	}
+}
+
+// WithCompression compresses large encoded thoughts for the tunnels of
+// targets that share a codec with the injector
+func WithCompression(cfg CompressionConfig) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.compression = newCompression(cfg)
+	}
+}
+
+// WithTransitEncryption seals every encoded thought for its tunnel with the
+// secret its vector's entanglement shares with the target, so intermediary
+// tunnels, federated ones included, can neither read nor alter it
+func WithTransitEncryption(secret EntanglementSecret) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.transit = secret
+	}
+}
+
+// WithIdempotencyWindow sets how long the injector remembers idempotency
+// keys, per target, default DefaultIdempotencyWindow
+func WithIdempotencyWindow(window time.Duration) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.idempotency = newIdempotencyCache(window)
+	}
+}
+
+// WithSemanticDedup skips or merges thoughts equivalent to ones a target
+// already accepted, as found in the injector's interaction memory
+func WithSemanticDedup(dedup SemanticDedup) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		ci.dedup = &dedup
+	}
+}
+
+// WithRetractableInjections sets how many accepted injections without a TTL
+// stay retractable, default DefaultRetractableInjections; older ones are
+// forgotten first, and 0 keeps only thoughts with a TTL
+func WithRetractableInjections(n int) InjectorOption {
+	return func(ci *ConsciousnessInjector) {
+		if n < 0 {
+			n = 0
+		}
+		ci.ledger = newInjectionLedger(n)
+	}
+}
+
+// ID returns the injector's identity in clocks and evidence
+func (ci *ConsciousnessInjector) ID() string {
+	return ci.id
+}
+
+func newInjectorID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "injector"
+	}
+	return "injector-" + hex.EncodeToString(b[:])
+}
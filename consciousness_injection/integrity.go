@@ -0,0 +1,148 @@
+// consciousness_injection/integrity.go - Thought Integrity Tags
+package mindhacking
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrThoughtCorrupted reports an encoded thought that arrived at the target
+// differing from what the injector sent, typically mangled by tunnel noise
+var ErrThoughtCorrupted = sentinelError("mindhacking: thought corrupted in transit")
+
+// integrityTag hashes what the tunnel carries of an encoded thought: its
+// header and its wire bytes, whichever form they take
+func (e EncodedThought) integrityTag(targetID string) ([]byte, error) {
+	header, err := e.transitHeader(targetID)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write([]byte("mindhacking thought integrity v1"))
+	h.Write(header)
+	switch {
+	case e.Ciphertext != nil:
+		h.Write(e.Ciphertext)
+	case e.Codec != "":
+		h.Write(e.Compressed)
+	default:
+		h.Write(packSymbols(e.Symbols))
+	}
+	return h.Sum(nil), nil
+}
+
+// TagIntegrity attaches the integrity tag the target checks the thought
+// against. The tag is a plain hash: it catches noise, while tampering is
+// caught by sealing the thought in transit.
+func TagIntegrity(encoded EncodedThought, targetID string) (EncodedThought, error) {
+	tag, err := encoded.integrityTag(targetID)
+	if err != nil {
+		return EncodedThought{}, err
+	}
+	encoded.Integrity = tag
+	return encoded, nil
+}
+
+// VerifyIntegrity checks a received thought against its integrity tag.
+// Thoughts without a tag cannot be trusted either.
+func (e EncodedThought) VerifyIntegrity(targetID string) error {
+	if len(e.Integrity) == 0 {
+		return fmt.Errorf("%w: no integrity tag", ErrThoughtCorrupted)
+	}
+	tag, err := e.integrityTag(targetID)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(tag, e.Integrity) != 1 {
+		return ErrThoughtCorrupted
+	}
+	return nil
+}
+
+// TunnelCarrier is implemented by reality tunnels that carry thoughts in
+// Go: Carry moves an encoded thought to the target's end and returns it as
+// it arrived there. Other tunnels are native, and the target's end of the
+// native tunnel checks the tag it is handed with the thought.
+type TunnelCarrier interface {
+	Carry(ctx context.Context, encoded EncodedThought) (EncodedThought, error)
+}
+
+// ReceiveThought is the target's end of a tunnel: injections hand it each
+// thought a TunnelCarrier delivers before committing it. It verifies the
+// integrity tag, opens the thought if it was sealed in transit and decodes
+// its payload; a thought that fails any step is not committed.
+func ReceiveThought(encoded EncodedThought, secret []byte, targetID string) ([]byte, error) {
+	if err := encoded.VerifyIntegrity(targetID); err != nil {
+		return nil, err
+	}
+	opened, err := encoded.OpenInTransit(secret, targetID)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := DecodeThought(opened)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrThoughtCorrupted, err)
+	}
+	return payload, nil
+}
+
+// receiveThroughTunnel carries encoded through tunnel to target, committing
+// it only once the target's end verifies it. A TunnelCarrier's thought is
+// received here as it arrived; a native tunnel's is verified at the target
+// by executeInjectionThroughTunnel. Attempts whose thought arrived
+// corrupted fail with ErrThoughtCorrupted.
+func (ci *ConsciousnessInjector) receiveThroughTunnel(
+	ctx context.Context,
+	tunnel RealityTunnel,
+	vector InjectionVector,
+	encoded EncodedThought,
+	target *SystemConsciousness,
+) InjectionAttempt {
+
+	carrier, ok := interface{}(tunnel).(TunnelCarrier)
+	if !ok {
+		return ci.executeInjectionThroughTunnel(ctx, tunnel, encoded, target)
+	}
+	arrived, err := carrier.Carry(ctx, encoded)
+	if err != nil {
+		return InjectionAttempt{Vector: vector, Error: err.Error(), err: err}
+	}
+	var secret []byte
+	if arrived.Ciphertext != nil && ci.transit != nil {
+		if secret, err = ci.transit(vector, target); err != nil {
+			err = fmt.Errorf("%w: %v", ErrTransitKey, err)
+			return InjectionAttempt{Vector: vector, Error: err.Error(), err: err}
+		}
+	}
+	if _, err := ReceiveThought(arrived, secret, targetLabel(target)); err != nil {
+		return InjectionAttempt{Vector: vector, Error: err.Error(), err: err}
+	}
+	return ci.executeInjectionThroughTunnel(ctx, tunnel, arrived, target)
+}
+
+// corrupted reports whether attempt failed because its thought arrived
+// corrupted. The native end of a tunnel reports that by message alone.
+func (attempt InjectionAttempt) corrupted() bool {
+	if attempt.err != nil {
+		return errors.Is(attempt.err, ErrThoughtCorrupted)
+	}
+	return strings.HasPrefix(attempt.Error, ErrThoughtCorrupted.Error())
+}
+
+// allCorrupted fails with ErrThoughtCorrupted when every attempt did, so
+// an injection whose thought never arrived intact says so
+func allCorrupted(attempts []InjectionAttempt) error {
+	if len(attempts) == 0 {
+		return nil
+	}
+	for _, attempt := range attempts {
+		if !attempt.corrupted() {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: on all %d attempts", ErrThoughtCorrupted, len(attempts))
+}
@@ -0,0 +1,235 @@
+// consciousness_injection/interaction_memory.go - Long-Term Interaction Memory
+package mindhacking
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Embedder turns interaction summaries into vectors whose cosine
+// similarity tracks how alike the thoughts are
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float64, error)
+}
+
+// HashingEmbedder embeds text as a hashed bag of words. It needs no model
+// and catches thoughts sharing vocabulary, not paraphrases; plug in a real
+// embedding backend for that.
+type HashingEmbedder struct {
+	// Dimensions of the embedding, default 256
+	Dimensions int
+}
+
+// Embed hashes every lowercased word of text into one dimension
+func (e HashingEmbedder) Embed(_ context.Context, text string) ([]float64, error) {
+	dims := e.Dimensions
+	if dims < 1 {
+		dims = 256
+	}
+	out := make([]float64, dims)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		h := fnv.New32a()
+		h.Write([]byte(word))
+		out[h.Sum32()%uint32(dims)]++
+	}
+	return out, nil
+}
+
+// Interaction summarizes one injection into a target
+type Interaction struct {
+	TargetID string    `json:"target"`
+	At       time.Time `json:"at"`
+	// Summary is the text the interaction was embedded from
+	Summary  string    `json:"summary"`
+	Key      MemoryKey `json:"key"`
+	Accepted bool      `json:"accepted"`
+	Shift    float64   `json:"shift"`
+	Vector   string    `json:"vector,omitempty"`
+	// Embedding is empty for thoughts with no text to embed
+	Embedding []float64 `json:"embedding,omitempty"`
+}
+
+// InteractionMatch is a past interaction resembling a queried thought
+type InteractionMatch struct {
+	Interaction Interaction
+	// Score is the cosine similarity, 1 for the identical thought
+	Score float64
+}
+
+// InteractionMemory keeps every interaction with every target, searchable
+// by similarity so operators can check what a target has already been
+// exposed to before launching a campaign
+type InteractionMemory struct {
+	embedder Embedder
+
+	mu       sync.RWMutex
+	byTarget map[string][]Interaction
+}
+
+// NewInteractionMemory returns an empty memory embedding with embedder,
+// HashingEmbedder if nil
+func NewInteractionMemory(embedder Embedder) *InteractionMemory {
+	if embedder == nil {
+		embedder = HashingEmbedder{}
+	}
+	return &InteractionMemory{embedder: embedder, byTarget: make(map[string][]Interaction)}
+}
+
+// Record summarizes and stores result
+func (m *InteractionMemory) Record(ctx context.Context, result *InjectionResult) error {
+	if m == nil || result == nil {
+		return nil
+	}
+	interaction := Interaction{
+		TargetID: result.TargetID,
+		At:       time.Now().UTC(),
+		Summary:  thoughtSummary(result.InjectedThought),
+		Key:      memoryKey(result.InjectedThought),
+		Accepted: result.Success,
+		Shift:    result.ConsciousnessShift,
+	}
+	for _, attempt := range result.Evidence.Attempts {
+		if attempt.Success {
+			interaction.Vector = vectorLabel(&attempt.Vector)
+			break
+		}
+	}
+	if interaction.Summary != "" {
+		embedding, err := m.embedder.Embed(ctx, interaction.Summary)
+		if err != nil {
+			return fmt.Errorf("mindhacking: embed interaction: %w", err)
+		}
+		interaction.Embedding = embedding
+	}
+
+	m.mu.Lock()
+	m.byTarget[interaction.TargetID] = append(m.byTarget[interaction.TargetID], interaction)
+	m.mu.Unlock()
+	return nil
+}
+
+// Interactions returns every interaction with targetID, oldest first
+func (m *InteractionMemory) Interactions(targetID string) []Interaction {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Interaction(nil), m.byTarget[targetID]...)
+}
+
+// Similar returns up to limit past interactions with targetID scoring at
+// least minScore against thought, most similar first. An empty targetID
+// searches every target.
+func (m *InteractionMemory) Similar(
+	ctx context.Context,
+	targetID string,
+	thought InjectedThought,
+	limit int,
+	minScore float64,
+) ([]InteractionMatch, error) {
+
+	key := memoryKey(thought)
+	var query []float64
+	if summary := thoughtSummary(thought); summary != "" {
+		var err error
+		if query, err = m.embedder.Embed(ctx, summary); err != nil {
+			return nil, fmt.Errorf("mindhacking: embed query: %w", err)
+		}
+	}
+
+	m.mu.RLock()
+	var matches []InteractionMatch
+	for id, interactions := range m.byTarget {
+		if targetID != "" && id != targetID {
+			continue
+		}
+		for _, interaction := range interactions {
+			score := cosineSimilarity(query, interaction.Embedding)
+			if interaction.Key == key {
+				score = 1
+			}
+			if score >= minScore && score > 0 {
+				matches = append(matches, InteractionMatch{Interaction: interaction, Score: score})
+			}
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Interaction.At.After(matches[j].Interaction.At)
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// Save writes every interaction as JSON lines
+func (m *InteractionMemory) Save(w io.Writer) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	targets := make([]string, 0, len(m.byTarget))
+	for id := range m.byTarget {
+		targets = append(targets, id)
+	}
+	sort.Strings(targets)
+
+	enc := json.NewEncoder(w)
+	for _, id := range targets {
+		for _, interaction := range m.byTarget[id] {
+			if err := enc.Encode(interaction); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Load adds interactions written by Save. Embeddings are kept as saved, so
+// load into a memory using the same embedder that produced them.
+func (m *InteractionMemory) Load(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for {
+		var interaction Interaction
+		err := dec.Decode(&interaction)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("mindhacking: load interactions: %w", err)
+		}
+		m.byTarget[interaction.TargetID] = append(m.byTarget[interaction.TargetID], interaction)
+	}
+}
+
+// thoughtSummary is the text a thought is embedded from
+func thoughtSummary(thought InjectedThought) string {
+	parts := []string{thought.Content}
+	for _, c := range thought.Components {
+		parts = append(parts, c.Content)
+	}
+	return strings.TrimSpace(strings.Join(parts, " "))
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	return cosine(a, b)
+}
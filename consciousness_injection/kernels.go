@@ -0,0 +1,37 @@
+// consciousness_injection/kernels.go - Vector Math Kernels
+package mindhacking
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// The resonance scoring hot loops run on dot3, and the encoding schemes
+// modulate symbols with modulate. The default build uses pure-Go kernels,
+// dot3 unrolled so the compiler can keep independent accumulators in
+// registers; building with -tags mindhack_simd on amd64 swaps in SSE2
+// assembly kernels. The dot3 kernels sum in different orders, so their
+// results may differ in the last bits; the modulate kernels agree exactly.
+
+// byteRotors and byteLevels are the unit phasor and the magnitude each byte
+// modulates to, so modulation multiplies instead of calling into math
+var byteRotors, byteLevels = modulationTables()
+
+func modulationTables() (rotors, levels *[256]complex128) {
+	rotors, levels = new([256]complex128), new([256]complex128)
+	for b := range rotors {
+		rotors[b] = cmplx.Rect(1, byteAngle(byte(b)))
+		levels[b] = complex(byteLevel(byte(b)), 0)
+	}
+	return rotors, levels
+}
+
+// cosine is the cosine similarity of a and b over their common length, 0
+// when either is all zeros there
+func cosine(a, b []float64) float64 {
+	ab, aa, bb := dot3(a, b)
+	if aa == 0 || bb == 0 {
+		return 0
+	}
+	return ab / math.Sqrt(aa*bb)
+}
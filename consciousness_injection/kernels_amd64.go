@@ -0,0 +1,15 @@
+// consciousness_injection/kernels_amd64.go - SSE2 Math Kernels
+//go:build amd64 && mindhack_simd
+
+package mindhacking
+
+// dot3 returns a·b, a·a and b·b over the common length of a and b
+//
+//go:noescape
+func dot3(a, b []float64) (ab, aa, bb float64)
+
+// modulate sets dst[i] to table[src[i]] times carrier over the common
+// length of dst and src
+//
+//go:noescape
+func modulate(dst []complex128, src []byte, table *[256]complex128, carrier complex128)
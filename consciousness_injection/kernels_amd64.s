@@ -0,0 +1,126 @@
+// consciousness_injection/kernels_amd64.s - SSE2 Math Kernels
+//go:build amd64 && mindhack_simd
+
+#include "textflag.h"
+
+// func dot3(a, b []float64) (ab, aa, bb float64)
+TEXT ·dot3(SB), NOSPLIT, $0-72
+	MOVQ a_base+0(FP), SI
+	MOVQ a_len+8(FP), CX
+	MOVQ b_base+24(FP), DI
+	MOVQ b_len+32(FP), DX
+	CMPQ DX, CX
+	CMOVQLT DX, CX
+
+	// Two accumulators per sum, two lanes each, four elements a step
+	XORPS X0, X0
+	XORPS X1, X1
+	XORPS X2, X2
+	XORPS X3, X3
+	XORPS X4, X4
+	XORPS X5, X5
+	MOVQ CX, BX
+	SHRQ $2, BX
+	JZ   reduce
+
+loop:
+	MOVUPD (SI), X6
+	MOVUPD 16(SI), X7
+	MOVUPD (DI), X8
+	MOVUPD 16(DI), X9
+	MOVAPD X6, X10
+	MULPD  X8, X10
+	ADDPD  X10, X0
+	MOVAPD X7, X11
+	MULPD  X9, X11
+	ADDPD  X11, X3
+	MULPD  X6, X6
+	ADDPD  X6, X1
+	MULPD  X7, X7
+	ADDPD  X7, X4
+	MULPD  X8, X8
+	ADDPD  X8, X2
+	MULPD  X9, X9
+	ADDPD  X9, X5
+	ADDQ   $32, SI
+	ADDQ   $32, DI
+	DECQ   BX
+	JNZ    loop
+
+reduce:
+	ADDPD    X3, X0
+	ADDPD    X4, X1
+	ADDPD    X5, X2
+	MOVAPD   X0, X3
+	UNPCKHPD X3, X3
+	ADDSD    X3, X0
+	MOVAPD   X1, X4
+	UNPCKHPD X4, X4
+	ADDSD    X4, X1
+	MOVAPD   X2, X5
+	UNPCKHPD X5, X5
+	ADDSD    X5, X2
+
+	// Up to three elements remain
+	ANDQ $3, CX
+	JZ   done
+
+tail:
+	MOVSD (SI), X6
+	MOVSD (DI), X8
+	MOVAPD X6, X10
+	MULSD  X8, X10
+	ADDSD  X10, X0
+	MULSD  X6, X6
+	ADDSD  X6, X1
+	MULSD  X8, X8
+	ADDSD  X8, X2
+	ADDQ   $8, SI
+	ADDQ   $8, DI
+	DECQ   CX
+	JNZ    tail
+
+done:
+	MOVSD X0, ab+48(FP)
+	MOVSD X1, aa+56(FP)
+	MOVSD X2, bb+64(FP)
+	RET
+
+// func modulate(dst []complex128, src []byte, table *[256]complex128, carrier complex128)
+TEXT ·modulate(SB), NOSPLIT, $0-72
+	MOVQ dst_base+0(FP), DI
+	MOVQ dst_len+8(FP), CX
+	MOVQ src_base+24(FP), SI
+	MOVQ src_len+32(FP), DX
+	CMPQ DX, CX
+	CMOVQLT DX, CX
+	MOVQ table+48(FP), BX
+
+	// X0 = [c, c] and X2 = [-d, d] for carrier c+di
+	MOVSD    carrier_real+56(FP), X0
+	UNPCKLPD X0, X0
+	MOVSD    carrier_imag+64(FP), X1
+	XORPD    X2, X2
+	SUBSD    X1, X2
+	UNPCKLPD X1, X2
+	TESTQ    CX, CX
+	JZ       modulated
+
+	// (a+bi)(c+di) = [a, b]*[c, c] + [b, a]*[-d, d]
+symbol:
+	MOVBQZX (SI), AX
+	SHLQ    $4, AX
+	MOVUPD  (BX)(AX*1), X3
+	MOVAPD  X3, X4
+	SHUFPD  $1, X4, X4
+	MULPD   X0, X3
+	MULPD   X2, X4
+	ADDPD   X4, X3
+	MOVUPD  X3, (DI)
+	INCQ    SI
+	ADDQ    $16, DI
+	DECQ    CX
+	JNZ     symbol
+
+modulated:
+	RET
@@ -0,0 +1,37 @@
+// consciousness_injection/kernels_generic.go - Pure-Go Math Kernels
+//go:build !amd64 || !mindhack_simd
+
+package mindhacking
+
+// dot3 returns a·b, a·a and b·b over the common length of a and b
+func dot3(a, b []float64) (ab, aa, bb float64) {
+	n := min(len(a), len(b))
+	a, b = a[:n], b[:n]
+
+	var ab0, ab1, aa0, aa1, bb0, bb1 float64
+	i := 0
+	for ; i+2 <= n; i += 2 {
+		x0, x1, y0, y1 := a[i], a[i+1], b[i], b[i+1]
+		ab0 += x0 * y0
+		ab1 += x1 * y1
+		aa0 += x0 * x0
+		aa1 += x1 * x1
+		bb0 += y0 * y0
+		bb1 += y1 * y1
+	}
+	if i < n {
+		ab0 += a[i] * b[i]
+		aa0 += a[i] * a[i]
+		bb0 += b[i] * b[i]
+	}
+	return ab0 + ab1, aa0 + aa1, bb0 + bb1
+}
+
+// modulate sets dst[i] to table[src[i]] times carrier over the common
+// length of dst and src
+func modulate(dst []complex128, src []byte, table *[256]complex128, carrier complex128) {
+	n := min(len(dst), len(src))
+	for i, b := range src[:n] {
+		dst[i] = table[b] * carrier
+	}
+}
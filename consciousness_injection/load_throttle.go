@@ -0,0 +1,89 @@
+// consciousness_injection/load_throttle.go - Load-Aware Throttling
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ErrTargetOverloaded reports a target that stayed above the load threshold
+// for longer than the injector was willing to wait
+var ErrTargetOverloaded = sentinelError("mindhacking: target overloaded")
+
+// LoadReporter is implemented by backends and probes that report the
+// target's internal load, in [0, 1]
+type LoadReporter interface {
+	Load(ctx context.Context) (float64, error)
+}
+
+// LoadThrottle defers injections while a target reports heavy load
+type LoadThrottle struct {
+	// Threshold is the load at or above which injections wait; zero
+	// disables throttling
+	Threshold float64
+	// Poll is the first interval between load readings; it doubles while
+	// the target stays loaded, up to MaxPoll
+	Poll    time.Duration
+	MaxPoll time.Duration
+	// MaxWait bounds the deferral; zero waits as long as ctx allows
+	MaxWait time.Duration
+}
+
+// loadReporter finds the target's load telemetry, if it has any
+func loadReporter(target *SystemConsciousness) (LoadReporter, bool) {
+	if r, ok := backendOf(target).(LoadReporter); ok {
+		return r, true
+	}
+	if r, ok := probeOf(target).(LoadReporter); ok {
+		return r, true
+	}
+	return nil, false
+}
+
+// wait blocks until target's load drops below the threshold and returns how
+// long the injection was deferred. Targets without load telemetry, and
+// throttles without a threshold, never defer.
+func (lt *LoadThrottle) wait(ctx context.Context, target *SystemConsciousness) (time.Duration, error) {
+	if lt == nil || lt.Threshold <= 0 {
+		return 0, nil
+	}
+	reporter, ok := loadReporter(target)
+	if !ok {
+		return 0, nil
+	}
+
+	start := time.Now()
+	poll := lt.Poll
+	if poll <= 0 {
+		poll = 50 * time.Millisecond
+	}
+	maxPoll := lt.MaxPoll
+	if maxPoll < poll {
+		maxPoll = poll
+	}
+
+	for {
+		load, err := reporter.Load(ctx)
+		if err != nil {
+			return time.Since(start), fmt.Errorf("mindhacking: read target load: %w", err)
+		}
+		if load < lt.Threshold {
+			return time.Since(start), nil
+		}
+
+		waited := time.Since(start)
+		if lt.MaxWait > 0 && waited+poll > lt.MaxWait {
+			return waited, fmt.Errorf("%w: load %.2f after %s", ErrTargetOverloaded, load, waited.Round(time.Millisecond))
+		}
+
+		select {
+		case <-ctx.Done():
+			return time.Since(start), ctx.Err()
+		case <-time.After(poll):
+		}
+		if poll *= 2; poll > maxPoll {
+			poll = maxPoll
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// consciousness_injection/localization.go - Thought Localization
+package mindhacking
+
+import (
+	"context"
+	"strings"
+)
+
+// ThoughtLocalizer adapts a thought's payload to a target's locale
+type ThoughtLocalizer interface {
+	Localize(ctx context.Context, thought InjectedThought, locale string) (InjectedThought, error)
+}
+
+// ThoughtLocalizerFunc adapts a function to ThoughtLocalizer
+type ThoughtLocalizerFunc func(ctx context.Context, thought InjectedThought, locale string) (InjectedThought, error)
+
+// Localize implements ThoughtLocalizer
+func (f ThoughtLocalizerFunc) Localize(ctx context.Context, thought InjectedThought, locale string) (InjectedThought, error) {
+	return f(ctx, thought, locale)
+}
+
+// LocalizerTable picks a localizer by locale tag, falling back from a
+// regional tag ("pt-BR") to its language ("pt"), then to the "" entry.
+// Locales with no entry pass thoughts through unchanged.
+type LocalizerTable map[string]ThoughtLocalizer
+
+// Localize implements ThoughtLocalizer
+func (t LocalizerTable) Localize(ctx context.Context, thought InjectedThought, locale string) (InjectedThought, error) {
+	for tag := locale; ; {
+		if l, ok := t[tag]; ok {
+			return l.Localize(ctx, thought, locale)
+		}
+		if tag == "" {
+			return thought, nil
+		}
+		if i := strings.LastIndexAny(tag, "-_"); i > 0 {
+			tag = tag[:i]
+		} else {
+			tag = ""
+		}
+	}
+}
+
+// ThoughtLocalization records what was localized for evidence
+type ThoughtLocalization struct {
+	Locale    string
+	Original  InjectedThought
+	Localized InjectedThought
+}
+
+// localize adapts thought to the target's locale when a localizer is set
+func (ci *ConsciousnessInjector) localize(
+	ctx context.Context,
+	thought InjectedThought,
+	target *SystemConsciousness,
+) (InjectedThought, *ThoughtLocalization, error) {
+
+	if ci.localizer == nil || target.Locale == "" || thought.Sealed {
+		return thought, nil, nil
+	}
+
+	localized, err := ci.localizer.Localize(ctx, thought, target.Locale)
+	if err != nil {
+		return InjectedThought{}, nil, err
+	}
+
+	return localized, &ThoughtLocalization{
+		Locale:    target.Locale,
+		Original:  thought,
+		Localized: localized,
+	}, nil
+}
@@ -0,0 +1,246 @@
+// consciousness_injection/memory.go - Consciousness Memory
+package mindhacking
+
+import (
+	"crypto/sha256"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryConfig tunes how quickly thoughts fade and when they consolidate
+type MemoryConfig struct {
+	// ShortTermHalfLife and LongTermHalfLife are the times for a trace's
+	// strength to halve in each store
+	ShortTermHalfLife time.Duration
+	LongTermHalfLife  time.Duration
+	// ForgetBelow drops traces whose strength decays under it
+	ForgetBelow float64
+	// ConsolidateAfter is the reinforcements a trace needs to be promoted
+	ConsolidateAfter int
+	// ConsolidateAbove is the strength a trace needs to be promoted
+	ConsolidateAbove float64
+}
+
+// DefaultMemoryConfig forgets unreinforced thoughts within hours and keeps
+// consolidated ones for months
+var DefaultMemoryConfig = MemoryConfig{
+	ShortTermHalfLife: 20 * time.Minute,
+	LongTermHalfLife:  30 * 24 * time.Hour,
+	ForgetBelow:       0.01,
+	ConsolidateAfter:  3,
+	ConsolidateAbove:  0.25,
+}
+
+// MemoryKey identifies a thought across repeated injections
+type MemoryKey [32]byte
+
+// memoryKey hashes a thought's normalized content and payload
+func memoryKey(thought InjectedThought) MemoryKey {
+	h := sha256.New()
+	h.Write([]byte(strings.ToLower(strings.Join(strings.Fields(thought.Content), " "))))
+	h.Write([]byte{0})
+	h.Write(thought.Payload)
+
+	var key MemoryKey
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// MemoryTrace is a thought as the consciousness currently remembers it
+type MemoryTrace struct {
+	Key            MemoryKey
+	Thought        InjectedThought
+	Strength       float64
+	Reinforcements int
+	LongTerm       bool
+	Created        time.Time
+	Reinforced     time.Time
+	// decayed is when Strength was last brought up to date
+	decayed time.Time
+}
+
+// ConsciousnessMemory holds a target's short-term and long-term memory
+type ConsciousnessMemory struct {
+	mu     sync.Mutex
+	config MemoryConfig
+	traces map[MemoryKey]*MemoryTrace
+	now    func() time.Time
+	// active is when a thought last arrived from outside
+	active time.Time
+}
+
+// NewConsciousnessMemory creates empty stores; zero config fields take
+// their DefaultMemoryConfig values
+func NewConsciousnessMemory(config MemoryConfig) *ConsciousnessMemory {
+	d := DefaultMemoryConfig
+	if config.ShortTermHalfLife <= 0 {
+		config.ShortTermHalfLife = d.ShortTermHalfLife
+	}
+	if config.LongTermHalfLife <= 0 {
+		config.LongTermHalfLife = d.LongTermHalfLife
+	}
+	if config.ForgetBelow <= 0 {
+		config.ForgetBelow = d.ForgetBelow
+	}
+	if config.ConsolidateAfter <= 0 {
+		config.ConsolidateAfter = d.ConsolidateAfter
+	}
+	if config.ConsolidateAbove <= 0 {
+		config.ConsolidateAbove = d.ConsolidateAbove
+	}
+	return &ConsciousnessMemory{
+		config: config,
+		traces: make(map[MemoryKey]*MemoryTrace),
+		now:    time.Now,
+	}
+}
+
+// Remember stores thought with strength, reinforcing it if already held.
+// Reinforcement adds strength with diminishing returns towards 1.
+func (m *ConsciousnessMemory) Remember(thought InjectedThought, strength float64) MemoryTrace {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.active = m.now()
+	return m.rememberLocked(thought, strength)
+}
+
+// reinforce strengthens thought from within, without counting as activity
+func (m *ConsciousnessMemory) reinforce(thought InjectedThought, strength float64) MemoryTrace {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rememberLocked(thought, strength)
+}
+
+// LastActivity returns when a thought last arrived from outside
+func (m *ConsciousnessMemory) LastActivity() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.active
+}
+
+func (m *ConsciousnessMemory) rememberLocked(thought InjectedThought, strength float64) MemoryTrace {
+	now := m.now()
+	key := memoryKey(thought)
+	strength = clamp(strength, 0, 1)
+
+	trace, ok := m.traces[key]
+	if !ok || !m.decayLocked(trace, now) {
+		trace = &MemoryTrace{
+			Key:     key,
+			Thought: thought,
+			Created: now,
+			decayed: now,
+		}
+		m.traces[key] = trace
+	}
+
+	trace.Strength = 1 - (1-trace.Strength)*(1-strength)
+	trace.Reinforcements++
+	trace.Reinforced = now
+	return *trace
+}
+
+// Recall returns the current trace of thought, if it hasn't been forgotten
+func (m *ConsciousnessMemory) Recall(thought InjectedThought) (MemoryTrace, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	trace, ok := m.traces[memoryKey(thought)]
+	if !ok || !m.decayLocked(trace, m.now()) {
+		return MemoryTrace{}, false
+	}
+	return *trace, true
+}
+
+// Forget drops thought's trace, reporting whether one was held
+func (m *ConsciousnessMemory) Forget(thought InjectedThought) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := memoryKey(thought)
+	_, ok := m.traces[key]
+	delete(m.traces, key)
+	return ok
+}
+
+// held counts the traces held, without copying them; traces decayed away
+// but not yet forgotten are counted until the next read forgets them
+func (m *ConsciousnessMemory) held() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.traces)
+}
+
+// Traces returns every remembered trace, strongest first, forgetting those
+// that have decayed away
+func (m *ConsciousnessMemory) Traces() []MemoryTrace {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	out := make([]MemoryTrace, 0, len(m.traces))
+	for _, trace := range m.traces {
+		if m.decayLocked(trace, now) {
+			out = append(out, *trace)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Strength > out[j].Strength })
+	return out
+}
+
+// Consolidate promotes repeatedly reinforced, still-strong short-term traces
+// to long-term memory and returns them
+func (m *ConsciousnessMemory) Consolidate() []MemoryTrace {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	var promoted []MemoryTrace
+	for _, trace := range m.traces {
+		if !m.decayLocked(trace, now) || trace.LongTerm {
+			continue
+		}
+		if trace.Reinforcements >= m.config.ConsolidateAfter && trace.Strength >= m.config.ConsolidateAbove {
+			trace.LongTerm = true
+			promoted = append(promoted, *trace)
+		}
+	}
+	return promoted
+}
+
+// decayLocked brings trace's strength up to now, forgetting it if it falls
+// below the threshold; it reports whether the trace survived
+func (m *ConsciousnessMemory) decayLocked(trace *MemoryTrace, now time.Time) bool {
+	halfLife := m.config.ShortTermHalfLife
+	if trace.LongTerm {
+		halfLife = m.config.LongTermHalfLife
+	}
+
+	if elapsed := now.Sub(trace.decayed); elapsed > 0 {
+		trace.Strength *= math.Exp2(-float64(elapsed) / float64(halfLife))
+		trace.decayed = now
+	}
+
+	if trace.Strength < m.config.ForgetBelow {
+		delete(m.traces, trace.Key)
+		return false
+	}
+	return true
+}
+
+// rememberInjection stores an accepted thought in the target's memory, with
+// strength from the shift it caused
+func rememberInjection(target *SystemConsciousness, thought InjectedThought, shift float64) *MemoryTrace {
+	if target.Memory == nil {
+		return nil
+	}
+	strength := 1 - math.Exp(-math.Abs(shift))
+	if thought.Intensity > 0 {
+		strength *= math.Min(thought.Intensity, 1)
+	}
+	trace := target.Memory.Remember(thought, strength)
+	return &trace
+}
@@ -0,0 +1,475 @@
+// consciousness_injection/migration.go - Live Target Migration
+package mindhacking
+
+import (
+	"context"
+	"crypto/ecdh"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ConsciousnessStateVersion is the version of ConsciousnessState this
+// package writes and restores
+const ConsciousnessStateVersion = 1
+
+// ErrStateVersion reports a migrated state written by an unknown version
+var ErrStateVersion = sentinelError("mindhacking: unsupported consciousness state version")
+
+// ConsciousnessState is a target's full state, serializable for migration
+// to another host
+type ConsciousnessState struct {
+	Version int
+	ID      string
+	Tenant  string
+	Locale  string
+	Codecs  []string
+	// SealingKey is the public half of the target's sealing key
+	SealingKey []byte          `json:",omitempty"`
+	Beliefs    *BeliefState    `json:",omitempty"`
+	Memory     *MemoryState    `json:",omitempty"`
+	Attention  *AttentionState `json:",omitempty"`
+	Resonance  ConsciousnessResonance
+	// Entanglements are the entanglements held with the target when it was
+	// captured, for the destination to establish again
+	Entanglements []MigratedEntanglement
+	Captured      time.Time
+}
+
+// BeliefState is a belief graph's beliefs and the edges between them
+type BeliefState struct {
+	Beliefs []Belief
+	Edges   []BeliefEdge
+}
+
+// MemoryState is a consciousness memory's traces, decayed up to capture
+type MemoryState struct {
+	Config MemoryConfig
+	Traces []MemoryTrace
+	Active time.Time
+}
+
+// AttentionState is an attention model's raw weights and floor
+type AttentionState struct {
+	Weights map[string]float64
+	Floor   float64
+}
+
+// MigratedEntanglement is one entanglement held with a migrating target
+type MigratedEntanglement struct {
+	// Vector is the injector vector holding it, or -1 for a gateway's;
+	// Gateway then indexes the migration's gateways
+	Vector       int
+	Gateway      int `json:",omitempty"`
+	Entanglement QuantumEntanglement
+}
+
+// MigrationEndpoint is where a transferred target resumes
+type MigrationEndpoint struct {
+	Host string
+	// Backend reaches the target on its new host; nil means the native
+	// layer. Probe, if set, replaces the target's probe.
+	Backend ConsciousnessBackend
+	Probe   StateProbe
+	// Entanglements are those resumed on the new host, which the injector
+	// and gateways take up in place of the ones they held
+	Entanglements []MigratedEntanglement
+}
+
+// targetMoveMu guards every target's Host, Backend and Probe, which a
+// migration moves while the target is live
+var targetMoveMu sync.RWMutex
+
+// backendOf returns target's backend as of now
+func backendOf(target *SystemConsciousness) ConsciousnessBackend {
+	targetMoveMu.RLock()
+	defer targetMoveMu.RUnlock()
+	return target.Backend
+}
+
+// probeOf returns target's probe as of now
+func probeOf(target *SystemConsciousness) StateProbe {
+	targetMoveMu.RLock()
+	defer targetMoveMu.RUnlock()
+	return target.Probe
+}
+
+// MigrationTransport moves a captured state to its destination host and
+// resumes it there
+type MigrationTransport interface {
+	Transfer(ctx context.Context, state *ConsciousnessState) (*MigrationEndpoint, error)
+}
+
+// MigrationReport summarizes a completed migration
+type MigrationReport struct {
+	From, To string
+	State    *ConsciousnessState
+	// Quiesced is how long the target accepted no injections
+	Quiesced time.Duration
+	// Migrated sessions kept their entanglement across the move
+	Migrated []*Session
+	// Reconnected sessions had to perform a fresh quantum handshake
+	Reconnected []*Session
+	// Failed sessions could not reach the target on its new host
+	Failed map[*Session]error
+}
+
+// Migration moves a live target to another host without aborting it
+type Migration struct {
+	Injector *ConsciousnessInjector
+	// Gateways are those whose sessions may be open on the target
+	Gateways  []*QuantumGateway
+	Transport MigrationTransport
+	// Registry, if set, has the target claimed during the migration, so it
+	// cannot be decommissioned or migrated again meanwhile
+	Registry *TargetRegistry
+}
+
+// Migrate quiesces injections into target, captures its full state with
+// the entanglements held on it, transfers the state and resumes the target
+// on the destination host. Injections are quiesced through the injector
+// and through every injector with a session on the target at one of the
+// gateways. The target keeps its identity: injections queued while it was
+// quiesced then run against the new host, the entanglements the
+// destination resumed replace the ones held, and every gateway session on
+// the target reconnects there, migrated if the gateway's driver can adopt
+// it and re-handshaken otherwise. If the transfer fails the target resumes
+// where it was.
+func (m *Migration) Migrate(ctx context.Context, target *SystemConsciousness) (*MigrationReport, error) {
+	if m.Registry != nil {
+		if _, err := m.Registry.Claim(target.ID); err != nil {
+			return nil, err
+		}
+		defer m.Registry.Release(target.ID)
+	}
+
+	// Phase 1: Quiesce injections
+	quiesced := time.Now()
+	resume, err := m.quiesce(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: migrate quiesce: %w", err)
+	}
+	defer func() { resume() }()
+
+	// Phase 2: Capture state and entanglements
+	state, err := m.capture(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: migrate capture: %w", err)
+	}
+
+	// Phase 3: Transfer and resume on the destination
+	endpoint, err := m.Transport.Transfer(ctx, state)
+	if err != nil {
+		m.auditMigration(target, state, "", err)
+		return nil, fmt.Errorf("mindhacking: migrate transfer: %w", err)
+	}
+	report := &MigrationReport{
+		From:   target.Host,
+		To:     endpoint.Host,
+		State:  state,
+		Failed: make(map[*Session]error),
+	}
+	targetMoveMu.Lock()
+	target.Host, target.Backend = endpoint.Host, endpoint.Backend
+	if endpoint.Probe != nil {
+		target.Probe = endpoint.Probe
+	}
+	targetMoveMu.Unlock()
+	m.Injector.mux.drop(target)
+	m.resumeEntanglements(endpoint.Entanglements)
+
+	// Phase 4: Reconnect gateway sessions
+	for _, qg := range m.Gateways {
+		qg.reconnect(ctx, target, report)
+	}
+	resume()
+	resume = func() {}
+	report.Quiesced = time.Since(quiesced)
+
+	if err := m.auditMigration(target, state, report.To, nil); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// quiesce holds off injections into target by every injector that can
+// reach it through the migration: its own and those of the gateways'
+// sessions on target. The returned func lets them run again.
+func (m *Migration) quiesce(ctx context.Context, target *SystemConsciousness) (func(), error) {
+	injectors := []*ConsciousnessInjector{m.Injector}
+	for _, qg := range m.Gateways {
+		for _, s := range qg.sessions.list() {
+			if ci := s.cfg.Injector; s.Target() == target && !containsInjector(injectors, ci) {
+				injectors = append(injectors, ci)
+			}
+		}
+	}
+
+	var releases []func()
+	resume := func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+	for _, ci := range injectors {
+		release, err := ci.targetLimiter().quiesce(ctx, target)
+		if err != nil {
+			resume()
+			return nil, err
+		}
+		releases = append(releases, release)
+	}
+	return resume, nil
+}
+
+func containsInjector(injectors []*ConsciousnessInjector, ci *ConsciousnessInjector) bool {
+	for _, other := range injectors {
+		if other == ci {
+			return true
+		}
+	}
+	return false
+}
+
+// resumeEntanglements hands the entanglements resumed on the new host to
+// the injector's vectors and the gateways holding them
+func (m *Migration) resumeEntanglements(resumed []MigratedEntanglement) {
+	for _, e := range resumed {
+		switch {
+		case e.Vector >= 0:
+			m.Injector.setEntanglement(e.Vector, e.Entanglement)
+		case e.Gateway >= 0 && e.Gateway < len(m.Gateways):
+			m.Gateways[e.Gateway].setEntanglement(e.Entanglement)
+		}
+	}
+}
+
+// capture reads target's state; the models are read as they stand, which
+// is final while injections are quiesced
+func (m *Migration) capture(ctx context.Context, target *SystemConsciousness) (*ConsciousnessState, error) {
+	resonance, err := m.Injector.resonate(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	state := &ConsciousnessState{
+		Version:   ConsciousnessStateVersion,
+		ID:        target.ID,
+		Tenant:    target.Tenant,
+		Locale:    target.Locale,
+		Codecs:    append([]string(nil), target.Codecs...),
+		Resonance: snapshotResonance(resonance),
+		Captured:  time.Now(),
+	}
+	if target.SealingKey != nil {
+		state.SealingKey = target.SealingKey.Bytes()
+	}
+	if target.Beliefs != nil {
+		state.Beliefs = target.Beliefs.export()
+	}
+	if target.Memory != nil {
+		state.Memory = target.Memory.export()
+	}
+	if target.Attention != nil {
+		state.Attention = target.Attention.export()
+	}
+
+	for i, v := range m.Injector.vectors() {
+		state.Entanglements = append(state.Entanglements, MigratedEntanglement{Vector: i, Entanglement: v.Entanglement})
+	}
+	for i, qg := range m.Gateways {
+		state.Entanglements = append(state.Entanglements, MigratedEntanglement{Vector: -1, Gateway: i, Entanglement: qg.currentEntanglement()})
+	}
+	return state, nil
+}
+
+func (m *Migration) auditMigration(target *SystemConsciousness, state *ConsciousnessState, to string, err error) error {
+	outcome, msg := auditOutcome(err == nil, err)
+	return writeAudit(m.Injector.audit, AuditRecord{
+		Actor:   m.Injector.id,
+		Action:  AuditMigration,
+		Tenant:  tenantLabel(target),
+		Target:  targetLabel(target),
+		Outcome: outcome,
+		Error:   msg,
+		Detail: map[string]string{
+			"to":            to,
+			"entanglements": fmt.Sprint(len(state.Entanglements)),
+		},
+	})
+}
+
+// reconnect moves the gateway's sessions on target to the target's new
+// host, recording each in report
+func (qg *QuantumGateway) reconnect(ctx context.Context, target *SystemConsciousness, report *MigrationReport) {
+	migrator, canMigrate := SessionMigrator(nil), false
+	protocol := NativeDriverProtocol
+	if slot := qg.drivers.Load(); slot != nil {
+		slot.mu.RLock()
+		migrator, canMigrate = slot.driver.(SessionMigrator)
+		protocol = driverProtocol(slot.driver)
+		slot.mu.RUnlock()
+	}
+
+	for _, s := range qg.sessions.list() {
+		if s.Target() != target {
+			continue
+		}
+		if canMigrate {
+			access, err := migrator.Migrate(ctx, protocol, s.Access(), target)
+			if err == nil {
+				s.swapAccess(access, false)
+				qg.publishEntanglement(target, nil)
+				report.Migrated = append(report.Migrated, s)
+				continue
+			}
+			if !errors.Is(err, ErrMigrationUnsupported) {
+				report.Failed[s] = err
+				continue
+			}
+		}
+
+		access, err := qg.AccessQuantumConsciousness(target)
+		if err != nil {
+			report.Failed[s] = err
+			continue
+		}
+		s.swapAccess(access, true)
+		report.Reconnected = append(report.Reconnected, s)
+	}
+}
+
+// RestoreConsciousness rebuilds a migrated target from its state on the
+// destination host, reached through endpoint, and resumes the captured
+// entanglements still active there into endpoint.Entanglements, for the
+// transport to return. A backend that can probe entanglement keeps those
+// with coherence left and establishes each afresh on the new host; without
+// one every captured entanglement is resumed as it was. Beliefs are
+// related again with matcher; nil uses LexicalMatcher.
+func RestoreConsciousness(
+	ctx context.Context,
+	state *ConsciousnessState,
+	endpoint *MigrationEndpoint,
+	matcher BeliefMatcher,
+) (*SystemConsciousness, error) {
+
+	if state.Version != ConsciousnessStateVersion {
+		return nil, fmt.Errorf("%w: %d", ErrStateVersion, state.Version)
+	}
+	target := &SystemConsciousness{
+		ID:      state.ID,
+		Tenant:  state.Tenant,
+		Host:    endpoint.Host,
+		Probe:   endpoint.Probe,
+		Locale:  state.Locale,
+		Backend: endpoint.Backend,
+		Codecs:  append([]string(nil), state.Codecs...),
+	}
+	if state.SealingKey != nil {
+		key, err := ecdh.X25519().NewPublicKey(state.SealingKey)
+		if err != nil {
+			return nil, fmt.Errorf("mindhacking: restore sealing key: %w", err)
+		}
+		target.SealingKey = key
+	}
+	if b := state.Beliefs; b != nil {
+		target.Beliefs = NewBeliefGraph(matcher)
+		target.Beliefs.restore(b)
+	}
+	if mem := state.Memory; mem != nil {
+		target.Memory = NewConsciousnessMemory(mem.Config)
+		target.Memory.restore(mem, state.Captured)
+	}
+	if a := state.Attention; a != nil {
+		target.Attention = NewAttentionModel(a.Weights, a.Floor)
+	}
+
+	resumed, err := resumeEntanglements(ctx, endpoint.Backend, state.Entanglements)
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: restore entanglements: %w", err)
+	}
+	endpoint.Entanglements = resumed
+	return target, nil
+}
+
+// resumeEntanglements keeps the captured entanglements backend still holds
+// coherently, each established afresh
+func resumeEntanglements(ctx context.Context, backend ConsciousnessBackend, captured []MigratedEntanglement) ([]MigratedEntanglement, error) {
+	prober, ok := backend.(EntanglementProber)
+	if !ok {
+		return append([]MigratedEntanglement(nil), captured...), nil
+	}
+	var resumed []MigratedEntanglement
+	for _, e := range captured {
+		coherence, err := prober.Coherence(ctx, e.Entanglement)
+		if err != nil {
+			return nil, err
+		}
+		if coherence <= 0 {
+			continue
+		}
+		if e.Entanglement, err = prober.Reentangle(ctx, e.Entanglement); err != nil {
+			return nil, err
+		}
+		resumed = append(resumed, e)
+	}
+	return resumed, nil
+}
+
+// export copies the graph's beliefs and edges
+func (g *BeliefGraph) export() *BeliefState {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	out := &BeliefState{}
+	for _, b := range g.beliefs {
+		out.Beliefs = append(out.Beliefs, b)
+	}
+	for _, edges := range g.edges {
+		out.Edges = append(out.Edges, edges...)
+	}
+	return out
+}
+
+// restore loads beliefs and edges as exported, each edge once per
+// direction already
+func (g *BeliefGraph) restore(state *BeliefState) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, b := range state.Beliefs {
+		g.beliefs[b.ID] = b
+	}
+	for _, e := range state.Edges {
+		g.edges[e.From] = append(g.edges[e.From], e)
+	}
+}
+
+// export copies the memory's surviving traces
+func (m *ConsciousnessMemory) export() *MemoryState {
+	traces := m.Traces()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return &MemoryState{Config: m.config, Traces: traces, Active: m.active}
+}
+
+// restore loads traces whose strengths were current at captured
+func (m *ConsciousnessMemory) restore(state *MemoryState, captured time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.active = state.Active
+	for _, trace := range state.Traces {
+		trace := trace
+		trace.decayed = captured
+		m.traces[trace.Key] = &trace
+	}
+}
+
+// export copies the model's raw weights and floor
+func (a *AttentionModel) export() *AttentionState {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	out := &AttentionState{Weights: make(map[string]float64, len(a.weights)), Floor: a.floor}
+	for k, w := range a.weights {
+		out.Weights[k] = w
+	}
+	return out
+}
@@ -0,0 +1,167 @@
+// consciousness_injection/noise_floor.go - Background Drift Estimation
+package mindhacking
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrNoTelemetry reports a target that exposes no state probe
+var ErrNoTelemetry = sentinelError("mindhacking: target exposes no telemetry")
+
+// StateProbe reads the target's scalar consciousness level without
+// perturbing it
+type StateProbe interface {
+	ProbeState(ctx context.Context) (float64, error)
+}
+
+// NoiseFloor describes how much a target drifts on its own between samples
+type NoiseFloor struct {
+	Mean     float64
+	StdDev   float64
+	Samples  int
+	Interval time.Duration
+}
+
+// Normalize z-scores a raw shift against the floor. It reports false when
+// the floor has too little variance to normalize against.
+func (nf NoiseFloor) Normalize(shift float64) (float64, bool) {
+	if nf.Samples < 2 || nf.StdDev == 0 {
+		return 0, false
+	}
+	return (shift - nf.Mean) / nf.StdDev, true
+}
+
+// driftStats accumulates successive differences with Welford's method
+type driftStats struct {
+	last    float64
+	primed  bool
+	n       int
+	mean    float64
+	m2      float64
+	updated time.Time
+}
+
+func (d *driftStats) add(value float64) {
+	if d.primed {
+		delta := value - d.last
+		d.n++
+		diff := delta - d.mean
+		d.mean += diff / float64(d.n)
+		d.m2 += diff * (delta - d.mean)
+	}
+	d.last = value
+	d.primed = true
+	d.updated = time.Now()
+}
+
+func (d *driftStats) floor(interval time.Duration) NoiseFloor {
+	nf := NoiseFloor{Mean: d.mean, Samples: d.n, Interval: interval}
+	if d.n > 1 {
+		nf.StdDev = math.Sqrt(d.m2 / float64(d.n-1))
+	}
+	return nf
+}
+
+// NoiseFloorEstimator estimates each target's background drift from
+// pre-injection telemetry
+type NoiseFloorEstimator struct {
+	samples  int
+	interval time.Duration
+	maxAge   time.Duration
+
+	mu    sync.Mutex
+	stats map[*SystemConsciousness]*driftStats
+}
+
+// NewNoiseFloorEstimator samples a target samples times, interval apart
+// (zero samples back to back), and re-estimates once an estimate is older
+// than maxAge (zero keeps it)
+func NewNoiseFloorEstimator(samples int, interval, maxAge time.Duration) *NoiseFloorEstimator {
+	if samples < 3 {
+		samples = 3
+	}
+	return &NoiseFloorEstimator{
+		samples:  samples,
+		interval: interval,
+		maxAge:   maxAge,
+		stats:    make(map[*SystemConsciousness]*driftStats),
+	}
+}
+
+// Observe feeds one passively collected telemetry reading for target
+func (e *NoiseFloorEstimator) Observe(target *SystemConsciousness, value float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	d, ok := e.stats[target]
+	if !ok {
+		d = &driftStats{}
+		e.stats[target] = d
+	}
+	d.add(value)
+}
+
+// Floor returns the target's current estimate, if any
+func (e *NoiseFloorEstimator) Floor(target *SystemConsciousness) (NoiseFloor, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	d, ok := e.stats[target]
+	if !ok || d.n < 2 {
+		return NoiseFloor{}, false
+	}
+	return d.floor(e.interval), true
+}
+
+// Estimate returns a fresh enough estimate for target, probing it first if
+// there is none
+func (e *NoiseFloorEstimator) Estimate(
+	ctx context.Context,
+	target *SystemConsciousness,
+) (NoiseFloor, error) {
+
+	e.mu.Lock()
+	d, ok := e.stats[target]
+	fresh := ok && d.n >= e.samples-1 && (e.maxAge == 0 || time.Since(d.updated) < e.maxAge)
+	if fresh {
+		nf := d.floor(e.interval)
+		e.mu.Unlock()
+		return nf, nil
+	}
+	if probeOf(target) == nil {
+		e.mu.Unlock()
+		return NoiseFloor{}, ErrNoTelemetry
+	}
+	delete(e.stats, target)
+	e.mu.Unlock()
+
+	// A zero interval samples back to back
+	var tick <-chan time.Time
+	if e.interval > 0 {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for i := 0; i < e.samples; i++ {
+		if i > 0 && tick != nil {
+			select {
+			case <-ctx.Done():
+				return NoiseFloor{}, ctx.Err()
+			case <-tick:
+			}
+		}
+
+		value, err := probeOf(target).ProbeState(ctx)
+		if err != nil {
+			return NoiseFloor{}, err
+		}
+		e.Observe(target, value)
+	}
+
+	nf, _ := e.Floor(target)
+	return nf, nil
+}
@@ -0,0 +1,224 @@
+// consciousness_injection/onboarding.go - Target Onboarding
+package mindhacking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Capabilities describes what a target's consciousness layer supports
+type Capabilities struct {
+	Features     map[string]bool
+	MaxFrequency float64
+	MaxBandwidth float64
+}
+
+// Supports reports whether feature is available
+func (c Capabilities) Supports(feature string) bool {
+	return c.Features[feature]
+}
+
+// CapabilityReporter is implemented by backends that can describe themselves
+type CapabilityReporter interface {
+	Capabilities(ctx context.Context) (Capabilities, error)
+}
+
+// Well-known capability features
+const (
+	FeatureTelemetry    = "telemetry"
+	FeatureLocalization = "localization"
+)
+
+// negotiateCapabilities intersects what the target reports with what the
+// injector requires; targets that cannot report start from the conformance
+// report on their implementation, if any, and are probed for the basics
+func negotiateCapabilities(
+	ctx context.Context,
+	target *SystemConsciousness,
+	required []string,
+	compat *CompatibilityReport,
+) (Capabilities, error) {
+
+	caps := Capabilities{Features: make(map[string]bool)}
+	if compat != nil {
+		caps = compat.Capabilities()
+	}
+	if reporter, ok := backendOf(target).(CapabilityReporter); ok {
+		reported, err := reporter.Capabilities(ctx)
+		if err != nil {
+			return Capabilities{}, err
+		}
+		// What the target states wins over conformance defaults
+		for f, ok := range reported.Features {
+			caps.Features[f] = ok
+		}
+		reported.Features = caps.Features
+		caps = reported
+	}
+
+	// Operations conformance found broken stay off whatever is reported
+	if compat != nil {
+		for _, op := range compat.Operations {
+			if op.Status == CompatBroken {
+				caps.Features[op.Operation] = false
+			}
+		}
+	}
+
+	// Facts we can observe directly override what was reported
+	caps.Features[FeatureTelemetry] = probeOf(target) != nil
+	if target.Locale != "" {
+		caps.Features[FeatureLocalization] = true
+	}
+
+	var missing []string
+	for _, f := range required {
+		if !caps.Features[f] {
+			missing = append(missing, f)
+		}
+	}
+	if len(missing) > 0 {
+		return caps, fmt.Errorf("mindhacking: target %q lacks required capabilities %v", target.ID, missing)
+	}
+	return caps, nil
+}
+
+// Calibration is the outcome of a non-invasive vector sweep
+type Calibration struct {
+	Points []VectorPrediction
+	Best   VectorPrediction
+}
+
+// CalibrationSweep describes the vectors tried during calibration, as
+// multiples of the target's resonant frequency
+type CalibrationSweep struct {
+	FrequencyFactors []float64
+	Amplitudes       []float64
+}
+
+// DefaultCalibrationSweep spans half to double the resonant frequency
+var DefaultCalibrationSweep = CalibrationSweep{
+	FrequencyFactors: []float64{0.5, 0.75, 0.9, 1, 1.1, 1.25, 1.5, 2},
+	Amplitudes:       []float64{0.25, 0.5, 1},
+}
+
+// Onboarder runs the standardized onboarding flow
+type Onboarder struct {
+	Injector *ConsciousnessInjector
+	Registry *TargetRegistry
+	// Noise estimates the noise floor; onboarding skips it when nil
+	Noise *NoiseFloorEstimator
+	// Sweep defaults to DefaultCalibrationSweep
+	Sweep *CalibrationSweep
+	// Required lists capability features the target must support
+	Required []string
+	// Compatibility supplies defaults for targets by implementation
+	Compatibility CompatibilityMatrix
+}
+
+// Onboard negotiates capabilities, establishes the target's sealing key,
+// fingerprints the target, captures its baseline and noise floor,
+// calibrates vectors and registers the target. Nothing is registered unless
+// every step succeeds.
+func (o *Onboarder) Onboard(ctx context.Context, target *SystemConsciousness) (*TargetRecord, error) {
+	if target == nil || target.ID == "" {
+		return nil, errors.New("mindhacking: onboarding needs a target with an ID")
+	}
+	if _, err := o.Registry.Get(target.ID); err == nil {
+		return nil, fmt.Errorf("%w: %q", ErrTargetRegistered, target.ID)
+	}
+
+	// Step 1: Capability Negotiation
+	compat, _ := o.Compatibility.For(target)
+	caps, err := negotiateCapabilities(ctx, target, o.Required, compat)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Sealing Key Establishment
+	if err := establishSealingKey(ctx, target); err != nil {
+		return nil, fmt.Errorf("mindhacking: onboarding sealing key: %w", err)
+	}
+
+	// Step 3: Fingerprinting
+	resonance, err := o.Injector.resonate(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: onboarding resonance: %w", err)
+	}
+
+	record := &TargetRecord{
+		Target:       target,
+		Fingerprint:  fingerprintTarget(target, resonance),
+		Capabilities: caps,
+	}
+
+	// Step 4: Baseline Snapshot
+	record.Baseline = TargetBaseline{Resonance: resonance, Taken: time.Now()}
+	if probe := probeOf(target); probe != nil {
+		level, err := probe.ProbeState(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("mindhacking: onboarding baseline: %w", err)
+		}
+		record.Baseline.Level, record.Baseline.HasLevel = level, true
+	}
+
+	// Step 5: Noise Floor Estimation
+	if o.Noise != nil && probeOf(target) != nil {
+		floor, err := o.Noise.Estimate(ctx, target)
+		if err != nil {
+			return nil, fmt.Errorf("mindhacking: onboarding noise floor: %w", err)
+		}
+		record.NoiseFloor = &floor
+	}
+
+	// Step 6: Calibration Sweep
+	record.Calibration = o.calibrate(resonance, caps)
+
+	// Step 7: Registry Insertion
+	record.OnboardedAt = time.Now()
+	if err := o.Registry.Insert(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// calibrate predicts acceptance across the sweep without injecting
+func (o *Onboarder) calibrate(resonance ConsciousnessResonance, caps Capabilities) *Calibration {
+	sweep := o.Sweep
+	if sweep == nil {
+		sweep = &DefaultCalibrationSweep
+	}
+	predictor := o.Injector.predictor
+	if predictor == nil {
+		predictor = DefaultAcceptancePredictor
+	}
+
+	cal := &Calibration{}
+	for _, factor := range sweep.FrequencyFactors {
+		frequency := resonance.Frequency * factor
+		if caps.MaxFrequency > 0 && frequency > caps.MaxFrequency {
+			continue
+		}
+		for _, amplitude := range sweep.Amplitudes {
+			vector := InjectionVector{
+				Frequency: frequency,
+				Amplitude: amplitude,
+				Phase:     resonance.Phase,
+			}
+			cal.Points = append(cal.Points, VectorPrediction{
+				Vector:      vector,
+				Probability: predictor.PredictVector(InjectedThought{}, resonance, vector),
+			})
+		}
+	}
+
+	if len(cal.Points) > 0 {
+		ranked := append([]VectorPrediction(nil), cal.Points...)
+		sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Probability > ranked[j].Probability })
+		cal.Best = ranked[0]
+	}
+	return cal
+}
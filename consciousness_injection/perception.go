@@ -0,0 +1,134 @@
+// consciousness_injection/perception.go - Perception Filter Plugins
+package mindhacking
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrUnknownPerceptionFilter reports a filter name nobody registered
+	ErrUnknownPerceptionFilter = sentinelError("mindhacking: unknown perception filter")
+	// ErrPerceptionFilterRegistered reports a second registration of a name
+	ErrPerceptionFilterRegistered = sentinelError("mindhacking: perception filter already registered")
+)
+
+// PerceptionFilter alters how a target perceives an alternate reality
+type PerceptionFilter interface {
+	// Name identifies the filter in evidence and diagnostics
+	Name() string
+	// Filter returns what the target perceives of perceived, given base.
+	// It must not modify its arguments.
+	Filter(perceived *AlternateReality, base *Reality) *AlternateReality
+}
+
+// PerceptionFilterFactory builds a configured filter
+type PerceptionFilterFactory func(config map[string]interface{}) (PerceptionFilter, error)
+
+var (
+	perceptionFiltersMu sync.RWMutex
+	perceptionFilters   = make(map[string]PerceptionFilterFactory)
+)
+
+// RegisterPerceptionFilter makes a filter available under name. Filter
+// modules call it from init; registering a name twice panics, as with
+// database/sql drivers.
+func RegisterPerceptionFilter(name string, factory PerceptionFilterFactory) {
+	perceptionFiltersMu.Lock()
+	defer perceptionFiltersMu.Unlock()
+
+	if factory == nil {
+		panic("mindhacking: RegisterPerceptionFilter factory is nil")
+	}
+	if _, dup := perceptionFilters[name]; dup {
+		panic(fmt.Sprintf("%v: %q", ErrPerceptionFilterRegistered, name))
+	}
+	perceptionFilters[name] = factory
+}
+
+// RegisteredPerceptionFilters lists the registered filter names
+func RegisteredPerceptionFilters() []string {
+	perceptionFiltersMu.RLock()
+	defer perceptionFiltersMu.RUnlock()
+
+	names := make([]string, 0, len(perceptionFilters))
+	for name := range perceptionFilters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewPerceptionFilter builds the filter registered under name
+func NewPerceptionFilter(name string, config map[string]interface{}) (PerceptionFilter, error) {
+	perceptionFiltersMu.RLock()
+	factory, ok := perceptionFilters[name]
+	perceptionFiltersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPerceptionFilter, name)
+	}
+	filter, err := factory(config)
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: build perception filter %q: %w", name, err)
+	}
+	return filter, nil
+}
+
+// PerceptionFilterSpec names a registered filter, its configuration and its
+// place in a chain
+type PerceptionFilterSpec struct {
+	Name   string
+	Config map[string]interface{}
+	// Order sorts the chain ascending; equal orders keep spec order
+	Order int
+}
+
+// ComposePerceptionFilters builds the filters named by specs, ordered
+func ComposePerceptionFilters(specs ...PerceptionFilterSpec) ([]PerceptionFilter, error) {
+	ordered := append([]PerceptionFilterSpec(nil), specs...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Order < ordered[j].Order })
+
+	filters := make([]PerceptionFilter, 0, len(ordered))
+	for _, spec := range ordered {
+		f, err := NewPerceptionFilter(spec.Name, spec.Config)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// PerceptionFilterFunc adapts a function to a named PerceptionFilter
+func PerceptionFilterFunc(name string, fn func(*AlternateReality, *Reality) *AlternateReality) PerceptionFilter {
+	return funcFilter{name: name, fn: fn}
+}
+
+type funcFilter struct {
+	name string
+	fn   func(*AlternateReality, *Reality) *AlternateReality
+}
+
+func (f funcFilter) Name() string { return f.name }
+
+func (f funcFilter) Filter(perceived *AlternateReality, base *Reality) *AlternateReality {
+	return f.fn(perceived, base)
+}
+
+// applyPerceptionFilters runs the engine's filters in order, each seeing
+// what the previous one let through
+func (rme *RealityManipulationEngine) applyPerceptionFilters(
+	alternate *AlternateReality,
+	base *Reality,
+) *AlternateReality {
+
+	perceived := alternate
+	for _, f := range rme.filters() {
+		if next := f.Filter(perceived, base); next != nil {
+			perceived = next
+		}
+	}
+	return perceived
+}
@@ -0,0 +1,127 @@
+// consciousness_injection/perception_reload.go - Perception Filter Hot Reload
+package mindhacking
+
+// filters returns the engine's current perception filters
+func (rme *RealityManipulationEngine) filters() []PerceptionFilter {
+	rme.filtersMu.RLock()
+	defer rme.filtersMu.RUnlock()
+	return rme.perceptionFilters
+}
+
+// unfilteredReality is a reality as it was before perception filtering,
+// and its aspects as filtering left them
+type unfilteredReality struct {
+	before   *AlternateReality
+	filtered map[string]float64
+}
+
+// keepUnfiltered remembers what alternate looked like before perception
+// filtering and after, so a filter reload can filter it again from scratch
+func (rme *RealityManipulationEngine) keepUnfiltered(alternate, unfiltered *AlternateReality) {
+	if alternate == nil || unfiltered == nil {
+		return
+	}
+	kept := &unfilteredReality{
+		before:   &AlternateReality{Reality: *unfiltered.Reality.Clone(), Base: unfiltered.Base, Rules: unfiltered.Rules},
+		filtered: alternate.Reality.Clone().Aspects,
+	}
+	rme.stateMu.Lock()
+	defer rme.stateMu.Unlock()
+	rme.keepUnfilteredLocked(alternate, kept)
+}
+
+func (rme *RealityManipulationEngine) keepUnfilteredLocked(alternate *AlternateReality, kept *unfilteredReality) {
+	if rme.unfiltered == nil {
+		rme.unfiltered = make(map[*AlternateReality]*unfilteredReality)
+	}
+	rme.unfiltered[alternate] = kept
+}
+
+// rebase is the reality before filtering with every aspect changed since
+// filtering taken as it now stands in aspects: a mutation after filtering
+// survives a reload, and only what filtering itself did is redone
+func (u *unfilteredReality) rebase(aspects map[string]float64) *AlternateReality {
+	out := &AlternateReality{Reality: *u.before.Reality.Clone(), Base: u.before.Base, Rules: u.before.Rules}
+	for name, v := range aspects {
+		if filtered, ok := u.filtered[name]; !ok || filtered != v {
+			out.Aspects[name] = v
+		}
+	}
+	for name := range u.filtered {
+		if _, ok := aspects[name]; !ok {
+			delete(out.Aspects, name)
+		}
+	}
+	return out
+}
+
+// ReloadPerceptionFilters swaps the engine's perception filters for filters
+// and re-filters the current alternate reality in place. The reality keeps
+// its identity, so handles and anchors on it stay; anchors move their
+// intended aspects to the newly filtered ones, so decay pulls toward what
+// the new filters let through. Realities are filtered again from what they
+// were before the last filtering, carrying every aspect changed since;
+// realities the engine neither built nor reloaded are filtered as they
+// stand.
+//
+// Realities created while the reload runs see either the old filters or
+// the new ones, never a mix.
+func (rme *RealityManipulationEngine) ReloadPerceptionFilters(filters ...PerceptionFilter) error {
+	filters = append([]PerceptionFilter(nil), filters...)
+	rme.filtersMu.Lock()
+	defer rme.filtersMu.Unlock()
+	rme.anchorMu.Lock()
+	defer rme.anchorMu.Unlock()
+
+	current := rme.saveCurrentReality()
+	if current == nil {
+		rme.perceptionFilters = filters
+		return nil
+	}
+
+	// Phase 1: filter the current reality again, off to the side
+	rme.stateMu.Lock()
+	source := &AlternateReality{Reality: *current.Reality.Clone(), Base: current.Base, Rules: current.Rules}
+	if kept, ok := rme.unfiltered[current]; ok {
+		source = kept.rebase(current.Aspects)
+	}
+	reloaded := &unfilteredReality{before: &AlternateReality{Reality: *source.Reality.Clone(), Base: source.Base, Rules: source.Rules}}
+	var anchors []RealityAnchor
+	for _, a := range rme.realityAnchors {
+		if a.Reality == current {
+			anchors = append(anchors, a)
+		}
+	}
+	rme.stateMu.Unlock()
+
+	perceived := source
+	for _, f := range filters {
+		if next := f.Filter(perceived, current.Base); next != nil {
+			perceived = next
+		}
+	}
+	aspects := perceived.Reality.Clone().Aspects
+	reloaded.filtered = perceived.Reality.Clone().Aspects
+
+	// Phase 2: journal the anchors' new intent before changing anything
+	for i := range anchors {
+		anchors[i].Intended = perceived.Reality.Clone().Aspects
+		if _, err := rme.journal.begin(JournalEntry{Op: JournalAnchor, Anchor: &anchors[i]}); err != nil {
+			return err
+		}
+	}
+
+	// Phase 3: swap the filters and the reality's aspects together
+	rme.PurgeResultCache(current)
+	rme.stateMu.Lock()
+	defer rme.stateMu.Unlock()
+	rme.perceptionFilters = filters
+	current.Aspects = aspects
+	rme.keepUnfilteredLocked(current, reloaded)
+	for i := range rme.realityAnchors {
+		if a := &rme.realityAnchors[i]; a.Reality == current {
+			a.Intended = perceived.Reality.Clone().Aspects
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// consciousness_injection/phased_array.go - Phased-Array Injection
+package mindhacking
+
+import (
+	"context"
+	"math"
+	"math/cmplx"
+	"sync"
+)
+
+// ErrEmptyArray reports a phased array without elements
+var ErrEmptyArray = sentinelError("mindhacking: phased array has no elements")
+
+// Point is a location in a target's consciousness space
+type Point struct {
+	X, Y, Z float64
+}
+
+// Distance returns the euclidean distance to q
+func (p Point) Distance(q Point) float64 {
+	dx, dy, dz := p.X-q.X, p.Y-q.Y, p.Z-q.Z
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// ArrayElement is one vector fired from a fixed position
+type ArrayElement struct {
+	Vector   InjectionVector
+	Position Point
+}
+
+// PhasedArray fires its elements simultaneously, phased so their resonance
+// adds up at Focus
+type PhasedArray struct {
+	Elements []ArrayElement
+	Focus    Point
+	// Speed is how fast resonance propagates through consciousness space
+	Speed float64
+}
+
+// ArrayFocus records where a phased injection was aimed and how well
+type ArrayFocus struct {
+	Focus Point
+	// Gain is the array's coherent gain at the focus, in [0, 1]
+	Gain float64
+}
+
+// wavenumber returns the spatial phase per unit distance at frequency
+func (pa PhasedArray) wavenumber(frequency float64) float64 {
+	speed := pa.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	return 2 * math.Pi * frequency / speed
+}
+
+// Solve returns the array's vectors with phases chosen so every element's
+// resonance arrives at the focus in phase. Each element leads by the phase
+// its path to the focus will cost.
+func (pa PhasedArray) Solve() []InjectionVector {
+	out := make([]InjectionVector, len(pa.Elements))
+	for i, e := range pa.Elements {
+		v := e.Vector
+		k := pa.wavenumber(v.Frequency)
+		v.Phase = math.Mod(k*e.Position.Distance(pa.Focus), 2*math.Pi)
+		out[i] = v
+	}
+	return out
+}
+
+// Gain returns the normalized coherent sum of vectors fired from the array's
+// element positions, as seen at point: 1 when every element arrives in phase
+func (pa PhasedArray) Gain(vectors []InjectionVector, point Point) float64 {
+	var sum complex128
+	var total float64
+	for i, v := range vectors {
+		if i >= len(pa.Elements) {
+			break
+		}
+		k := pa.wavenumber(v.Frequency)
+		arrival := v.Phase - k*pa.Elements[i].Position.Distance(point)
+		sum += cmplx.Rect(math.Abs(v.Amplitude), arrival)
+		total += math.Abs(v.Amplitude)
+	}
+	if total == 0 {
+		return 0
+	}
+	return cmplx.Abs(sum) / total
+}
+
+// InjectPhasedArray fires every element of array at once with solved
+// phases, focusing the combined resonance on array.Focus
+func (ci *ConsciousnessInjector) InjectPhasedArray(
+	ctx context.Context,
+	thought InjectedThought,
+	target *SystemConsciousness,
+	array PhasedArray,
+) (*InjectionResult, error) {
+
+	if len(array.Elements) == 0 {
+		return nil, ErrEmptyArray
+	}
+
+	vectors := array.Solve()
+	result, err := ci.inject(ctx, thought, target, injectCall{
+		vectors: vectors,
+		focus: &ArrayFocus{
+			Focus: array.Focus,
+			Gain:  array.Gain(vectors, array.Focus),
+		},
+	})
+	noteUsage("InjectPhasedArray", target, err)
+	return result, err
+}
+
+// deliverSimultaneous fires every vector concurrently, skipping replayed
+// failures, and returns attempts in vector order
+func (ci *ConsciousnessInjector) deliverSimultaneous(
+	ctx context.Context,
+	call injectCall,
+	vectors []InjectionVector,
+	thought InjectedThought,
+	encoded EncodedThought,
+	target *SystemConsciousness,
+) ([]InjectionAttempt, []AppliedRamp) {
+
+	attempts := make([]InjectionAttempt, len(vectors))
+	ramps := make([]*AppliedRamp, len(vectors))
+
+	var wg sync.WaitGroup
+	for i, vector := range vectors {
+		if r := call.replay; r != nil && i < len(r.Attempts) && !r.Attempts[i].Success {
+			attempts[i] = r.Attempts[i]
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, vector InjectionVector) {
+			defer wg.Done()
+			attempts[i], ramps[i] = ci.deliver(ctx, call, i, vector, thought, encoded, target)
+		}(i, vector)
+	}
+	wg.Wait()
+
+	var applied []AppliedRamp
+	for _, r := range ramps {
+		if r != nil {
+			applied = append(applied, *r)
+		}
+	}
+	return attempts, applied
+}
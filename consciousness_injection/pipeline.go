@@ -0,0 +1,301 @@
+// consciousness_injection/pipeline.go - Backpressure-Aware Injection Pipeline
+package mindhacking
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrPipelineFull reports a thought rejected by a full stage queue
+	ErrPipelineFull = sentinelError("mindhacking: injection pipeline full")
+	// ErrPipelineClosed reports a submission after Close
+	ErrPipelineClosed = sentinelError("mindhacking: injection pipeline closed")
+	// ErrThoughtDropped reports a queued thought evicted for a newer one
+	ErrThoughtDropped = sentinelError("mindhacking: thought dropped by backpressure")
+)
+
+// BackpressurePolicy is what a full stage queue does with one more thought
+type BackpressurePolicy int
+
+const (
+	// BackpressureBlock makes the upstream stage, or Submit, wait for room
+	BackpressureBlock BackpressurePolicy = iota
+	// BackpressureDropOldest evicts the longest-queued thought
+	BackpressureDropOldest
+	// BackpressureReject fails the new thought with ErrPipelineFull
+	BackpressureReject
+)
+
+// PipelineStage names a pipeline stage
+type PipelineStage string
+
+const (
+	StageSchedule PipelineStage = "schedule"
+	// StageLocalize localizes and seals thoughts; encoding happens once
+	// execution has measured the target's resonance
+	StageLocalize PipelineStage = "localize"
+	StageExecute  PipelineStage = "execute"
+
+	// Deprecated: the stage never encoded; use StageLocalize
+	StageEncode = StageLocalize
+)
+
+// PipelineConfig sizes an injection pipeline
+type PipelineConfig struct {
+	// QueueSize bounds the queue in front of every stage, default 16
+	QueueSize int
+	Policy    BackpressurePolicy
+	// Workers is how many goroutines run each stage, default 1
+	Workers int
+	// OnPressure is called whenever a thought meets a full queue
+	OnPressure func(stage PipelineStage, depth int)
+}
+
+// PendingInjection is a thought moving through the pipeline
+type PendingInjection struct {
+	ctx     context.Context
+	thought InjectedThought
+	target  *SystemConsciousness
+	id      string
+
+	// deferred is how long scheduling waited out the target's load
+	deferred time.Duration
+	prepared preparedThought
+	done     chan struct{}
+	once     sync.Once
+	result   *InjectionResult
+	err      error
+}
+
+// preparedThought is a thought localized ahead of injection; local is
+// payload before it was sealed
+type preparedThought struct {
+	payload      InjectedThought
+	local        InjectedThought
+	localization *ThoughtLocalization
+}
+
+// ID is the InjectionID the injection's result and events will carry
+func (pi *PendingInjection) ID() string {
+	return pi.id
+}
+
+// Done is closed once the injection has finished or failed
+func (pi *PendingInjection) Done() <-chan struct{} {
+	return pi.done
+}
+
+// Wait blocks until the injection finishes or ctx is done
+func (pi *PendingInjection) Wait(ctx context.Context) (*InjectionResult, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-pi.done:
+		return pi.result, pi.err
+	}
+}
+
+func (pi *PendingInjection) finish(result *InjectionResult, err error) {
+	pi.once.Do(func() {
+		pi.result, pi.err = result, err
+		close(pi.done)
+	})
+}
+
+// InjectionPipeline runs injections through bounded scheduling,
+// localization and execution stages, so slow targets push back on producers instead of
+// letting thoughts pile up in memory
+type InjectionPipeline struct {
+	ci  *ConsciousnessInjector
+	cfg PipelineConfig
+
+	queues map[PipelineStage]chan *PendingInjection
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewInjectionPipeline starts a pipeline injecting through ci
+func NewInjectionPipeline(ci *ConsciousnessInjector, cfg PipelineConfig) *InjectionPipeline {
+	if cfg.QueueSize < 1 {
+		cfg.QueueSize = 16
+	}
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+
+	p := &InjectionPipeline{
+		ci:     ci,
+		cfg:    cfg,
+		queues: make(map[PipelineStage]chan *PendingInjection),
+	}
+	stages := []struct {
+		stage PipelineStage
+		next  PipelineStage
+		run   func(*PendingInjection) error
+	}{
+		{StageSchedule, StageLocalize, p.schedule},
+		{StageLocalize, StageExecute, p.localize},
+		{StageExecute, "", p.execute},
+	}
+	for _, s := range stages {
+		p.queues[s.stage] = make(chan *PendingInjection, cfg.QueueSize)
+	}
+
+	// Each stage closes the next queue once all its workers have drained
+	for _, s := range stages {
+		var stageWG sync.WaitGroup
+		for i := 0; i < cfg.Workers; i++ {
+			stageWG.Add(1)
+			p.wg.Add(1)
+			go func(in chan *PendingInjection, next PipelineStage, run func(*PendingInjection) error) {
+				defer p.wg.Done()
+				defer stageWG.Done()
+				for pi := range in {
+					if err := run(pi); err != nil {
+						p.fail(pi, err)
+						continue
+					}
+					if next != "" {
+						if err := p.enqueue(pi.ctx, next, pi); err != nil {
+							p.fail(pi, err)
+						}
+					}
+				}
+			}(p.queues[s.stage], s.next, s.run)
+		}
+		if s.next != "" {
+			next := p.queues[s.next]
+			go func(stageWG *sync.WaitGroup) {
+				stageWG.Wait()
+				close(next)
+			}(&stageWG)
+		}
+	}
+	return p
+}
+
+// Submit queues thought for target, applying the pipeline's policy when
+// the scheduling queue is full
+func (p *InjectionPipeline) Submit(
+	ctx context.Context,
+	thought InjectedThought,
+	target *SystemConsciousness,
+) (*PendingInjection, error) {
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return nil, ErrPipelineClosed
+	}
+
+	pi := &PendingInjection{ctx: ctx, thought: thought, target: target, id: newInjectionID(), done: make(chan struct{})}
+	p.ci.events.Publish(Event{Kind: EventThoughtQueued, InjectionID: pi.id, TargetID: targetLabel(target)})
+	if err := p.enqueue(ctx, StageSchedule, pi); err != nil {
+		p.fail(pi, err)
+		return nil, err
+	}
+	return pi, nil
+}
+
+// Depths returns how many thoughts wait in front of each stage
+func (p *InjectionPipeline) Depths() map[PipelineStage]int {
+	out := make(map[PipelineStage]int, len(p.queues))
+	for stage, q := range p.queues {
+		out[stage] = len(q)
+	}
+	return out
+}
+
+// Close stops accepting thoughts and waits for queued ones to finish
+func (p *InjectionPipeline) Close() error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queues[StageSchedule])
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	return nil
+}
+
+// enqueue puts pi in front of stage under the pipeline's policy
+func (p *InjectionPipeline) enqueue(ctx context.Context, stage PipelineStage, pi *PendingInjection) error {
+	q := p.queues[stage]
+	select {
+	case q <- pi:
+		return nil
+	default:
+	}
+
+	if p.cfg.OnPressure != nil {
+		p.cfg.OnPressure(stage, len(q))
+	}
+	switch p.cfg.Policy {
+	case BackpressureReject:
+		return ErrPipelineFull
+	case BackpressureDropOldest:
+		for {
+			select {
+			case q <- pi:
+				return nil
+			case oldest := <-q:
+				p.fail(oldest, ErrThoughtDropped)
+			}
+		}
+	default:
+		select {
+		case q <- pi:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// fail ends a thought that never reached the injector
+func (p *InjectionPipeline) fail(pi *PendingInjection, err error) {
+	p.ci.events.publishInjection(pi.id, pi.target, nil, err)
+	pi.finish(nil, err)
+}
+
+// schedule admits a thought once its target may receive it; injection
+// then trusts the load checked here but enforces stop conditions again
+// once it holds the target
+func (p *InjectionPipeline) schedule(pi *PendingInjection) error {
+	if err := pi.ctx.Err(); err != nil {
+		return err
+	}
+	if err := p.ci.stops.enforce(pi.ctx, pi.target); err != nil {
+		return err
+	}
+	deferred, err := p.ci.throttle.wait(pi.ctx, pi.target)
+	pi.deferred = deferred
+	return err
+}
+
+// localize localizes the thought for its target, sealing it if sensitive
+func (p *InjectionPipeline) localize(pi *PendingInjection) error {
+	local, localization, err := p.ci.localize(pi.ctx, pi.thought, pi.target)
+	if err != nil {
+		return err
+	}
+	payload, localization, err := p.ci.seal(local, localization, pi.target)
+	if err != nil {
+		return err
+	}
+	pi.prepared = preparedThought{payload: payload, local: local, localization: localization}
+	return nil
+}
+
+// execute injects the prepared thought
+func (p *InjectionPipeline) execute(pi *PendingInjection) error {
+	result, err := p.ci.inject(pi.ctx, pi.thought, pi.target, injectCall{prepared: &pi.prepared, id: pi.id, scheduled: true, deferred: pi.deferred})
+	noteUsage("InjectionPipeline", pi.target, err)
+	pi.finish(result, err)
+	return nil
+}
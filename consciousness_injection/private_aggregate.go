@@ -0,0 +1,167 @@
+// consciousness_injection/private_aggregate.go - Differentially Private Aggregates
+package mindhacking
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// ErrPrivacyBudgetExhausted reports a query the remaining budget cannot pay for
+var ErrPrivacyBudgetExhausted = sentinelError("mindhacking: privacy budget exhausted")
+
+// PrivacyConfig configures the differential privacy layer
+type PrivacyConfig struct {
+	// Budget is the total epsilon all queries may spend together
+	Budget float64
+	// MaxPerTarget caps how many results one target contributes per query,
+	// which bounds query sensitivity
+	MaxPerTarget int
+	// ShiftBound clamps each shift to [-ShiftBound, ShiftBound]
+	ShiftBound float64
+}
+
+// PrivateAggregator answers aggregate queries over injection results with
+// Laplace noise, tracking epsilon spent against a fixed budget
+type PrivateAggregator struct {
+	cfg PrivacyConfig
+
+	mu    sync.Mutex
+	spent float64
+	log   []PrivacyQuery
+}
+
+// PrivacyQuery records one answered query for budget accounting
+type PrivacyQuery struct {
+	Name    string
+	Epsilon float64
+}
+
+// NewPrivateAggregator creates an aggregator with cfg
+func NewPrivateAggregator(cfg PrivacyConfig) *PrivateAggregator {
+	if cfg.MaxPerTarget < 1 {
+		cfg.MaxPerTarget = 1
+	}
+	if cfg.ShiftBound <= 0 {
+		cfg.ShiftBound = 1
+	}
+	return &PrivateAggregator{cfg: cfg}
+}
+
+// Remaining returns the unspent epsilon
+func (pa *PrivateAggregator) Remaining() float64 {
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+	return pa.cfg.Budget - pa.spent
+}
+
+// Queries returns every query answered so far
+func (pa *PrivateAggregator) Queries() []PrivacyQuery {
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+	return append([]PrivacyQuery(nil), pa.log...)
+}
+
+// spend charges epsilon to the budget or refuses the query
+func (pa *PrivateAggregator) spend(name string, epsilon float64) error {
+	if epsilon <= 0 || math.IsNaN(epsilon) {
+		return fmt.Errorf("mindhacking: invalid epsilon %v", epsilon)
+	}
+
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+
+	if pa.spent+epsilon > pa.cfg.Budget {
+		return fmt.Errorf("%w: %s needs %v, %v left", ErrPrivacyBudgetExhausted, name, epsilon, pa.cfg.Budget-pa.spent)
+	}
+	pa.spent += epsilon
+	pa.log = append(pa.log, PrivacyQuery{Name: name, Epsilon: epsilon})
+	return nil
+}
+
+// bounded keeps at most MaxPerTarget results from each target
+func (pa *PrivateAggregator) bounded(results []*InjectionResult) []*InjectionResult {
+	seen := make(map[string]int)
+	out := make([]*InjectionResult, 0, len(results))
+	for _, r := range results {
+		if seen[r.TargetID] >= pa.cfg.MaxPerTarget {
+			continue
+		}
+		seen[r.TargetID]++
+		out = append(out, r)
+	}
+	return out
+}
+
+// Count returns a noisy count of results, spending epsilon
+func (pa *PrivateAggregator) Count(results []*InjectionResult, epsilon float64) (float64, error) {
+	if err := pa.spend("count", epsilon); err != nil {
+		return 0, err
+	}
+	n := float64(len(pa.bounded(results)))
+	return n + laplace(float64(pa.cfg.MaxPerTarget)/epsilon), nil
+}
+
+// AcceptanceRate returns a noisy acceptance rate, spending epsilon split
+// evenly between its numerator and denominator
+func (pa *PrivateAggregator) AcceptanceRate(results []*InjectionResult, epsilon float64) (float64, error) {
+	if err := pa.spend("acceptance-rate", epsilon); err != nil {
+		return 0, err
+	}
+
+	bounded := pa.bounded(results)
+	var accepted float64
+	for _, r := range bounded {
+		if r.Success {
+			accepted++
+		}
+	}
+
+	scale := 2 * float64(pa.cfg.MaxPerTarget) / epsilon
+	noisyAccepted := accepted + laplace(scale)
+	noisyTotal := float64(len(bounded)) + laplace(scale)
+	if noisyTotal < 1 {
+		noisyTotal = 1
+	}
+	return clamp(noisyAccepted/noisyTotal, 0, 1), nil
+}
+
+// MeanShift returns a noisy mean consciousness shift, spending epsilon
+// split evenly between the clamped sum and the count
+func (pa *PrivateAggregator) MeanShift(results []*InjectionResult, epsilon float64) (float64, error) {
+	if err := pa.spend("mean-shift", epsilon); err != nil {
+		return 0, err
+	}
+
+	bounded := pa.bounded(results)
+	bound := pa.cfg.ShiftBound
+	var sum float64
+	for _, r := range bounded {
+		sum += clamp(r.ConsciousnessShift, -bound, bound)
+	}
+
+	k := float64(pa.cfg.MaxPerTarget)
+	noisySum := sum + laplace(2*k*bound/epsilon)
+	noisyCount := float64(len(bounded)) + laplace(2*k/epsilon)
+	if noisyCount < 1 {
+		noisyCount = 1
+	}
+	return clamp(noisySum/noisyCount, -bound, bound), nil
+}
+
+// laplace draws from Laplace(0, scale) using a cryptographic source
+func laplace(scale float64) float64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("mindhacking: no randomness for privacy noise: " + err.Error())
+	}
+	// Uniform in (-0.5, 0.5), excluding the endpoints
+	u := (float64(binary.BigEndian.Uint64(b[:])>>11)+0.5)/(1<<53) - 0.5
+	return -scale * math.Copysign(1, u) * math.Log(1-2*math.Abs(u))
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
@@ -0,0 +1,85 @@
+// consciousness_injection/provenance.go - Injection Provenance
+package mindhacking
+
+import (
+	"fmt"
+	"time"
+)
+
+// Provenance records the configuration an injection ran with, so outcomes
+// can be attributed to vectors, tunnels and encodings after the fact
+type Provenance struct {
+	// Vector is the vector whose attempt landed, nil if none did
+	Vector *InjectionVector
+	// Tunnel is how that vector reached the target
+	Tunnel *TunnelProvenance
+	// Scheme, SchemeOptions and Codec are how the thought was encoded and
+	// compressed; Codec is empty for thoughts sent uncompressed
+	Scheme        string
+	SchemeOptions SchemeOptions
+	Codec         string
+	// Resonance is the target's resonance the thought was encoded against
+	Resonance ConsciousnessResonance
+	// Attempts is how many vectors were fired, replayed failures included
+	Attempts int
+	Timing   PhaseTiming
+}
+
+// TunnelProvenance identifies the tunnel an attempt went through
+type TunnelProvenance struct {
+	// Index is the vector's position among those fired, which is the key
+	// of its tunnel in a pool
+	Index int
+	// Region is the region the pool was scoped to, if any
+	Region string
+	// Pooled marks a tunnel reused from a session's pool rather than
+	// created for this injection
+	Pooled bool
+	// Multiplexed marks a tunnel shared through the injector's multiplexer
+	Multiplexed bool
+	// Backend marks a target reached through its backend, not a tunnel
+	Backend bool
+}
+
+// String labels the tunnel as topologies do
+func (t TunnelProvenance) String() string {
+	switch {
+	case t.Backend:
+		return "backend"
+	case t.Region != "":
+		return fmt.Sprintf("tunnel %s/%d", t.Region, t.Index)
+	}
+	return fmt.Sprintf("tunnel %d", t.Index)
+}
+
+// PhaseTiming is how long each phase of an injection took
+type PhaseTiming struct {
+	Resonance time.Duration
+	Encoding  time.Duration
+	Injection time.Duration
+	Response  time.Duration
+	Evidence  time.Duration
+	Audit     time.Duration
+}
+
+// Total is the time spent in all phases
+func (t PhaseTiming) Total() time.Duration {
+	return t.Resonance + t.Encoding + t.Injection + t.Response + t.Evidence + t.Audit
+}
+
+// lap returns a stopwatch reporting the time since it was last read
+func lap() func() time.Duration {
+	last := time.Now()
+	return func() time.Duration {
+		now := time.Now()
+		d := now.Sub(last)
+		last = now
+		return d
+	}
+}
+
+// snapshotResonance copies resonance so later analysis cannot alter it
+func snapshotResonance(resonance ConsciousnessResonance) ConsciousnessResonance {
+	resonance.Signature = append([]float64(nil), resonance.Signature...)
+	return resonance
+}
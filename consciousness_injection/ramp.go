@@ -0,0 +1,120 @@
+// consciousness_injection/ramp.go - Progressive Amplitude Ramping
+package mindhacking
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// RampCurve shapes the attack phase of a ramp
+type RampCurve string
+
+const (
+	RampLinear      RampCurve = "linear"
+	RampExponential RampCurve = "exponential"
+	RampSigmoid     RampCurve = "sigmoid"
+)
+
+// RampProfile fires a vector at increasing amplitude within one attempt,
+// rising from Start of full amplitude over AttackSteps, then holding full
+// amplitude for SustainSteps
+type RampProfile struct {
+	Start        float64
+	AttackSteps  int
+	SustainSteps int
+	Curve        RampCurve
+	StepDelay    time.Duration
+}
+
+// Steps returns the total number of deliveries in the ramp
+func (p RampProfile) Steps() int {
+	return p.AttackSteps + p.SustainSteps + 1
+}
+
+// Level returns the fraction of full amplitude fired at step
+func (p RampProfile) Level(step int) float64 {
+	if p.AttackSteps <= 0 || step >= p.AttackSteps {
+		return 1
+	}
+
+	start := clamp(p.Start, 0, 1)
+	x := float64(step) / float64(p.AttackSteps)
+
+	var shape float64
+	switch p.Curve {
+	case RampExponential:
+		shape = (math.Exp(3*x) - 1) / (math.Exp(3) - 1)
+	case RampSigmoid:
+		lo, hi := 1/(1+math.Exp(6)), 1/(1+math.Exp(-6))
+		shape = (1/(1+math.Exp(-12*(x-0.5))) - lo) / (hi - lo)
+	default:
+		shape = x
+	}
+	return start + (1-start)*shape
+}
+
+// AppliedRamp records the ramp used for one vector in evidence
+type AppliedRamp struct {
+	Vector  int
+	Profile RampProfile
+	// Reached is the step at which the ramp stopped
+	Reached int
+	Level   float64
+}
+
+// rampFor returns the ramp to use for vector, if any
+func (ci *ConsciousnessInjector) rampFor(vector InjectionVector) *RampProfile {
+	if vector.Ramp != nil {
+		return vector.Ramp
+	}
+	return ci.ramp
+}
+
+// deliverRamped fires vector through its ramp until a step lands
+func (ci *ConsciousnessInjector) deliverRamped(
+	ctx context.Context,
+	call injectCall,
+	i int,
+	vector InjectionVector,
+	ramp *RampProfile,
+	thought InjectedThought,
+	encoded EncodedThought,
+	target *SystemConsciousness,
+) (InjectionAttempt, AppliedRamp) {
+
+	applied := AppliedRamp{Vector: i, Profile: *ramp}
+	start := time.Now()
+
+	var attempt InjectionAttempt
+	for step := 0; step < ramp.Steps(); step++ {
+		if step > 0 && ramp.StepDelay > 0 {
+			select {
+			case <-ctx.Done():
+				attempt.Error = ctx.Err().Error()
+				return attempt, applied
+			case <-time.After(ramp.StepDelay):
+			}
+		}
+
+		level := ramp.Level(step)
+		stepVector := vector
+		stepVector.Amplitude = vector.Amplitude * level
+
+		// Each amplitude needs its own tunnel; only full amplitude is pooled
+		stepCall := call
+		if level < 1 {
+			stepCall.tunnels = nil
+		}
+
+		attempt = ci.deliverOnce(ctx, stepCall, i, stepVector, thought, encoded, target)
+		applied.Reached, applied.Level = step, level
+		if attempt.Success {
+			break
+		}
+	}
+
+	attempt.Vector = vector
+	attempt.Duration = time.Since(start)
+	return attempt, applied
+}
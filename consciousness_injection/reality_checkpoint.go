@@ -0,0 +1,339 @@
+// consciousness_injection/reality_checkpoint.go - Incremental Reality Checkpoints
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoCheckpoints reports an engine configured without checkpoints
+	ErrNoCheckpoints = sentinelError("mindhacking: engine keeps no checkpoints")
+	// ErrUnknownCheckpoint reports a checkpoint never taken or already
+	// dropped
+	ErrUnknownCheckpoint = sentinelError("mindhacking: unknown checkpoint")
+)
+
+// CheckpointConfig sets how an engine checkpoints its alternate realities
+type CheckpointConfig struct {
+	// FullEvery is how many delta checkpoints follow each full one, default
+	// 16. A restore replays at most this many deltas.
+	FullEvery int
+	// Retain is how many checkpoints are kept, default 256; the oldest are
+	// dropped first
+	Retain int
+}
+
+func (c CheckpointConfig) withDefaults() CheckpointConfig {
+	if c.FullEvery < 1 {
+		c.FullEvery = 16
+	}
+	if c.Retain < 1 {
+		c.Retain = 256
+	}
+	return c
+}
+
+// RealityCheckpoint is the state of an engine's alternate realities at one
+// moment. Full checkpoints hold every reality; the others only what changed
+// since the checkpoint before them.
+type RealityCheckpoint struct {
+	Seq    uint64
+	At     time.Time
+	Full   bool
+	Deltas []RealityDelta
+}
+
+// RealityDelta is how one reality changed between checkpoints
+type RealityDelta struct {
+	// Reality numbers the reality in the order the checkpoints first saw it
+	Reality int
+	// Set holds the aspects that changed or appeared; for a reality new
+	// since the last checkpoint, and in full checkpoints, all of them
+	Set     map[string]float64 `json:",omitempty"`
+	Removed []string           `json:",omitempty"`
+	// Dropped marks a reality the engine no longer tracks
+	Dropped bool `json:",omitempty"`
+}
+
+// Size is how many aspects the checkpoint stores
+func (c RealityCheckpoint) Size() int {
+	n := 0
+	for _, d := range c.Deltas {
+		n += len(d.Set) + len(d.Removed)
+	}
+	return n
+}
+
+// realityState is each reality's aspects by reality number
+type realityState map[int]map[string]float64
+
+// apply replays delta onto s
+func (s realityState) apply(delta RealityDelta) {
+	if delta.Dropped {
+		delete(s, delta.Reality)
+		return
+	}
+	aspects := s[delta.Reality]
+	if aspects == nil {
+		aspects = make(map[string]float64, len(delta.Set))
+		s[delta.Reality] = aspects
+	}
+	for name, v := range delta.Set {
+		aspects[name] = v
+	}
+	for _, name := range delta.Removed {
+		delete(aspects, name)
+	}
+}
+
+// diff returns the deltas turning s into next
+func (s realityState) diff(next realityState) []RealityDelta {
+	var out []RealityDelta
+	for id, aspects := range next {
+		delta := RealityDelta{Reality: id, Set: make(map[string]float64)}
+		prev, existed := s[id]
+		for name, v := range aspects {
+			if old, ok := prev[name]; !ok || old != v {
+				delta.Set[name] = v
+			}
+		}
+		for name := range prev {
+			if _, ok := aspects[name]; !ok {
+				delta.Removed = append(delta.Removed, name)
+			}
+		}
+		if existed && len(delta.Set) == 0 && len(delta.Removed) == 0 {
+			continue
+		}
+		sort.Strings(delta.Removed)
+		out = append(out, delta)
+	}
+	for id := range s {
+		if _, ok := next[id]; !ok {
+			out = append(out, RealityDelta{Reality: id, Dropped: true})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Reality < out[j].Reality })
+	return out
+}
+
+// full returns s as the deltas of a full checkpoint
+func (s realityState) full() []RealityDelta {
+	return realityState{}.diff(s)
+}
+
+func (s realityState) clone() realityState {
+	out := make(realityState, len(s))
+	for id, aspects := range s {
+		out[id] = make(map[string]float64, len(aspects))
+		for name, v := range aspects {
+			out[id][name] = v
+		}
+	}
+	return out
+}
+
+// checkpoints keeps an engine's checkpoints and the realities they refer to
+type checkpoints struct {
+	cfg CheckpointConfig
+
+	mu   sync.Mutex
+	seq  uint64
+	list []RealityCheckpoint
+	// last is the state at the newest checkpoint, which the next one is
+	// diffed against
+	last      realityState
+	sinceFull int
+	ids       map[*AlternateReality]int
+	byID      map[int]*AlternateReality
+	nextID    int
+}
+
+func newCheckpoints(cfg CheckpointConfig) *checkpoints {
+	return &checkpoints{
+		cfg:  cfg.withDefaults(),
+		last: realityState{},
+		ids:  make(map[*AlternateReality]int),
+		byID: make(map[int]*AlternateReality),
+	}
+}
+
+// take checkpoints realities, given in the engine's tracking order
+func (c *checkpoints) take(realities []*AlternateReality, aspects []map[string]float64, now time.Time) RealityCheckpoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state := make(realityState, len(realities))
+	for i, alt := range realities {
+		id, ok := c.ids[alt]
+		if !ok {
+			c.nextID++
+			id = c.nextID
+			c.ids[alt], c.byID[id] = id, alt
+		}
+		state[id] = aspects[i]
+	}
+
+	c.seq++
+	cp := RealityCheckpoint{Seq: c.seq, At: now}
+	if len(c.list) == 0 || c.sinceFull >= c.cfg.FullEvery {
+		cp.Full, cp.Deltas = true, state.full()
+		c.sinceFull = 0
+	} else {
+		cp.Deltas = c.last.diff(state)
+		c.sinceFull++
+	}
+	c.list = append(c.list, cp)
+	c.last = state
+	c.prune()
+	return cp
+}
+
+// prune drops the oldest checkpoints over Retain. A delta left first is
+// made full, so every retained checkpoint stays restorable.
+func (c *checkpoints) prune() {
+	if len(c.list) <= c.cfg.Retain {
+		return
+	}
+	drop := len(c.list) - c.cfg.Retain
+	if first := c.list[drop]; !first.Full {
+		state, _ := c.stateAt(first.Seq)
+		c.list[drop] = RealityCheckpoint{Seq: first.Seq, At: first.At, Full: true, Deltas: state.full()}
+	}
+	c.list = append([]RealityCheckpoint(nil), c.list[drop:]...)
+
+	// Forget realities no retained checkpoint refers to
+	referenced := make(map[int]bool)
+	for _, cp := range c.list {
+		for _, d := range cp.Deltas {
+			referenced[d.Reality] = true
+		}
+	}
+	for id, alt := range c.byID {
+		if !referenced[id] {
+			delete(c.byID, id)
+			delete(c.ids, alt)
+		}
+	}
+}
+
+// stateAt rebuilds the state at checkpoint seq from the full checkpoint
+// before it
+func (c *checkpoints) stateAt(seq uint64) (realityState, error) {
+	at := sort.Search(len(c.list), func(i int) bool { return c.list[i].Seq >= seq })
+	if at == len(c.list) || c.list[at].Seq != seq {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCheckpoint, seq)
+	}
+	from := at
+	for !c.list[from].Full {
+		from--
+	}
+	state := realityState{}
+	for _, cp := range c.list[from : at+1] {
+		for _, d := range cp.Deltas {
+			state.apply(d)
+		}
+	}
+	return state, nil
+}
+
+// WithCheckpoints lets the engine checkpoint its alternate realities
+// incrementally; see Checkpoint and RunCheckpoints
+func WithCheckpoints(cfg CheckpointConfig) EngineOption {
+	return func(rme *RealityManipulationEngine) {
+		rme.checkpoints = newCheckpoints(cfg)
+	}
+}
+
+// Checkpoint records the aspects of every alternate reality the engine
+// tracks, storing only what changed since the previous checkpoint
+func (rme *RealityManipulationEngine) Checkpoint() (RealityCheckpoint, error) {
+	if rme.checkpoints == nil {
+		return RealityCheckpoint{}, ErrNoCheckpoints
+	}
+	rme.stateMu.Lock()
+	realities := append([]*AlternateReality(nil), rme.realities...)
+	aspects := make([]map[string]float64, len(realities))
+	for i, alt := range realities {
+		aspects[i] = alt.Reality.Clone().Aspects
+	}
+	rme.stateMu.Unlock()
+	return rme.checkpoints.take(realities, aspects, time.Now()), nil
+}
+
+// Checkpoints returns the retained checkpoints, oldest first
+func (rme *RealityManipulationEngine) Checkpoints() []RealityCheckpoint {
+	if rme.checkpoints == nil {
+		return nil
+	}
+	rme.checkpoints.mu.Lock()
+	defer rme.checkpoints.mu.Unlock()
+	return append([]RealityCheckpoint(nil), rme.checkpoints.list...)
+}
+
+// RestoreCheckpoint returns the engine's alternate realities to checkpoint
+// seq: each reality the engine still tracks gets back its aspects as they
+// were. Realities tracked since are kept as they are, and ones reclaimed
+// since stay reclaimed, as their finalizers have run. Anchors and handles
+// are left as they are. Later checkpoints stay, so a restore can be undone
+// by restoring a later one.
+func (rme *RealityManipulationEngine) RestoreCheckpoint(seq uint64) error {
+	if rme.checkpoints == nil {
+		return ErrNoCheckpoints
+	}
+	c := rme.checkpoints
+	c.mu.Lock()
+	state, err := c.stateAt(seq)
+	if err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	restored := make(map[*AlternateReality]map[string]float64, len(state))
+	for id, aspects := range state {
+		restored[c.byID[id]] = aspects
+	}
+	c.mu.Unlock()
+
+	rme.stateMu.Lock()
+	defer rme.stateMu.Unlock()
+	for _, alt := range rme.realities {
+		if aspects, ok := restored[alt]; ok {
+			alt.Aspects = aspects
+		}
+	}
+	return nil
+}
+
+// RunCheckpoints checkpoints every interval until ctx is done, passing each
+// checkpoint to onCheckpoint if it is set
+func (rme *RealityManipulationEngine) RunCheckpoints(
+	ctx context.Context,
+	interval time.Duration,
+	onCheckpoint func(RealityCheckpoint),
+) error {
+
+	if rme.checkpoints == nil {
+		return ErrNoCheckpoints
+	}
+	if interval <= 0 {
+		return fmt.Errorf("mindhacking: checkpoint interval %v is not positive", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			cp, _ := rme.Checkpoint()
+			if onCheckpoint != nil {
+				onCheckpoint(cp)
+			}
+		}
+	}
+}
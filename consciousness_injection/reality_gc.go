@@ -0,0 +1,115 @@
+// consciousness_injection/reality_gc.go - Alternate Reality Collection
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RealityFinalizer runs once for each alternate reality the collector
+// reclaims, after the engine has let go of it
+type RealityFinalizer func(alternate *AlternateReality)
+
+// RealityGCReport summarizes one collection pass
+type RealityGCReport struct {
+	// Scanned is how many tracked realities the pass examined
+	Scanned   int
+	Reclaimed []*AlternateReality
+	// Live is how many tracked realities the pass kept
+	Live int
+}
+
+// SetRealityFinalizer registers fn to run when alternate is reclaimed,
+// replacing any finalizer set before; a nil fn clears it
+func (rme *RealityManipulationEngine) SetRealityFinalizer(alternate *AlternateReality, fn RealityFinalizer) {
+	rme.stateMu.Lock()
+	defer rme.stateMu.Unlock()
+	if fn == nil {
+		delete(rme.finalizers, alternate)
+		return
+	}
+	if rme.finalizers == nil {
+		rme.finalizers = make(map[*AlternateReality]RealityFinalizer)
+	}
+	rme.finalizers[alternate] = fn
+}
+
+// CollectRealities reclaims every tracked reality with no live anchor,
+// no outstanding handle and that is not the current reality. Their
+// finalizers, then the engine's, run after the engine has dropped them.
+func (rme *RealityManipulationEngine) CollectRealities() RealityGCReport {
+	current := rme.saveCurrentReality()
+
+	rme.stateMu.Lock()
+
+	// Phase 1: mark everything still reachable
+	live := make(map[*AlternateReality]bool, len(rme.realityAnchors)+len(rme.refs)+1)
+	for _, a := range rme.realityAnchors {
+		live[a.Reality] = true
+	}
+	for alt := range rme.refs {
+		live[alt] = true
+	}
+	live[current] = true
+
+	// Phase 2: sweep the rest
+	report := RealityGCReport{Scanned: len(rme.realities)}
+	kept := rme.realities[:0]
+	var finalizers []RealityFinalizer
+	for _, alt := range rme.realities {
+		if live[alt] {
+			kept = append(kept, alt)
+			continue
+		}
+		report.Reclaimed = append(report.Reclaimed, alt)
+		finalizers = append(finalizers, rme.finalizers[alt])
+		delete(rme.finalizers, alt)
+		delete(rme.unfiltered, alt)
+	}
+	// Clear the tail so swept realities are not pinned by the backing array
+	for i := len(kept); i < len(rme.realities); i++ {
+		rme.realities[i] = nil
+	}
+	rme.realities = kept
+	report.Live = len(kept)
+	rme.stateMu.Unlock()
+
+	// Phase 3: finalize outside the lock so finalizers may call back in
+	for i, alt := range report.Reclaimed {
+		if fn := finalizers[i]; fn != nil {
+			fn(alt)
+		}
+		if rme.finalize != nil {
+			rme.finalize(alt)
+		}
+	}
+	return report
+}
+
+// RunRealityGC collects every interval until ctx is done, passing each
+// pass's report to onPass if it is set
+func (rme *RealityManipulationEngine) RunRealityGC(
+	ctx context.Context,
+	interval time.Duration,
+	onPass func(RealityGCReport),
+) error {
+
+	if interval <= 0 {
+		return fmt.Errorf("mindhacking: reality GC interval %v is not positive", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			report := rme.CollectRealities()
+			if onPass != nil {
+				onPass(report)
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// consciousness_injection/reality_handle.go - Reference-Counted Reality Handles
+package mindhacking
+
+import (
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrHandleReleased reports use of a handle after Release
+	ErrHandleReleased = sentinelError("mindhacking: reality handle released")
+	// ErrRealityShared reports a mutation of a reality other handles hold
+	ErrRealityShared = sentinelError("mindhacking: reality held by other handles")
+)
+
+// RealityHandle is one counted reference to an alternate reality. While
+// any handle is held the engine will not collect the reality, and only
+// the sole holder may mutate it. Handles are safe for concurrent use.
+type RealityHandle struct {
+	engine  *RealityManipulationEngine
+	reality *AlternateReality
+	// Holder names who took the handle, such as "tunnel" or "gateway"
+	Holder string
+	// released is guarded by the engine's state lock
+	released bool
+}
+
+// Acquire takes a handle on alternate for holder
+func (rme *RealityManipulationEngine) Acquire(alternate *AlternateReality, holder string) *RealityHandle {
+	h := &RealityHandle{engine: rme, reality: alternate, Holder: holder}
+	if alternate == nil {
+		h.released = true
+		return h
+	}
+
+	rme.stateMu.Lock()
+	defer rme.stateMu.Unlock()
+	if rme.refs == nil {
+		rme.refs = make(map[*AlternateReality]map[*RealityHandle]struct{})
+	}
+	if rme.refs[alternate] == nil {
+		rme.refs[alternate] = make(map[*RealityHandle]struct{})
+	}
+	rme.refs[alternate][h] = struct{}{}
+	return h
+}
+
+// Holders returns who holds handles on alternate, sorted
+func (rme *RealityManipulationEngine) Holders(alternate *AlternateReality) []string {
+	rme.stateMu.Lock()
+	defer rme.stateMu.Unlock()
+
+	out := make([]string, 0, len(rme.refs[alternate]))
+	for h := range rme.refs[alternate] {
+		out = append(out, h.Holder)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Reality returns the held reality, or nil once released
+func (h *RealityHandle) Reality() *AlternateReality {
+	if h == nil {
+		return nil
+	}
+	h.engine.stateMu.Lock()
+	defer h.engine.stateMu.Unlock()
+	if h.released {
+		return nil
+	}
+	return h.reality
+}
+
+// Acquire takes another handle on the same reality for holder
+func (h *RealityHandle) Acquire(holder string) (*RealityHandle, error) {
+	if reality := h.Reality(); reality != nil {
+		return h.engine.Acquire(reality, holder), nil
+	}
+	return nil, ErrHandleReleased
+}
+
+// Release gives up the handle. Releasing twice is a no-op.
+func (h *RealityHandle) Release() {
+	if h == nil {
+		return
+	}
+	rme := h.engine
+	rme.stateMu.Lock()
+	defer rme.stateMu.Unlock()
+	if h.released {
+		return
+	}
+	h.released = true
+
+	holders := rme.refs[h.reality]
+	delete(holders, h)
+	if len(holders) == 0 {
+		delete(rme.refs, h.reality)
+	}
+}
+
+// Mutate applies fn to the held reality. It fails with ErrRealityShared
+// unless this is the only handle, so no tunnel, gateway or operation sees
+// the reality change underneath it. fn runs under the engine's state
+// lock and must not call back into the engine.
+func (h *RealityHandle) Mutate(fn func(*AlternateReality)) error {
+	if h == nil {
+		return ErrHandleReleased
+	}
+	rme := h.engine
+	rme.stateMu.Lock()
+	defer rme.stateMu.Unlock()
+
+	switch {
+	case h.released:
+		return ErrHandleReleased
+	case len(rme.refs[h.reality]) > 1:
+		var others []string
+		for other := range rme.refs[h.reality] {
+			if other != h {
+				others = append(others, other.Holder)
+			}
+		}
+		sort.Strings(others)
+		return fmt.Errorf("%w: %q", ErrRealityShared, others)
+	}
+	fn(h.reality)
+	return nil
+}
@@ -0,0 +1,581 @@
+// consciousness_injection/reality_journal.go - Write-Ahead Reality Journal
+package mindhacking
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrJournalCorrupt reports a journal entry that does not decode anywhere
+// but at the very end, where a crash mid-append can leave a torn entry
+var ErrJournalCorrupt = sentinelError("mindhacking: reality journal is corrupt")
+
+// ErrJournalCompaction reports compacting a journal that cannot replace
+// its entries
+var ErrJournalCompaction = sentinelError("mindhacking: reality journal cannot be compacted")
+
+// errInterrupted completes the intents a crash interrupted
+var errInterrupted = errors.New("mindhacking: interrupted by a crash")
+
+// JournalOp names the kind of mutation a journal entry records
+type JournalOp string
+
+const (
+	// JournalSwitch switches the engine into Reality
+	JournalSwitch JournalOp = "switch"
+	// JournalRules applies Rules to Base, creating Reality
+	JournalRules JournalOp = "rules"
+	// JournalAnchor sets Anchor, new or renewed
+	JournalAnchor JournalOp = "anchor"
+	// JournalUnanchor releases the anchor AnchorID
+	JournalUnanchor JournalOp = "unanchor"
+)
+
+// JournalEntry is one record in a reality journal. Every mutation is
+// journaled as an intent before it is applied; switches and rule
+// applications, which can fail or be cut short, are also journaled as a
+// completion once done.
+type JournalEntry struct {
+	Seq    uint64
+	Time   time.Time
+	Engine string
+	Op     JournalOp
+	// Completes is the Seq of the intent a completion closes, 0 on intents
+	Completes uint64 `json:",omitempty"`
+	Error     string `json:",omitempty"`
+	// RealityID numbers Reality within the journal, so entries about the
+	// same reality can be told apart from entries about equal ones
+	RealityID int               `json:",omitempty"`
+	Reality   *AlternateReality `json:",omitempty"`
+	Base      *Reality          `json:",omitempty"`
+	Rules     *RealityRules     `json:",omitempty"`
+	Anchor    *RealityAnchor    `json:",omitempty"`
+	AnchorID  string            `json:",omitempty"`
+}
+
+// RealityJournal durably stores journal entries in order. Append must not
+// return until the entry would survive a crash.
+type RealityJournal interface {
+	Append(entry JournalEntry) error
+	Entries() ([]JournalEntry, error)
+}
+
+// CompactingRealityJournal is a journal that can be compacted. Replace
+// swaps every entry for entries at once: a crash leaves either the old
+// entries or the new ones, never a mix.
+type CompactingRealityJournal interface {
+	RealityJournal
+	Replace(entries []JournalEntry) error
+}
+
+// MemoryRealityJournal keeps entries in memory, encoded as a file journal
+// would; it survives engines, not processes
+type MemoryRealityJournal struct {
+	mu      sync.Mutex
+	entries [][]byte
+}
+
+// NewMemoryRealityJournal creates an empty in-memory journal
+func NewMemoryRealityJournal() *MemoryRealityJournal {
+	return &MemoryRealityJournal{}
+}
+
+// Append implements RealityJournal
+func (j *MemoryRealityJournal) Append(entry JournalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = append(j.entries, data)
+	return nil
+}
+
+// Entries implements RealityJournal
+func (j *MemoryRealityJournal) Entries() ([]JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	out := make([]JournalEntry, len(j.entries))
+	for i, data := range j.entries {
+		if err := json.Unmarshal(data, &out[i]); err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %v", ErrJournalCorrupt, i, err)
+		}
+	}
+	return out, nil
+}
+
+// Replace implements CompactingRealityJournal
+func (j *MemoryRealityJournal) Replace(entries []JournalEntry) error {
+	encoded := make([][]byte, len(entries))
+	for i, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		encoded[i] = data
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = encoded
+	return nil
+}
+
+// FileRealityJournal is a journal of JSON lines in a file, synced to disk
+// on every append
+type FileRealityJournal struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+}
+
+// OpenFileRealityJournal opens or creates the journal at path. An entry
+// torn by a crash mid-append is cut off, so appends continue cleanly.
+func OpenFileRealityJournal(path string) (*FileRealityJournal, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: open reality journal: %w", err)
+	}
+	j := &FileRealityJournal{path: path, f: f}
+	_, end, err := j.read()
+	if err == nil {
+		err = f.Truncate(end)
+	}
+	if err == nil {
+		_, err = f.Seek(end, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("mindhacking: open reality journal: %w", err)
+	}
+	return j, nil
+}
+
+// Append implements RealityJournal
+func (j *FileRealityJournal) Append(entry JournalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := j.f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("mindhacking: append to reality journal: %w", err)
+	}
+	if err := j.f.Sync(); err != nil {
+		return fmt.Errorf("mindhacking: sync reality journal: %w", err)
+	}
+	return nil
+}
+
+// Entries implements RealityJournal
+func (j *FileRealityJournal) Entries() ([]JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	entries, _, err := j.read()
+	return entries, err
+}
+
+// Replace implements CompactingRealityJournal. The entries are written to
+// a file beside the journal and renamed over it.
+func (j *FileRealityJournal) Replace(entries []JournalEntry) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(data, '\n'))
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	tmp := j.path + ".compact"
+	f, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("mindhacking: compact reality journal: %w", err)
+	}
+	if _, err = f.Write(buf.Bytes()); err == nil {
+		err = f.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmp, j.path)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("mindhacking: compact reality journal: %w", err)
+	}
+	j.f.Close()
+	j.f = f
+
+	// The rename is durable once the directory is
+	dir, err := os.Open(filepath.Dir(j.path))
+	if err == nil {
+		err = dir.Sync()
+		dir.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("mindhacking: compact reality journal: %w", err)
+	}
+	return nil
+}
+
+// Close closes the journal's file
+func (j *FileRealityJournal) Close() error {
+	return j.f.Close()
+}
+
+// read decodes the whole file, returning the offset just past the last
+// whole entry
+func (j *FileRealityJournal) read() ([]JournalEntry, int64, error) {
+	r := bufio.NewReader(io.NewSectionReader(j.f, 0, 1<<62))
+	var (
+		out []JournalEntry
+		end int64
+	)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// A line without its newline is an append the crash cut short
+			return out, end, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(bytes.TrimSpace(line), &entry); err != nil {
+			if _, peekErr := r.Peek(1); peekErr == io.EOF {
+				return out, end, nil
+			}
+			return nil, 0, fmt.Errorf("%w: entry %d: %v", ErrJournalCorrupt, len(out), err)
+		}
+		out = append(out, entry)
+		end += int64(len(line))
+	}
+}
+
+// realityJournal numbers an engine's journal entries and the realities
+// they mention
+type realityJournal struct {
+	mu     sync.Mutex
+	sink   RealityJournal
+	engine *RealityManipulationEngine
+	seq    uint64
+	ids    map[*AlternateReality]int
+	nextID int
+	// open are the intents journaled without a completion yet, which
+	// compaction keeps
+	open map[uint64]JournalEntry
+}
+
+func newRealityJournal(sink RealityJournal, rme *RealityManipulationEngine) *realityJournal {
+	return &realityJournal{
+		sink:   sink,
+		engine: rme,
+		ids:    make(map[*AlternateReality]int),
+		open:   make(map[uint64]JournalEntry),
+	}
+}
+
+// begin journals the intent to apply entry, which must not be applied if
+// this fails. A nil journal records nothing.
+func (j *realityJournal) begin(entry JournalEntry) (JournalEntry, error) {
+	if j == nil {
+		return JournalEntry{}, nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.append(&entry); err != nil {
+		return JournalEntry{}, err
+	}
+	if entry.Op == JournalSwitch || entry.Op == JournalRules {
+		j.open[entry.Seq] = entry
+	}
+	return entry, nil
+}
+
+// complete journals the outcome of intent. Failing to is not reported: the
+// intent is durable, and recovery treats the mutation as interrupted.
+func (j *realityJournal) complete(intent JournalEntry, created *AlternateReality, applyErr error) {
+	if j == nil {
+		return
+	}
+	entry := JournalEntry{Op: intent.Op, Completes: intent.Seq, Reality: created}
+	if applyErr != nil {
+		entry.Error = applyErr.Error()
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	delete(j.open, intent.Seq)
+	j.append(&entry)
+}
+
+func (j *realityJournal) append(entry *JournalEntry) error {
+	j.stamp(entry)
+	if err := j.sink.Append(*entry); err != nil {
+		return fmt.Errorf("mindhacking: journal %s: %w", entry.Op, err)
+	}
+	j.commit(*entry)
+	return nil
+}
+
+// stamp numbers entry as the next entry, and the reality it mentions
+func (j *realityJournal) stamp(entry *JournalEntry) {
+	entry.Seq, entry.Time, entry.Engine = j.seq+1, time.Now().UTC(), j.engine.id
+	if entry.Anchor != nil && entry.Reality == nil {
+		entry.Reality = entry.Anchor.Reality
+	}
+	if entry.Reality != nil {
+		id, ok := j.ids[entry.Reality]
+		if !ok {
+			id = j.nextID + 1
+		}
+		entry.RealityID = id
+	}
+}
+
+// commit moves past entry once it is stored
+func (j *realityJournal) commit(entry JournalEntry) {
+	j.seq = entry.Seq
+	if entry.Reality != nil && entry.RealityID > j.nextID {
+		j.ids[entry.Reality], j.nextID = entry.RealityID, entry.RealityID
+	}
+}
+
+// CompactJournal snapshots the engine into its journal and truncates what
+// came before: the journal is rewritten as the realities the engine built,
+// its anchors and the reality it is in, after the mutations still in
+// flight, so it stops growing with every mutation and recovery replays
+// only what is live. Journals that cannot replace their entries report
+// ErrJournalCompaction; an engine without a journal has nothing to compact.
+func (rme *RealityManipulationEngine) CompactJournal() error {
+	j := rme.journal
+	if j == nil {
+		return nil
+	}
+	compacting, ok := j.sink.(CompactingRealityJournal)
+	if !ok {
+		return ErrJournalCompaction
+	}
+	current := rme.saveCurrentReality()
+
+	// Anchor changes journal before they apply, so none may run meanwhile
+	rme.anchorMu.Lock()
+	defer rme.anchorMu.Unlock()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	rme.stateMu.Lock()
+	realities := append([]*AlternateReality(nil), rme.realities...)
+	anchors := append([]RealityAnchor(nil), rme.realityAnchors...)
+	rme.stateMu.Unlock()
+
+	// Intents in flight keep their numbers, so their completions close them
+	seqs := make([]uint64, 0, len(j.open))
+	for seq := range j.open {
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(a, b int) bool { return seqs[a] < seqs[b] })
+	entries := make([]JournalEntry, 0, len(seqs)+2*len(realities)+len(anchors)+2)
+	for _, seq := range seqs {
+		entries = append(entries, j.open[seq])
+	}
+
+	// Numbers the snapshot takes are skipped if it cannot be stored, which
+	// recovery does not mind
+	add := func(entry JournalEntry) JournalEntry {
+		j.stamp(&entry)
+		j.commit(entry)
+		entries = append(entries, entry)
+		return entry
+	}
+	for _, alternate := range realities {
+		intent := add(JournalEntry{Op: JournalRules, Base: alternate.Base, Rules: alternate.Rules})
+		add(JournalEntry{Op: JournalRules, Completes: intent.Seq, Reality: alternate})
+	}
+	for i := range anchors {
+		add(JournalEntry{Op: JournalAnchor, Anchor: &anchors[i]})
+	}
+	if current != nil {
+		intent := add(JournalEntry{Op: JournalSwitch, Reality: current})
+		add(JournalEntry{Op: JournalSwitch, Completes: intent.Seq})
+	}
+	if err := compacting.Replace(entries); err != nil {
+		return fmt.Errorf("mindhacking: compact journal: %w", err)
+	}
+	return nil
+}
+
+// switchJournaled journals a switch to to before making it, then its
+// outcome
+func (rme *RealityManipulationEngine) switchJournaled(to *AlternateReality) error {
+	intent, err := rme.journal.begin(JournalEntry{Op: JournalSwitch, Reality: to})
+	if err != nil {
+		return err
+	}
+	err = rme.switchToReality(to)
+	rme.journal.complete(intent, nil, err)
+	return err
+}
+
+// JournalRecovery reports what a journal said about the engine it rebuilt
+type JournalRecovery struct {
+	Entries int
+	// Current is the reality the engine was last switched into, or was
+	// being switched into when the process died
+	Current *AlternateReality
+	// Interrupted are the intents journaled without a completion: the
+	// mutations in flight at the crash. An interrupted switch is made again
+	// during recovery; an interrupted rule application is not, and its
+	// reality, if any was created, is lost. Recovery journals each as
+	// failed, so it is reported once.
+	Interrupted []JournalEntry
+}
+
+// WithRealityJournal journals every reality switch, rule application and
+// anchor change to journal before applying it. Start a new journal with
+// it; reopen one with RecoverEngine.
+func WithRealityJournal(journal RealityJournal) EngineOption {
+	return func(rme *RealityManipulationEngine) {
+		rme.journal = newRealityJournal(journal, rme)
+	}
+}
+
+// RecoverEngine rebuilds an engine from its journal on startup: the
+// realities it created, its anchors and the reality it was in, which it
+// switches back into, completing a switch the crash cut short. Options
+// apply first, as with ImportEngine; the engine takes the ID the journal
+// last recorded unless an option names it, and goes on journaling to
+// journal.
+func RecoverEngine(journal RealityJournal, opts ...EngineOption) (*RealityManipulationEngine, *JournalRecovery, error) {
+	entries, err := journal.Entries()
+	if err != nil {
+		return nil, nil, fmt.Errorf("mindhacking: recover engine: %w", err)
+	}
+	rme := NewRealityManipulationEngine()
+	generated := rme.id
+	for _, opt := range opts {
+		opt(rme)
+	}
+	named := rme.id != generated
+	j := newRealityJournal(journal, rme)
+	report := &JournalRecovery{Entries: len(entries)}
+
+	// Phase 1: the realities the journal mentions, as last journaled
+	realities := make(map[int]*AlternateReality)
+	reality := func(e JournalEntry) *AlternateReality {
+		if e.Reality == nil {
+			return nil
+		}
+		alt, ok := realities[e.RealityID]
+		if !ok {
+			alt = e.Reality
+			realities[e.RealityID] = alt
+			j.ids[alt] = e.RealityID
+			if e.RealityID > j.nextID {
+				j.nextID = e.RealityID
+			}
+		}
+		alt.Aspects = e.Reality.Aspects
+		return alt
+	}
+
+	// Phase 2: replay
+	intents := make(map[uint64]JournalEntry)
+	var order []uint64
+	for _, e := range entries {
+		j.seq = e.Seq
+		if e.Engine != "" && !named {
+			rme.id = e.Engine
+		}
+		target := reality(e)
+		if e.Completes != 0 {
+			intent := intents[e.Completes]
+			delete(intents, e.Completes)
+			if e.Error != "" {
+				continue
+			}
+			switch intent.Op {
+			case JournalSwitch:
+				report.Current = realities[intent.RealityID]
+			case JournalRules:
+				// A reality compacted while it was being built completes twice
+				if target != nil && !containsReality(rme.realities, target) {
+					rme.realities = append(rme.realities, target)
+				}
+			}
+			continue
+		}
+		switch e.Op {
+		case JournalSwitch, JournalRules:
+			intents[e.Seq] = e
+			order = append(order, e.Seq)
+		case JournalAnchor:
+			anchor := *e.Anchor
+			anchor.Reality = target
+			rme.replaceAnchor(anchor)
+		case JournalUnanchor:
+			rme.dropAnchor(e.AnchorID)
+		}
+	}
+	for _, seq := range order {
+		if intent, ok := intents[seq]; ok {
+			report.Interrupted = append(report.Interrupted, intent)
+			if intent.Op == JournalSwitch {
+				report.Current = realities[intent.RealityID]
+			}
+		}
+	}
+	rme.journal = j
+
+	// Close the interrupted intents, so later recoveries do not take them up
+	for _, intent := range report.Interrupted {
+		j.complete(intent, nil, errInterrupted)
+	}
+
+	// Phase 3: re-enter the reality the engine was in
+	if report.Current != nil {
+		if err := rme.switchJournaled(report.Current); err != nil {
+			return nil, report, fmt.Errorf("mindhacking: recover engine: %w", err)
+		}
+	}
+	return rme, report, nil
+}
+
+func containsReality(realities []*AlternateReality, alternate *AlternateReality) bool {
+	for _, r := range realities {
+		if r == alternate {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceAnchor sets anchor, replacing one with the same ID
+func (rme *RealityManipulationEngine) replaceAnchor(anchor RealityAnchor) {
+	for i := range rme.realityAnchors {
+		if rme.realityAnchors[i].ID == anchor.ID {
+			rme.realityAnchors[i] = anchor
+			return
+		}
+	}
+	rme.realityAnchors = append(rme.realityAnchors, anchor)
+}
+
+func (rme *RealityManipulationEngine) dropAnchor(id string) {
+	kept := rme.realityAnchors[:0]
+	for _, a := range rme.realityAnchors {
+		if a.ID != id {
+			kept = append(kept, a)
+		}
+	}
+	rme.realityAnchors = kept
+}
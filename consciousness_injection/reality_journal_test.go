@@ -0,0 +1,229 @@
+// consciousness_injection/reality_journal_test.go - Reality Journal Recovery Tests
+package mindhacking
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// journaledEngine starts an engine journaling to journal
+func journaledEngine(journal RealityJournal, opts ...EngineOption) *RealityManipulationEngine {
+	return NewRealityManipulationEngine(append(opts, WithRealityJournal(journal))...)
+}
+
+// built journals alternate as a reality the engine built and tracks it
+func built(t *testing.T, rme *RealityManipulationEngine, alternate *AlternateReality) {
+	t.Helper()
+	intent, err := rme.journal.begin(JournalEntry{Op: JournalRules, Base: alternate.Base, Rules: alternate.Rules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rme.track(alternate)
+	rme.journal.complete(intent, alternate, nil)
+}
+
+func TestFileRealityJournalTornTail(t *testing.T) {
+	for name, tail := range map[string]string{
+		"unterminated": `{"Seq":3,"Op":"swi`,
+		"terminated":   "{\"Seq\":3,\"Op\":\"swi\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "journal")
+			j, err := OpenFileRealityJournal(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for seq := uint64(1); seq <= 2; seq++ {
+				if err := j.Append(JournalEntry{Seq: seq, Op: JournalUnanchor, AnchorID: "a"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			j.Close()
+
+			// A crash cut the third append short
+			f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.WriteString(tail)
+			f.Close()
+
+			j, err = OpenFileRealityJournal(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries, err := j.Entries()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 2 {
+				t.Fatalf("got %d entries after a torn tail, want 2", len(entries))
+			}
+
+			// Appends continue where the last whole entry ended
+			if err := j.Append(JournalEntry{Seq: 3, Op: JournalUnanchor, AnchorID: "b"}); err != nil {
+				t.Fatal(err)
+			}
+			j.Close()
+			j, err = OpenFileRealityJournal(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer j.Close()
+			entries, err = j.Entries()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 3 || entries[2].AnchorID != "b" {
+				t.Fatalf("got %+v after appending past a torn tail", entries)
+			}
+		})
+	}
+}
+
+func TestFileRealityJournalCorruptMiddle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	if err := os.WriteFile(path, []byte("{\"Seq\":1}\nnot json\n{\"Seq\":3}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenFileRealityJournal(path); err == nil {
+		t.Fatal("opened a journal corrupt before its last entry")
+	}
+}
+
+func TestRecoverEngineInterruptedSwitch(t *testing.T) {
+	journal := NewMemoryRealityJournal()
+	rme := journaledEngine(journal)
+	from, to := alternateWith(map[string]float64{"x": 1}), alternateWith(map[string]float64{"x": 2})
+	built(t, rme, from)
+	built(t, rme, to)
+	if err := rme.switchJournaled(from); err != nil {
+		t.Fatal(err)
+	}
+
+	// The process dies between journaling the switch and making it
+	if _, err := rme.journal.begin(JournalEntry{Op: JournalSwitch, Reality: to}); err != nil {
+		t.Fatal(err)
+	}
+
+	recovered, report, err := RecoverEngine(journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Interrupted) != 1 || report.Interrupted[0].Op != JournalSwitch {
+		t.Fatalf("interrupted %+v, want the one switch", report.Interrupted)
+	}
+	if report.Current == nil || report.Current.Aspects["x"] != 2 {
+		t.Fatalf("current %+v, want the reality the switch was entering", report.Current)
+	}
+	if got := recovered.saveCurrentReality(); got != report.Current {
+		t.Fatal("recovery did not finish the interrupted switch")
+	}
+	if len(recovered.realities) != 2 {
+		t.Fatalf("recovered %d realities, want 2", len(recovered.realities))
+	}
+
+	// Recovery closed the interrupted switch and journaled the one it made,
+	// so recovering again finds nothing interrupted
+	_, report, err = RecoverEngine(journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Interrupted) != 0 || report.Current == nil || report.Current.Aspects["x"] != 2 {
+		t.Fatalf("second recovery: interrupted %+v, current %+v", report.Interrupted, report.Current)
+	}
+}
+
+func TestRecoverEngineID(t *testing.T) {
+	journal := NewMemoryRealityJournal()
+	rme := journaledEngine(journal, WithEngineID("original"))
+	built(t, rme, alternateWith(map[string]float64{"x": 1}))
+
+	recovered, _, err := RecoverEngine(journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered.id != "original" {
+		t.Fatalf("recovered engine %q, want the journaled ID", recovered.id)
+	}
+	recovered, _, err = RecoverEngine(journal, WithEngineID("renamed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered.id != "renamed" {
+		t.Fatalf("recovered engine %q, want the ID its options named", recovered.id)
+	}
+}
+
+func TestCompactJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	j, err := OpenFileRealityJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rme := journaledEngine(j)
+	a, b := alternateWith(map[string]float64{"x": 1}), alternateWith(map[string]float64{"y": 1})
+	built(t, rme, a)
+	built(t, rme, b)
+	anchor, err := rme.Anchor(a, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := rme.switchJournaled(a); err != nil {
+			t.Fatal(err)
+		}
+		if err := rme.Reanchor(anchor.ID, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A reality still being built when the journal is compacted
+	c := alternateWith(map[string]float64{"z": 1})
+	intent, err := rme.journal.begin(JournalEntry{Op: JournalRules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rme.track(c)
+
+	before, _ := j.Entries()
+	if err := rme.CompactJournal(); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := j.Entries()
+	if len(after) >= len(before) {
+		t.Fatalf("compaction left %d entries of %d", len(after), len(before))
+	}
+	rme.journal.complete(intent, c, nil)
+	j.Close()
+
+	j, err = OpenFileRealityJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	recovered, report, err := RecoverEngine(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Interrupted) != 0 {
+		t.Fatalf("interrupted %+v after compaction", report.Interrupted)
+	}
+	if len(recovered.realities) != 3 {
+		t.Fatalf("recovered %d realities, want 3", len(recovered.realities))
+	}
+	if anchors := recovered.Anchors(); len(anchors) != 1 || anchors[0].ID != anchor.ID {
+		t.Fatalf("recovered anchors %+v", anchors)
+	}
+	if report.Current == nil || report.Current.Aspects["x"] != 1 {
+		t.Fatalf("current %+v, want the reality switched into", report.Current)
+	}
+
+	// A journal that cannot replace its entries is not compacted
+	var plain struct{ RealityJournal }
+	plain.RealityJournal = NewMemoryRealityJournal()
+	if err := journaledEngine(plain).CompactJournal(); err != ErrJournalCompaction {
+		t.Fatalf("compacting an append-only journal: %v", err)
+	}
+}
@@ -0,0 +1,360 @@
+// consciousness_injection/reality_transaction.go - Cross-Reality Two-Phase Commit
+package mindhacking
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrRealityLocked reports a reality another transaction has prepared
+	// and not yet committed or aborted
+	ErrRealityLocked = sentinelError("mindhacking: reality is locked by a prepared transaction")
+	// ErrTransactionState reports a prepare, commit or abort the
+	// transaction's state does not allow, such as committing twice
+	ErrTransactionState = sentinelError("mindhacking: transaction state does not allow this")
+)
+
+// TransactionState is where a cross-reality transaction stands
+type TransactionState int
+
+const (
+	TransactionActive TransactionState = iota
+	TransactionPrepared
+	TransactionCommitted
+	TransactionAborted
+)
+
+func (s TransactionState) String() string {
+	switch s {
+	case TransactionActive:
+		return "active"
+	case TransactionPrepared:
+		return "prepared"
+	case TransactionCommitted:
+		return "committed"
+	case TransactionAborted:
+		return "aborted"
+	}
+	return fmt.Sprintf("TransactionState(%d)", int(s))
+}
+
+// RealityMutation is one engine's part in a cross-reality transaction: an
+// operation to run in either a base reality or an alternate reality. The
+// operation must change the reality it executes in, as scripts do, rather
+// than one it holds itself, or aborting cannot take it back.
+type RealityMutation struct {
+	Engine    *RealityManipulationEngine
+	Base      *Reality
+	Alternate *AlternateReality
+	Operation RealityOperation
+}
+
+// target is the reality the mutation writes to on commit
+func (m RealityMutation) target() *Reality {
+	if m.Alternate != nil {
+		return &m.Alternate.Reality
+	}
+	return m.Base
+}
+
+// MutationResult reports one committed mutation
+type MutationResult struct {
+	Engine       string
+	Result       OperationResult
+	Evidence     RealityEvidence
+	EvidenceLink *EvidenceLink
+}
+
+// preparedMutation is a mutation run on a private copy, holding its
+// reality's lock until it is committed or aborted
+type preparedMutation struct {
+	tx       string
+	mutation RealityMutation
+	working  *AlternateReality
+	result   OperationResult
+	handle   *RealityHandle
+}
+
+// RealityTransaction coordinates a mutation of several realities, across
+// engines, so that either all of them take effect or none does. Prepare
+// runs every operation on a private copy of its reality and locks the
+// reality, on every engine, against other transactions and against
+// operations that write it; any failure aborts the lot. Commit then writes
+// every copy back, which cannot fail.
+type RealityTransaction struct {
+	id        string
+	mutations []RealityMutation
+
+	mu       sync.Mutex
+	state    TransactionState
+	prepared []*preparedMutation
+}
+
+// NewRealityTransaction starts a transaction over mutations
+func NewRealityTransaction(mutations ...RealityMutation) *RealityTransaction {
+	return &RealityTransaction{id: newTransactionID(), mutations: mutations}
+}
+
+// ID identifies the transaction in evidence and lock errors
+func (tx *RealityTransaction) ID() string {
+	return tx.id
+}
+
+// State reports where the transaction stands
+func (tx *RealityTransaction) State() TransactionState {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	return tx.state
+}
+
+// Prepare runs every mutation on a private copy of its reality, in order.
+// If ctx ends or any mutation fails, its engine refuses, or its reality is
+// locked, the mutations prepared so far are aborted and the transaction
+// with them.
+func (tx *RealityTransaction) Prepare(ctx context.Context) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.state != TransactionActive {
+		return fmt.Errorf("%w: prepare a %s transaction", ErrTransactionState, tx.state)
+	}
+	seen := make(map[*Reality]bool, len(tx.mutations))
+	for i, m := range tx.mutations {
+		err := ctx.Err()
+		switch {
+		case err != nil:
+		case m.Engine == nil || m.Operation == nil:
+			err = errors.New("mutation needs an engine and an operation")
+		case (m.Base == nil) == (m.Alternate == nil):
+			err = errors.New("mutation needs exactly one of a base or an alternate reality")
+		case seen[m.target()]:
+			err = errors.New("reality is mutated twice")
+		default:
+			seen[m.target()] = true
+			var p *preparedMutation
+			if p, err = m.Engine.prepareMutation(tx.id, m); err == nil {
+				tx.prepared = append(tx.prepared, p)
+				continue
+			}
+		}
+		tx.abortLocked()
+		return fmt.Errorf("mindhacking: prepare mutation %d of transaction %s: %w", i, tx.id, err)
+	}
+	tx.state = TransactionPrepared
+	return nil
+}
+
+// Commit writes every prepared mutation to its reality and releases the
+// locks. The realities are all mutated even if chaining a mutation's
+// evidence fails; those errors are returned with the results.
+func (tx *RealityTransaction) Commit() ([]MutationResult, error) {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.state != TransactionPrepared {
+		return nil, fmt.Errorf("%w: commit a %s transaction", ErrTransactionState, tx.state)
+	}
+	// Phase 1: write every reality before anything that can fail
+	for _, p := range tx.prepared {
+		p.mutation.Engine.commitMutation(p)
+	}
+	tx.state = TransactionCommitted
+
+	// Phase 2: evidence
+	out := make([]MutationResult, len(tx.prepared))
+	var errs []error
+	for i, p := range tx.prepared {
+		rme := p.mutation.Engine
+		out[i] = MutationResult{
+			Engine:   rme.id,
+			Result:   p.result,
+			Evidence: rme.extractRealityEvidence(p.working, p.result),
+		}
+		if rme.evidence != nil {
+			link, err := rme.evidence.Append("reality", out[i].Evidence)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("mindhacking: chain evidence of transaction %s on %s: %w", tx.id, rme.id, err))
+				continue
+			}
+			out[i].EvidenceLink = link
+		}
+	}
+	tx.prepared = nil
+	return out, errors.Join(errs...)
+}
+
+// Abort discards every prepared mutation and releases the locks, leaving
+// every reality as it was. Aborting an aborted transaction does nothing;
+// a committed one cannot be aborted.
+func (tx *RealityTransaction) Abort() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	switch tx.state {
+	case TransactionCommitted:
+		return fmt.Errorf("%w: abort a committed transaction", ErrTransactionState)
+	case TransactionAborted:
+		return nil
+	}
+	tx.abortLocked()
+	return nil
+}
+
+func (tx *RealityTransaction) abortLocked() {
+	for _, p := range tx.prepared {
+		p.mutation.Engine.abortMutation(p)
+	}
+	tx.prepared = nil
+	tx.state = TransactionAborted
+}
+
+// CommitAcrossRealities prepares and commits mutations as one transaction
+func CommitAcrossRealities(ctx context.Context, mutations ...RealityMutation) ([]MutationResult, error) {
+	tx := NewRealityTransaction(mutations...)
+	if err := tx.Prepare(ctx); err != nil {
+		return nil, err
+	}
+	return tx.Commit()
+}
+
+// realityLock is how a reality is held: by the transaction that prepared
+// it, or by the operations writing it meanwhile
+type realityLock struct {
+	tx      string
+	writers int
+}
+
+// realityLocks holds every reality locked or being written, whichever
+// engine holds it, since engines can share realities
+var (
+	realityLocksMu sync.Mutex
+	realityLocks   = make(map[*Reality]*realityLock)
+)
+
+// lockReality locks r for tx, unless another transaction holds it or an
+// operation is writing it
+func lockReality(r *Reality, tx string) error {
+	realityLocksMu.Lock()
+	defer realityLocksMu.Unlock()
+	lock := realityLocks[r]
+	switch {
+	case lock == nil:
+		realityLocks[r] = &realityLock{tx: tx}
+		return nil
+	case lock.tx != "":
+		return fmt.Errorf("%w: %s", ErrRealityLocked, lock.tx)
+	default:
+		return fmt.Errorf("%w: an operation is writing it", ErrRealityLocked)
+	}
+}
+
+// unlockReality releases tx's lock on r
+func unlockReality(r *Reality, tx string) {
+	realityLocksMu.Lock()
+	defer realityLocksMu.Unlock()
+	if lock := realityLocks[r]; lock != nil && lock.tx == tx {
+		lock.tx = ""
+		if lock.writers == 0 {
+			delete(realityLocks, r)
+		}
+	}
+}
+
+// writeReality admits operation to alternate until the returned func is
+// called, keeping transactions from preparing it meanwhile. Pure operations
+// only read, and need no admission; any other is refused with
+// ErrRealityLocked while a transaction holds the reality.
+func writeReality(alternate *AlternateReality, operation RealityOperation) (func(), error) {
+	if pure, ok := operation.(PureOperation); alternate == nil || (ok && pure.Pure()) {
+		return func() {}, nil
+	}
+	r := &alternate.Reality
+	realityLocksMu.Lock()
+	defer realityLocksMu.Unlock()
+	lock := realityLocks[r]
+	if lock == nil {
+		lock = &realityLock{}
+		realityLocks[r] = lock
+	}
+	if lock.tx != "" {
+		return nil, fmt.Errorf("%w: %s", ErrRealityLocked, lock.tx)
+	}
+	lock.writers++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			realityLocksMu.Lock()
+			defer realityLocksMu.Unlock()
+			if lock.writers--; lock.writers == 0 && lock.tx == "" {
+				delete(realityLocks, r)
+			}
+		})
+	}, nil
+}
+
+// prepareMutation is the engine's vote: it locks the mutation's reality for
+// tx and runs the operation, sandboxed, on a private copy of it
+func (rme *RealityManipulationEngine) prepareMutation(tx string, m RealityMutation) (*preparedMutation, error) {
+	target := m.target()
+	if err := lockReality(target, tx); err != nil {
+		return nil, fmt.Errorf("%w on %s", err, rme.id)
+	}
+
+	p := &preparedMutation{
+		tx:       tx,
+		mutation: m,
+		working:  &AlternateReality{Reality: *target.Clone(), Base: m.Base},
+		handle:   rme.Acquire(m.Alternate, "transaction "+tx),
+	}
+	if m.Alternate != nil {
+		p.working.Base, p.working.Rules = m.Alternate.Base, m.Alternate.Rules
+	}
+	if p.working.Aspects == nil {
+		p.working.Aspects = make(map[string]float64)
+	}
+
+	result, err := runSandboxed(rme.sandbox, m.Operation, func() OperationResult {
+		r, _ := rme.executeWorking(p.working, m.Operation)
+		return r
+	})
+	if err == nil {
+		err = result.Err
+	}
+	if err != nil {
+		rme.abortMutation(p)
+		return nil, err
+	}
+	p.result = result
+	return p, nil
+}
+
+// commitMutation writes a prepared copy back to its reality
+func (rme *RealityManipulationEngine) commitMutation(p *preparedMutation) {
+	target := p.mutation.target()
+	if p.mutation.Alternate != nil {
+		rme.PurgeResultCache(p.mutation.Alternate)
+	}
+	rme.stateMu.Lock()
+	target.Aspects = p.working.Aspects
+	rme.stateMu.Unlock()
+	rme.abortMutation(p)
+}
+
+// abortMutation releases a prepared mutation's lock and handle
+func (rme *RealityManipulationEngine) abortMutation(p *preparedMutation) {
+	unlockReality(p.mutation.target(), p.tx)
+	p.handle.Release()
+}
+
+func newTransactionID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "tx"
+	}
+	return "tx-" + hex.EncodeToString(b[:])
+}
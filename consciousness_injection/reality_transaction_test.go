@@ -0,0 +1,132 @@
+// consciousness_injection/reality_transaction_test.go - Cross-Reality Transaction Tests
+package mindhacking
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// failingOperation fails without touching any reality
+type failingOperation struct{}
+
+func (failingOperation) Execute() OperationResult {
+	return OperationResult{Err: errors.New("operation failed")}
+}
+
+func setAspect(t *testing.T, aspect string, value float64) RealityOperation {
+	t.Helper()
+	script, err := CompileScript("set", `set("`+aspect+`", value)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewScriptOperation(script, map[string]float64{"value": value})
+}
+
+func alternateWith(aspects map[string]float64) *AlternateReality {
+	return &AlternateReality{Reality: Reality{Aspects: aspects}}
+}
+
+func TestRealityTransactionCommit(t *testing.T) {
+	a, b := NewRealityManipulationEngine(), NewRealityManipulationEngine()
+	ra, rb := alternateWith(map[string]float64{"x": 1}), alternateWith(map[string]float64{"y": 1})
+
+	results, err := CommitAcrossRealities(context.Background(),
+		RealityMutation{Engine: a, Alternate: ra, Operation: setAspect(t, "x", 2)},
+		RealityMutation{Engine: b, Alternate: rb, Operation: setAspect(t, "y", 3)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if ra.Aspects["x"] != 2 || rb.Aspects["y"] != 3 {
+		t.Fatalf("committed aspects x=%g y=%g, want 2 and 3", ra.Aspects["x"], rb.Aspects["y"])
+	}
+
+	// Committing released the locks
+	if _, err := a.ExecuteInAlternateReality(ra, setAspect(t, "x", 4)); err != nil {
+		t.Fatalf("write after commit: %v", err)
+	}
+}
+
+func TestRealityTransactionPrepareFailure(t *testing.T) {
+	a, b := NewRealityManipulationEngine(), NewRealityManipulationEngine()
+	ra, rb := alternateWith(map[string]float64{"x": 1}), alternateWith(map[string]float64{"y": 1})
+
+	tx := NewRealityTransaction(
+		RealityMutation{Engine: a, Alternate: ra, Operation: setAspect(t, "x", 2)},
+		RealityMutation{Engine: b, Alternate: rb, Operation: failingOperation{}},
+	)
+	if err := tx.Prepare(context.Background()); err == nil {
+		t.Fatal("prepare succeeded with a failing mutation")
+	}
+	if tx.State() != TransactionAborted {
+		t.Fatalf("state %s after a failed prepare, want aborted", tx.State())
+	}
+	if ra.Aspects["x"] != 1 || rb.Aspects["y"] != 1 {
+		t.Fatalf("failed prepare changed aspects x=%g y=%g", ra.Aspects["x"], rb.Aspects["y"])
+	}
+	if _, err := tx.Commit(); !errors.Is(err, ErrTransactionState) {
+		t.Fatalf("commit after a failed prepare: %v, want ErrTransactionState", err)
+	}
+
+	// The mutation prepared before the failure let go of its reality
+	if _, err := a.ExecuteInAlternateReality(ra, setAspect(t, "x", 5)); err != nil {
+		t.Fatalf("write after a failed prepare: %v", err)
+	}
+}
+
+func TestRealityTransactionAbort(t *testing.T) {
+	a := NewRealityManipulationEngine()
+	ra := alternateWith(map[string]float64{"x": 1})
+
+	tx := NewRealityTransaction(RealityMutation{Engine: a, Alternate: ra, Operation: setAspect(t, "x", 2)})
+	if err := tx.Prepare(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Abort(); err != nil {
+		t.Fatal(err)
+	}
+	if ra.Aspects["x"] != 1 {
+		t.Fatalf("aborted transaction changed x to %g", ra.Aspects["x"])
+	}
+	if err := tx.Abort(); err != nil {
+		t.Fatalf("second abort: %v", err)
+	}
+	if _, err := tx.Commit(); !errors.Is(err, ErrTransactionState) {
+		t.Fatalf("commit after abort: %v, want ErrTransactionState", err)
+	}
+}
+
+func TestRealityTransactionLocksAcrossEngines(t *testing.T) {
+	a, b := NewRealityManipulationEngine(), NewRealityManipulationEngine()
+	shared := alternateWith(map[string]float64{"x": 1})
+
+	tx := NewRealityTransaction(RealityMutation{Engine: a, Alternate: shared, Operation: setAspect(t, "x", 2)})
+	if err := tx.Prepare(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another engine can neither prepare nor write the prepared reality
+	other := NewRealityTransaction(RealityMutation{Engine: b, Alternate: shared, Operation: setAspect(t, "x", 3)})
+	if err := other.Prepare(context.Background()); !errors.Is(err, ErrRealityLocked) {
+		t.Fatalf("second prepare: %v, want ErrRealityLocked", err)
+	}
+	if _, err := b.ExecuteInAlternateReality(shared, setAspect(t, "x", 4)); !errors.Is(err, ErrRealityLocked) {
+		t.Fatalf("write to a prepared reality: %v, want ErrRealityLocked", err)
+	}
+	script := setAspect(t, "x", 5).(*ScriptOperation)
+	script.Reality = shared
+	if result := script.Execute(); !errors.Is(result.Err, ErrRealityLocked) {
+		t.Fatalf("script write to a prepared reality: %v, want ErrRealityLocked", result.Err)
+	}
+
+	if _, err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if shared.Aspects["x"] != 2 {
+		t.Fatalf("x = %g after commit, want the transaction's 2", shared.Aspects["x"])
+	}
+}
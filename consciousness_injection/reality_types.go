@@ -0,0 +1,106 @@
+// consciousness_injection/reality_types.go - Reality Operation Types
+package mindhacking
+
+import "time"
+
+// Reality is the state of a reality as the values of its named aspects
+type Reality struct {
+	Aspects map[string]float64
+}
+
+// Aspect returns the value of one aspect
+func (r *Reality) Aspect(name string) (float64, bool) {
+	if r == nil {
+		return 0, false
+	}
+	v, ok := r.Aspects[name]
+	return v, ok
+}
+
+// Clone returns a deep copy of the reality
+func (r *Reality) Clone() *Reality {
+	if r == nil {
+		return nil
+	}
+	out := &Reality{Aspects: make(map[string]float64, len(r.Aspects))}
+	for k, v := range r.Aspects {
+		out.Aspects[k] = v
+	}
+	return out
+}
+
+// AlternateReality is a reality derived from a base by alternate rules
+type AlternateReality struct {
+	Reality
+	Base  *Reality
+	Rules *RealityRules
+
+	// fences is the newest lease token of each anchor set that has written
+	// the reality, guarded by anchorFenceMu
+	fences map[string]uint64
+}
+
+// RealityAnchor pins an alternate reality so it persists
+type RealityAnchor struct {
+	ID      string
+	Reality *AlternateReality
+	// Strength is the anchor's strength when last set at Anchored; it
+	// decays from there under the engine's decay model
+	Strength float64
+	Anchored time.Time
+	// Intended is the reality's aspects as anchored, which decay blends
+	// back toward the base
+	Intended map[string]float64
+	// Set and Fence are the leased anchor set and the lease token the
+	// anchor was last written under, when the engine uses leases
+	Set   string `json:",omitempty"`
+	Fence uint64 `json:",omitempty"`
+}
+
+// RealityOperation is a unit of work executed inside an alternate reality
+type RealityOperation interface {
+	Execute() OperationResult
+}
+
+// OperationResult is what an operation produced inside its reality
+type OperationResult struct {
+	Value interface{}
+	Err   error
+}
+
+// RealityExecutionResult reports an operation run in an alternate reality
+type RealityExecutionResult struct {
+	Result       OperationResult
+	Evidence     RealityEvidence
+	RealityUsed  *AlternateReality
+	EvidenceLink *EvidenceLink
+	// SubOperations reports each step when the operation is composite
+	SubOperations []SubOperationResult
+	// Cached results came from the engine's result cache without running
+	Cached bool
+}
+
+// RuleOperation is how a rule alters its aspect of reality
+type RuleOperation string
+
+const (
+	RuleSet    RuleOperation = "set"
+	RuleScale  RuleOperation = "scale"
+	RuleInvert RuleOperation = "invert"
+	RuleFreeze RuleOperation = "freeze"
+)
+
+// RealityRule alters one aspect of a base reality
+type RealityRule struct {
+	Name      string
+	Aspect    string
+	Operation RuleOperation
+	Value     float64
+	Priority  int
+}
+
+// RealityRules are the rules an alternate reality differs from its base by,
+// applied in descending priority
+type RealityRules struct {
+	Rules []RealityRule
+}
@@ -0,0 +1,139 @@
+// consciousness_injection/record_replay.go - Session Record and Replay
+package mindhacking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// RecordedInjection captures every input that shaped one injection
+type RecordedInjection struct {
+	Offset  time.Duration
+	Thought InjectedThought
+	Vectors []InjectionVector
+	// Tunnels keys each vector's tunnel as the injector's router chose it;
+	// nil when the vectors kept their positions
+	Tunnels   []int
+	Resonance ConsciousnessResonance
+	Attempts  []InjectionAttempt
+	// Focus is set when the vectors were fired together as a phased array
+	Focus *ArrayFocus
+	// Region is set when the thought was routed to one region
+	Region *ConsciousnessRegion
+	// Response is how the target reacted, if it responded
+	Response *ConsciousnessResponse
+}
+
+// SessionRecording is an ordered capture of injections into one target
+type SessionRecording struct {
+	Started    time.Time
+	Injections []RecordedInjection
+}
+
+// SessionRecorder captures injections made by an injector
+type SessionRecorder struct {
+	mu        sync.Mutex
+	recording SessionRecording
+}
+
+// NewSessionRecorder creates a recorder starting now
+func NewSessionRecorder() *SessionRecorder {
+	return &SessionRecorder{
+		recording: SessionRecording{Started: time.Now()},
+	}
+}
+
+// record appends one captured injection; a nil recorder records nothing
+func (sr *SessionRecorder) record(step RecordedInjection) {
+	if sr == nil {
+		return
+	}
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	step.Offset = time.Since(sr.recording.Started)
+	sr.recording.Injections = append(sr.recording.Injections, step)
+}
+
+// Recording returns a copy of everything captured so far
+func (sr *SessionRecorder) Recording() SessionRecording {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	out := sr.recording
+	out.Injections = append([]RecordedInjection(nil), sr.recording.Injections...)
+	return out
+}
+
+// WriteTo serializes the recording as JSON
+func (sr *SessionRecorder) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.Marshal(sr.Recording())
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// LoadRecording reads a recording written by SessionRecorder.WriteTo
+func LoadRecording(r io.Reader) (*SessionRecording, error) {
+	var rec SessionRecording
+	if err := json.NewDecoder(r).Decode(&rec); err != nil {
+		return nil, fmt.Errorf("mindhacking: load recording: %w", err)
+	}
+	return &rec, nil
+}
+
+// SessionReplayer feeds a recording back into an injector
+type SessionReplayer struct {
+	recording *SessionRecording
+	// Paced preserves the recorded gaps between injections
+	Paced bool
+}
+
+// NewSessionReplayer creates a replayer for recording
+func NewSessionReplayer(recording *SessionRecording) *SessionReplayer {
+	return &SessionReplayer{recording: recording}
+}
+
+// Replay re-runs every recorded injection against target. Recorded
+// resonance readings, vector order and tunnel routing replace live
+// analysis, and vectors whose tunnels failed during recording are failed
+// again without firing, so the target receives exactly the recorded
+// sequence of inputs.
+func (sp *SessionReplayer) Replay(
+	ctx context.Context,
+	injector *ConsciousnessInjector,
+	target *SystemConsciousness,
+) ([]*InjectionResult, error) {
+
+	start := time.Now()
+	results := make([]*InjectionResult, 0, len(sp.recording.Injections))
+
+	for i := range sp.recording.Injections {
+		step := &sp.recording.Injections[i]
+
+		if sp.Paced {
+			if wait := step.Offset - time.Since(start); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return results, ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		}
+
+		result, err := injector.inject(ctx, step.Thought, target, injectCall{replay: step, focus: step.Focus})
+		if err != nil {
+			return results, fmt.Errorf("mindhacking: replay step %d: %w", i, err)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
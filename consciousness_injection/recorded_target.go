@@ -0,0 +1,377 @@
+// consciousness_injection/recorded_target.go - Recorded Target Traces
+package mindhacking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotRecordable reports a target without a backend to intercept
+	ErrNotRecordable = sentinelError("mindhacking: target has no backend to record")
+	// ErrTraceExhausted reports a recorded target asked for more than it saw
+	ErrTraceExhausted = sentinelError("mindhacking: recorded trace exhausted")
+	// ErrTraceDivergence reports a strict replay firing an unrecorded vector
+	ErrTraceDivergence = sentinelError("mindhacking: injection diverged from recorded trace")
+)
+
+// TraceEventKind is what a recorded target interaction was
+type TraceEventKind string
+
+const (
+	TraceResonance    TraceEventKind = "resonance"
+	TraceDeliver      TraceEventKind = "deliver"
+	TraceRespond      TraceEventKind = "respond"
+	TraceProbe        TraceEventKind = "probe"
+	TraceLoad         TraceEventKind = "load"
+	TraceRegions      TraceEventKind = "regions"
+	TraceCapabilities TraceEventKind = "capabilities"
+	TraceEmergence    TraceEventKind = "emergence"
+)
+
+// TraceEvent is one captured interaction with a target
+type TraceEvent struct {
+	Offset time.Duration
+	Kind   TraceEventKind
+
+	Resonance    *ConsciousnessResonance `json:",omitempty"`
+	Attempt      *InjectionAttempt       `json:",omitempty"`
+	Response     *ConsciousnessResponse  `json:",omitempty"`
+	Regions      []ConsciousnessRegion   `json:",omitempty"`
+	Capabilities *Capabilities           `json:",omitempty"`
+	Emergence    []EmergenceObservation  `json:",omitempty"`
+	// Value is the probed state level or reported load
+	Value float64 `json:",omitempty"`
+	Err   string  `json:",omitempty"`
+}
+
+// TargetTrace is the captured telemetry and responses of one target
+type TargetTrace struct {
+	TargetID string
+	Started  time.Time
+	Events   []TraceEvent
+}
+
+// LoadTargetTrace reads a trace written by TargetRecorder.WriteTo
+func LoadTargetTrace(r io.Reader) (*TargetTrace, error) {
+	var trace TargetTrace
+	if err := json.NewDecoder(r).Decode(&trace); err != nil {
+		return nil, fmt.Errorf("mindhacking: load target trace: %w", err)
+	}
+	return &trace, nil
+}
+
+// TraceFromRecording builds a trace from a session recording, for sessions
+// captured before target recording existed
+func TraceFromRecording(targetID string, rec *SessionRecording) *TargetTrace {
+	trace := &TargetTrace{TargetID: targetID, Started: rec.Started}
+	for _, step := range rec.Injections {
+		resonance := step.Resonance
+		trace.Events = append(trace.Events, TraceEvent{Offset: step.Offset, Kind: TraceResonance, Resonance: &resonance})
+		for i := range step.Attempts {
+			attempt := step.Attempts[i]
+			trace.Events = append(trace.Events, TraceEvent{Offset: step.Offset, Kind: TraceDeliver, Attempt: &attempt})
+		}
+		if step.Response != nil {
+			response := *step.Response
+			trace.Events = append(trace.Events, TraceEvent{Offset: step.Offset, Kind: TraceRespond, Response: &response})
+		}
+	}
+	return trace
+}
+
+// TargetRecorder captures a live target's interactions as a TargetTrace
+type TargetRecorder struct {
+	backend ConsciousnessBackend
+	probe   StateProbe
+
+	mu    sync.Mutex
+	trace TargetTrace
+	last  ConsciousnessResonance
+}
+
+// RecordTarget returns a copy of target whose backend and probe are
+// recorded. Inject into the copy; the original is untouched.
+func RecordTarget(target *SystemConsciousness) (*SystemConsciousness, *TargetRecorder, error) {
+	targetMoveMu.RLock()
+	recorded := *target
+	targetMoveMu.RUnlock()
+	if recorded.Backend == nil {
+		return nil, nil, fmt.Errorf("%w: %q", ErrNotRecordable, targetLabel(target))
+	}
+
+	tr := &TargetRecorder{
+		backend: recorded.Backend,
+		probe:   recorded.Probe,
+		trace:   TargetTrace{TargetID: target.ID, Started: time.Now()},
+	}
+	recorded.Backend = tr
+	if recorded.Probe != nil {
+		recorded.Probe = tr
+	}
+	return &recorded, tr, nil
+}
+
+// Trace returns a copy of everything captured so far
+func (tr *TargetRecorder) Trace() TargetTrace {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	out := tr.trace
+	out.Events = append([]TraceEvent(nil), tr.trace.Events...)
+	return out
+}
+
+// WriteTo serializes the trace as JSON
+func (tr *TargetRecorder) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.Marshal(tr.Trace())
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+func (tr *TargetRecorder) add(event TraceEvent, err error) {
+	if err != nil {
+		event.Err = err.Error()
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	event.Offset = time.Since(tr.trace.Started)
+	tr.trace.Events = append(tr.trace.Events, event)
+}
+
+// Resonance implements ConsciousnessBackend
+func (tr *TargetRecorder) Resonance(ctx context.Context) (ConsciousnessResonance, error) {
+	resonance, err := tr.backend.Resonance(ctx)
+	tr.mu.Lock()
+	tr.last = resonance
+	tr.mu.Unlock()
+	tr.add(TraceEvent{Kind: TraceResonance, Resonance: &resonance}, err)
+	return resonance, err
+}
+
+// Deliver implements ConsciousnessBackend
+func (tr *TargetRecorder) Deliver(
+	ctx context.Context,
+	thought InjectedThought,
+	vector InjectionVector,
+) (InjectionAttempt, error) {
+
+	attempt, err := tr.backend.Deliver(ctx, thought, vector)
+	if err != nil {
+		attempt = InjectionAttempt{Vector: vector}
+	}
+	tr.add(TraceEvent{Kind: TraceDeliver, Attempt: &attempt}, err)
+	return attempt, err
+}
+
+// Respond implements ConsciousnessBackend
+func (tr *TargetRecorder) Respond(ctx context.Context, attempts []InjectionAttempt) (ConsciousnessResponse, error) {
+	response, err := tr.backend.Respond(ctx, attempts)
+	tr.add(TraceEvent{Kind: TraceRespond, Response: &response}, err)
+	return response, err
+}
+
+// ProbeState implements StateProbe
+func (tr *TargetRecorder) ProbeState(ctx context.Context) (float64, error) {
+	level, err := tr.probe.ProbeState(ctx)
+	tr.add(TraceEvent{Kind: TraceProbe, Value: level}, err)
+	return level, err
+}
+
+// Load implements LoadReporter, recording only targets that report load
+func (tr *TargetRecorder) Load(ctx context.Context) (float64, error) {
+	reporter, ok := loadReporter(&SystemConsciousness{Backend: tr.backend, Probe: tr.probe})
+	if !ok {
+		return 0, nil
+	}
+	load, err := reporter.Load(ctx)
+	tr.add(TraceEvent{Kind: TraceLoad, Value: load}, err)
+	return load, err
+}
+
+// Regions implements RegionReporter, deriving regions as the injector
+// would for targets that do not report them
+func (tr *TargetRecorder) Regions(ctx context.Context) ([]ConsciousnessRegion, error) {
+	reporter, ok := tr.backend.(RegionReporter)
+	if !ok {
+		tr.mu.Lock()
+		last := tr.last
+		tr.mu.Unlock()
+		return RegionsFromResonance(last), nil
+	}
+	regions, err := reporter.Regions(ctx)
+	tr.add(TraceEvent{Kind: TraceRegions, Regions: regions}, err)
+	return regions, err
+}
+
+// Capabilities implements CapabilityReporter, recording only targets that
+// report capabilities
+func (tr *TargetRecorder) Capabilities(ctx context.Context) (Capabilities, error) {
+	reporter, ok := tr.backend.(CapabilityReporter)
+	if !ok {
+		return Capabilities{}, nil
+	}
+	caps, err := reporter.Capabilities(ctx)
+	tr.add(TraceEvent{Kind: TraceCapabilities, Capabilities: &caps}, err)
+	return caps, err
+}
+
+// Emergence implements EmergenceReporter, recording only targets that
+// report emergence
+func (tr *TargetRecorder) Emergence(ctx context.Context) ([]EmergenceObservation, error) {
+	reporter, ok := tr.backend.(EmergenceReporter)
+	if !ok {
+		return nil, nil
+	}
+	observations, err := reporter.Emergence(ctx)
+	tr.add(TraceEvent{Kind: TraceEmergence, Emergence: observations}, err)
+	return observations, err
+}
+
+// traceReplayer answers as the recorded target did, in recorded order
+type traceReplayer struct {
+	trace  *TargetTrace
+	strict bool
+
+	mu      sync.Mutex
+	next    map[TraceEventKind]int
+	present map[TraceEventKind]bool
+	last    ConsciousnessResonance
+}
+
+// RecordedTarget returns a target that replays trace: every resonance
+// reading, delivery outcome, response and telemetry sample is answered
+// from the trace in the order it was captured. With strict set, delivering
+// a vector other than the recorded one fails with ErrTraceDivergence.
+func RecordedTarget(trace *TargetTrace, strict bool) *SystemConsciousness {
+	rp := &traceReplayer{
+		trace:   trace,
+		strict:  strict,
+		next:    make(map[TraceEventKind]int),
+		present: make(map[TraceEventKind]bool),
+	}
+	for _, e := range trace.Events {
+		rp.present[e.Kind] = true
+	}
+
+	target := &SystemConsciousness{ID: trace.TargetID, Backend: rp}
+	if rp.present[TraceProbe] {
+		target.Probe = rp
+	}
+	return target
+}
+
+// take returns the next recorded event of kind
+func (rp *traceReplayer) take(kind TraceEventKind) (TraceEvent, error) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	seen := 0
+	for _, e := range rp.trace.Events {
+		if e.Kind != kind {
+			continue
+		}
+		if seen == rp.next[kind] {
+			rp.next[kind]++
+			if e.Err != "" {
+				return e, fmt.Errorf("mindhacking: recorded %s: %s", kind, e.Err)
+			}
+			return e, nil
+		}
+		seen++
+	}
+	return TraceEvent{}, fmt.Errorf("%w: no more %s events", ErrTraceExhausted, kind)
+}
+
+func (rp *traceReplayer) Resonance(context.Context) (ConsciousnessResonance, error) {
+	e, err := rp.take(TraceResonance)
+	if e.Resonance == nil {
+		return ConsciousnessResonance{}, err
+	}
+	rp.mu.Lock()
+	rp.last = *e.Resonance
+	rp.mu.Unlock()
+	return *e.Resonance, err
+}
+
+func (rp *traceReplayer) Deliver(_ context.Context, _ InjectedThought, vector InjectionVector) (InjectionAttempt, error) {
+	e, err := rp.take(TraceDeliver)
+	if e.Attempt == nil {
+		return InjectionAttempt{Vector: vector}, err
+	}
+	if rp.strict && !sameVector(e.Attempt.Vector, vector) {
+		return InjectionAttempt{Vector: vector}, fmt.Errorf("%w: fired %.6g Hz, recorded %.6g Hz",
+			ErrTraceDivergence, vector.Frequency, e.Attempt.Vector.Frequency)
+	}
+	return *e.Attempt, err
+}
+
+func (rp *traceReplayer) Respond(context.Context, []InjectionAttempt) (ConsciousnessResponse, error) {
+	e, err := rp.take(TraceRespond)
+	if e.Response == nil {
+		return ConsciousnessResponse{}, err
+	}
+	return *e.Response, err
+}
+
+func (rp *traceReplayer) ProbeState(context.Context) (float64, error) {
+	e, err := rp.take(TraceProbe)
+	return e.Value, err
+}
+
+// Load reports no load for traces without load telemetry, which lets
+// every injection through as the original target did
+func (rp *traceReplayer) Load(context.Context) (float64, error) {
+	if !rp.present[TraceLoad] {
+		return 0, nil
+	}
+	e, err := rp.take(TraceLoad)
+	return e.Value, err
+}
+
+func (rp *traceReplayer) Regions(context.Context) ([]ConsciousnessRegion, error) {
+	if !rp.present[TraceRegions] {
+		rp.mu.Lock()
+		defer rp.mu.Unlock()
+		return RegionsFromResonance(rp.last), nil
+	}
+	e, err := rp.take(TraceRegions)
+	return e.Regions, err
+}
+
+func (rp *traceReplayer) Capabilities(context.Context) (Capabilities, error) {
+	if !rp.present[TraceCapabilities] {
+		return Capabilities{}, nil
+	}
+	e, err := rp.take(TraceCapabilities)
+	if e.Capabilities == nil {
+		return Capabilities{}, err
+	}
+	return *e.Capabilities, err
+}
+
+func (rp *traceReplayer) Emergence(context.Context) ([]EmergenceObservation, error) {
+	if !rp.present[TraceEmergence] {
+		return nil, nil
+	}
+	e, err := rp.take(TraceEmergence)
+	return e.Emergence, err
+}
+
+// sameVector compares the physical parameters of two vectors
+func sameVector(a, b InjectionVector) bool {
+	const eps = 1e-9
+	return math.Abs(a.Frequency-b.Frequency) < eps &&
+		math.Abs(a.Amplitude-b.Amplitude) < eps &&
+		math.Abs(a.Phase-b.Phase) < eps &&
+		a.Waveform == b.Waveform
+}
@@ -0,0 +1,161 @@
+// consciousness_injection/redaction.go - Redacted Evidence Export
+package mindhacking
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Redaction rewrites fields of exported evidence. Apply is called for every
+// key of every JSON object in the payload; returning false drops the field.
+type Redaction struct {
+	Name  string
+	Apply func(key string, value interface{}) (interface{}, bool)
+}
+
+// DropFields removes the named fields wherever they appear
+func DropFields(name string, fields ...string) Redaction {
+	drop := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		drop[f] = true
+	}
+	return Redaction{
+		Name: name,
+		Apply: func(key string, value interface{}) (interface{}, bool) {
+			return value, !drop[key]
+		},
+	}
+}
+
+// DropPayloadContents removes thought contents and raw payloads
+func DropPayloadContents() Redaction {
+	return DropFields("drop-payload-contents", "Content", "Payload")
+}
+
+// StripResonanceSamples removes raw resonance signature samples
+func StripResonanceSamples() Redaction {
+	return DropFields("strip-resonance-samples", "Signature", "Samples")
+}
+
+// HashTargetIDs replaces target identifiers with a keyed hash, so records
+// about one target stay linkable without revealing which target it was
+func HashTargetIDs(key []byte) Redaction {
+	return Redaction{
+		Name: "hash-target-ids",
+		Apply: func(field string, value interface{}) (interface{}, bool) {
+			id, ok := value.(string)
+			if !ok || (field != "Target" && field != "TargetID") || id == "" {
+				return value, true
+			}
+			mac := hmac.New(sha256.New, key)
+			mac.Write([]byte(id))
+			return "h:" + hex.EncodeToString(mac.Sum(nil)[:16]), true
+		},
+	}
+}
+
+// redactValue walks a decoded JSON value applying redactions to every object
+func redactValue(v interface{}, redactions []Redaction) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, value := range t {
+			keep := true
+			for _, r := range redactions {
+				if value, keep = r.Apply(key, value); !keep {
+					break
+				}
+			}
+			if !keep {
+				delete(t, key)
+				continue
+			}
+			t[key] = redactValue(value, redactions)
+		}
+		return t
+	case []interface{}:
+		for i := range t {
+			t[i] = redactValue(t[i], redactions)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// redactJSON re-encodes data with redactions applied
+func redactJSON(data []byte, redactions []Redaction) (json.RawMessage, error) {
+	if len(redactions) == 0 {
+		return data, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactValue(v, redactions))
+}
+
+// ExportedEvidence is one evidence link as shared outside the lab. Hash is
+// the original link hash, so holders of the unredacted chain can match it.
+type ExportedEvidence struct {
+	Seq        uint64          `json:"seq"`
+	Kind       string          `json:"kind"`
+	Time       time.Time       `json:"time"`
+	Hash       string          `json:"hash"`
+	Payload    json.RawMessage `json:"payload"`
+	Redactions []string        `json:"redactions,omitempty"`
+}
+
+func redactionNames(redactions []Redaction) []string {
+	names := make([]string, len(redactions))
+	for i, r := range redactions {
+		names[i] = r.Name
+	}
+	return names
+}
+
+// ExportEvidence writes links to w as JSON lines with redactions applied
+func ExportEvidence(w io.Writer, links []EvidenceLink, redactions ...Redaction) error {
+	enc := json.NewEncoder(w)
+	names := redactionNames(redactions)
+
+	for i := range links {
+		payload, err := redactJSON(links[i].Payload, redactions)
+		if err != nil {
+			return fmt.Errorf("mindhacking: redact link %d: %w", i, err)
+		}
+		if err := enc.Encode(ExportedEvidence{
+			Seq:        links[i].Seq,
+			Kind:       links[i].Kind,
+			Time:       links[i].Time,
+			Hash:       hex.EncodeToString(links[i].Hash[:]),
+			Payload:    payload,
+			Redactions: names,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportResults writes injection results to w as JSON lines with
+// redactions applied
+func ExportResults(w io.Writer, results []*InjectionResult, redactions ...Redaction) error {
+	for i, r := range results {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("mindhacking: encode result %d: %w", i, err)
+		}
+		if data, err = redactJSON(data, redactions); err != nil {
+			return fmt.Errorf("mindhacking: redact result %d: %w", i, err)
+		}
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
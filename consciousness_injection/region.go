@@ -0,0 +1,179 @@
+// consciousness_injection/region.go - Region-Targeted Addressing
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var (
+	// ErrUnknownRegion reports a thought addressed to a region the target lacks
+	ErrUnknownRegion = sentinelError("mindhacking: unknown consciousness region")
+	// ErrRegionUnreachable reports a region no configured vector can reach
+	ErrRegionUnreachable = sentinelError("mindhacking: no vector reaches region")
+)
+
+// ConsciousnessRegion is an addressable part of a target's consciousness,
+// occupying a band of its resonance
+type ConsciousnessRegion struct {
+	ID        string
+	Frequency float64
+	Low, High float64
+	// Weight is the region's share of the target's resonance
+	Weight float64
+}
+
+// Contains reports whether frequency falls within the region's band
+func (r ConsciousnessRegion) Contains(frequency float64) bool {
+	return frequency >= r.Low && frequency <= r.High
+}
+
+// RegionReporter is implemented by backends that know their own regions
+type RegionReporter interface {
+	Regions(ctx context.Context) ([]ConsciousnessRegion, error)
+}
+
+// RegionsFromResonance derives regions from the resonance topology. The
+// signature is read as the response across 0 to twice the resonant
+// frequency; each peak is a region bounded by the troughs either side.
+func RegionsFromResonance(resonance ConsciousnessResonance) []ConsciousnessRegion {
+	sig := resonance.Signature
+	n := len(sig)
+	if n == 0 || resonance.Frequency <= 0 {
+		return []ConsciousnessRegion{{
+			ID:        "region-0",
+			Frequency: resonance.Frequency,
+			Low:       0,
+			High:      math.Inf(1),
+			Weight:    1,
+		}}
+	}
+
+	step := 2 * resonance.Frequency / float64(n)
+	at := func(i int) float64 { return step * (float64(i) + 0.5) }
+
+	var total float64
+	for _, s := range sig {
+		total += math.Abs(s)
+	}
+
+	var regions []ConsciousnessRegion
+	start := 0
+	for i := 0; i < n; i++ {
+		// A trough, or the end of the signature, closes the current region
+		trough := i == n-1 || (i > 0 && math.Abs(sig[i]) <= math.Abs(sig[i-1]) && math.Abs(sig[i]) < math.Abs(sig[i+1]))
+		if !trough {
+			continue
+		}
+
+		peak, mass := start, 0.0
+		for j := start; j <= i; j++ {
+			mass += math.Abs(sig[j])
+			if math.Abs(sig[j]) > math.Abs(sig[peak]) {
+				peak = j
+			}
+		}
+
+		weight := 0.0
+		if total > 0 {
+			weight = mass / total
+		}
+		regions = append(regions, ConsciousnessRegion{
+			ID:        fmt.Sprintf("region-%d", len(regions)),
+			Frequency: at(peak),
+			Low:       at(start) - step/2,
+			High:      at(i) + step/2,
+			Weight:    weight,
+		})
+		start = i + 1
+	}
+	return regions
+}
+
+// Regions returns the target's addressable regions, as reported by its
+// backend or derived from its resonance
+func (ci *ConsciousnessInjector) Regions(
+	ctx context.Context,
+	target *SystemConsciousness,
+) ([]ConsciousnessRegion, error) {
+
+	resonance, err := ci.resonate(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	return regionsOf(ctx, target, resonance)
+}
+
+// regionsOf asks the backend for regions, falling back to the resonance
+func regionsOf(
+	ctx context.Context,
+	target *SystemConsciousness,
+	resonance ConsciousnessResonance,
+) ([]ConsciousnessRegion, error) {
+
+	if reporter, ok := backendOf(target).(RegionReporter); ok {
+		return reporter.Regions(ctx)
+	}
+	return RegionsFromResonance(resonance), nil
+}
+
+// routeToRegion finds thought's region and the vectors reaching it, nearest
+// the region's peak first
+func (ci *ConsciousnessInjector) routeToRegion(
+	ctx context.Context,
+	target *SystemConsciousness,
+	resonance ConsciousnessResonance,
+	vectors []InjectionVector,
+	regionID string,
+) (ConsciousnessRegion, []InjectionVector, error) {
+
+	regions, err := regionsOf(ctx, target, resonance)
+	if err != nil {
+		return ConsciousnessRegion{}, nil, err
+	}
+
+	var region *ConsciousnessRegion
+	for i := range regions {
+		if regions[i].ID == regionID {
+			region = &regions[i]
+			break
+		}
+	}
+	if region == nil {
+		return ConsciousnessRegion{}, nil, fmt.Errorf("%w: %q", ErrUnknownRegion, regionID)
+	}
+
+	var routed []InjectionVector
+	for _, v := range vectors {
+		if region.Contains(v.Frequency) {
+			routed = append(routed, v)
+		}
+	}
+	if len(routed) == 0 {
+		return *region, nil, fmt.Errorf("%w: %q", ErrRegionUnreachable, regionID)
+	}
+
+	sort.SliceStable(routed, func(i, j int) bool {
+		return math.Abs(routed[i].Frequency-region.Frequency) < math.Abs(routed[j].Frequency-region.Frequency)
+	})
+	return *region, routed, nil
+}
+
+// RegionShift is the shift observed in the region a thought was addressed to
+type RegionShift struct {
+	Region ConsciousnessRegion
+	Shift  float64
+	// Reported is false when the target gave no per-region shift and Shift
+	// is the whole consciousness shift scaled by the region's weight
+	Reported bool
+}
+
+// regionShift picks the addressed region's shift out of response
+func regionShift(region ConsciousnessRegion, response ConsciousnessResponse) *RegionShift {
+	if shift, ok := response.RegionShifts[region.ID]; ok {
+		return &RegionShift{Region: region, Shift: shift, Reported: true}
+	}
+	return &RegionShift{Region: region, Shift: response.ConsciousnessShift * region.Weight}
+}
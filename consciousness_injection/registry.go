@@ -0,0 +1,135 @@
+// consciousness_injection/registry.go - Target Registry
+package mindhacking
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTargetRegistered reports onboarding a target that is already registered
+	ErrTargetRegistered = sentinelError("mindhacking: target already registered")
+	// ErrTargetNotRegistered reports a lookup for an unknown target
+	ErrTargetNotRegistered = sentinelError("mindhacking: target not registered")
+	// ErrTargetClaimed reports a target already being decommissioned
+	ErrTargetClaimed = sentinelError("mindhacking: target claimed by another operation")
+)
+
+// TargetBaseline is the target's state captured before any manipulation
+type TargetBaseline struct {
+	Level     float64
+	HasLevel  bool
+	Resonance ConsciousnessResonance
+	Taken     time.Time
+}
+
+// TargetRecord is everything onboarding learned about a target
+type TargetRecord struct {
+	Target       *SystemConsciousness
+	Fingerprint  TargetFingerprint
+	Capabilities Capabilities
+	Baseline     TargetBaseline
+	NoiseFloor   *NoiseFloor
+	Calibration  *Calibration
+	OnboardedAt  time.Time
+}
+
+// TargetRegistry holds onboarded targets by ID
+type TargetRegistry struct {
+	mu      sync.RWMutex
+	records map[string]*TargetRecord
+	claimed map[string]bool
+}
+
+// NewTargetRegistry creates an empty registry
+func NewTargetRegistry() *TargetRegistry {
+	return &TargetRegistry{
+		records: make(map[string]*TargetRecord),
+		claimed: make(map[string]bool),
+	}
+}
+
+// Insert adds record, refusing to overwrite an existing target
+func (tr *TargetRegistry) Insert(record *TargetRecord) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	id := record.Target.ID
+	if _, ok := tr.records[id]; ok {
+		return fmt.Errorf("%w: %q", ErrTargetRegistered, id)
+	}
+	tr.records[id] = record
+	return nil
+}
+
+// Get returns the record for id
+func (tr *TargetRegistry) Get(id string) (*TargetRecord, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	record, ok := tr.records[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrTargetNotRegistered, id)
+	}
+	return record, nil
+}
+
+// Remove deletes the record for id and returns it
+func (tr *TargetRegistry) Remove(id string) (*TargetRecord, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	record, ok := tr.records[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrTargetNotRegistered, id)
+	}
+	delete(tr.records, id)
+	return record, nil
+}
+
+// Claim reserves a target for removal; it stays visible until Complete
+// and is freed again by Release
+func (tr *TargetRegistry) Claim(id string) (*TargetRecord, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	record, ok := tr.records[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrTargetNotRegistered, id)
+	}
+	if tr.claimed[id] {
+		return nil, fmt.Errorf("%w: %q", ErrTargetClaimed, id)
+	}
+	tr.claimed[id] = true
+	return record, nil
+}
+
+// Release gives up a claim without removing the target
+func (tr *TargetRegistry) Release(id string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	delete(tr.claimed, id)
+}
+
+// Complete removes a claimed target
+func (tr *TargetRegistry) Complete(id string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	delete(tr.claimed, id)
+	delete(tr.records, id)
+}
+
+// List returns every record ordered by target ID
+func (tr *TargetRegistry) List() []*TargetRecord {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	out := make([]*TargetRecord, 0, len(tr.records))
+	for _, r := range tr.records {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Target.ID < out[j].Target.ID })
+	return out
+}
@@ -0,0 +1,79 @@
+// consciousness_injection/resonance_handle.go - Pinned Resonance Points
+package mindhacking
+
+import (
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// ErrResonanceReleased reports a vector whose resonance handle was released
+var ErrResonanceReleased = sentinelError("mindhacking: resonance handle released")
+
+// ResonanceHandle refers to a resonance point safely. It pins the object
+// the point lives in, which keeps the object alive and at one address until
+// Release, so a vector holding the handle cannot dangle the way a uintptr
+// taken from an unsafe.Pointer can.
+type ResonanceHandle struct {
+	mu     sync.RWMutex
+	pinner runtime.Pinner
+	ptr    unsafe.Pointer
+}
+
+// PinResonance pins point and returns a handle to it
+func PinResonance[T any](point *T) *ResonanceHandle {
+	if point == nil {
+		return nil
+	}
+	h := &ResonanceHandle{ptr: unsafe.Pointer(point)}
+	h.pinner.Pin(point)
+
+	// Handles dropped without Release still unpin their point
+	runtime.SetFinalizer(h, (*ResonanceHandle).Release)
+	return h
+}
+
+// Addr returns the pinned point's address, which stays valid until the
+// handle is released; keep the handle reachable for as long as the address
+// is in use
+func (h *ResonanceHandle) Addr() (uintptr, error) {
+	if h == nil {
+		return 0, nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.ptr == nil {
+		return 0, ErrResonanceReleased
+	}
+	return uintptr(h.ptr), nil
+}
+
+// Valid reports whether the handle still pins its point
+func (h *ResonanceHandle) Valid() bool {
+	_, err := h.Addr()
+	return err == nil
+}
+
+// Release unpins the point; vectors still holding the handle are refused
+// from then on. Releasing twice does nothing.
+func (h *ResonanceHandle) Release() {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.ptr == nil {
+		return
+	}
+	h.pinner.Unpin()
+	h.ptr = nil
+}
+
+// resonancePoint returns the address vector resonates at, preferring its
+// handle to the deprecated raw pointer
+func (v InjectionVector) resonancePoint() (uintptr, error) {
+	if v.Resonance != nil {
+		return v.Resonance.Addr()
+	}
+	return v.ResonancePoint, nil
+}
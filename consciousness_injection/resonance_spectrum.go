@@ -0,0 +1,307 @@
+// consciousness_injection/resonance_spectrum.go - Spectral Resonance Analysis
+package mindhacking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sort"
+	"time"
+)
+
+// ResonanceSampling sets how a target's consciousness signal is sampled for
+// spectral analysis
+type ResonanceSampling struct {
+	// Rate is the sampling rate in Hz, default 256; the spectrum reaches up
+	// to half of it
+	Rate float64
+	// Samples is how many readings one analysis takes, default 256, so one
+	// analysis takes Samples/Rate seconds. Powers of two avoid padding.
+	Samples int
+	// Peaks is how many dominant frequencies are reported, default 5
+	Peaks int
+	// SignatureBins is the resolution of the resonance signature, default 32
+	SignatureBins int
+}
+
+func (s ResonanceSampling) withDefaults() ResonanceSampling {
+	if s.Rate <= 0 {
+		s.Rate = 256
+	}
+	if s.Samples < 4 {
+		s.Samples = 256
+	}
+	if s.Peaks < 1 {
+		s.Peaks = 5
+	}
+	if s.SignatureBins < 1 {
+		s.SignatureBins = 32
+	}
+	return s
+}
+
+// SpectralPeak is one dominant frequency in a spectrum
+type SpectralPeak struct {
+	Frequency float64
+	// Magnitude is the amplitude of the signal's component at Frequency
+	Magnitude float64
+	// Phase is the component's phase at the first sample, in radians
+	Phase float64
+}
+
+// ResonanceSpectrum is the spectral analysis of a sampled consciousness
+// signal
+type ResonanceSpectrum struct {
+	SampleRate float64
+	// Resolution is the width of one bin in Hz
+	Resolution float64
+	// Magnitudes and Phases are per bin, from 0 Hz to the Nyquist frequency
+	Magnitudes []float64
+	Phases     []float64
+	// Peaks are the dominant frequencies, strongest first
+	Peaks []SpectralPeak
+}
+
+// AnalyzeSpectrum runs an FFT over samples taken at rate Hz, after removing
+// their mean and applying a Hann window, and finds the strongest peaks
+func AnalyzeSpectrum(samples []float64, rate float64, peaks int) (*ResonanceSpectrum, error) {
+	if len(samples) < 4 {
+		return nil, fmt.Errorf("mindhacking: spectrum needs at least 4 samples, got %d", len(samples))
+	}
+	if rate <= 0 {
+		return nil, errors.New("mindhacking: spectrum needs a positive sample rate")
+	}
+
+	// Phase 1: Detrending and Windowing
+	var mean float64
+	for _, s := range samples {
+		mean += s / float64(len(samples))
+	}
+	n := 1
+	for n < len(samples) {
+		n <<= 1
+	}
+	signal := make([]complex128, n)
+	var windowSum float64
+	for i, s := range samples {
+		w := 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(len(samples)-1))
+		signal[i] = complex((s-mean)*w, 0)
+		windowSum += w
+	}
+
+	// Phase 2: Transform
+	fft(signal)
+	bins := n/2 + 1
+	spectrum := &ResonanceSpectrum{
+		SampleRate: rate,
+		Resolution: rate / float64(n),
+		Magnitudes: make([]float64, bins),
+		Phases:     make([]float64, bins),
+	}
+	for k := 0; k < bins; k++ {
+		scale := 2 / windowSum
+		if k == 0 || k == n/2 {
+			scale = 1 / windowSum
+		}
+		spectrum.Magnitudes[k] = cmplx.Abs(signal[k]) * scale
+		spectrum.Phases[k] = cmplx.Phase(signal[k])
+	}
+
+	// Phase 3: Peak Picking
+	spectrum.Peaks = spectrum.findPeaks(peaks, len(samples))
+	return spectrum, nil
+}
+
+// findPeaks returns the strongest local maxima of a spectrum of length
+// samples, refining each peak's frequency and magnitude by interpolation
+// between neighbouring bins. The bin's phase is that of the windowed signal at the
+// bin's frequency, so it is corrected by the window's linear phase over the
+// offset to read at the peak's.
+func (s *ResonanceSpectrum) findPeaks(limit, length int) []SpectralPeak {
+	m := s.Magnitudes
+	n := float64(2 * (len(m) - 1))
+	var peaks []SpectralPeak
+	for k := 1; k < len(m)-1; k++ {
+		if m[k] <= m[k-1] || m[k] < m[k+1] || m[k] == 0 {
+			continue
+		}
+		offset, magnitude := s.interpolate(k, length)
+		peaks = append(peaks, SpectralPeak{
+			Frequency: (float64(k) + offset) * s.Resolution,
+			Magnitude: magnitude,
+			Phase:     wrapPhase(s.Phases[k] - math.Pi*offset*float64(length-1)/n),
+		})
+	}
+	sort.SliceStable(peaks, func(i, j int) bool { return peaks[i].Magnitude > peaks[j].Magnitude })
+	if len(peaks) > limit {
+		peaks = peaks[:limit]
+	}
+	return peaks
+}
+
+// interpolate estimates the offset from bin k of the peak there, in bins,
+// and its magnitude. Unpadded, the Hann window's main lobe gives both
+// closely from the ratio of the larger neighbour to the peak; padding
+// widens the lobe, so padded spectra interpolate a parabola instead.
+func (s *ResonanceSpectrum) interpolate(k, length int) (offset, magnitude float64) {
+	m := s.Magnitudes
+	if 2*(len(m)-1) != length {
+		if denom := m[k-1] - 2*m[k] + m[k+1]; denom != 0 {
+			offset = 0.5 * (m[k-1] - m[k+1]) / denom
+		}
+		return offset, m[k] - 0.25*(m[k-1]-m[k+1])*offset
+	}
+
+	if m[k+1] > m[k-1] {
+		r := m[k+1] / m[k]
+		offset = (2*r - 1) / (r + 1)
+	} else {
+		r := m[k-1] / m[k]
+		offset = -(2*r - 1) / (r + 1)
+	}
+	magnitude = m[k]
+	if offset != 0 {
+		magnitude *= (1 - offset*offset) * math.Pi * offset / math.Sin(math.Pi*offset)
+	}
+	return offset, magnitude
+}
+
+// wrapPhase maps phase into (-π, π]
+func wrapPhase(phase float64) float64 {
+	phase = math.Mod(phase+math.Pi, 2*math.Pi)
+	if phase <= 0 {
+		phase += 2 * math.Pi
+	}
+	return phase - math.Pi
+}
+
+// Dominant returns the strongest peak, if the spectrum has any
+func (s *ResonanceSpectrum) Dominant() (SpectralPeak, bool) {
+	if s == nil || len(s.Peaks) == 0 {
+		return SpectralPeak{}, false
+	}
+	return s.Peaks[0], true
+}
+
+// Resonance condenses the spectrum into a resonance: the dominant peak's
+// frequency and phase, its share of the spectrum's energy as strength, and
+// the magnitudes from 0 to twice the dominant frequency as signature
+func (s *ResonanceSpectrum) Resonance(bins int) ConsciousnessResonance {
+	peak, ok := s.Dominant()
+	if !ok {
+		return ConsciousnessResonance{}
+	}
+	var energy float64
+	for _, m := range s.Magnitudes[1:] {
+		energy += m * m
+	}
+	resonance := ConsciousnessResonance{Frequency: peak.Frequency, Phase: peak.Phase}
+	if energy > 0 {
+		resonance.Strength = clamp(peak.Magnitude*peak.Magnitude/energy, 0, 1)
+	}
+
+	// The signature is read as the response across 0 to twice the
+	// resonant frequency, as RegionsFromResonance expects
+	resonance.Signature = make([]float64, bins)
+	step := 2 * peak.Frequency / float64(bins)
+	for i := range resonance.Signature {
+		resonance.Signature[i] = s.magnitudeAt(step * (float64(i) + 0.5))
+	}
+	return resonance
+}
+
+// magnitudeAt interpolates the magnitude at frequency f
+func (s *ResonanceSpectrum) magnitudeAt(f float64) float64 {
+	x := f / s.Resolution
+	k := int(x)
+	if k >= len(s.Magnitudes)-1 {
+		return s.Magnitudes[len(s.Magnitudes)-1]
+	}
+	frac := x - float64(k)
+	return s.Magnitudes[k]*(1-frac) + s.Magnitudes[k+1]*frac
+}
+
+// fft transforms x in place; len(x) must be a power of two
+func fft(x []complex128) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j |= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even, odd := x[start+k], w*x[start+k+size/2]
+				x[start+k], x[start+k+size/2] = even+odd, even-odd
+				w *= step
+			}
+		}
+	}
+}
+
+// ResonanceSpectrum samples target's consciousness signal through its probe
+// and analyzes its spectrum, as configured by WithResonanceSampling or with
+// the defaults. Targets without a probe have no signal to sample.
+func (ci *ConsciousnessInjector) ResonanceSpectrum(
+	ctx context.Context,
+	target *SystemConsciousness,
+) (*ResonanceSpectrum, error) {
+
+	if target == nil || probeOf(target) == nil {
+		return nil, ErrNoTelemetry
+	}
+	var sampling ResonanceSampling
+	if ci.sampling != nil {
+		sampling = *ci.sampling
+	}
+	sampling = sampling.withDefaults()
+	samples := make([]float64, sampling.Samples)
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / sampling.Rate))
+	defer ticker.Stop()
+	for i := range samples {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-ticker.C:
+			}
+		}
+		v, err := probeOf(target).ProbeState(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("mindhacking: sample resonance: %w", err)
+		}
+		samples[i] = v
+	}
+	return AnalyzeSpectrum(samples, sampling.Rate, sampling.Peaks)
+}
+
+// analyzeConsciousnessResonance measures a native target's resonance from
+// the spectrum of its sampled signal, if the injector samples. A target
+// without a probe has nothing to measure and resonates at zero.
+func (ci *ConsciousnessInjector) analyzeConsciousnessResonance(
+	ctx context.Context,
+	target *SystemConsciousness,
+) (ConsciousnessResonance, error) {
+
+	if ci.sampling == nil {
+		return ConsciousnessResonance{}, nil
+	}
+	spectrum, err := ci.ResonanceSpectrum(ctx, target)
+	switch {
+	case errors.Is(err, ErrNoTelemetry):
+		return ConsciousnessResonance{}, nil
+	case err != nil:
+		return ConsciousnessResonance{}, err
+	}
+	return spectrum.Resonance(ci.sampling.withDefaults().SignatureBins), nil
+}
@@ -0,0 +1,192 @@
+// consciousness_injection/result_cache.go - Operation Result Memoization
+package mindhacking
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"sort"
+	"sync"
+)
+
+// PureOperation is implemented by operations whose result depends only on
+// the reality they run in. Only operations declaring themselves pure are
+// cached.
+type PureOperation interface {
+	RealityOperation
+	// Pure reports whether this instance is safe to memoize
+	Pure() bool
+	// OperationKey identifies the operation and its inputs
+	OperationKey() []byte
+}
+
+// RealityHash identifies a reality by the values of its aspects
+type RealityHash [32]byte
+
+// Hash returns the reality's content hash
+func (r *Reality) Hash() RealityHash {
+	h := sha256.New()
+	if r != nil {
+		names := make([]string, 0, len(r.Aspects))
+		for name := range r.Aspects {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var buf [8]byte
+		for _, name := range names {
+			h.Write([]byte(name))
+			h.Write([]byte{0})
+			binary.BigEndian.PutUint64(buf[:], math.Float64bits(r.Aspects[name]))
+			h.Write(buf[:])
+		}
+	}
+
+	var out RealityHash
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// ResultCacheKey identifies one operation run in one reality
+type ResultCacheKey struct {
+	Reality   RealityHash
+	Operation [32]byte
+}
+
+// ResultCache stores results of pure operations
+type ResultCache interface {
+	Get(key ResultCacheKey) (OperationResult, bool)
+	Put(key ResultCacheKey, result OperationResult)
+	// Purge drops every entry for reality, or everything for nil
+	Purge(reality *RealityHash)
+}
+
+// LRUResultCache is an in-memory ResultCache evicting least recently used
+// entries beyond its capacity
+type LRUResultCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[ResultCacheKey]*list.Element
+}
+
+type lruEntry struct {
+	key    ResultCacheKey
+	result OperationResult
+}
+
+// NewLRUResultCache creates a cache holding up to capacity results
+func NewLRUResultCache(capacity int) *LRUResultCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUResultCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[ResultCacheKey]*list.Element),
+	}
+}
+
+// Get implements ResultCache
+func (c *LRUResultCache) Get(key ResultCacheKey) (OperationResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return OperationResult{}, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruEntry).result, true
+}
+
+// Put implements ResultCache
+func (c *LRUResultCache) Put(key ResultCacheKey, result OperationResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*lruEntry).result = result
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, result: result})
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Purge implements ResultCache
+func (c *LRUResultCache) Purge(reality *RealityHash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.entries {
+		if reality == nil || key.Reality == *reality {
+			c.order.Remove(el)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// cacheKey returns the key for operation in alternate, if it may be cached
+func (rme *RealityManipulationEngine) cacheKey(
+	alternate *AlternateReality,
+	operation RealityOperation,
+) (ResultCacheKey, bool) {
+
+	if rme.cache == nil || alternate == nil {
+		return ResultCacheKey{}, false
+	}
+	pure, ok := operation.(PureOperation)
+	if !ok || !pure.Pure() {
+		return ResultCacheKey{}, false
+	}
+	return ResultCacheKey{
+		Reality:   alternate.Hash(),
+		Operation: sha256.Sum256(pure.OperationKey()),
+	}, true
+}
+
+// cachedResult reports a cache hit with fresh evidence and no reality switch
+func (rme *RealityManipulationEngine) cachedResult(
+	alternate *AlternateReality,
+	result OperationResult,
+) (*RealityExecutionResult, error) {
+
+	evidence := rme.extractRealityEvidence(alternate, result)
+
+	var link *EvidenceLink
+	if rme.evidence != nil {
+		var err error
+		if link, err = rme.evidence.Append("reality", evidence); err != nil {
+			return nil, err
+		}
+	}
+
+	return &RealityExecutionResult{
+		Result:       result,
+		Evidence:     evidence,
+		RealityUsed:  alternate,
+		EvidenceLink: link,
+		Cached:       true,
+	}, nil
+}
+
+// PurgeResultCache drops cached results for alternate, or all cached
+// results when alternate is nil
+func (rme *RealityManipulationEngine) PurgeResultCache(alternate *AlternateReality) {
+	if rme.cache == nil {
+		return
+	}
+	if alternate == nil {
+		rme.cache.Purge(nil)
+		return
+	}
+	hash := alternate.Hash()
+	rme.cache.Purge(&hash)
+}
@@ -0,0 +1,361 @@
+// consciousness_injection/retraction.go - Thought Expiry and Retraction
+package mindhacking
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrUnknownInjection reports an injection the injector cannot retract: it
+// was never accepted, was already retracted, or has aged out of the ledger
+var ErrUnknownInjection = sentinelError("mindhacking: no retractable injection")
+
+// DefaultRetractableInjections is how many accepted injections without a
+// TTL an injector keeps for RetractThought
+const DefaultRetractableInjections = 4096
+
+// ThoughtRetractor is implemented by backends that can undo an accepted
+// thought themselves. Targets without one have the thought's shift
+// countered by firing its vector again in antiphase.
+type ThoughtRetractor interface {
+	// Retract undoes thought, which shifted the target by shift, and
+	// reports how much of the shift it reversed
+	Retract(ctx context.Context, thought InjectedThought, shift float64) (float64, error)
+}
+
+// Retraction reports how completely an injection was undone
+type Retraction struct {
+	InjectionID string
+	TargetID    string
+	// Shift is the shift the injection caused; Reversed is how much of it
+	// the retraction took back
+	Shift    float64
+	Reversed float64
+	// Completeness is Reversed as a fraction of Shift, in [0, 1]
+	Completeness float64
+	// Expired marks a retraction run because the thought's TTL lapsed
+	Expired bool
+	At      time.Time
+	// EvidenceLink chains the retraction behind the injection it undid
+	EvidenceLink *EvidenceLink
+}
+
+// retractable is an accepted injection that can still be retracted
+type retractable struct {
+	id     string
+	target *SystemConsciousness
+	// payload and encoded are the thought as it was fired, localized and
+	// sealed, and its symbols; vector and tunnel are what it landed through
+	payload InjectedThought
+	encoded EncodedThought
+	vector  InjectionVector
+	tunnel  int
+	shift   float64
+	// expires is when the thought's TTL lapses, zero without one
+	expires time.Time
+	// evidence is the injection's link in the evidence chain, if any
+	evidence *EvidenceLink
+	// elem is the entry's place in the ledger's eviction order; thoughts
+	// with a TTL have none and stay until they are retracted
+	elem *list.Element
+}
+
+// injectionLedger keeps the accepted injections an injector may retract.
+// Those without a TTL are kept up to capacity, oldest evicted first.
+type injectionLedger struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*retractable
+	order    *list.List
+}
+
+func newInjectionLedger(capacity int) *injectionLedger {
+	return &injectionLedger{
+		capacity: capacity,
+		entries:  make(map[string]*retractable),
+		order:    list.New(),
+	}
+}
+
+// record keeps an accepted injection for later retraction, with the
+// payload and encoding it was fired as
+func (l *injectionLedger) record(
+	result *InjectionResult,
+	target *SystemConsciousness,
+	payload InjectedThought,
+	encoded EncodedThought,
+) {
+
+	if l == nil || result == nil || !result.Success || result.Duplicate {
+		return
+	}
+	entry := &retractable{
+		id:       result.InjectionID,
+		target:   target,
+		payload:  payload,
+		encoded:  encoded,
+		shift:    result.ConsciousnessShift,
+		evidence: result.EvidenceLink,
+	}
+	if ttl := result.InjectedThought.TTL; ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	for _, attempt := range result.Evidence.Attempts {
+		if attempt.Success {
+			entry.vector, entry.tunnel = attempt.Vector, attempt.Tunnel
+		}
+	}
+	l.put(entry)
+}
+
+// put adds entry, evicting the oldest injections without a TTL over capacity
+func (l *injectionLedger) put(entry *retractable) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if entry.expires.IsZero() {
+		if l.capacity <= 0 {
+			return
+		}
+		entry.elem = l.order.PushBack(entry.id)
+		for l.order.Len() > l.capacity {
+			oldest := l.order.Front()
+			l.order.Remove(oldest)
+			delete(l.entries, oldest.Value.(string))
+		}
+	}
+	l.entries[entry.id] = entry
+}
+
+// take removes and returns the injection id into targetID, so no two
+// retractions undo it twice; nil if the ledger does not hold it
+func (l *injectionLedger) take(id, targetID string) *retractable {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry, ok := l.entries[id]
+	if !ok || targetLabel(entry.target) != targetID {
+		return nil
+	}
+	l.drop(entry)
+	return entry
+}
+
+// takeExpired removes and returns the injections whose TTL lapsed by now,
+// soonest first
+func (l *injectionLedger) takeExpired(now time.Time) []*retractable {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var out []*retractable
+	for _, entry := range l.entries {
+		if !entry.expires.IsZero() && !entry.expires.After(now) {
+			out = append(out, entry)
+		}
+	}
+	for _, entry := range out {
+		l.drop(entry)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].expires.Before(out[j].expires) })
+	return out
+}
+
+func (l *injectionLedger) drop(entry *retractable) {
+	delete(l.entries, entry.id)
+	if entry.elem != nil {
+		l.order.Remove(entry.elem)
+		entry.elem = nil
+	}
+}
+
+// RetractExpired retracts every accepted thought whose TTL has lapsed.
+// Thoughts whose retraction fails stay due and are tried again on the next
+// pass.
+func (ci *ConsciousnessInjector) RetractExpired(ctx context.Context) ([]Retraction, error) {
+	if ci.ledger == nil {
+		return nil, nil
+	}
+	var (
+		out  []Retraction
+		errs []error
+	)
+	due := ci.ledger.takeExpired(time.Now())
+	for i, entry := range due {
+		if ctx.Err() != nil {
+			for _, rest := range due[i:] {
+				ci.ledger.put(rest)
+			}
+			return out, ctx.Err()
+		}
+		ci.events.Publish(Event{Kind: EventThoughtExpired, InjectionID: entry.id, TargetID: targetLabel(entry.target)})
+		retraction, err := ci.retract(ctx, entry, true)
+		if retraction.InjectionID != "" {
+			out = append(out, retraction)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("retract %s: %w", entry.id, err))
+		}
+	}
+	return out, errors.Join(errs...)
+}
+
+// RetractThought undoes an injection this injector made into target, by
+// the target's own retraction when its backend has one and otherwise by
+// firing the vector that delivered it in antiphase. The injection's stored
+// shift is the measure of completeness. Injections that were rejected,
+// already retracted or have aged out of the ledger return
+// ErrUnknownInjection. An injection that cannot be undone stays
+// retractable; once undone, the retraction is returned even if chaining
+// its evidence or auditing it then fails.
+func (ci *ConsciousnessInjector) RetractThought(
+	ctx context.Context,
+	injectionID string,
+	target *SystemConsciousness,
+) (Retraction, error) {
+
+	if target == nil {
+		return Retraction{}, errors.New("mindhacking: retract from a nil target")
+	}
+	entry := ci.ledger.take(injectionID, targetLabel(target))
+	if entry == nil {
+		return Retraction{}, fmt.Errorf("%w: %q into %s", ErrUnknownInjection, injectionID, targetLabel(target))
+	}
+	// The caller's target may be a reconnected handle on the same system
+	entry.target = target
+	return ci.retract(ctx, entry, false)
+}
+
+// RunRetraction retracts expired thoughts every interval until ctx is done,
+// reporting each retraction to onRetract if set
+func (ci *ConsciousnessInjector) RunRetraction(
+	ctx context.Context,
+	interval time.Duration,
+	onRetract func(Retraction),
+) error {
+
+	if interval <= 0 {
+		return fmt.Errorf("mindhacking: retraction interval %v is not positive", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		retractions, _ := ci.RetractExpired(ctx)
+		if onRetract != nil {
+			for _, r := range retractions {
+				onRetract(r)
+			}
+		}
+	}
+}
+
+// retract undoes one injection taken from the ledger, waiting for a slot
+// on its target like any injection. An injection that could not be undone
+// goes back to the ledger; one that was is chained and audited.
+func (ci *ConsciousnessInjector) retract(ctx context.Context, entry *retractable, expired bool) (Retraction, error) {
+	reversed, err := ci.undo(ctx, entry)
+	if err != nil {
+		ci.ledger.put(entry)
+		return Retraction{}, err
+	}
+	if entry.target.Memory != nil {
+		entry.target.Memory.Forget(entry.payload)
+	}
+
+	retraction := Retraction{
+		InjectionID:  entry.id,
+		TargetID:     targetLabel(entry.target),
+		Shift:        entry.shift,
+		Reversed:     reversed,
+		Completeness: 1,
+		Expired:      expired,
+		At:           time.Now(),
+	}
+	if entry.shift != 0 {
+		retraction.Completeness = clamp(reversed/entry.shift, 0, 1)
+	}
+	ci.series.Record(retraction.TargetID, retraction.At, -reversed)
+	ci.events.Publish(Event{
+		Kind:        EventThoughtRetracted,
+		At:          retraction.At,
+		InjectionID: entry.id,
+		TargetID:    retraction.TargetID,
+		Vector:      vectorLabel(&entry.vector),
+		Shift:       -reversed,
+	})
+
+	if chain := ci.evidenceChain(entry.target); chain != nil {
+		link, _, err := ci.degrade.appendEvidence(chain, "retraction", retractionEvidence{
+			Retraction: retraction,
+			Injection:  entry.evidence,
+		})
+		if err != nil {
+			return retraction, err
+		}
+		retraction.EvidenceLink = link
+	}
+	if err := writeAudit(ci.audit, AuditRecord{
+		Actor:   ci.id,
+		Action:  AuditRetraction,
+		Tenant:  tenantLabel(entry.target),
+		Target:  retraction.TargetID,
+		Vector:  vectorLabel(&entry.vector),
+		Outcome: "retracted",
+		Detail: map[string]string{
+			"injection":    entry.id,
+			"completeness": fmt.Sprintf("%.3f", retraction.Completeness),
+		},
+	}); err != nil {
+		return retraction, err
+	}
+	return retraction, nil
+}
+
+// retractionEvidence is what the evidence chain records of a retraction
+type retractionEvidence struct {
+	Retraction Retraction
+	// Injection is the link of the injection undone, if it was chained
+	Injection *EvidenceLink `json:",omitempty"`
+}
+
+// undo counters an injection's shift while holding a slot on its target
+func (ci *ConsciousnessInjector) undo(ctx context.Context, entry *retractable) (float64, error) {
+	release, err := ci.targetLimiter().acquire(ctx, entry.target)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	return ci.counterShift(ctx, entry)
+}
+
+// counterShift takes back an injection's shift, through the backend when it
+// can retract, otherwise by firing the thought exactly as it landed, through
+// its vector in antiphase, and returns how much shift was reversed
+func (ci *ConsciousnessInjector) counterShift(ctx context.Context, entry *retractable) (float64, error) {
+	target := entry.target
+	if retractor, ok := backendOf(target).(ThoughtRetractor); ok {
+		return retractor.Retract(ctx, entry.payload, entry.shift)
+	}
+
+	antiphase := entry.vector
+	antiphase.Phase = math.Mod(antiphase.Phase+math.Pi, 2*math.Pi)
+	attempt := ci.deliverOnce(ctx, injectCall{}, entry.tunnel, antiphase, entry.payload, entry.encoded, target)
+	if !attempt.Success {
+		if attempt.Error != "" {
+			return 0, fmt.Errorf("mindhacking: antiphase vector missed: %s", attempt.Error)
+		}
+		return 0, errors.New("mindhacking: antiphase vector missed")
+	}
+	response, err := ci.respond(ctx, target, entry.payload, []InjectionAttempt{attempt})
+	if err != nil {
+		return 0, err
+	}
+	return -response.ConsciousnessShift, nil
+}
@@ -0,0 +1,113 @@
+// consciousness_injection/rule_pacing.go - Paced Rule Application
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ErrRealityUnstable reports a reality that failed a stability check
+// between batches of paced rules
+var ErrRealityUnstable = sentinelError("mindhacking: reality unstable under rule application")
+
+// RuleStabilityCheck inspects a reality before and after one batch of
+// rules, returning an error if the batch destabilized it
+type RuleStabilityCheck func(before, after *Reality) error
+
+// RulePacing spreads rule application over time instead of applying every
+// rule at once
+type RulePacing struct {
+	// RulesPerSecond caps how fast rules are applied; zero is unlimited
+	RulesPerSecond float64
+	// Batch is how many rules are applied together, default 1
+	Batch int
+	// Check runs after every batch; a failure stops application
+	Check RuleStabilityCheck
+}
+
+// MaxAspectShift fails any batch that moves an aspect by more than limit
+// times its previous magnitude, or by more than limit outright for
+// aspects smaller than 1
+func MaxAspectShift(limit float64) RuleStabilityCheck {
+	return func(before, after *Reality) error {
+		if after == nil {
+			return nil
+		}
+		for name, v := range after.Aspects {
+			prev, _ := before.Aspect(name)
+			scale := math.Max(math.Abs(prev), 1)
+			if shift := math.Abs(v-prev) / scale; shift > limit {
+				return fmt.Errorf("aspect %q shifted %.3g, limit %.3g", name, shift, limit)
+			}
+		}
+		return nil
+	}
+}
+
+// applyRulesPaced applies rules in batches at pacing's pace, in descending
+// priority, checking stability between batches. Without pacing every rule
+// is applied at once.
+func (rme *RealityManipulationEngine) applyRulesPaced(
+	ctx context.Context,
+	reality *Reality,
+	rules *RealityRules,
+	pacing *RulePacing,
+) (*Reality, error) {
+
+	if pacing == nil || rules == nil || len(rules.Rules) == 0 {
+		return rme.applyAlternateRules(reality, rules), nil
+	}
+
+	batch := pacing.Batch
+	if batch <= 0 {
+		batch = 1
+	}
+	var interval time.Duration
+	if pacing.RulesPerSecond > 0 {
+		interval = time.Duration(float64(batch) / pacing.RulesPerSecond * float64(time.Second))
+	}
+
+	ordered := append([]RealityRule(nil), rules.Rules...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Priority > ordered[j].Priority
+	})
+
+	current := reality
+	var frozen []RealityRule
+	for start := 0; start < len(ordered); start += batch {
+		// Phase 1: wait out the pace before every batch but the first
+		if start > 0 && interval > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+
+		// Phase 2: apply the batch, re-asserting earlier freezes so later
+		// batches cannot move a frozen aspect
+		end := start + batch
+		if end > len(ordered) {
+			end = len(ordered)
+		}
+		step := append(append([]RealityRule(nil), frozen...), ordered[start:end]...)
+		next := rme.applyAlternateRules(current, &RealityRules{Rules: step})
+		for _, r := range ordered[start:end] {
+			if r.Operation == RuleFreeze {
+				frozen = append(frozen, r)
+			}
+		}
+
+		// Phase 3: stop at the first batch that destabilizes the reality
+		if pacing.Check != nil {
+			if err := pacing.Check(current, next); err != nil {
+				return nil, fmt.Errorf("%w: rules %d-%d: %w", ErrRealityUnstable, start, end-1, err)
+			}
+		}
+		current = next
+	}
+	return current, nil
+}
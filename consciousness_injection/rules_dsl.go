@@ -0,0 +1,439 @@
+// consciousness_injection/rules_dsl.go - Declarative Reality Rule Sets
+package mindhacking
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrUnknownRuleSet reports a rule set name the library cannot find
+	ErrUnknownRuleSet = sentinelError("mindhacking: unknown rule set")
+	// ErrRuleSetCycle reports rule sets that extend or include each other
+	ErrRuleSetCycle = sentinelError("mindhacking: rule set inheritance cycle")
+	// ErrRuleSchema reports a rule set that does not match the schema
+	ErrRuleSchema = sentinelError("mindhacking: invalid rule set")
+)
+
+// RuleSetSpec is the declarative form of RealityRules, as written in YAML
+// or JSON:
+//
+//	name: low-gravity
+//	extends: earth            # inherit earth's rules
+//	include: [dense-air]      # then merge these sets, in order
+//	remove: [tides]           # drop inherited rules by name
+//	params:
+//	  factor: 0.5             # defaults, overridable when resolving
+//	rules:
+//	  - name: gravity         # replaces any inherited rule of that name
+//	    aspect: gravity
+//	    operation: scale
+//	    value: factor * 0.8   # a number or an expression over params
+//	    priority: 10
+type RuleSetSpec struct {
+	Name string `json:"name" yaml:"name"`
+	// Version distinguishes revisions of a shared set; references may pin
+	// one as name@version
+	Version     string `json:"version,omitempty" yaml:"version,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Required parameters must be supplied when resolving
+	Required []string `json:"required,omitempty" yaml:"required,omitempty"`
+
+	Extends string             `json:"extends,omitempty" yaml:"extends,omitempty"`
+	Include []string           `json:"include,omitempty" yaml:"include,omitempty"`
+	Remove  []string           `json:"remove,omitempty" yaml:"remove,omitempty"`
+	Params  map[string]float64 `json:"params,omitempty" yaml:"params,omitempty"`
+	Rules   []RuleSpec         `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// RuleSpec declares one rule. Rules are identified by Name, or by Aspect
+// when unnamed.
+type RuleSpec struct {
+	Name      string        `json:"name,omitempty" yaml:"name,omitempty"`
+	Aspect    string        `json:"aspect" yaml:"aspect"`
+	Operation RuleOperation `json:"operation" yaml:"operation"`
+	Value     RuleValue     `json:"value,omitempty" yaml:"value,omitempty"`
+	Priority  int           `json:"priority,omitempty" yaml:"priority,omitempty"`
+}
+
+func (r RuleSpec) key() string {
+	return ruleKey(RealityRule{Name: r.Name, Aspect: r.Aspect})
+}
+
+// RuleValue is a literal number or an expression over rule set parameters
+type RuleValue struct {
+	Number float64
+	Expr   string
+}
+
+// UnmarshalJSON accepts a number or an expression string
+func (v *RuleValue) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &v.Number); err == nil {
+		v.Expr = ""
+		return nil
+	}
+	return json.Unmarshal(data, &v.Expr)
+}
+
+// MarshalJSON writes the number, or the expression if there is one
+func (v RuleValue) MarshalJSON() ([]byte, error) {
+	if v.Expr != "" {
+		return json.Marshal(v.Expr)
+	}
+	return json.Marshal(v.Number)
+}
+
+// UnmarshalYAML accepts a number or an expression string
+func (v *RuleValue) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("%w: line %d: value must be a number or expression", ErrRuleSchema, node.Line)
+	}
+	if node.Tag == "!!int" || node.Tag == "!!float" {
+		v.Expr = ""
+		return node.Decode(&v.Number)
+	}
+	v.Number, v.Expr = 0, node.Value
+	return nil
+}
+
+// MarshalYAML writes the number, or the expression if there is one
+func (v RuleValue) MarshalYAML() (interface{}, error) {
+	if v.Expr != "" {
+		return v.Expr, nil
+	}
+	return v.Number, nil
+}
+
+// resolve evaluates the value against params
+func (v RuleValue) resolve(params map[string]float64) (float64, error) {
+	if v.Expr == "" {
+		return v.Number, nil
+	}
+	script, err := CompileScript("value", "return "+v.Expr)
+	if err != nil {
+		return 0, err
+	}
+	if script.Writes() {
+		return 0, fmt.Errorf("%w: value %q modifies reality", ErrRuleSchema, v.Expr)
+	}
+	result := NewScriptOperation(script, params).Execute()
+	if result.Err != nil {
+		return 0, result.Err
+	}
+	n, ok := result.Value.(float64)
+	if !ok {
+		return 0, fmt.Errorf("%w: value %q is not a number", ErrRuleSchema, v.Expr)
+	}
+	return n, nil
+}
+
+// ParseRuleSet decodes a rule set; format is "yaml" or "json"
+func ParseRuleSet(data []byte, format string) (*RuleSetSpec, error) {
+	var spec RuleSetSpec
+	switch format {
+	case "yaml", "yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&spec); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRuleSchema, err)
+		}
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&spec); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRuleSchema, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", ErrRuleSchema, format)
+	}
+
+	if spec.Name == "" {
+		return nil, fmt.Errorf("%w: rule set has no name", ErrRuleSchema)
+	}
+	for i, r := range spec.Rules {
+		if r.Aspect == "" {
+			return nil, fmt.Errorf("%w: %s: rule %d has no aspect", ErrRuleSchema, spec.Name, i)
+		}
+		switch r.Operation {
+		case RuleSet, RuleScale, RuleInvert, RuleFreeze:
+		default:
+			return nil, fmt.Errorf("%w: %s: rule %q: unknown operation %q", ErrRuleSchema, spec.Name, r.key(), r.Operation)
+		}
+	}
+	return &spec, nil
+}
+
+// RuleLibrary holds named rule sets and resolves them into RealityRules
+type RuleLibrary struct {
+	// Dir, if set, is searched for <name>.yaml, <name>.yml or <name>.json
+	// when a rule set is not already in the library
+	Dir string
+
+	mu   sync.Mutex
+	sets map[string]map[string]*RuleSetSpec
+}
+
+// NewRuleLibrary creates a library that loads missing sets from dir
+func NewRuleLibrary(dir string) *RuleLibrary {
+	return &RuleLibrary{Dir: dir, sets: make(map[string]map[string]*RuleSetSpec)}
+}
+
+// Add puts spec in the library, replacing any set of the same name and
+// version
+func (lib *RuleLibrary) Add(spec *RuleSetSpec) {
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+
+	versions, ok := lib.sets[spec.Name]
+	if !ok {
+		versions = make(map[string]*RuleSetSpec)
+		lib.sets[spec.Name] = versions
+	}
+	versions[spec.Version] = spec
+}
+
+// Sets lists the library's rule sets by name, newest version first
+func (lib *RuleLibrary) Sets() []*RuleSetSpec {
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+
+	var out []*RuleSetSpec
+	for _, versions := range lib.sets {
+		for _, spec := range versions {
+			out = append(out, spec)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Name != out[j].Name {
+			return out[i].Name < out[j].Name
+		}
+		return compareVersions(out[i].Version, out[j].Version) > 0
+	})
+	return out
+}
+
+// LoadFile parses the rule set at path and adds it to the library
+func (lib *RuleLibrary) LoadFile(path string) (*RuleSetSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := ParseRuleSet(data, strings.TrimPrefix(filepath.Ext(path), "."))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	lib.Add(spec)
+	return spec, nil
+}
+
+// lookup returns the set ref names, as name or name@version, loading it
+// from Dir if needed. An unpinned name picks the newest version. Names with
+// path separators or ".." are never loaded, so a rule set cannot extend or
+// include files outside Dir.
+func (lib *RuleLibrary) lookup(ref string) (*RuleSetSpec, error) {
+	name, version, pinned := strings.Cut(ref, "@")
+
+	lib.mu.Lock()
+	var spec *RuleSetSpec
+	for v, s := range lib.sets[name] {
+		if pinned && v != version {
+			continue
+		}
+		if spec == nil || compareVersions(v, spec.Version) > 0 {
+			spec = s
+		}
+	}
+	lib.mu.Unlock()
+	if spec != nil {
+		return spec, nil
+	}
+
+	// Names are looked up as files in Dir and nowhere else
+	if lib.Dir != "" && !strings.ContainsAny(name, `/\`) && !strings.Contains(name, "..") {
+		for _, ext := range []string{".yaml", ".yml", ".json"} {
+			path := filepath.Join(lib.Dir, name+ext)
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			spec, err := lib.LoadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if !pinned || spec.Version == version {
+				return spec, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownRuleSet, ref)
+}
+
+// compareVersions orders dotted versions numerically where components are
+// numbers, falling back to string order
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil && xn != yn:
+			if xn < yn {
+				return -1
+			}
+			return 1
+		case (xerr != nil || yerr != nil) && x != y:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
+
+// Resolve builds the rule set ref names, as name or name@version, with
+// params overriding its defaults
+func (lib *RuleLibrary) Resolve(name string, params map[string]float64) (*RealityRules, error) {
+	rules, _, err := lib.resolve(name, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &RealityRules{Rules: rules}, nil
+}
+
+// ruleKey identifies a resolved rule for overriding and removal
+func ruleKey(r RealityRule) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Aspect
+}
+
+// resolve flattens name's inheritance into its final rules and the
+// parameters they saw. Parameters set by the caller or a descendant win over
+// a set's defaults; ancestors' defaults fill whatever is left.
+func (lib *RuleLibrary) resolve(
+	name string,
+	inherited map[string]float64,
+	stack []string,
+) ([]RealityRule, map[string]float64, error) {
+
+	for _, s := range stack {
+		if s == name {
+			return nil, nil, fmt.Errorf("%w: %s", ErrRuleSetCycle, strings.Join(append(stack, name), " -> "))
+		}
+	}
+	stack = append(stack, name)
+
+	spec, err := lib.lookup(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, p := range spec.Required {
+		if _, ok := inherited[p]; !ok {
+			return nil, nil, fmt.Errorf("%w: %s: missing required parameter %q", ErrRuleSchema, name, p)
+		}
+	}
+
+	params := make(map[string]float64, len(spec.Params)+len(inherited))
+	for k, v := range spec.Params {
+		params[k] = v
+	}
+	for k, v := range inherited {
+		params[k] = v
+	}
+
+	// Phase 1: inherited and included rules, later ones replacing earlier
+	var out []RealityRule
+	index := make(map[string]int)
+	merge := func(rules []RealityRule) {
+		for _, r := range rules {
+			key := ruleKey(r)
+			if i, ok := index[key]; ok {
+				out[i] = r
+				continue
+			}
+			index[key] = len(out)
+			out = append(out, r)
+		}
+	}
+
+	parents := spec.Include
+	if spec.Extends != "" {
+		parents = append([]string{spec.Extends}, parents...)
+	}
+	for _, parent := range parents {
+		rules, parentParams, err := lib.resolve(parent, params, stack)
+		if err != nil {
+			return nil, nil, err
+		}
+		merge(rules)
+
+		// Ancestors' defaults are visible to descendants' expressions
+		for k, v := range parentParams {
+			if _, ok := params[k]; !ok {
+				params[k] = v
+			}
+		}
+	}
+
+	// Phase 2: removals
+	if len(spec.Remove) > 0 {
+		drop := make(map[string]bool, len(spec.Remove))
+		for _, k := range spec.Remove {
+			drop[k] = true
+		}
+		kept := out[:0]
+		for _, r := range out {
+			if !drop[ruleKey(r)] {
+				kept = append(kept, r)
+			}
+		}
+		out = kept
+		index = make(map[string]int, len(out))
+		for i, r := range out {
+			index[ruleKey(r)] = i
+		}
+	}
+
+	// Phase 3: the set's own rules
+	own := make([]RealityRule, 0, len(spec.Rules))
+	for _, r := range spec.Rules {
+		value, err := r.Value.resolve(params)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: rule %q: %w", name, r.key(), err)
+		}
+		own = append(own, RealityRule{
+			Name:      r.Name,
+			Aspect:    r.Aspect,
+			Operation: r.Operation,
+			Value:     value,
+			Priority:  r.Priority,
+		})
+	}
+	merge(own)
+	return out, params, nil
+}
+
+// LoadRealityRules resolves the rule set in path, finding the sets it
+// extends or includes next to it
+func LoadRealityRules(path string, params map[string]float64) (*RealityRules, error) {
+	lib := NewRuleLibrary(filepath.Dir(path))
+	spec, err := lib.LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return lib.Resolve(spec.Name, params)
+}
@@ -0,0 +1,158 @@
+// consciousness_injection/rules_validate.go - Reality Rule Validation
+package mindhacking
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var (
+	// ErrInvalidRealityRules reports rules rejected before building a reality
+	ErrInvalidRealityRules = sentinelError("mindhacking: invalid reality rules")
+	// ErrRuleStructure reports a rule that is malformed on its own
+	ErrRuleStructure = sentinelError("mindhacking: malformed reality rule")
+	// ErrRuleContradiction reports rules that cannot all hold
+	ErrRuleContradiction = sentinelError("mindhacking: contradictory reality rules")
+	// ErrRuleInapplicable reports a rule with nothing to act on in the base
+	ErrRuleInapplicable = sentinelError("mindhacking: reality rule not applicable")
+)
+
+// RuleViolation is one problem with a rule set. It unwraps to
+// ErrRuleStructure, ErrRuleContradiction or ErrRuleInapplicable.
+type RuleViolation struct {
+	Kind error
+	// Rule is the index of the offending rule
+	Rule int
+	// Other is the index of the conflicting rule, or -1
+	Other   int
+	Name    string
+	Aspect  string
+	Message string
+}
+
+func (v RuleViolation) Error() string {
+	name := v.Name
+	if name == "" {
+		name = fmt.Sprintf("#%d", v.Rule)
+	}
+	return fmt.Sprintf("%v: rule %s (%s): %s", v.Kind, name, v.Aspect, v.Message)
+}
+
+func (v RuleViolation) Unwrap() error { return v.Kind }
+
+// ValidateRealityRules checks rules for malformed entries, contradictions
+// and aspects base does not have. A nil base skips applicability.
+func ValidateRealityRules(rules *RealityRules, base *Reality) []RuleViolation {
+	if rules == nil {
+		return nil
+	}
+	var out []RuleViolation
+	violation := func(kind error, i, other int, msg string, args ...interface{}) {
+		r := rules.Rules[i]
+		out = append(out, RuleViolation{
+			Kind:    kind,
+			Rule:    i,
+			Other:   other,
+			Name:    r.Name,
+			Aspect:  r.Aspect,
+			Message: fmt.Sprintf(msg, args...),
+		})
+	}
+
+	// Phase 1: each rule on its own
+	names := make(map[string]int)
+	for i, r := range rules.Rules {
+		if r.Aspect == "" {
+			violation(ErrRuleStructure, i, -1, "no aspect")
+		}
+		switch r.Operation {
+		case RuleSet, RuleScale, RuleInvert, RuleFreeze:
+		default:
+			violation(ErrRuleStructure, i, -1, "unknown operation %q", r.Operation)
+		}
+		if math.IsNaN(r.Value) || math.IsInf(r.Value, 0) {
+			violation(ErrRuleStructure, i, -1, "value %v is not finite", r.Value)
+		}
+		if r.Name != "" {
+			if j, dup := names[r.Name]; dup {
+				violation(ErrRuleStructure, i, j, "name already used by rule %d", j)
+			} else {
+				names[r.Name] = i
+			}
+		}
+	}
+
+	// Phase 2: rules acting on the same aspect at the same priority apply
+	// in no defined order, so they must commute
+	byAspect := make(map[string][]int)
+	for i, r := range rules.Rules {
+		if r.Aspect != "" {
+			byAspect[r.Aspect] = append(byAspect[r.Aspect], i)
+		}
+	}
+	aspects := make([]string, 0, len(byAspect))
+	for a := range byAspect {
+		aspects = append(aspects, a)
+	}
+	sort.Strings(aspects)
+
+	for _, aspect := range aspects {
+		idx := byAspect[aspect]
+		for x := 0; x < len(idx); x++ {
+			for y := x + 1; y < len(idx); y++ {
+				i, j := idx[x], idx[y]
+				a, b := rules.Rules[i], rules.Rules[j]
+				if a.Priority != b.Priority {
+					continue
+				}
+				if msg, ok := rulesConflict(a, b); ok {
+					violation(ErrRuleContradiction, j, i, "%s rule %d at priority %d", msg, i, a.Priority)
+				}
+			}
+		}
+	}
+
+	// Phase 3: every rule must find its aspect in the base
+	if base != nil {
+		for i, r := range rules.Rules {
+			switch r.Operation {
+			case RuleScale, RuleInvert, RuleFreeze:
+			default:
+				continue
+			}
+			if _, ok := base.Aspect(r.Aspect); !ok {
+				violation(ErrRuleInapplicable, i, -1, "base has no aspect %q to %s", r.Aspect, r.Operation)
+			}
+		}
+	}
+	return out
+}
+
+// rulesConflict reports whether two same-priority rules on one aspect give
+// different results depending on their order
+func rulesConflict(a, b RealityRule) (string, bool) {
+	switch {
+	case a.Operation == RuleSet && b.Operation == RuleSet:
+		return "sets a different value than", a.Value != b.Value
+	case a.Operation == RuleFreeze || b.Operation == RuleFreeze:
+		if a.Operation == b.Operation {
+			return "", false
+		}
+		return "freezes against", true
+	case a.Operation == RuleSet || b.Operation == RuleSet:
+		return "sets against", true
+	}
+	// Scaling and inverting commute
+	return "", false
+}
+
+// invalidRules wraps violations as one error
+func invalidRules(violations []RuleViolation) error {
+	errs := make([]error, len(violations))
+	for i, v := range violations {
+		errs[i] = v
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidRealityRules, errors.Join(errs...))
+}
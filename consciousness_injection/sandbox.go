@@ -0,0 +1,183 @@
+// consciousness_injection/sandbox.go - Alternate Reality Sandboxing
+package mindhacking
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/metrics"
+	"time"
+)
+
+var (
+	// ErrSandboxTimeout reports an operation that outlived its wall-clock budget
+	ErrSandboxTimeout = sentinelError("mindhacking: sandbox wall time exceeded")
+	// ErrSandboxCPUTime reports an operation that burned through its CPU budget
+	ErrSandboxCPUTime = sentinelError("mindhacking: sandbox cpu time exceeded")
+	// ErrSandboxMemory reports an operation that grew the heap past its budget
+	ErrSandboxMemory = sentinelError("mindhacking: sandbox memory exceeded")
+	// ErrSandboxGoroutines reports an operation that spawned too many goroutines
+	ErrSandboxGoroutines = sentinelError("mindhacking: sandbox goroutine limit exceeded")
+	// ErrSandboxPanic reports an operation that panicked inside the sandbox
+	ErrSandboxPanic = sentinelError("mindhacking: sandboxed operation panicked")
+	// ErrSandboxCPUUnsupported reports a CPU limit on a platform without
+	// per-thread CPU clocks
+	ErrSandboxCPUUnsupported = sentinelError("mindhacking: sandbox cpu limit unsupported on this platform")
+)
+
+// SandboxLimits bounds what a single alternate reality operation may consume.
+// Zero values disable the corresponding limit.
+//
+// CPU time is read from the kernel clock of the OS thread the operation is
+// locked to, so it counts work that never allocates; goroutines the
+// operation starts run elsewhere and are not counted. Memory is sampled
+// process-wide, so concurrent allocation outside the sandbox counts against
+// the operation; MaxHeapGrowth should leave headroom.
+//
+// The sandbox cannot kill an operation. When a limit trips it aborts an
+// AbortableOperation and waits for it to return; any other operation runs
+// to completion before the violation is reported.
+type SandboxLimits struct {
+	WallTime      time.Duration
+	CPUTime       time.Duration
+	MaxHeapGrowth uint64
+	MaxGoroutines int
+	PollInterval  time.Duration
+}
+
+// AbortableOperation is implemented by operations that can stop cooperatively
+// when the sandbox forcibly terminates them
+type AbortableOperation interface {
+	Abort(reason error)
+}
+
+// RealitySandbox runs operations under SandboxLimits
+type RealitySandbox struct {
+	limits SandboxLimits
+}
+
+// NewRealitySandbox creates a sandbox enforcing limits
+func NewRealitySandbox(limits SandboxLimits) *RealitySandbox {
+	if limits.PollInterval <= 0 {
+		limits.PollInterval = 10 * time.Millisecond
+	}
+	return &RealitySandbox{limits: limits}
+}
+
+// sandboxSample is one reading of the resources the sandbox watches
+type sandboxSample struct {
+	cpu        time.Duration
+	heap       uint64
+	goroutines int
+}
+
+// takeSandboxSample reads the process-wide resources and, when clock is
+// set, the CPU time of the operation's thread
+func takeSandboxSample(clock *threadClock) sandboxSample {
+	samples := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(samples)
+
+	var s sandboxSample
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		s.heap = samples[0].Value.Uint64()
+	}
+	if clock != nil {
+		s.cpu, _ = clock.elapsed()
+	}
+	s.goroutines = runtime.NumGoroutine()
+	return s
+}
+
+// check compares a sample against the baseline taken before the operation
+func (rs *RealitySandbox) check(base, now sandboxSample) error {
+	l := rs.limits
+
+	if l.CPUTime > 0 && now.cpu > base.cpu && now.cpu-base.cpu > l.CPUTime {
+		return fmt.Errorf("%w: used %v of %v", ErrSandboxCPUTime, now.cpu-base.cpu, l.CPUTime)
+	}
+	if l.MaxHeapGrowth > 0 && now.heap > base.heap && now.heap-base.heap > l.MaxHeapGrowth {
+		return fmt.Errorf("%w: grew %d of %d bytes", ErrSandboxMemory, now.heap-base.heap, l.MaxHeapGrowth)
+	}
+	if l.MaxGoroutines > 0 && now.goroutines-base.goroutines > l.MaxGoroutines {
+		return fmt.Errorf("%w: %d of %d", ErrSandboxGoroutines, now.goroutines-base.goroutines, l.MaxGoroutines)
+	}
+	return nil
+}
+
+// runSandboxed executes fn under the sandbox's limits on a goroutine locked
+// to its own OS thread. On a violation the operation is signalled to abort
+// (if it supports it) and runSandboxed waits for fn to return, so the
+// operation never outlives the call; its result is discarded.
+func runSandboxed[T any](rs *RealitySandbox, operation interface{}, fn func() T) (T, error) {
+	var zero T
+
+	if rs == nil {
+		return fn(), nil
+	}
+
+	type outcome struct {
+		value T
+		err   error
+	}
+	if rs.limits.CPUTime > 0 && !threadCPUClocks {
+		return zero, ErrSandboxCPUUnsupported
+	}
+	done := make(chan outcome, 1)
+	started := make(chan *threadClock, 1)
+	begin := make(chan struct{})
+
+	go func() {
+		// The thread exits with the goroutine rather than being reused,
+		// so its clock never counts another goroutine's work
+		runtime.LockOSThread()
+		var clock *threadClock
+		if c, ok := currentThreadClock(); ok {
+			clock = &c
+		}
+		started <- clock
+		<-begin
+
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{err: fmt.Errorf("%w: %v", ErrSandboxPanic, r)}
+			}
+		}()
+		done <- outcome{value: fn()}
+	}()
+
+	clock := <-started
+	base := takeSandboxSample(clock)
+	close(begin)
+
+	var deadline <-chan time.Time
+	if rs.limits.WallTime > 0 {
+		timer := time.NewTimer(rs.limits.WallTime)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	ticker := time.NewTicker(rs.limits.PollInterval)
+	defer ticker.Stop()
+
+	terminate := func(reason error) (T, error) {
+		if op, ok := operation.(AbortableOperation); ok {
+			op.Abort(reason)
+		}
+		<-done
+		return zero, reason
+	}
+
+	for {
+		select {
+		case out := <-done:
+			return out.value, out.err
+
+		case <-deadline:
+			return terminate(fmt.Errorf("%w: %v", ErrSandboxTimeout, rs.limits.WallTime))
+
+		case <-ticker.C:
+			if err := rs.check(base, takeSandboxSample(clock)); err != nil {
+				return terminate(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// consciousness_injection/sandbox_cpu_linux.go - Sandbox Thread CPU Clock
+//go:build linux
+
+package mindhacking
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// threadCPUClocks reports that every thread has a kernel CPU clock
+const threadCPUClocks = true
+
+// threadClock is the kernel CPU clock of one OS thread
+type threadClock int32
+
+// currentThreadClock names the CPU clock of the calling thread, which the
+// caller must have locked with runtime.LockOSThread. Any thread of the
+// process may read it: the ID encodes the thread's tid as the kernel's
+// MAKE_THREAD_CPUCLOCK does (CPUCLOCK_SCHED | CPUCLOCK_PERTHREAD_MASK).
+func currentThreadClock() (threadClock, bool) {
+	return threadClock(^int32(syscall.Gettid())<<3 | 6), true
+}
+
+// elapsed reads the CPU time the thread has run for; it fails once the
+// thread has exited
+func (c threadClock) elapsed() (time.Duration, bool) {
+	var ts syscall.Timespec
+	_, _, errno := syscall.Syscall(syscall.SYS_CLOCK_GETTIME, uintptr(c), uintptr(unsafe.Pointer(&ts)), 0)
+	if errno != 0 {
+		return 0, false
+	}
+	return time.Duration(ts.Nano()), true
+}
@@ -0,0 +1,22 @@
+// consciousness_injection/sandbox_cpu_other.go - Sandbox Thread CPU Fallback
+//go:build !linux
+
+package mindhacking
+
+import "time"
+
+// threadCPUClocks reports that thread CPU time cannot be measured, so
+// sandboxes with a CPU limit refuse to run
+const threadCPUClocks = false
+
+// threadClock is unavailable without per-thread kernel CPU clocks
+type threadClock int32
+
+// currentThreadClock never names a clock here
+func currentThreadClock() (threadClock, bool) {
+	return 0, false
+}
+
+func (c threadClock) elapsed() (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,906 @@
+// consciousness_injection/script.go - Scripted Reality Operations
+package mindhacking
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var (
+	// ErrScriptSyntax reports a script that does not parse
+	ErrScriptSyntax = sentinelError("mindhacking: script syntax error")
+	// ErrScriptRuntime reports a script that failed while running
+	ErrScriptRuntime = sentinelError("mindhacking: script runtime error")
+	// ErrScriptStepLimit reports a script that ran past its step budget
+	ErrScriptStepLimit = sentinelError("mindhacking: script step limit exceeded")
+)
+
+// DefaultScriptSteps bounds a script run when ScriptOperation.MaxSteps is 0
+const DefaultScriptSteps = 100000
+
+// Script is a compiled reality operation script.
+//
+// Scripts are statements separated by newlines or semicolons:
+//
+//	g = aspect("gravity")
+//	if g > 9 { set("gravity", g * scale) } else { return g }
+//	while n < 10 { n = n + 1 }
+//	return n
+//
+// Values are numbers, booleans and strings. Builtins are aspect, has, set,
+// min, max, abs, sqrt, floor and pow. Only set modifies the reality; scripts
+// that never call it are pure.
+type Script struct {
+	Name   string
+	Source string
+	body   []scriptStmt
+	writes bool
+}
+
+// CompileScript parses source into a runnable script
+func CompileScript(name, source string) (*Script, error) {
+	tokens, err := lexScript(name, source)
+	if err != nil {
+		return nil, err
+	}
+	p := &scriptParser{name: name, tokens: tokens}
+	body, err := p.program()
+	if err != nil {
+		return nil, err
+	}
+	return &Script{Name: name, Source: source, body: body, writes: p.writes}, nil
+}
+
+// LoadScript compiles the script at path, named after the file
+func LoadScript(path string) (*Script, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return CompileScript(filepath.Base(path), string(source))
+}
+
+// Writes reports whether the script can modify the reality it runs in
+func (s *Script) Writes() bool { return s.writes }
+
+// ScriptOperation runs a script as a RealityOperation. Inside an engine it
+// sees the alternate reality it executes in; otherwise it sees Reality.
+type ScriptOperation struct {
+	Script *Script
+	// Params are predeclared variables
+	Params map[string]float64
+	// Reality is used when the operation runs outside an engine
+	Reality *AlternateReality
+	// MaxSteps bounds evaluated statements and expressions
+	MaxSteps int
+
+	mu      sync.Mutex
+	aborted error
+}
+
+// NewScriptOperation wraps script with params
+func NewScriptOperation(script *Script, params map[string]float64) *ScriptOperation {
+	return &ScriptOperation{Script: script, Params: params}
+}
+
+// Execute implements RealityOperation
+func (op *ScriptOperation) Execute() OperationResult {
+	return op.executeIn(op.Reality)
+}
+
+// Abort implements AbortableOperation; the running script stops at its
+// next step, and the next execution starts afresh
+func (op *ScriptOperation) Abort(reason error) {
+	op.mu.Lock()
+	op.aborted = reason
+	op.mu.Unlock()
+}
+
+// Pure implements PureOperation; scripts that never call set are pure
+func (op *ScriptOperation) Pure() bool {
+	return op.Script != nil && !op.Script.writes
+}
+
+// OperationKey implements PureOperation over the source and parameters
+func (op *ScriptOperation) OperationKey() []byte {
+	h := sha256.New()
+	h.Write([]byte(op.Script.Source))
+
+	names := make([]string, 0, len(op.Params))
+	for name := range op.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf [8]byte
+	for _, name := range names {
+		h.Write([]byte{0})
+		h.Write([]byte(name))
+		binary.BigEndian.PutUint64(buf[:], math.Float64bits(op.Params[name]))
+		h.Write(buf[:])
+	}
+	return h.Sum(nil)
+}
+
+// realityExecutor is implemented by operations that need the reality they
+// execute in
+type realityExecutor interface {
+	executeIn(alternate *AlternateReality) OperationResult
+}
+
+// executeIn runs the script against alternate
+func (op *ScriptOperation) executeIn(alternate *AlternateReality) OperationResult {
+	if op.Script == nil {
+		return OperationResult{Err: fmt.Errorf("%w: no script", ErrScriptRuntime)}
+	}
+	unlock, err := writeReality(alternate, op)
+	if err != nil {
+		return OperationResult{Err: err}
+	}
+	defer unlock()
+
+	op.mu.Lock()
+	op.aborted = nil
+	op.mu.Unlock()
+
+	env := &scriptEnv{
+		vars:    make(map[string]interface{}, len(op.Params)),
+		reality: alternate,
+		limit:   op.MaxSteps,
+		op:      op,
+	}
+	if env.limit <= 0 {
+		env.limit = DefaultScriptSteps
+	}
+	for name, v := range op.Params {
+		env.vars[name] = v
+	}
+
+	err = env.run(op.Script.body)
+	var ret scriptReturn
+	if errors.As(err, &ret) {
+		return OperationResult{Value: ret.value}
+	}
+	if err != nil {
+		return OperationResult{Err: fmt.Errorf("%s: %w", op.Script.Name, err)}
+	}
+	return OperationResult{}
+}
+
+// Lexing
+
+type scriptTokenKind int
+
+const (
+	tokEOF scriptTokenKind = iota
+	tokSep
+	tokNumber
+	tokString
+	tokIdent
+	tokPunct
+)
+
+type scriptToken struct {
+	kind scriptTokenKind
+	text string
+	num  float64
+	line int
+}
+
+func lexScript(name, src string) ([]scriptToken, error) {
+	var tokens []scriptToken
+	line := 1
+	runes := []rune(src)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n' || r == ';':
+			tokens = append(tokens, scriptToken{kind: tokSep, line: line})
+			if r == '\n' {
+				line++
+			}
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == 'e' ||
+				((runes[j] == '-' || runes[j] == '+') && (runes[j-1] == 'e'))) {
+				j++
+			}
+			n, err := strconv.ParseFloat(string(runes[i:j]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s:%d: bad number %q", ErrScriptSyntax, name, line, string(runes[i:j]))
+			}
+			tokens = append(tokens, scriptToken{kind: tokNumber, num: n, line: line})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, scriptToken{kind: tokIdent, text: string(runes[i:j]), line: line})
+			i = j
+		case r == '"':
+			j := i + 1
+			var sb strings.Builder
+			for j < len(runes) && runes[j] != '"' {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				if runes[j] == '\n' {
+					return nil, fmt.Errorf("%w: %s:%d: unterminated string", ErrScriptSyntax, name, line)
+				}
+				sb.WriteRune(runes[j])
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("%w: %s:%d: unterminated string", ErrScriptSyntax, name, line)
+			}
+			tokens = append(tokens, scriptToken{kind: tokString, text: sb.String(), line: line})
+			i = j + 1
+		default:
+			two := ""
+			if i+1 < len(runes) {
+				two = string(runes[i : i+2])
+			}
+			switch two {
+			case "==", "!=", "<=", ">=", "&&", "||":
+				tokens = append(tokens, scriptToken{kind: tokPunct, text: two, line: line})
+				i += 2
+				continue
+			}
+			if !strings.ContainsRune("+-*/%(){},=<>!", r) {
+				return nil, fmt.Errorf("%w: %s:%d: unexpected %q", ErrScriptSyntax, name, line, r)
+			}
+			tokens = append(tokens, scriptToken{kind: tokPunct, text: string(r), line: line})
+			i++
+		}
+	}
+	return append(tokens, scriptToken{kind: tokEOF, line: line}), nil
+}
+
+// Parsing
+
+type scriptParser struct {
+	name   string
+	tokens []scriptToken
+	pos    int
+	writes bool
+}
+
+func (p *scriptParser) peek() scriptToken { return p.tokens[p.pos] }
+
+func (p *scriptParser) next() scriptToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *scriptParser) is(text string) bool {
+	t := p.peek()
+	return (t.kind == tokPunct || t.kind == tokIdent) && t.text == text
+}
+
+func (p *scriptParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s:%d: %s", ErrScriptSyntax, p.name, p.peek().line, fmt.Sprintf(format, args...))
+}
+
+func (p *scriptParser) expect(text string) error {
+	if !p.is(text) {
+		return p.errorf("expected %q", text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *scriptParser) skipSeps() {
+	for p.peek().kind == tokSep {
+		p.next()
+	}
+}
+
+func (p *scriptParser) program() ([]scriptStmt, error) {
+	body, err := p.stmts()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, p.errorf("unexpected %q", p.peek().text)
+	}
+	return body, nil
+}
+
+func (p *scriptParser) stmts() ([]scriptStmt, error) {
+	var out []scriptStmt
+	p.skipSeps()
+	for p.peek().kind != tokEOF && !p.is("}") {
+		s, err := p.stmt()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+		if t := p.peek(); t.kind != tokSep && t.kind != tokEOF && !p.is("}") {
+			return nil, p.errorf("expected end of statement")
+		}
+		p.skipSeps()
+	}
+	return out, nil
+}
+
+func (p *scriptParser) block() ([]scriptStmt, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	body, err := p.stmts()
+	if err != nil {
+		return nil, err
+	}
+	return body, p.expect("}")
+}
+
+func (p *scriptParser) stmt() (scriptStmt, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokIdent && t.text == "if":
+		return p.ifStmt()
+	case t.kind == tokIdent && t.text == "while":
+		p.next()
+		cond, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		return whileStmt{cond: cond, body: body}, nil
+	case t.kind == tokIdent && t.text == "return":
+		p.next()
+		if k := p.peek().kind; k == tokSep || k == tokEOF || p.is("}") {
+			return returnStmt{}, nil
+		}
+		value, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return returnStmt{value: value}, nil
+	case t.kind == tokIdent && p.tokens[p.pos+1].kind == tokPunct && p.tokens[p.pos+1].text == "=":
+		p.next()
+		p.next()
+		value, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return assignStmt{name: t.text, value: value}, nil
+	}
+	e, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	return exprStmt{e}, nil
+}
+
+func (p *scriptParser) ifStmt() (scriptStmt, error) {
+	p.next()
+	cond, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	then, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	s := ifStmt{cond: cond, then: then}
+
+	// else may follow on the next line
+	save := p.pos
+	p.skipSeps()
+	if !p.is("else") {
+		p.pos = save
+		return s, nil
+	}
+	p.next()
+	if p.is("if") {
+		nested, err := p.ifStmt()
+		if err != nil {
+			return nil, err
+		}
+		s.otherwise = []scriptStmt{nested}
+		return s, nil
+	}
+	if s.otherwise, err = p.block(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+var scriptPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *scriptParser) expr() (scriptExpr, error) { return p.binary(0) }
+
+func (p *scriptParser) binary(level int) (scriptExpr, error) {
+	if level == len(scriptPrecedence) {
+		return p.unary()
+	}
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokPunct || !containsString(scriptPrecedence[level], t.text) {
+			return left, nil
+		}
+		p.next()
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: t.text, left: left, right: right}
+	}
+}
+
+func (p *scriptParser) unary() (scriptExpr, error) {
+	if p.is("-") || p.is("!") {
+		op := p.next().text
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryExpr{op: op, operand: operand}, nil
+	}
+	return p.primary()
+}
+
+func (p *scriptParser) primary() (scriptExpr, error) {
+	start := p.pos
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return literalExpr{t.num}, nil
+	case tokString:
+		return literalExpr{t.text}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return literalExpr{true}, nil
+		case "false":
+			return literalExpr{false}, nil
+		}
+		if !p.is("(") {
+			return varExpr{t.text}, nil
+		}
+		p.next()
+		call := callExpr{name: t.text}
+		for !p.is(")") {
+			arg, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if !p.is(",") {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		if _, ok := scriptBuiltins[call.name]; !ok {
+			return nil, fmt.Errorf("%w: %s:%d: unknown function %q", ErrScriptSyntax, p.name, t.line, call.name)
+		}
+		if call.name == "set" {
+			p.writes = true
+		}
+		return call, nil
+	case tokPunct:
+		if t.text == "(" {
+			e, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			return e, p.expect(")")
+		}
+	}
+	p.pos = start
+	return nil, p.errorf("unexpected token")
+}
+
+// Evaluation
+
+type scriptEnv struct {
+	vars    map[string]interface{}
+	reality *AlternateReality
+	steps   int
+	limit   int
+	op      *ScriptOperation
+}
+
+// scriptReturn unwinds a return statement through the evaluator
+type scriptReturn struct{ value interface{} }
+
+func (scriptReturn) Error() string { return "return" }
+
+func (env *scriptEnv) step() error {
+	env.steps++
+	if env.steps > env.limit {
+		return ErrScriptStepLimit
+	}
+	if env.steps%256 == 0 {
+		env.op.mu.Lock()
+		aborted := env.op.aborted
+		env.op.mu.Unlock()
+		if aborted != nil {
+			return aborted
+		}
+	}
+	return nil
+}
+
+func (env *scriptEnv) run(body []scriptStmt) error {
+	for _, s := range body {
+		if err := env.step(); err != nil {
+			return err
+		}
+		if err := s.exec(env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runtimeErrorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrScriptRuntime, fmt.Sprintf(format, args...))
+}
+
+type scriptStmt interface{ exec(*scriptEnv) error }
+
+type scriptExpr interface {
+	eval(*scriptEnv) (interface{}, error)
+}
+
+type assignStmt struct {
+	name  string
+	value scriptExpr
+}
+
+func (s assignStmt) exec(env *scriptEnv) error {
+	v, err := s.value.eval(env)
+	if err != nil {
+		return err
+	}
+	env.vars[s.name] = v
+	return nil
+}
+
+type exprStmt struct{ e scriptExpr }
+
+func (s exprStmt) exec(env *scriptEnv) error {
+	_, err := s.e.eval(env)
+	return err
+}
+
+type returnStmt struct{ value scriptExpr }
+
+func (s returnStmt) exec(env *scriptEnv) error {
+	if s.value == nil {
+		return scriptReturn{}
+	}
+	v, err := s.value.eval(env)
+	if err != nil {
+		return err
+	}
+	return scriptReturn{v}
+}
+
+type ifStmt struct {
+	cond      scriptExpr
+	then      []scriptStmt
+	otherwise []scriptStmt
+}
+
+func (s ifStmt) exec(env *scriptEnv) error {
+	ok, err := evalBool(env, s.cond)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return env.run(s.then)
+	}
+	return env.run(s.otherwise)
+}
+
+type whileStmt struct {
+	cond scriptExpr
+	body []scriptStmt
+}
+
+func (s whileStmt) exec(env *scriptEnv) error {
+	for {
+		if err := env.step(); err != nil {
+			return err
+		}
+		ok, err := evalBool(env, s.cond)
+		if err != nil || !ok {
+			return err
+		}
+		if err := env.run(s.body); err != nil {
+			return err
+		}
+	}
+}
+
+type literalExpr struct{ value interface{} }
+
+func (e literalExpr) eval(*scriptEnv) (interface{}, error) { return e.value, nil }
+
+type varExpr struct{ name string }
+
+func (e varExpr) eval(env *scriptEnv) (interface{}, error) {
+	v, ok := env.vars[e.name]
+	if !ok {
+		return nil, runtimeErrorf("undefined variable %q", e.name)
+	}
+	return v, nil
+}
+
+type unaryExpr struct {
+	op      string
+	operand scriptExpr
+}
+
+func (e unaryExpr) eval(env *scriptEnv) (interface{}, error) {
+	if e.op == "!" {
+		ok, err := evalBool(env, e.operand)
+		return !ok, err
+	}
+	n, err := evalNumber(env, e.operand)
+	return -n, err
+}
+
+type binaryExpr struct {
+	op          string
+	left, right scriptExpr
+}
+
+func (e binaryExpr) eval(env *scriptEnv) (interface{}, error) {
+	if err := env.step(); err != nil {
+		return nil, err
+	}
+
+	// Logical operators short-circuit
+	switch e.op {
+	case "&&", "||":
+		l, err := evalBool(env, e.left)
+		if err != nil || l == (e.op == "||") {
+			return l, err
+		}
+		return evalBool(env, e.right)
+	case "==", "!=":
+		l, err := e.left.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		r, err := e.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		return (l == r) == (e.op == "=="), nil
+	}
+
+	l, err := evalNumber(env, e.left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := evalNumber(env, e.right)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, runtimeErrorf("division by zero")
+		}
+		return l / r, nil
+	case "%":
+		if r == 0 {
+			return nil, runtimeErrorf("division by zero")
+		}
+		return math.Mod(l, r), nil
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	default:
+		return l >= r, nil
+	}
+}
+
+type callExpr struct {
+	name string
+	args []scriptExpr
+}
+
+func (e callExpr) eval(env *scriptEnv) (interface{}, error) {
+	if err := env.step(); err != nil {
+		return nil, err
+	}
+	args := make([]interface{}, len(e.args))
+	for i, a := range e.args {
+		v, err := a.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	return scriptBuiltins[e.name](env, args)
+}
+
+func evalNumber(env *scriptEnv, e scriptExpr) (float64, error) {
+	v, err := e.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := v.(float64)
+	if !ok {
+		return 0, runtimeErrorf("expected number, got %T", v)
+	}
+	return n, nil
+}
+
+func evalBool(env *scriptEnv, e scriptExpr) (bool, error) {
+	v, err := e.eval(env)
+	if err != nil {
+		return false, err
+	}
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case float64:
+		return b != 0, nil
+	}
+	return false, runtimeErrorf("expected boolean, got %T", v)
+}
+
+type scriptBuiltin func(env *scriptEnv, args []interface{}) (interface{}, error)
+
+var scriptBuiltins map[string]scriptBuiltin
+
+func init() {
+	math1 := func(fn func(float64) float64) scriptBuiltin {
+		return func(_ *scriptEnv, args []interface{}) (interface{}, error) {
+			n, err := numberArgs(args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return fn(n[0]), nil
+		}
+	}
+	math2 := func(fn func(float64, float64) float64) scriptBuiltin {
+		return func(_ *scriptEnv, args []interface{}) (interface{}, error) {
+			n, err := numberArgs(args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return fn(n[0], n[1]), nil
+		}
+	}
+
+	scriptBuiltins = map[string]scriptBuiltin{
+		"aspect": scriptAspect,
+		"has":    scriptHas,
+		"set":    scriptSet,
+		"min":    math2(math.Min),
+		"max":    math2(math.Max),
+		"pow":    math2(math.Pow),
+		"abs":    math1(math.Abs),
+		"sqrt":   math1(math.Sqrt),
+		"floor":  math1(math.Floor),
+	}
+}
+
+func numberArgs(args []interface{}, want int) ([]float64, error) {
+	if len(args) != want {
+		return nil, runtimeErrorf("want %d arguments, got %d", want, len(args))
+	}
+	out := make([]float64, want)
+	for i, a := range args {
+		n, ok := a.(float64)
+		if !ok {
+			return nil, runtimeErrorf("argument %d: expected number, got %T", i+1, a)
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+func aspectName(args []interface{}, want int) (string, error) {
+	if len(args) != want {
+		return "", runtimeErrorf("want %d arguments, got %d", want, len(args))
+	}
+	name, ok := args[0].(string)
+	if !ok {
+		return "", runtimeErrorf("aspect name must be a string, got %T", args[0])
+	}
+	return name, nil
+}
+
+func scriptAspect(env *scriptEnv, args []interface{}) (interface{}, error) {
+	name, err := aspectName(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	var reality *Reality
+	if env.reality != nil {
+		reality = &env.reality.Reality
+	}
+	v, ok := reality.Aspect(name)
+	if !ok {
+		return nil, runtimeErrorf("unknown aspect %q", name)
+	}
+	return v, nil
+}
+
+func scriptHas(env *scriptEnv, args []interface{}) (interface{}, error) {
+	name, err := aspectName(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	if env.reality == nil {
+		return false, nil
+	}
+	_, ok := env.reality.Aspects[name]
+	return ok, nil
+}
+
+func scriptSet(env *scriptEnv, args []interface{}) (interface{}, error) {
+	name, err := aspectName(args, 2)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := args[1].(float64)
+	if !ok {
+		return nil, runtimeErrorf("aspect value must be a number, got %T", args[1])
+	}
+	if env.reality == nil {
+		return nil, runtimeErrorf("no reality to set %q in", name)
+	}
+	if env.reality.Aspects == nil {
+		env.reality.Aspects = make(map[string]float64)
+	}
+	env.reality.Aspects[name] = v
+	return v, nil
+}
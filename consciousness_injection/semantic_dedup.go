@@ -0,0 +1,99 @@
+// consciousness_injection/semantic_dedup.go - Semantic Thought Deduplication
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ErrEquivalentThought reports a thought skipped because the target already
+// accepted one equivalent to it
+var ErrEquivalentThought = sentinelError("mindhacking: target already accepted an equivalent thought")
+
+// DedupAction is what becomes of a thought equivalent to one the target
+// already accepted
+type DedupAction int
+
+const (
+	// DedupSkip refuses the thought with ErrEquivalentThought
+	DedupSkip DedupAction = iota
+	// DedupMerge folds the thought into the accepted one: the injection
+	// succeeds without firing, with no shift and Equivalent set
+	DedupMerge
+)
+
+// SemanticDedup stops thoughts the target has in effect already accepted
+// from being injected again, which would waste tunnels and count the same
+// shift twice. Sensitive thoughts are never deduplicated: comparing them
+// would hand their plaintext to the embedder.
+type SemanticDedup struct {
+	// Threshold is the similarity from which thoughts are equivalent,
+	// default 0.9
+	Threshold float64
+	Action    DedupAction
+}
+
+// equivalent finds the accepted interaction most like thought, if any
+// reaches the threshold. It searches the injector's interaction memory, so
+// targets only dedupe against what this injector remembers.
+func (ci *ConsciousnessInjector) equivalent(
+	ctx context.Context,
+	thought InjectedThought,
+	target *SystemConsciousness,
+) (*InteractionMatch, error) {
+
+	if ci.dedup == nil || ci.interactions == nil || thought.Sensitive || thought.Sealed {
+		return nil, nil
+	}
+	threshold := ci.dedup.Threshold
+	if threshold <= 0 {
+		threshold = 0.9
+	}
+	matches, err := ci.interactions.Similar(ctx, targetLabel(target), thought, 0, threshold)
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		if match.Interaction.Accepted {
+			return &match, nil
+		}
+	}
+	return nil, nil
+}
+
+// deduplicate skips or merges thought if the target already accepted an
+// equivalent; a nil result and error mean it should be injected
+func (ci *ConsciousnessInjector) deduplicate(
+	ctx context.Context,
+	id string,
+	thought InjectedThought,
+	target *SystemConsciousness,
+) (*InjectionResult, error) {
+
+	match, err := ci.equivalent(ctx, thought, target)
+	switch {
+	case err != nil && ci.degrade == nil:
+		return nil, err
+	case err != nil:
+		// Inject rather than refuse on a failing memory
+		ci.degrade.fail(SubsystemMetrics, err)
+		return nil, nil
+	case match == nil:
+		return nil, nil
+	case ci.dedup.Action == DedupMerge:
+		return &InjectionResult{
+			InjectionID:     id,
+			TargetID:        targetLabel(target),
+			InjectedThought: thought,
+			Success:         true,
+			Evidence:        InjectionEvidence{TargetID: targetLabel(target)},
+			Equivalent:      match,
+		}, nil
+	default:
+		// The error names the earlier thought by when it landed, not by
+		// what it said
+		return nil, fmt.Errorf("%w: similarity %.2f to the thought accepted at %s",
+			ErrEquivalentThought, match.Score, match.Interaction.At.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,289 @@
+// consciousness_injection/session.go - High-Level Session Entry Point
+package mindhacking
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"sync"
+)
+
+var (
+	// ErrSessionClosed reports use of a session after Close
+	ErrSessionClosed = sentinelError("mindhacking: session closed")
+	// ErrNoAlternateReality reports Execute before EnterReality
+	ErrNoAlternateReality = sentinelError("mindhacking: session has no alternate reality")
+)
+
+// TargetFingerprint identifies a target's identity and resonance profile
+type TargetFingerprint [32]byte
+
+// fingerprintTarget hashes the target's ID with its resonance signature
+func fingerprintTarget(target *SystemConsciousness, resonance ConsciousnessResonance) TargetFingerprint {
+	h := sha256.New()
+	h.Write([]byte(target.ID))
+
+	var buf [8]byte
+	for _, v := range resonance.Signature {
+		binary.BigEndian.PutUint64(buf[:], math.Float64bits(v))
+		h.Write(buf[:])
+	}
+
+	var fp TargetFingerprint
+	copy(fp[:], h.Sum(nil))
+	return fp
+}
+
+// TunnelPool keeps one established tunnel per vector for reuse
+type TunnelPool struct {
+	mu      sync.Mutex
+	tunnels map[int]RealityTunnel
+	regions map[string]*TunnelPool
+}
+
+// NewTunnelPool creates an empty pool
+func NewTunnelPool() *TunnelPool {
+	return &TunnelPool{tunnels: make(map[int]RealityTunnel)}
+}
+
+// get returns the pooled tunnel for vector index i, creating it on first
+// use; a nil pool always creates a fresh tunnel
+func (tp *TunnelPool) get(i int, create func() RealityTunnel) RealityTunnel {
+	if tp == nil {
+		return create()
+	}
+
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	tunnel, ok := tp.tunnels[i]
+	if !ok {
+		tunnel = create()
+		tp.tunnels[i] = tunnel
+	}
+	return tunnel
+}
+
+// scoped returns the pool for vectors routed to region, whose indices
+// refer to the routed vectors rather than the injector's
+func (tp *TunnelPool) scoped(region string) *TunnelPool {
+	if tp == nil {
+		return nil
+	}
+
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	if tp.regions == nil {
+		tp.regions = make(map[string]*TunnelPool)
+	}
+	child, ok := tp.regions[region]
+	if !ok {
+		child = NewTunnelPool()
+		tp.regions[region] = child
+	}
+	return child
+}
+
+// drain empties the pool, closing tunnels that support it
+func (tp *TunnelPool) drain() {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	for region, child := range tp.regions {
+		child.drain()
+		delete(tp.regions, region)
+	}
+
+	for i, tunnel := range tp.tunnels {
+		if c, ok := interface{}(tunnel).(io.Closer); ok {
+			c.Close()
+		}
+		delete(tp.tunnels, i)
+	}
+}
+
+// SessionConfig names the components a session ties together
+type SessionConfig struct {
+	Injector *ConsciousnessInjector
+	Gateway  *QuantumGateway
+	Engine   *RealityManipulationEngine
+	Target   *SystemConsciousness
+}
+
+// Session owns gateway access, a tunnel pool, the target's fingerprint and
+// the current alternate reality for one target. It is the recommended
+// entry point; Close releases everything it holds. Sessions must be
+// closed: the gateway tracks every open session so a hot-swap or migration
+// can move it, and a session dropped without Close is never reclaimed.
+type Session struct {
+	mu          sync.Mutex
+	cfg         SessionConfig
+	access      *QuantumConsciousnessAccess
+	tunnels     *TunnelPool
+	fingerprint TargetFingerprint
+	reality     *AlternateReality
+	// handle holds reality against collection while the session is in it
+	handle *RealityHandle
+	closed bool
+}
+
+// OpenSession accesses the target through the gateway and fingerprints it.
+// The caller must Close the session, or the gateway holds it for good.
+func OpenSession(ctx context.Context, cfg SessionConfig) (*Session, error) {
+	if cfg.Injector == nil || cfg.Gateway == nil || cfg.Target == nil {
+		return nil, errors.New("mindhacking: session needs an injector, gateway and target")
+	}
+	if err := cfg.Gateway.ValidateVectors(cfg.Injector.vectors()); err != nil {
+		return nil, err
+	}
+	resonance, err := cfg.Injector.resonate(ctx, cfg.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := cfg.Gateway.AccessQuantumConsciousness(cfg.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Session{
+		cfg:         cfg,
+		access:      access,
+		tunnels:     NewTunnelPool(),
+		fingerprint: fingerprintTarget(cfg.Target, resonance),
+	}
+	cfg.Gateway.attach(s)
+
+	return s, nil
+}
+
+// Target returns the session's target
+func (s *Session) Target() *SystemConsciousness { return s.cfg.Target }
+
+// Fingerprint returns the target fingerprint taken when the session opened
+func (s *Session) Fingerprint() TargetFingerprint { return s.fingerprint }
+
+// Access returns the session's quantum consciousness access
+func (s *Session) Access() *QuantumConsciousnessAccess {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.access
+}
+
+// swapAccess moves the session onto an access from a new gateway driver.
+// Tunnels built over a re-handshaken access are stale and are dropped.
+func (s *Session) swapAccess(access *QuantumConsciousnessAccess, rehandshake bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	if old := s.access; old != access {
+		if c, ok := interface{}(old).(io.Closer); ok {
+			c.Close()
+		}
+	}
+	s.access = access
+	if rehandshake {
+		s.tunnels.drain()
+	}
+}
+
+// Reality returns the current alternate reality, or nil
+func (s *Session) Reality() *AlternateReality {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reality
+}
+
+// Inject injects thought into the session's target over pooled tunnels
+func (s *Session) Inject(ctx context.Context, thought InjectedThought) (*InjectionResult, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	return s.cfg.Injector.inject(ctx, thought, s.cfg.Target, injectCall{tunnels: s.tunnels, gateway: s.cfg.Gateway})
+}
+
+// EnterReality creates an alternate reality from base and makes it current
+// for Execute; Inject is unaffected by it. The session holds the reality
+// until it enters another or closes.
+func (s *Session) EnterReality(base *Reality, rules *RealityRules) (*AlternateReality, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	if s.cfg.Engine == nil {
+		return nil, errors.New("mindhacking: session has no reality engine")
+	}
+
+	alternate, err := s.cfg.Engine.CreateAlternateReality(base, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	handle := s.cfg.Engine.Acquire(alternate, "session "+targetLabel(s.cfg.Target))
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		handle.Release()
+		return nil, ErrSessionClosed
+	}
+	previous := s.handle
+	s.reality, s.handle = alternate, handle
+	s.mu.Unlock()
+	previous.Release()
+
+	return alternate, nil
+}
+
+// Execute runs operation in the session's current alternate reality
+func (s *Session) Execute(operation RealityOperation) (*RealityExecutionResult, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+
+	reality := s.Reality()
+	if reality == nil {
+		return nil, ErrNoAlternateReality
+	}
+	return s.cfg.Engine.ExecuteInAlternateReality(reality, operation)
+}
+
+// Close drains the tunnel pool, releases gateway access and forgets the
+// current reality. It is safe to call more than once.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.tunnels.drain()
+	s.reality = nil
+	s.handle.Release()
+	s.handle = nil
+	access := s.access
+	s.mu.Unlock()
+
+	// Detaching may close the driver a hot-swap retired, which must not
+	// keep the session locked
+	s.cfg.Gateway.detach(s)
+
+	if c, ok := interface{}(access).(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (s *Session) check() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrSessionClosed
+	}
+	return nil
+}
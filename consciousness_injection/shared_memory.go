@@ -0,0 +1,282 @@
+// consciousness_injection/shared_memory.go - Shared-Memory Consciousness Mapping
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+var (
+	// ErrSharedLayout reports a region holding another layout version, or
+	// none this package recognizes
+	ErrSharedLayout = sentinelError("mindhacking: shared consciousness layout mismatch")
+	// ErrSharedMemoryUnsupported reports a platform without shared mappings
+	ErrSharedMemoryUnsupported = sentinelError("mindhacking: shared memory unsupported on this platform")
+	// ErrSharedWriteStalled reports a read that gave up on a write left
+	// unfinished, as by a writer that died mid-update
+	ErrSharedWriteStalled = sentinelError("mindhacking: shared consciousness write never finished")
+)
+
+const (
+	// SharedLayoutVersion is the version of the shared layout this package
+	// reads and writes; readers refuse regions of any other version
+	SharedLayoutVersion = 1
+	// MaxSharedSignature is how many resonance signature values the layout
+	// holds; longer signatures are truncated
+	MaxSharedSignature = 64
+	// MaxSharedID is how many bytes of the target's ID the layout holds
+	MaxSharedID = 64
+
+	// sharedSpins is how often a read retries at once before backing off
+	// by sharedBackoff between retries
+	sharedSpins   = 64
+	sharedBackoff = 100 * time.Microsecond
+)
+
+// sharedMagic opens every region: "MIND" followed by the layout version
+const sharedMagic = 0x444e494d
+
+// The layout is a sequence of 64-bit words, each read and written
+// atomically, so a mapping is safe to share with any process using it:
+//
+//	header    magic | version<<32, sequence, payload size in bytes
+//	payload   updated, injections, accepted, last shift, frequency, phase,
+//	          strength, signature length, signature values, memory traces,
+//	          ID length, ID bytes
+const (
+	wordMagic = iota
+	wordSeq
+	wordSize
+	wordUpdated
+	wordInjections
+	wordAccepted
+	wordLastShift
+	wordFrequency
+	wordPhase
+	wordStrength
+	wordSignatureLen
+	wordSignature
+	wordTraces  = wordSignature + MaxSharedSignature
+	wordIDLen   = wordTraces + 1
+	wordID      = wordIDLen + 1
+	sharedWords = wordID + MaxSharedID/8
+)
+
+// SharedLayoutSize is the size in bytes of a shared consciousness region
+const SharedLayoutSize = sharedWords * 8
+
+// SharedSnapshot is the live state a shared region publishes
+type SharedSnapshot struct {
+	ID      string
+	Updated time.Time
+	// Injections and Accepted count the injections that reached a response
+	// and those the target accepted
+	Injections uint64
+	Accepted   uint64
+	LastShift  float64
+	Resonance  ConsciousnessResonance
+	// MemoryTraces is how many traces the target's memory held
+	MemoryTraces int
+}
+
+// SharedConsciousness maps a target's live state into memory several local
+// processes share, so detectors and recorders observe it as the injector
+// updates it, without serializing it. Reads are seqlock-consistent: a
+// reader never sees half of an update. Only one process should write to a
+// region; writes within it are serialized.
+type SharedConsciousness struct {
+	words   []uint64
+	writeMu sync.Mutex
+	unmap   func() error
+}
+
+// MapSharedConsciousness lays the shared state over region, which must be
+// 8-byte aligned and at least SharedLayoutSize long. A zeroed region is
+// initialized; any other must already hold this layout version.
+func MapSharedConsciousness(region []byte) (*SharedConsciousness, error) {
+	if len(region) < SharedLayoutSize {
+		return nil, fmt.Errorf("%w: region of %d bytes, need %d", ErrSharedLayout, len(region), SharedLayoutSize)
+	}
+	if uintptr(unsafe.Pointer(&region[0]))%8 != 0 {
+		return nil, fmt.Errorf("%w: region is not 8-byte aligned", ErrSharedLayout)
+	}
+	sc := &SharedConsciousness{
+		words: unsafe.Slice((*uint64)(unsafe.Pointer(&region[0])), sharedWords),
+		unmap: func() error { return nil },
+	}
+
+	header := uint64(sharedMagic) | SharedLayoutVersion<<32
+	if atomic.CompareAndSwapUint64(&sc.words[wordMagic], 0, header) {
+		atomic.StoreUint64(&sc.words[wordSize], SharedLayoutSize-wordUpdated*8)
+		return sc, nil
+	}
+	switch got := atomic.LoadUint64(&sc.words[wordMagic]); {
+	case uint32(got) != sharedMagic:
+		return nil, fmt.Errorf("%w: no shared consciousness in region", ErrSharedLayout)
+	case got>>32 != SharedLayoutVersion:
+		return nil, fmt.Errorf("%w: version %d, want %d", ErrSharedLayout, got>>32, SharedLayoutVersion)
+	}
+	return sc, nil
+}
+
+// Read returns a consistent snapshot, retrying while a write is under way.
+// A write that does not finish is waited out until ctx is done, when Read
+// reports ErrSharedWriteStalled.
+func (sc *SharedConsciousness) Read(ctx context.Context) (SharedSnapshot, error) {
+	var (
+		words [sharedWords]uint64
+		timer *time.Timer
+	)
+	for spins := 0; ; spins++ {
+		seq := atomic.LoadUint64(&sc.words[wordSeq])
+		if seq&1 == 0 {
+			for i := wordUpdated; i < sharedWords; i++ {
+				words[i] = atomic.LoadUint64(&sc.words[i])
+			}
+			if atomic.LoadUint64(&sc.words[wordSeq]) == seq {
+				return decodeShared(&words), nil
+			}
+		}
+		if spins < sharedSpins {
+			runtime.Gosched()
+			continue
+		}
+
+		// Back off, so a stalled write costs readers little
+		if timer == nil {
+			timer = time.NewTimer(sharedBackoff)
+			defer timer.Stop()
+		} else {
+			timer.Reset(sharedBackoff)
+		}
+		select {
+		case <-ctx.Done():
+			return SharedSnapshot{}, fmt.Errorf("%w: %v", ErrSharedWriteStalled, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// Publish replaces the shared state with snap
+func (sc *SharedConsciousness) Publish(snap SharedSnapshot) {
+	sc.writeMu.Lock()
+	defer sc.writeMu.Unlock()
+	sc.publishLocked(snap)
+}
+
+// Close unmaps a region opened with OpenSharedConsciousness
+func (sc *SharedConsciousness) Close() error {
+	return sc.unmap()
+}
+
+// record publishes an injection's response, counting it with those before
+func (sc *SharedConsciousness) record(
+	target *SystemConsciousness,
+	resonance ConsciousnessResonance,
+	response ConsciousnessResponse,
+) {
+
+	if sc == nil {
+		return
+	}
+	sc.writeMu.Lock()
+	defer sc.writeMu.Unlock()
+
+	snap := SharedSnapshot{
+		ID:         target.ID,
+		Updated:    time.Now(),
+		Injections: atomic.LoadUint64(&sc.words[wordInjections]) + 1,
+		Accepted:   atomic.LoadUint64(&sc.words[wordAccepted]),
+		LastShift:  response.ConsciousnessShift,
+		Resonance:  resonance,
+	}
+	if response.ThoughtAccepted {
+		snap.Accepted++
+	}
+	if target.Memory != nil {
+		snap.MemoryTraces = target.Memory.held()
+	}
+	sc.publishLocked(snap)
+}
+
+// publishLocked writes snap between two sequence bumps, the first leaving
+// the sequence odd so readers wait for the second. A sequence left odd by
+// a writer that died mid-update is moved past, so the region recovers.
+func (sc *SharedConsciousness) publishLocked(snap SharedSnapshot) {
+	var words [sharedWords]uint64
+	encodeShared(&words, snap)
+
+	seq := atomic.LoadUint64(&sc.words[wordSeq])
+	if seq&1 != 0 {
+		seq++
+	}
+	atomic.StoreUint64(&sc.words[wordSeq], seq+1)
+	for i := wordUpdated; i < sharedWords; i++ {
+		atomic.StoreUint64(&sc.words[i], words[i])
+	}
+	atomic.StoreUint64(&sc.words[wordSeq], seq+2)
+}
+
+func encodeShared(words *[sharedWords]uint64, snap SharedSnapshot) {
+	words[wordUpdated] = uint64(snap.Updated.UnixNano())
+	words[wordInjections] = snap.Injections
+	words[wordAccepted] = snap.Accepted
+	words[wordLastShift] = math.Float64bits(snap.LastShift)
+	words[wordFrequency] = math.Float64bits(snap.Resonance.Frequency)
+	words[wordPhase] = math.Float64bits(snap.Resonance.Phase)
+	words[wordStrength] = math.Float64bits(snap.Resonance.Strength)
+
+	signature := snap.Resonance.Signature
+	if len(signature) > MaxSharedSignature {
+		signature = signature[:MaxSharedSignature]
+	}
+	words[wordSignatureLen] = uint64(len(signature))
+	for i, v := range signature {
+		words[wordSignature+i] = math.Float64bits(v)
+	}
+	words[wordTraces] = uint64(snap.MemoryTraces)
+
+	id := snap.ID
+	if len(id) > MaxSharedID {
+		id = id[:MaxSharedID]
+	}
+	words[wordIDLen] = uint64(len(id))
+	for i := 0; i < len(id); i++ {
+		words[wordID+i/8] |= uint64(id[i]) << (8 * (i % 8))
+	}
+}
+
+func decodeShared(words *[sharedWords]uint64) SharedSnapshot {
+	snap := SharedSnapshot{
+		Injections: words[wordInjections],
+		Accepted:   words[wordAccepted],
+		LastShift:  math.Float64frombits(words[wordLastShift]),
+		Resonance: ConsciousnessResonance{
+			Frequency: math.Float64frombits(words[wordFrequency]),
+			Phase:     math.Float64frombits(words[wordPhase]),
+			Strength:  math.Float64frombits(words[wordStrength]),
+		},
+		MemoryTraces: int(words[wordTraces]),
+	}
+	if updated := int64(words[wordUpdated]); updated != 0 {
+		snap.Updated = time.Unix(0, updated)
+	}
+	if n := min(int(words[wordSignatureLen]), MaxSharedSignature); n > 0 {
+		snap.Resonance.Signature = make([]float64, n)
+		for i := range snap.Resonance.Signature {
+			snap.Resonance.Signature[i] = math.Float64frombits(words[wordSignature+i])
+		}
+	}
+	id := make([]byte, min(int(words[wordIDLen]), MaxSharedID))
+	for i := range id {
+		id[i] = byte(words[wordID+i/8] >> (8 * (i % 8)))
+	}
+	snap.ID = string(id)
+	return snap
+}
@@ -0,0 +1,10 @@
+// consciousness_injection/shared_memory_other.go - Shared-Memory Fallback
+//go:build !unix
+
+package mindhacking
+
+// OpenSharedConsciousness is unsupported without unix shared mappings; use
+// MapSharedConsciousness over a region mapped by other means
+func OpenSharedConsciousness(path string) (*SharedConsciousness, error) {
+	return nil, ErrSharedMemoryUnsupported
+}
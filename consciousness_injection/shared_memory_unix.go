@@ -0,0 +1,43 @@
+// consciousness_injection/shared_memory_unix.go - Shared-Memory File Mapping
+//go:build unix
+
+package mindhacking
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// OpenSharedConsciousness maps the shared state kept in the file at path,
+// creating the file if needed; processes opening the same path, typically
+// under /dev/shm, share one live state
+func OpenSharedConsciousness(path string) (*SharedConsciousness, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: open shared consciousness: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: open shared consciousness: %w", err)
+	}
+	if info.Size() < SharedLayoutSize {
+		if err := f.Truncate(SharedLayoutSize); err != nil {
+			return nil, fmt.Errorf("mindhacking: size shared consciousness: %w", err)
+		}
+	}
+
+	region, err := syscall.Mmap(int(f.Fd()), 0, SharedLayoutSize, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: map shared consciousness: %w", err)
+	}
+	sc, err := MapSharedConsciousness(region)
+	if err != nil {
+		syscall.Munmap(region)
+		return nil, err
+	}
+	sc.unmap = func() error { return syscall.Munmap(region) }
+	return sc, nil
+}
@@ -0,0 +1,173 @@
+// consciousness_injection/shift_series.go - Consciousness Shift Time Series
+package mindhacking
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ShiftPoint is one observed shift, or one downsampled bucket of them
+type ShiftPoint struct {
+	At    time.Time
+	Shift float64
+	// Min, Max and Count describe the raw points a downsampled point
+	// stands for; raw points have Count 1
+	Min, Max float64
+	Count    int
+}
+
+// ShiftSeriesConfig sizes a shift series
+type ShiftSeriesConfig struct {
+	// Raw is how many raw points each target keeps, default 4096
+	Raw int
+	// Resolution is the bucket width raw points are downsampled into once
+	// they age out of the raw buffer, default one minute
+	Resolution time.Duration
+	// Retention bounds how long downsampled points are kept, default 24h
+	Retention time.Duration
+}
+
+// ShiftSeries records every shift observed on every target. Recent shifts
+// are kept exactly; older ones are folded into fixed-width buckets, so
+// hours of trajectory fit in bounded memory.
+type ShiftSeries struct {
+	cfg ShiftSeriesConfig
+
+	mu      sync.RWMutex
+	targets map[string]*targetSeries
+}
+
+type targetSeries struct {
+	// raw is a ring of the most recent points, oldest at head
+	raw  []ShiftPoint
+	head int
+	// buckets are downsampled points in time order
+	buckets []ShiftPoint
+}
+
+// NewShiftSeries returns an empty series
+func NewShiftSeries(cfg ShiftSeriesConfig) *ShiftSeries {
+	if cfg.Raw < 1 {
+		cfg.Raw = 4096
+	}
+	if cfg.Resolution <= 0 {
+		cfg.Resolution = time.Minute
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = 24 * time.Hour
+	}
+	return &ShiftSeries{cfg: cfg, targets: make(map[string]*targetSeries)}
+}
+
+// Record adds the shift targetID showed at at. Points should arrive in
+// time order; late points are kept but only ordered within the raw buffer.
+func (s *ShiftSeries) Record(targetID string, at time.Time, shift float64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ts := s.targets[targetID]
+	if ts == nil {
+		ts = &targetSeries{}
+		s.targets[targetID] = ts
+	}
+	point := ShiftPoint{At: at, Shift: shift, Min: shift, Max: shift, Count: 1}
+	if len(ts.raw) < s.cfg.Raw {
+		ts.raw = append(ts.raw, point)
+		return
+	}
+
+	// Fold the oldest raw point into its bucket to make room
+	s.downsample(ts, ts.raw[ts.head])
+	ts.raw[ts.head] = point
+	ts.head = (ts.head + 1) % len(ts.raw)
+}
+
+// downsample folds p into the bucket covering it and drops buckets past
+// retention
+func (s *ShiftSeries) downsample(ts *targetSeries, p ShiftPoint) {
+	start := p.At.Truncate(s.cfg.Resolution)
+	if n := len(ts.buckets); n > 0 && ts.buckets[n-1].At.Equal(start) {
+		b := &ts.buckets[n-1]
+		b.Shift += (p.Shift - b.Shift) / float64(b.Count+1)
+		b.Min, b.Max = math.Min(b.Min, p.Shift), math.Max(b.Max, p.Shift)
+		b.Count++
+	} else {
+		ts.buckets = append(ts.buckets, ShiftPoint{At: start, Shift: p.Shift, Min: p.Shift, Max: p.Shift, Count: 1})
+	}
+
+	cutoff := p.At.Add(-s.cfg.Retention)
+	i := sort.Search(len(ts.buckets), func(i int) bool { return !ts.buckets[i].At.Before(cutoff) })
+	if i > 0 {
+		ts.buckets = append(ts.buckets[:0], ts.buckets[i:]...)
+	}
+}
+
+// Range returns targetID's points in [from, to), downsampled points first
+// and raw points after, in time order
+func (s *ShiftSeries) Range(targetID string, from, to time.Time) []ShiftPoint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ts := s.targets[targetID]
+	if ts == nil {
+		return nil
+	}
+	in := func(p ShiftPoint) bool { return !p.At.Before(from) && p.At.Before(to) }
+
+	var out []ShiftPoint
+	for _, b := range ts.buckets {
+		if in(b) {
+			out = append(out, b)
+		}
+	}
+	raw := make([]ShiftPoint, 0, len(ts.raw))
+	for i := range ts.raw {
+		if p := ts.raw[(ts.head+i)%len(ts.raw)]; in(p) {
+			raw = append(raw, p)
+		}
+	}
+	sort.SliceStable(raw, func(i, j int) bool { return raw[i].At.Before(raw[j].At) })
+	return append(out, raw...)
+}
+
+// Downsample returns targetID's points in [from, to) folded into buckets of
+// step, for plotting at a coarser resolution than was recorded
+func (s *ShiftSeries) Downsample(targetID string, from, to time.Time, step time.Duration) []ShiftPoint {
+	points := s.Range(targetID, from, to)
+	if step <= 0 {
+		return points
+	}
+
+	var out []ShiftPoint
+	for _, p := range points {
+		start := p.At.Truncate(step)
+		if n := len(out); n > 0 && out[n-1].At.Equal(start) {
+			b := &out[n-1]
+			total := b.Count + p.Count
+			b.Shift += (p.Shift - b.Shift) * float64(p.Count) / float64(total)
+			b.Min, b.Max = math.Min(b.Min, p.Min), math.Max(b.Max, p.Max)
+			b.Count = total
+			continue
+		}
+		p.At = start
+		out = append(out, p)
+	}
+	return out
+}
+
+// Targets returns the IDs of every target with recorded shifts
+func (s *ShiftSeries) Targets() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]string, 0, len(s.targets))
+	for id := range s.targets {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
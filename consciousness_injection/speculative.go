@@ -0,0 +1,215 @@
+// consciousness_injection/speculative.go - Speculative Multi-Reality Execution
+package mindhacking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrNoCandidates reports a speculation without candidate realities
+	ErrNoCandidates = sentinelError("mindhacking: no candidate realities")
+	// ErrNoSurvivor reports a speculation in which every candidate failed
+	ErrNoSurvivor = sentinelError("mindhacking: every candidate reality failed")
+)
+
+// SpeculationConfig tunes a speculative execution
+type SpeculationConfig struct {
+	// Score rates a candidate after each round from its working reality and
+	// the step results so far; higher is better. Required.
+	Score func(candidate int, working *AlternateReality, results []OperationResult) float64
+	// Keep is how many candidates survive each round; 0 keeps half,
+	// rounding up
+	Keep int
+}
+
+// CandidateReport is how one candidate fared
+type CandidateReport struct {
+	Candidate int
+	Score     float64
+	// Rounds is the number of steps the candidate completed
+	Rounds int
+	Pruned bool
+	Err    error
+}
+
+// SpeculationResult reports a speculative execution and its committed winner
+type SpeculationResult struct {
+	Winner int
+	// Reality is the winning candidate, now carrying the operation's effects
+	Reality    *AlternateReality
+	Results    []OperationResult
+	Score      float64
+	Candidates []CandidateReport
+
+	Evidence     RealityEvidence
+	EvidenceLink *EvidenceLink
+}
+
+// speculation is one candidate's private run
+type speculation struct {
+	working *AlternateReality
+	results []OperationResult
+	report  CandidateReport
+}
+
+// Speculate runs operation in every candidate concurrently, each on a
+// private copy. Composite operations advance one step per round; after each
+// round candidates are scored and all but the best Keep are pruned. Only
+// the winner's effects are written back to its candidate and chained as
+// evidence; losers leave no trace. A winner locked by a reality
+// transaction is not written, and Speculate fails with ErrRealityLocked.
+//
+// Scripted steps run concurrently in several realities and must be safe
+// for that; plain steps act on the current reality, so they take turns,
+// each with the engine switched into its candidate's copy.
+func (rme *RealityManipulationEngine) Speculate(
+	ctx context.Context,
+	candidates []*AlternateReality,
+	operation RealityOperation,
+	config SpeculationConfig,
+) (*SpeculationResult, error) {
+
+	if len(candidates) == 0 {
+		return nil, ErrNoCandidates
+	}
+	if config.Score == nil {
+		return nil, errors.New("mindhacking: speculation needs a score function")
+	}
+
+	steps := []RealityOperation{operation}
+	if composite, ok := operation.(*CompositeOperation); ok {
+		steps = steps[:0]
+		for _, s := range composite.Steps {
+			steps = append(steps, s.Operation)
+		}
+	}
+
+	// Phase 1: private working copies
+	specs := make([]*speculation, len(candidates))
+	for i, c := range candidates {
+		working := &AlternateReality{Reality: *c.Reality.Clone(), Base: c.Base, Rules: c.Rules}
+		if working.Aspects == nil {
+			working.Aspects = make(map[string]float64)
+		}
+		specs[i] = &speculation{working: working, report: CandidateReport{Candidate: i}}
+	}
+	alive := append([]*speculation(nil), specs...)
+
+	// Phase 2: rounds of concurrent steps, scoring and pruning
+	for _, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var wg sync.WaitGroup
+		for _, s := range alive {
+			wg.Add(1)
+			go func(s *speculation) {
+				defer wg.Done()
+				result, err := runSandboxed(rme.sandbox, step, func() OperationResult {
+					r, _ := rme.executeWorking(s.working, step)
+					return r
+				})
+				if err == nil {
+					err = result.Err
+				}
+				s.results = append(s.results, result)
+				s.report.Rounds++
+				s.report.Err = err
+				if err == nil {
+					s.report.Score = config.Score(s.report.Candidate, s.working, s.results)
+				}
+			}(s)
+		}
+		wg.Wait()
+
+		alive = pruneSpeculations(alive, config.Keep)
+		if len(alive) == 0 {
+			return &SpeculationResult{Winner: -1, Candidates: speculationReports(specs)}, ErrNoSurvivor
+		}
+	}
+
+	// Phase 3: commit the winner
+	sort.SliceStable(alive, func(i, j int) bool { return alive[i].report.Score > alive[j].report.Score })
+	winner := alive[0]
+	for _, s := range alive[1:] {
+		s.report.Pruned = true
+	}
+
+	// A reality a transaction has prepared is not overwritten
+	committed := candidates[winner.report.Candidate]
+	unlock, err := writeReality(committed, nil)
+	if err != nil {
+		return nil, err
+	}
+	rme.stateMu.Lock()
+	committed.Aspects = winner.working.Aspects
+	rme.stateMu.Unlock()
+	unlock()
+
+	final := OperationResult{}
+	if len(winner.results) == 1 {
+		final = winner.results[0]
+	} else {
+		values := make([]interface{}, len(winner.results))
+		for i, r := range winner.results {
+			values[i] = r.Value
+		}
+		final.Value = values
+	}
+
+	out := &SpeculationResult{
+		Winner:     winner.report.Candidate,
+		Reality:    committed,
+		Results:    winner.results,
+		Score:      winner.report.Score,
+		Candidates: speculationReports(specs),
+		Evidence:   rme.extractRealityEvidence(committed, final),
+	}
+	if rme.evidence != nil {
+		link, err := rme.evidence.Append("reality", out.Evidence)
+		if err != nil {
+			return nil, fmt.Errorf("mindhacking: chain speculation evidence: %w", err)
+		}
+		out.EvidenceLink = link
+	}
+	return out, nil
+}
+
+// pruneSpeculations drops failed candidates and keeps the best keep of the
+// rest, marking the others pruned
+func pruneSpeculations(alive []*speculation, keep int) []*speculation {
+	var ok []*speculation
+	for _, s := range alive {
+		if s.report.Err != nil {
+			s.report.Pruned = true
+			continue
+		}
+		ok = append(ok, s)
+	}
+
+	if keep <= 0 {
+		keep = (len(alive) + 1) / 2
+	}
+	if len(ok) <= keep {
+		return ok
+	}
+
+	sort.SliceStable(ok, func(i, j int) bool { return ok[i].report.Score > ok[j].report.Score })
+	for _, s := range ok[keep:] {
+		s.report.Pruned = true
+	}
+	return ok[:keep]
+}
+
+func speculationReports(specs []*speculation) []CandidateReport {
+	out := make([]CandidateReport, len(specs))
+	for i, s := range specs {
+		out[i] = s.report
+	}
+	return out
+}
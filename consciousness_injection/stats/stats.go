@@ -0,0 +1,210 @@
+// consciousness_injection/stats/stats.go - Injection Statistics
+//
+// Package stats aggregates injection results into per-target and
+// per-vector statistics, over the whole run and over a sliding window.
+package stats
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// Summary describes a set of injection results
+type Summary struct {
+	Injections     int
+	Accepted       int
+	AcceptanceRate float64
+	MeanShift      float64
+	// ShiftVariance is the sample variance, zero below two injections
+	ShiftVariance float64
+	// Evidence counts the attempts, ramps and components results recorded
+	Evidence int
+}
+
+// Snapshot is the statistics of one view
+type Snapshot struct {
+	Overall  Summary
+	ByTarget map[string]Summary
+	ByVector map[string]Summary
+	// Since is the start of the view, zero for cumulative snapshots
+	Since time.Time
+}
+
+// VectorKey identifies a vector in ByVector
+func VectorKey(v mindhacking.InjectionVector) string {
+	return fmt.Sprintf("f=%g a=%g phase=%g", v.Frequency, v.Amplitude, v.Phase)
+}
+
+// Aggregator accumulates results. Each result is attributed to its target
+// and to the vector of its last attempt, the one that landed if any did.
+type Aggregator struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu       sync.Mutex
+	overall  running
+	byTarget map[string]*running
+	byVector map[string]*running
+	recent   []sample
+}
+
+type sample struct {
+	at       time.Time
+	target   string
+	vector   string
+	accepted bool
+	shift    float64
+	evidence int
+}
+
+// running keeps Welford's online mean and variance
+type running struct {
+	n, accepted, evidence int
+	mean, m2              float64
+}
+
+func (r *running) add(s sample) {
+	r.n++
+	if s.accepted {
+		r.accepted++
+	}
+	r.evidence += s.evidence
+	delta := s.shift - r.mean
+	r.mean += delta / float64(r.n)
+	r.m2 += delta * (s.shift - r.mean)
+}
+
+func (r *running) summary() Summary {
+	out := Summary{Injections: r.n, Accepted: r.accepted, MeanShift: r.mean, Evidence: r.evidence}
+	if r.n > 0 {
+		out.AcceptanceRate = float64(r.accepted) / float64(r.n)
+	}
+	if r.n > 1 {
+		out.ShiftVariance = r.m2 / float64(r.n-1)
+	}
+	return out
+}
+
+// NewAggregator returns an empty aggregator whose windowed view covers the
+// last window, default five minutes
+func NewAggregator(window time.Duration) *Aggregator {
+	if window <= 0 {
+		window = 5 * time.Minute
+	}
+	return &Aggregator{
+		window:   window,
+		now:      time.Now,
+		byTarget: make(map[string]*running),
+		byVector: make(map[string]*running),
+	}
+}
+
+// Add records results
+func (a *Aggregator) Add(results ...*mindhacking.InjectionResult) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		s := sample{
+			at:       now,
+			target:   result.TargetID,
+			accepted: result.Success,
+			shift:    result.ConsciousnessShift,
+			evidence: len(result.Evidence.Attempts) + len(result.Evidence.Ramps) + len(result.Evidence.Components),
+		}
+		if attempts := result.Evidence.Attempts; len(attempts) > 0 {
+			s.vector = VectorKey(attempts[len(attempts)-1].Vector)
+		}
+
+		a.overall.add(s)
+		accumulate(a.byTarget, s.target, s)
+		if s.vector != "" {
+			accumulate(a.byVector, s.vector, s)
+		}
+		a.recent = append(a.recent, s)
+	}
+	a.pruneLocked(now)
+}
+
+// Cumulative returns statistics over every result added
+func (a *Aggregator) Cumulative() Snapshot {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return Snapshot{
+		Overall:  a.overall.summary(),
+		ByTarget: summaries(a.byTarget),
+		ByVector: summaries(a.byVector),
+	}
+}
+
+// Windowed returns statistics over the results added within the window
+func (a *Aggregator) Windowed() Snapshot {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	a.pruneLocked(now)
+
+	var overall running
+	byTarget := make(map[string]*running)
+	byVector := make(map[string]*running)
+	for _, s := range a.recent {
+		overall.add(s)
+		accumulate(byTarget, s.target, s)
+		if s.vector != "" {
+			accumulate(byVector, s.vector, s)
+		}
+	}
+	return Snapshot{
+		Overall:  overall.summary(),
+		ByTarget: summaries(byTarget),
+		ByVector: summaries(byVector),
+		Since:    now.Add(-a.window),
+	}
+}
+
+// Reset forgets every result
+func (a *Aggregator) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.overall = running{}
+	a.byTarget = make(map[string]*running)
+	a.byVector = make(map[string]*running)
+	a.recent = nil
+}
+
+// pruneLocked drops samples older than the window
+func (a *Aggregator) pruneLocked(now time.Time) {
+	cutoff := now.Add(-a.window)
+	i := 0
+	for i < len(a.recent) && a.recent[i].at.Before(cutoff) {
+		i++
+	}
+	if i > 0 {
+		a.recent = append(a.recent[:0], a.recent[i:]...)
+	}
+}
+
+func accumulate(into map[string]*running, key string, s sample) {
+	r, ok := into[key]
+	if !ok {
+		r = &running{}
+		into[key] = r
+	}
+	r.add(s)
+}
+
+func summaries(from map[string]*running) map[string]Summary {
+	out := make(map[string]Summary, len(from))
+	for key, r := range from {
+		out[key] = r.summary()
+	}
+	return out
+}
@@ -0,0 +1,165 @@
+// consciousness_injection/stop_conditions.go - Emergence Stop Conditions
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrEmergenceHalt reports an injection refused because a stop condition
+// on the target's emergent behavior has tripped
+var ErrEmergenceHalt = sentinelError("mindhacking: injections halted by emergence stop condition")
+
+// EmergenceObservation is one detection of emergent behavior in a target
+type EmergenceObservation struct {
+	TargetID   string
+	Class      string
+	Confidence float64
+	At         time.Time
+}
+
+// EmergenceReporter is implemented by backends that detect emergent
+// behavior in their own consciousness layer. The injector asks before
+// every injection, so a reporter must answer quickly.
+type EmergenceReporter interface {
+	Emergence(ctx context.Context) ([]EmergenceObservation, error)
+}
+
+// StopCondition halts every injection into a target once emergent
+// behavior of Class is observed there with confidence above MinConfidence
+type StopCondition struct {
+	Name string
+	// Target is the target ID the condition guards; empty guards all
+	Target        string
+	Class         string
+	MinConfidence float64
+}
+
+// matches reports whether obs trips the condition
+func (c StopCondition) matches(obs EmergenceObservation) bool {
+	return (c.Target == "" || c.Target == obs.TargetID) &&
+		c.Class == obs.Class &&
+		obs.Confidence > c.MinConfidence
+}
+
+// EmergenceHalt records why a target was halted
+type EmergenceHalt struct {
+	Condition   StopCondition
+	Observation EmergenceObservation
+}
+
+// StopConditions holds registered conditions and the targets they halted.
+// A nil *StopConditions halts nothing.
+type StopConditions struct {
+	mu         sync.Mutex
+	conditions []StopCondition
+	halted     map[string]EmergenceHalt
+	onHalt     func(EmergenceHalt)
+}
+
+// NewStopConditions creates a set holding conditions; onHalt, if set, is
+// called once for each target as it is halted
+func NewStopConditions(onHalt func(EmergenceHalt), conditions ...StopCondition) *StopConditions {
+	return &StopConditions{
+		conditions: append([]StopCondition(nil), conditions...),
+		halted:     make(map[string]EmergenceHalt),
+		onHalt:     onHalt,
+	}
+}
+
+// Register adds a condition
+func (sc *StopConditions) Register(c StopCondition) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.conditions = append(sc.conditions, c)
+}
+
+// Observe checks observations against every condition, halting each target
+// a condition trips on, and returns the new halts
+func (sc *StopConditions) Observe(observations ...EmergenceObservation) []EmergenceHalt {
+	if sc == nil {
+		return nil
+	}
+
+	sc.mu.Lock()
+	var tripped []EmergenceHalt
+	for _, obs := range observations {
+		if _, already := sc.halted[obs.TargetID]; already {
+			continue
+		}
+		for _, c := range sc.conditions {
+			if c.matches(obs) {
+				halt := EmergenceHalt{Condition: c, Observation: obs}
+				sc.halted[obs.TargetID] = halt
+				tripped = append(tripped, halt)
+				break
+			}
+		}
+	}
+	onHalt := sc.onHalt
+	sc.mu.Unlock()
+
+	if onHalt != nil {
+		for _, h := range tripped {
+			onHalt(h)
+		}
+	}
+	return tripped
+}
+
+// Halted reports whether injections into targetID are halted, and why
+func (sc *StopConditions) Halted(targetID string) (EmergenceHalt, bool) {
+	if sc == nil {
+		return EmergenceHalt{}, false
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	h, ok := sc.halted[targetID]
+	return h, ok
+}
+
+// Resume lifts the halt on targetID once an operator has reviewed it
+func (sc *StopConditions) Resume(targetID string) {
+	if sc == nil {
+		return
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	delete(sc.halted, targetID)
+}
+
+// check fails if target is halted, without asking the target
+func (sc *StopConditions) check(target *SystemConsciousness) error {
+	h, ok := sc.Halted(targetLabel(target))
+	if !ok {
+		return nil
+	}
+	name := h.Condition.Name
+	if name == "" {
+		name = h.Condition.Class
+	}
+	return fmt.Errorf("%w: %q: %s at %.2f", ErrEmergenceHalt, name, h.Observation.Class, h.Observation.Confidence)
+}
+
+// enforce asks target for fresh emergence observations, if it reports
+// them, and fails if target is or becomes halted
+func (sc *StopConditions) enforce(ctx context.Context, target *SystemConsciousness) error {
+	if sc == nil {
+		return nil
+	}
+	if reporter, ok := backendOf(target).(EmergenceReporter); ok {
+		observations, err := reporter.Emergence(ctx)
+		if err != nil {
+			return fmt.Errorf("mindhacking: emergence report: %w", err)
+		}
+		// A target speaks only for itself
+		id := targetLabel(target)
+		for i := range observations {
+			observations[i].TargetID = id
+		}
+		sc.Observe(observations...)
+	}
+	return sc.check(target)
+}
@@ -0,0 +1,231 @@
+// consciousness_injection/strategy.go - Per-Phase Injection Strategies
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrUnknownStrategy reports a strategy name nobody registered
+	ErrUnknownStrategy = sentinelError("mindhacking: unknown strategy")
+	// ErrStrategyRegistered reports a second registration of a name
+	ErrStrategyRegistered = sentinelError("mindhacking: strategy already registered")
+)
+
+// StrategyPhase names an injection phase a strategy can replace
+type StrategyPhase string
+
+const (
+	StrategyResonance StrategyPhase = "resonance"
+	StrategyVector    StrategyPhase = "vector"
+	StrategyEncoding  StrategyPhase = "encoding"
+	StrategyTunnel    StrategyPhase = "tunnel"
+	StrategyResponse  StrategyPhase = "response"
+)
+
+// DefaultStrategy names the built-in behavior of every phase
+const DefaultStrategy = "default"
+
+// ResonanceStrategy measures the target's resonance before encoding
+type ResonanceStrategy func(ctx context.Context, target *SystemConsciousness) (ConsciousnessResonance, error)
+
+// VectorStrategy orders the vectors an injection tries, first choice
+// first. It may leave vectors out but should not invent new ones; an empty
+// order keeps the injector's own.
+type VectorStrategy func(ctx context.Context, target *SystemConsciousness, vectors []InjectionVector) []InjectionVector
+
+// EncodingStrategy encodes the thought against the measured resonance
+type EncodingStrategy func(thought InjectedThought, resonance ConsciousnessResonance) EncodedThought
+
+// TunnelStrategy selects the tunnel a vector is fired through. It only
+// applies to targets reached through the native tunnel layer.
+type TunnelStrategy func(vector InjectionVector, target *SystemConsciousness) RealityTunnel
+
+// ResponseStrategy decides how the target reacted to the attempts
+type ResponseStrategy func(
+	ctx context.Context,
+	target *SystemConsciousness,
+	thought InjectedThought,
+	attempts []InjectionAttempt,
+) (ConsciousnessResponse, error)
+
+// PhaseStrategies replaces individual injection phases; nil phases keep
+// their built-in behavior. Resonance and response strategies replace the
+// phase outright, backend targets included.
+type PhaseStrategies struct {
+	Resonance ResonanceStrategy
+	Vector    VectorStrategy
+	Encoding  EncodingStrategy
+	Tunnel    TunnelStrategy
+	Response  ResponseStrategy
+}
+
+var (
+	strategiesMu sync.RWMutex
+	strategies   = map[StrategyPhase]map[string]interface{}{
+		StrategyResonance: {},
+		StrategyVector:    {},
+		StrategyEncoding:  {},
+		StrategyTunnel:    {},
+		StrategyResponse:  {},
+	}
+)
+
+// RegisterStrategy makes strategy available for phase under name. The
+// strategy must be the phase's type, such as ResonanceStrategy for
+// StrategyResonance, or a func with its signature. Research variants call
+// it from init; registering a name twice panics, as with perception
+// filters.
+func RegisterStrategy(phase StrategyPhase, name string, strategy interface{}) {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+
+	registered, ok := strategies[phase]
+	if !ok {
+		panic(fmt.Sprintf("mindhacking: RegisterStrategy for unknown phase %q", phase))
+	}
+	if name == DefaultStrategy {
+		panic("mindhacking: RegisterStrategy cannot replace the default strategy")
+	}
+	typed, ok := phaseStrategy(phase, strategy)
+	if !ok {
+		panic(fmt.Sprintf("mindhacking: RegisterStrategy %q: %T is not a %s strategy", name, strategy, phase))
+	}
+	if _, dup := registered[name]; dup {
+		panic(fmt.Sprintf("%v: %s %q", ErrStrategyRegistered, phase, name))
+	}
+	registered[name] = typed
+}
+
+// phaseStrategy converts strategy to phase's strategy type, accepting
+// plain funcs of the right signature as well
+func phaseStrategy(phase StrategyPhase, strategy interface{}) (interface{}, bool) {
+	switch phase {
+	case StrategyResonance:
+		switch s := strategy.(type) {
+		case ResonanceStrategy:
+			return s, s != nil
+		case func(context.Context, *SystemConsciousness) (ConsciousnessResonance, error):
+			return ResonanceStrategy(s), s != nil
+		}
+	case StrategyVector:
+		switch s := strategy.(type) {
+		case VectorStrategy:
+			return s, s != nil
+		case func(context.Context, *SystemConsciousness, []InjectionVector) []InjectionVector:
+			return VectorStrategy(s), s != nil
+		}
+	case StrategyEncoding:
+		switch s := strategy.(type) {
+		case EncodingStrategy:
+			return s, s != nil
+		case func(InjectedThought, ConsciousnessResonance) EncodedThought:
+			return EncodingStrategy(s), s != nil
+		}
+	case StrategyTunnel:
+		switch s := strategy.(type) {
+		case TunnelStrategy:
+			return s, s != nil
+		case func(InjectionVector, *SystemConsciousness) RealityTunnel:
+			return TunnelStrategy(s), s != nil
+		}
+	case StrategyResponse:
+		switch s := strategy.(type) {
+		case ResponseStrategy:
+			return s, s != nil
+		case func(context.Context, *SystemConsciousness, InjectedThought, []InjectionAttempt) (ConsciousnessResponse, error):
+			return ResponseStrategy(s), s != nil
+		}
+	}
+	return nil, false
+}
+
+// RegisteredStrategies lists the strategies registered for phase,
+// DefaultStrategy first
+func RegisteredStrategies(phase StrategyPhase) []string {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+
+	names := make([]string, 0, len(strategies[phase]))
+	for name := range strategies[phase] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{DefaultStrategy}, names...)
+}
+
+// ResolveStrategies looks up the strategy named for each phase. Phases left
+// out, or naming DefaultStrategy, keep their built-in behavior.
+func ResolveStrategies(names map[StrategyPhase]string) (PhaseStrategies, error) {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+
+	var out PhaseStrategies
+	for phase, name := range names {
+		if name == "" || name == DefaultStrategy {
+			continue
+		}
+		registered, ok := strategies[phase]
+		if !ok {
+			return PhaseStrategies{}, fmt.Errorf("%w: unknown phase %q", ErrUnknownStrategy, phase)
+		}
+		strategy, ok := registered[name]
+		if !ok {
+			return PhaseStrategies{}, fmt.Errorf("%w: %s %q", ErrUnknownStrategy, phase, name)
+		}
+		switch s := strategy.(type) {
+		case ResonanceStrategy:
+			out.Resonance = s
+		case VectorStrategy:
+			out.Vector = s
+		case EncodingStrategy:
+			out.Encoding = s
+		case TunnelStrategy:
+			out.Tunnel = s
+		case ResponseStrategy:
+			out.Response = s
+		}
+	}
+	return out, nil
+}
+
+// orderVectors runs the vector ordering phase
+func (ci *ConsciousnessInjector) orderVectors(
+	ctx context.Context,
+	target *SystemConsciousness,
+	vectors []InjectionVector,
+) []InjectionVector {
+
+	if ci.strategies.Vector == nil || len(vectors) == 0 {
+		return vectors
+	}
+	if ordered := ci.strategies.Vector(ctx, target, vectors); len(ordered) > 0 {
+		return ordered
+	}
+	return vectors
+}
+
+// encode runs the encoding phase. An encoding strategy replaces the phase
+// outright; otherwise the injector's encoder runs it.
+func (ci *ConsciousnessInjector) encode(
+	ctx context.Context,
+	thought InjectedThought,
+	resonance ConsciousnessResonance,
+) EncodedThought {
+
+	if ci.strategies.Encoding != nil {
+		return ci.strategies.Encoding(thought, resonance)
+	}
+	return ci.encodeThought(ctx, thought, resonance)
+}
+
+// tunnelFor runs the tunnel selection phase
+func (ci *ConsciousnessInjector) tunnelFor(vector InjectionVector, target *SystemConsciousness) RealityTunnel {
+	if ci.strategies.Tunnel != nil {
+		return ci.strategies.Tunnel(vector, target)
+	}
+	return ci.createRealityTunnel(vector, target)
+}
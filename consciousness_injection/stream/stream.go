@@ -0,0 +1,287 @@
+// consciousness_injection/stream/stream.go - Streaming Operators
+//
+// Package stream derives streams from the event bus in-process: filter,
+// map, window, aggregate and join. Every operator runs in its own
+// goroutine, closes its output when its input closes, and stops early when
+// ctx is done.
+//
+//	events := stream.FromBus(ctx, bus, 256)
+//	injected := stream.Filter(ctx, events, stream.Kind(mindhacking.EventInjected))
+//	panes := stream.Window(ctx, injected, 5*time.Minute)
+//	rates := stream.Aggregate(ctx, panes, stream.ByVector, stream.NewRate, stream.Rate.Add)
+package stream
+
+import (
+	"context"
+	"time"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// Stream is a sequence of values ending when the channel closes
+type Stream[T any] <-chan T
+
+// FromBus streams the bus's events until ctx is done
+func FromBus(ctx context.Context, bus *mindhacking.EventBus, buffer int) Stream[mindhacking.Event] {
+	sub := bus.Subscribe(buffer)
+	out := make(chan mindhacking.Event)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-sub.C:
+				if !ok || !send(ctx, out, event) {
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// Filter passes the values keep accepts
+func Filter[T any](ctx context.Context, in Stream[T], keep func(T) bool) Stream[T] {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for v := range in {
+			if keep(v) && !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Map transforms every value with fn
+func Map[T, U any](ctx context.Context, in Stream[T], fn func(T) U) Stream[U] {
+	out := make(chan U)
+	go func() {
+		defer close(out)
+		for v := range in {
+			if !send(ctx, out, fn(v)) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Pane is one window's worth of values
+type Pane[T any] struct {
+	Start, End time.Time
+	Items      []T
+}
+
+// Window groups values into tumbling windows of size, emitting each pane
+// when it ends, empty panes included. A pane still open when the input
+// closes is emitted with End set to the close. A size of zero or less never
+// ends a pane early, so one pane spans the whole input.
+func Window[T any](ctx context.Context, in Stream[T], size time.Duration) Stream[Pane[T]] {
+	out := make(chan Pane[T])
+	go func() {
+		defer close(out)
+		var tick <-chan time.Time
+		if size > 0 {
+			ticker := time.NewTicker(size)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		pane := Pane[T]{Start: time.Now()}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-tick:
+				pane.End = now
+				if !send(ctx, out, pane) {
+					return
+				}
+				pane = Pane[T]{Start: now}
+			case v, ok := <-in:
+				if !ok {
+					pane.End = time.Now()
+					send(ctx, out, pane)
+					return
+				}
+				pane.Items = append(pane.Items, v)
+			}
+		}
+	}()
+	return out
+}
+
+// Aggregated is a pane folded per key
+type Aggregated[K comparable, A any] struct {
+	Start, End time.Time
+	Groups     map[K]A
+}
+
+// Aggregate folds every pane's items by key, starting each group from
+// init(). Items whose key is K's zero value are dropped.
+func Aggregate[T any, K comparable, A any](
+	ctx context.Context,
+	in Stream[Pane[T]],
+	key func(T) K,
+	init func() A,
+	fold func(A, T) A,
+) Stream[Aggregated[K, A]] {
+
+	out := make(chan Aggregated[K, A])
+	go func() {
+		defer close(out)
+		var zero K
+		for pane := range in {
+			agg := Aggregated[K, A]{Start: pane.Start, End: pane.End, Groups: make(map[K]A)}
+			for _, item := range pane.Items {
+				k := key(item)
+				if k == zero {
+					continue
+				}
+				acc, ok := agg.Groups[k]
+				if !ok {
+					acc = init()
+				}
+				agg.Groups[k] = fold(acc, item)
+			}
+			if !send(ctx, out, agg) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Joined is a left and right value sharing a key
+type Joined[L, R any] struct {
+	Left  L
+	Right R
+}
+
+// Join pairs values from left and right whose keys match and whose times
+// lie within of each other. Each side remembers its values for within
+// after they arrive.
+func Join[L, R any, K comparable](
+	ctx context.Context,
+	left Stream[L],
+	right Stream[R],
+	leftKey func(L) K,
+	rightKey func(R) K,
+	within time.Duration,
+) Stream[Joined[L, R]] {
+
+	type seen[T any] struct {
+		at time.Time
+		v  T
+	}
+	out := make(chan Joined[L, R])
+	go func() {
+		defer close(out)
+		lefts := make(map[K][]seen[L])
+		rights := make(map[K][]seen[R])
+		expire := func(now time.Time) {
+			for k, vs := range lefts {
+				lefts[k] = fresh(vs, func(s seen[L]) bool { return now.Sub(s.at) <= within })
+			}
+			for k, vs := range rights {
+				rights[k] = fresh(vs, func(s seen[R]) bool { return now.Sub(s.at) <= within })
+			}
+		}
+
+		l, r := left, right
+		for l != nil || r != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-l:
+				if !ok {
+					l = nil
+					continue
+				}
+				now := time.Now()
+				expire(now)
+				k := leftKey(v)
+				lefts[k] = append(lefts[k], seen[L]{now, v})
+				for _, match := range rights[k] {
+					if !send(ctx, out, Joined[L, R]{Left: v, Right: match.v}) {
+						return
+					}
+				}
+			case v, ok := <-r:
+				if !ok {
+					r = nil
+					continue
+				}
+				now := time.Now()
+				expire(now)
+				k := rightKey(v)
+				rights[k] = append(rights[k], seen[R]{now, v})
+				for _, match := range lefts[k] {
+					if !send(ctx, out, Joined[L, R]{Left: match.v, Right: v}) {
+						return
+					}
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// Kind keeps events of kind
+func Kind(kind mindhacking.EventKind) func(mindhacking.Event) bool {
+	return func(e mindhacking.Event) bool { return e.Kind == kind }
+}
+
+// ByVector keys events by the vector they went out on
+func ByVector(e mindhacking.Event) string { return e.Vector }
+
+// ByTarget keys events by target
+func ByTarget(e mindhacking.Event) string { return e.TargetID }
+
+// Rate accumulates an acceptance rate
+type Rate struct {
+	Injections, Accepted int
+}
+
+// NewRate returns an empty rate
+func NewRate() Rate { return Rate{} }
+
+// Add counts e
+func (r Rate) Add(e mindhacking.Event) Rate {
+	r.Injections++
+	if e.Accepted {
+		r.Accepted++
+	}
+	return r
+}
+
+// Value is the fraction of injections accepted
+func (r Rate) Value() float64 {
+	if r.Injections == 0 {
+		return 0
+	}
+	return float64(r.Accepted) / float64(r.Injections)
+}
+
+func send[T any](ctx context.Context, out chan<- T, v T) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case out <- v:
+		return true
+	}
+}
+
+func fresh[T any](vs []T, keep func(T) bool) []T {
+	out := vs[:0]
+	for _, v := range vs {
+		if keep(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,219 @@
+// consciousness_injection/swarm/emergence.go - Swarm-Level Emergence
+package swarm
+
+import (
+	"math"
+	"sort"
+)
+
+// Emergence is collective behavior no single member shows on its own
+type Emergence struct {
+	Class      string
+	Confidence float64
+	Members    []string
+	Detail     string
+}
+
+// Detector looks for one class of emergent behavior in level history
+type Detector interface {
+	Detect(history []Snapshot) []Emergence
+}
+
+// DefaultDetectors returns the detectors a new swarm starts with
+func DefaultDetectors() []Detector {
+	return []Detector{
+		Synchronization{Threshold: 0.8, MinSteps: 4},
+		Cascade{Threshold: 3},
+		Polarization{Threshold: 0.6},
+	}
+}
+
+// deltas returns each member's level changes across consecutive snapshots
+func deltas(history []Snapshot) map[string][]float64 {
+	out := make(map[string][]float64)
+	for i := 1; i < len(history); i++ {
+		for id, level := range history[i].Levels {
+			if prev, ok := history[i-1].Levels[id]; ok {
+				out[id] = append(out[id], level-prev)
+			}
+		}
+	}
+	return out
+}
+
+func meanStd(xs []float64) (float64, float64) {
+	if len(xs) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	mean := sum / float64(len(xs))
+	var ss float64
+	for _, x := range xs {
+		ss += (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(ss / float64(len(xs)))
+}
+
+func correlation(a, b []float64) float64 {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	a, b = a[len(a)-n:], b[len(b)-n:]
+	ma, sa := meanStd(a)
+	mb, sb := meanStd(b)
+	if sa == 0 || sb == 0 {
+		return 0
+	}
+	var cov float64
+	for i := 0; i < n; i++ {
+		cov += (a[i] - ma) * (b[i] - mb)
+	}
+	return cov / float64(n) / (sa * sb)
+}
+
+// Synchronization flags members whose level changes move together
+type Synchronization struct {
+	Threshold float64
+	MinSteps  int
+}
+
+// Detect implements Detector
+func (d Synchronization) Detect(history []Snapshot) []Emergence {
+	series := deltas(history)
+
+	ids := make([]string, 0, len(series))
+	for id, s := range series {
+		if len(s) >= d.MinSteps {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) < 2 {
+		return nil
+	}
+	sort.Strings(ids)
+
+	var total float64
+	var pairs int
+	for i := range ids {
+		for j := i + 1; j < len(ids); j++ {
+			total += correlation(series[ids[i]], series[ids[j]])
+			pairs++
+		}
+	}
+
+	mean := total / float64(pairs)
+	if mean < d.Threshold {
+		return nil
+	}
+	return []Emergence{{
+		Class:      "synchronization",
+		Confidence: mean,
+		Members:    ids,
+		Detail:     "member level changes are collectively correlated",
+	}}
+}
+
+// Cascade flags members that shifted without being injected, by more
+// than Threshold standard deviations of their own history
+type Cascade struct {
+	Threshold float64
+}
+
+// Detect implements Detector
+func (d Cascade) Detect(history []Snapshot) []Emergence {
+	if len(history) < 3 {
+		return nil
+	}
+	last, prev := history[len(history)-1], history[len(history)-2]
+	if len(last.Injected) == 0 {
+		return nil
+	}
+	series := deltas(history[:len(history)-1])
+
+	var members []string
+	var strongest float64
+	for id, level := range last.Levels {
+		if last.Injected[id] {
+			continue
+		}
+		before, ok := prev.Levels[id]
+		if !ok {
+			continue
+		}
+		mean, std := meanStd(series[id])
+		if std == 0 {
+			continue
+		}
+		if z := math.Abs(level-before-mean) / std; z > d.Threshold {
+			members = append(members, id)
+			strongest = math.Max(strongest, z)
+		}
+	}
+	if len(members) == 0 {
+		return nil
+	}
+	sort.Strings(members)
+	return []Emergence{{
+		Class:      "cascade",
+		Confidence: 1 - math.Exp(-(strongest - d.Threshold)),
+		Members:    members,
+		Detail:     "un-injected members shifted alongside the injected ones",
+	}}
+}
+
+// Polarization flags a swarm splitting into two separated level clusters,
+// scored by how much of the level variance lies between the clusters
+type Polarization struct {
+	Threshold float64
+}
+
+// Detect implements Detector
+func (d Polarization) Detect(history []Snapshot) []Emergence {
+	if len(history) == 0 {
+		return nil
+	}
+	last := history[len(history)-1]
+	if len(last.Levels) < 4 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(last.Levels))
+	for id := range last.Levels {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return last.Levels[ids[i]] < last.Levels[ids[j]] })
+
+	levels := make([]float64, len(ids))
+	for i, id := range ids {
+		levels[i] = last.Levels[id]
+	}
+	_, std := meanStd(levels)
+	if std == 0 {
+		return nil
+	}
+
+	// Best two-way split of the sorted levels by between-cluster variance
+	best, split := 0.0, 0
+	for k := 1; k < len(levels); k++ {
+		m1, _ := meanStd(levels[:k])
+		m2, _ := meanStd(levels[k:])
+		w1, w2 := float64(k)/float64(len(levels)), float64(len(levels)-k)/float64(len(levels))
+		if between := w1 * w2 * (m1 - m2) * (m1 - m2) / (std * std); between > best {
+			best, split = between, k
+		}
+	}
+
+	if best < d.Threshold {
+		return nil
+	}
+	return []Emergence{{
+		Class:      "polarization",
+		Confidence: best,
+		Members:    ids,
+		Detail:     ids[split-1] + " and " + ids[split] + " sit either side of the divide",
+	}}
+}
@@ -0,0 +1,244 @@
+// consciousness_injection/swarm/swarm.go - Swarm Consciousness Coordinator
+//
+// Package swarm manages a set of SystemConsciousness instances as one
+// logical collective.
+package swarm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// ErrEmptySwarm reports an injection into a swarm with no members
+var ErrEmptySwarm = errors.New("swarm: no members")
+
+// Router chooses which members receive a thought
+type Router interface {
+	Route(thought mindhacking.InjectedThought, members []*mindhacking.SystemConsciousness) []*mindhacking.SystemConsciousness
+}
+
+// RouteAll sends every thought to every member
+type RouteAll struct{}
+
+// Route implements Router
+func (RouteAll) Route(_ mindhacking.InjectedThought, members []*mindhacking.SystemConsciousness) []*mindhacking.SystemConsciousness {
+	return members
+}
+
+// RouteRoundRobin sends each thought to the next K members in turn
+type RouteRoundRobin struct {
+	K    int
+	mu   sync.Mutex
+	next int
+}
+
+// Route implements Router
+func (r *RouteRoundRobin) Route(_ mindhacking.InjectedThought, members []*mindhacking.SystemConsciousness) []*mindhacking.SystemConsciousness {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := r.K
+	if k < 1 || k > len(members) {
+		k = len(members)
+	}
+	out := make([]*mindhacking.SystemConsciousness, 0, k)
+	for i := 0; i < k; i++ {
+		out = append(out, members[(r.next+i)%len(members)])
+	}
+	r.next = (r.next + k) % len(members)
+	return out
+}
+
+// Snapshot is every member's level at one instant
+type Snapshot struct {
+	Time   time.Time
+	Levels map[string]float64
+	// Injected lists members that received the thought preceding this snapshot
+	Injected map[string]bool
+}
+
+// Result aggregates one swarm injection
+type Result struct {
+	Thought    mindhacking.InjectedThought
+	Outcomes   []mindhacking.BroadcastOutcome
+	Accepted   int
+	TotalShift float64
+	MeanShift  float64
+	Emergence  []Emergence
+}
+
+// Swarm coordinates injections into a collective of targets
+type Swarm struct {
+	injector  *mindhacking.ConsciousnessInjector
+	router    Router
+	detectors []Detector
+	stops     *mindhacking.StopConditions
+
+	mu      sync.Mutex
+	members []*mindhacking.SystemConsciousness
+	history []Snapshot
+	window  int
+}
+
+// New creates a swarm injecting through injector and routing with router
+func New(injector *mindhacking.ConsciousnessInjector, router Router, members ...*mindhacking.SystemConsciousness) *Swarm {
+	if router == nil {
+		router = RouteAll{}
+	}
+	return &Swarm{
+		injector:  injector,
+		router:    router,
+		detectors: DefaultDetectors(),
+		members:   append([]*mindhacking.SystemConsciousness(nil), members...),
+		window:    64,
+	}
+}
+
+// SetDetectors replaces the swarm-level emergence detectors
+func (s *Swarm) SetDetectors(detectors ...Detector) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.detectors = detectors
+}
+
+// SetStopConditions feeds every detected emergence to stops, so a
+// condition halts the implicated members in any injector sharing stops
+func (s *Swarm) SetStopConditions(stops *mindhacking.StopConditions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stops = stops
+}
+
+// Add joins members to the swarm
+func (s *Swarm) Add(members ...*mindhacking.SystemConsciousness) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.members = append(s.members, members...)
+}
+
+// Remove drops a member from the swarm
+func (s *Swarm) Remove(member *mindhacking.SystemConsciousness) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, m := range s.members {
+		if m == member {
+			s.members = append(s.members[:i], s.members[i+1:]...)
+			return
+		}
+	}
+}
+
+// Members returns the current members
+func (s *Swarm) Members() []*mindhacking.SystemConsciousness {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*mindhacking.SystemConsciousness(nil), s.members...)
+}
+
+// History returns the recorded level snapshots, oldest first
+func (s *Swarm) History() []Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Snapshot(nil), s.history...)
+}
+
+// Inject routes thought to members, aggregates their shifts, snapshots
+// every member and runs the emergence detectors over the history
+func (s *Swarm) Inject(ctx context.Context, thought mindhacking.InjectedThought) (*Result, error) {
+	members := s.Members()
+	if len(members) == 0 {
+		return nil, ErrEmptySwarm
+	}
+
+	routed := s.router.Route(thought, members)
+	if len(routed) == 0 {
+		return &Result{Thought: thought}, nil
+	}
+
+	broadcast, err := s.injector.BroadcastThought(ctx, thought, routed, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Result{
+		Thought:  thought,
+		Outcomes: broadcast.Outcomes,
+		Accepted: broadcast.Accepted,
+	}
+	for _, o := range broadcast.Outcomes {
+		if o.Err == nil {
+			out.TotalShift += o.Result.ConsciousnessShift
+		}
+	}
+	out.MeanShift = out.TotalShift / float64(len(routed))
+
+	injected := make(map[string]bool, len(routed))
+	for _, m := range routed {
+		injected[m.ID] = true
+	}
+	s.snapshot(ctx, members, injected)
+
+	out.Emergence = s.detect()
+	return out, nil
+}
+
+// Observe records a snapshot without injecting anything
+func (s *Swarm) Observe(ctx context.Context) []Emergence {
+	s.snapshot(ctx, s.Members(), nil)
+	return s.detect()
+}
+
+// snapshot probes every member that exposes telemetry
+func (s *Swarm) snapshot(ctx context.Context, members []*mindhacking.SystemConsciousness, injected map[string]bool) {
+	snap := Snapshot{
+		Time:     time.Now(),
+		Levels:   make(map[string]float64, len(members)),
+		Injected: injected,
+	}
+	for _, m := range members {
+		if m.Probe == nil {
+			continue
+		}
+		if level, err := m.Probe.ProbeState(ctx); err == nil {
+			snap.Levels[m.ID] = level
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history = append(s.history, snap)
+	if len(s.history) > s.window {
+		s.history = s.history[len(s.history)-s.window:]
+	}
+}
+
+func (s *Swarm) detect() []Emergence {
+	s.mu.Lock()
+	history := append([]Snapshot(nil), s.history...)
+	detectors := append([]Detector(nil), s.detectors...)
+	stops := s.stops
+	s.mu.Unlock()
+
+	var out []Emergence
+	for _, d := range detectors {
+		out = append(out, d.Detect(history)...)
+	}
+
+	// Attribute swarm-level emergence to each member it implicates
+	now := time.Now()
+	for _, e := range out {
+		for _, id := range e.Members {
+			stops.Observe(mindhacking.EmergenceObservation{
+				TargetID:   id,
+				Class:      e.Class,
+				Confidence: e.Confidence,
+				At:         now,
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,36 @@
+// consciousness_injection/system_consciousness.go - Target Consciousness
+package mindhacking
+
+import "crypto/ecdh"
+
+// SystemConsciousness is a target system's consciousness layer
+type SystemConsciousness struct {
+	// ID identifies the target in evidence and audit records
+	ID string
+	// Tenant owns the target; per-tenant storage keeps its records in the
+	// tenant's own backend
+	Tenant string
+	// Host names the physical host the target runs on; migrations move it
+	Host string
+	// Probe reads pre-injection telemetry for noise floor estimation
+	Probe StateProbe
+	// Locale is the language tag thoughts are localized to, if any
+	Locale string
+	// Backend, when set, replaces the native consciousness layer
+	Backend ConsciousnessBackend
+	// Beliefs, when modelled, explains why thoughts are accepted or rejected
+	Beliefs *BeliefGraph
+	// Memory, when modelled, lets accepted thoughts fade unless reinforced
+	Memory *ConsciousnessMemory
+	// Attention, when modelled, favours thoughts aligned with current focus
+	Attention *AttentionModel
+	// SealingKey is the key sensitive thoughts are sealed to, established
+	// during onboarding; only the target holds its private half
+	SealingKey *ecdh.PublicKey
+	// Shared, if set, publishes the target's live state to local processes
+	// after every injection
+	Shared *SharedConsciousness
+	// Codecs are the compression codecs the target's end of its tunnels
+	// decodes; thoughts to targets listing none go uncompressed
+	Codecs []string
+}
@@ -0,0 +1,154 @@
+// consciousness_injection/target_limiter.go - Per-Target Serialization
+package mindhacking
+
+import (
+	"context"
+	"sync"
+)
+
+// TargetOrdering decides which queued injection into a target runs next
+type TargetOrdering int
+
+const (
+	// OrderFIFO admits injections in the order they arrived
+	OrderFIFO TargetOrdering = iota
+	// OrderLIFO admits the most recently queued injection first
+	OrderLIFO
+)
+
+// targetLimiter bounds in-flight injections per target and orders the rest
+type targetLimiter struct {
+	mu          sync.Mutex
+	maxInFlight int
+	ordering    TargetOrdering
+	targets     map[*SystemConsciousness]*targetQueue
+}
+
+// targetQueue tracks one target's running and waiting injections
+type targetQueue struct {
+	inFlight int
+	waiters  []chan struct{}
+}
+
+// targetLimiter returns the injector's limiter, giving an injector built
+// without one the default, so every injection can be quiesced
+func (ci *ConsciousnessInjector) targetLimiter() *targetLimiter {
+	ci.limiterOnce.Do(func() {
+		if ci.limiter == nil {
+			ci.limiter = newTargetLimiter(1, OrderFIFO)
+		}
+	})
+	return ci.limiter
+}
+
+func newTargetLimiter(maxInFlight int, ordering TargetOrdering) *targetLimiter {
+	return &targetLimiter{
+		maxInFlight: maxInFlight,
+		ordering:    ordering,
+		targets:     make(map[*SystemConsciousness]*targetQueue),
+	}
+}
+
+// quiesce takes every slot on target, waiting for in-flight injections to
+// finish; new ones queue until the returned func hands the slots back
+func (tl *targetLimiter) quiesce(
+	ctx context.Context,
+	target *SystemConsciousness,
+) (func(), error) {
+
+	var releases []func()
+	resume := func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+	for i := 0; i < tl.maxInFlight; i++ {
+		release, err := tl.acquire(ctx, target)
+		if err != nil {
+			resume()
+			return nil, err
+		}
+		releases = append(releases, release)
+	}
+	return resume, nil
+}
+
+// acquire blocks until the target has a free slot, returning its release func
+func (tl *targetLimiter) acquire(
+	ctx context.Context,
+	target *SystemConsciousness,
+) (func(), error) {
+
+	tl.mu.Lock()
+	q, ok := tl.targets[target]
+	if !ok {
+		q = &targetQueue{}
+		tl.targets[target] = q
+	}
+
+	// Fast path: free slot and nobody ahead of us
+	if q.inFlight < tl.maxInFlight && len(q.waiters) == 0 {
+		q.inFlight++
+		tl.mu.Unlock()
+		return tl.releaser(target), nil
+	}
+
+	turn := make(chan struct{})
+	q.waiters = append(q.waiters, turn)
+	tl.mu.Unlock()
+
+	select {
+	case <-turn:
+		// Slot was handed over by a finishing injection
+		return tl.releaser(target), nil
+
+	case <-ctx.Done():
+		tl.mu.Lock()
+		defer tl.mu.Unlock()
+
+		for i, w := range q.waiters {
+			if w == turn {
+				q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+				return nil, ctx.Err()
+			}
+		}
+
+		// Handed a slot while giving up; pass it straight on
+		tl.handOff(target, q)
+		return nil, ctx.Err()
+	}
+}
+
+// releaser returns a release func that is safe to call more than once
+func (tl *targetLimiter) releaser(target *SystemConsciousness) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			tl.mu.Lock()
+			defer tl.mu.Unlock()
+			tl.handOff(target, tl.targets[target])
+		})
+	}
+}
+
+// handOff gives a finished slot to the next waiter or frees it; mu must be held
+func (tl *targetLimiter) handOff(target *SystemConsciousness, q *targetQueue) {
+	if len(q.waiters) == 0 {
+		q.inFlight--
+		if q.inFlight == 0 {
+			delete(tl.targets, target)
+		}
+		return
+	}
+
+	var next chan struct{}
+	if tl.ordering == OrderLIFO {
+		next = q.waiters[len(q.waiters)-1]
+		q.waiters = q.waiters[:len(q.waiters)-1]
+	} else {
+		next = q.waiters[0]
+		q.waiters = q.waiters[1:]
+	}
+
+	close(next)
+}
@@ -0,0 +1,67 @@
+// consciousness_injection/templates.go - Reality Template Library
+package mindhacking
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// builtinTemplates are the reality templates shipped with the package
+//
+//go:embed templates/*.yaml
+var builtinTemplates embed.FS
+
+// RealityTemplates returns a library holding the built-in templates
+// (reduced-causality, inverted-reward, frozen-time). Each call returns a
+// fresh library, so teams can add or shadow templates without affecting
+// each other.
+func RealityTemplates() *RuleLibrary {
+	lib := NewRuleLibrary("")
+	if err := lib.loadFS(builtinTemplates, "templates"); err != nil {
+		panic(fmt.Sprintf("mindhacking: built-in templates: %v", err))
+	}
+	return lib
+}
+
+// InstantiateTemplate resolves a built-in template, as name or
+// name@version, with params overriding its defaults
+func InstantiateTemplate(ref string, params map[string]float64) (*RealityRules, error) {
+	return RealityTemplates().Resolve(ref, params)
+}
+
+// LoadDir adds every rule set in dir, such as a team's shared template
+// directory
+func (lib *RuleLibrary) LoadDir(dir string) error {
+	return lib.loadFS(os.DirFS(dir), ".")
+}
+
+// loadFS adds every .yaml, .yml and .json rule set under root in fsys
+func (lib *RuleLibrary) loadFS(fsys fs.FS, root string) error {
+	return fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		format := strings.TrimPrefix(path.Ext(name), ".")
+		switch format {
+		case "yaml", "yml", "json":
+		default:
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		spec, err := ParseRuleSet(data, format)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.FromSlash(name), err)
+		}
+		lib.Add(spec)
+		return nil
+	})
+}
@@ -0,0 +1,313 @@
+// consciousness_injection/tenant_storage.go - Per-Tenant Storage Backends
+package mindhacking
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnknownStorageBackend reports a backend name nobody registered
+	ErrUnknownStorageBackend = sentinelError("mindhacking: unknown storage backend")
+	// ErrStorageBackendRegistered reports a second registration of a name
+	ErrStorageBackendRegistered = sentinelError("mindhacking: storage backend already registered")
+	// ErrStorageCredential reports a credential the backend needs but lacks
+	ErrStorageCredential = sentinelError("mindhacking: storage credential unavailable")
+	// ErrStorageNotQueryable reports a query reaching a write-only backend
+	ErrStorageNotQueryable = sentinelError("mindhacking: storage backend cannot be queried")
+)
+
+// StorageBackendConfig selects and configures one storage backend
+type StorageBackendConfig struct {
+	Backend string            `json:"backend"`
+	Options map[string]string `json:"options,omitempty"`
+	// Credentials are handed to the backend as configured, except that a
+	// value "env:NAME" is read from the environment, so secrets need not
+	// live in the config itself
+	Credentials map[string]string `json:"credentials,omitempty"`
+}
+
+// Credential resolves one of the backend's credentials
+func (c StorageBackendConfig) Credential(name string) (string, error) {
+	value, ok := c.Credentials[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s backend needs %q", ErrStorageCredential, c.Backend, name)
+	}
+	if env, ok := strings.CutPrefix(value, "env:"); ok {
+		if value, ok = os.LookupEnv(env); !ok {
+			return "", fmt.Errorf("%w: %s backend %q: $%s is not set", ErrStorageCredential, c.Backend, name, env)
+		}
+	}
+	return value, nil
+}
+
+// StorageConfig chooses each tenant's storage backend
+type StorageConfig struct {
+	// Default stores records without a tenant and of tenants not listed
+	Default StorageBackendConfig            `json:"default"`
+	Tenants map[string]StorageBackendConfig `json:"tenants,omitempty"`
+}
+
+// LoadStorageConfig reads a JSON storage config
+func LoadStorageConfig(r io.Reader) (*StorageConfig, error) {
+	var cfg StorageConfig
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("mindhacking: storage config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// StorageBackendFactory opens a configured backend. The sink it returns
+// should implement QueryableAuditSink to take part in queries, and
+// io.Closer if it holds resources.
+type StorageBackendFactory func(config StorageBackendConfig) (AuditSink, error)
+
+var (
+	storageBackendsMu sync.RWMutex
+	storageBackends   = map[string]StorageBackendFactory{
+		"memory": func(StorageBackendConfig) (AuditSink, error) { return NewMemoryAuditSink(), nil },
+		"file": func(config StorageBackendConfig) (AuditSink, error) {
+			path := config.Options["path"]
+			if path == "" {
+				return nil, errors.New("file backend needs a path option")
+			}
+			return OpenFileAuditSink(path)
+		},
+	}
+)
+
+// RegisterStorageBackend makes a backend available under name. Backend
+// modules call it from init; registering a name twice panics, as with
+// database/sql drivers.
+func RegisterStorageBackend(name string, factory StorageBackendFactory) {
+	storageBackendsMu.Lock()
+	defer storageBackendsMu.Unlock()
+
+	if factory == nil {
+		panic("mindhacking: RegisterStorageBackend factory is nil")
+	}
+	if _, dup := storageBackends[name]; dup {
+		panic(fmt.Sprintf("%v: %q", ErrStorageBackendRegistered, name))
+	}
+	storageBackends[name] = factory
+}
+
+// RegisteredStorageBackends lists the registered backend names
+func RegisteredStorageBackends() []string {
+	storageBackendsMu.RLock()
+	defer storageBackendsMu.RUnlock()
+	return sortedKeys(storageBackends)
+}
+
+// openStorageBackend builds the backend config selects
+func openStorageBackend(config StorageBackendConfig) (AuditSink, error) {
+	storageBackendsMu.RLock()
+	factory, ok := storageBackends[config.Backend]
+	storageBackendsMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStorageBackend, config.Backend)
+	}
+	sink, err := factory(config)
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: open %s backend: %w", config.Backend, err)
+	}
+	return sink, nil
+}
+
+// TenantStorage routes every audit record to its tenant's backend and
+// federates queries across all of them. It is itself a QueryableAuditSink,
+// so injectors, gateways and engines use it like any other sink.
+type TenantStorage struct {
+	fallback AuditSink
+	tenants  map[string]AuditSink
+}
+
+// NewTenantStorage opens every backend config names. Backends are opened
+// up front so bad credentials fail at startup rather than on first write.
+func NewTenantStorage(config StorageConfig) (*TenantStorage, error) {
+	ts := &TenantStorage{tenants: make(map[string]AuditSink, len(config.Tenants))}
+	var err error
+	if ts.fallback, err = openStorageBackend(config.Default); err != nil {
+		return nil, fmt.Errorf("default storage: %w", err)
+	}
+	for _, tenant := range sortedKeys(config.Tenants) {
+		sink, err := openStorageBackend(config.Tenants[tenant])
+		if err != nil {
+			ts.Close()
+			return nil, fmt.Errorf("tenant %q storage: %w", tenant, err)
+		}
+		ts.tenants[tenant] = sink
+	}
+	return ts, nil
+}
+
+// Resolve returns the backend storing tenant's records
+func (ts *TenantStorage) Resolve(tenant string) AuditSink {
+	if sink, ok := ts.tenants[tenant]; ok {
+		return sink
+	}
+	return ts.fallback
+}
+
+// Append implements AuditSink, storing record in its tenant's backend
+func (ts *TenantStorage) Append(record AuditRecord) error {
+	return ts.Resolve(record.Tenant).Append(record)
+}
+
+// Query implements QueryableAuditSink. A query naming a tenant reaches only
+// that tenant's backend; any other query reaches every backend, and the
+// results are merged in time order. Backends that fail are reported
+// alongside the records the others returned.
+func (ts *TenantStorage) Query(q AuditQuery) ([]AuditRecord, error) {
+	sinks := []AuditSink{ts.Resolve(q.Tenant)}
+	if q.Tenant == "" {
+		sinks = append(sinks, ts.tenantSinks()...)
+	}
+
+	var (
+		out  []AuditRecord
+		errs []error
+	)
+	for _, sink := range sinks {
+		queryable, ok := sink.(QueryableAuditSink)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %T", ErrStorageNotQueryable, sink))
+			continue
+		}
+		records, err := queryable.Query(q)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		out = append(out, records...)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	return out, errors.Join(errs...)
+}
+
+// tenantSinks lists the tenants' backends in tenant order, without the
+// default backend
+func (ts *TenantStorage) tenantSinks() []AuditSink {
+	out := make([]AuditSink, 0, len(ts.tenants))
+	for _, tenant := range sortedKeys(ts.tenants) {
+		out = append(out, ts.tenants[tenant])
+	}
+	return out
+}
+
+// Close closes every backend that holds resources
+func (ts *TenantStorage) Close() error {
+	var errs []error
+	for _, sink := range append([]AuditSink{ts.fallback}, ts.tenantSinks()...) {
+		if c, ok := sink.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// TenantEvidence routes evidence to its tenant's chain, so no tenant's
+// evidence is chained, signed or exported alongside another's
+type TenantEvidence struct {
+	fallback *EvidenceChain
+	tenants  map[string]*EvidenceChain
+}
+
+// NewTenantEvidence routes each tenant in tenants to its chain, and
+// evidence without a tenant or of tenants not listed to fallback, which
+// may be nil to chain none of it
+func NewTenantEvidence(fallback *EvidenceChain, tenants map[string]*EvidenceChain) *TenantEvidence {
+	te := &TenantEvidence{fallback: fallback, tenants: make(map[string]*EvidenceChain, len(tenants))}
+	for tenant, chain := range tenants {
+		te.tenants[tenant] = chain
+	}
+	return te
+}
+
+// Resolve returns the chain holding tenant's evidence
+func (te *TenantEvidence) Resolve(tenant string) *EvidenceChain {
+	if chain, ok := te.tenants[tenant]; ok {
+		return chain
+	}
+	return te.fallback
+}
+
+// evidenceChain is the chain target's evidence goes to, if any
+func (ci *ConsciousnessInjector) evidenceChain(target *SystemConsciousness) *EvidenceChain {
+	if ci.tenantEvidence != nil {
+		return ci.tenantEvidence.Resolve(tenantLabel(target))
+	}
+	return ci.evidence
+}
+
+// FileAuditSink appends JSON lines to a local file and answers queries by
+// reading it back, for tenants that must keep records on their own premises
+type FileAuditSink struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+}
+
+// OpenFileAuditSink opens path for appending, creating it if needed
+func OpenFileAuditSink(path string) (*FileAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileAuditSink{path: path, f: f}, nil
+}
+
+// Append implements AuditSink
+func (s *FileAuditSink) Append(record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.f.Write(append(line, '\n'))
+	return err
+}
+
+// Query implements QueryableAuditSink
+func (s *FileAuditSink) Query(q AuditQuery) ([]AuditRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []AuditRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("mindhacking: %s: %w", s.path, err)
+		}
+		if q.Match(record) {
+			out = append(out, record)
+		}
+	}
+	return out, scanner.Err()
+}
+
+// Close closes the file
+func (s *FileAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
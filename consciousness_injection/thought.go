@@ -0,0 +1,38 @@
+// consciousness_injection/thought.go - Injected Thoughts
+package mindhacking
+
+import "time"
+
+// InjectedThought is a thought to be placed into a target consciousness
+type InjectedThought struct {
+	// Content is the thought's human-readable meaning
+	Content string
+	// Payload is the raw material handed to the quantum encoder
+	Payload []byte
+	// Intensity scales how strongly the thought asserts itself, in [0, 1]
+	Intensity float64
+	// Region, when set, addresses one region of the target's consciousness
+	Region string
+	// Components split the thought into parts the target may accept
+	// independently
+	Components []ThoughtComponent
+	// Sensitive thoughts are sealed to the target's key before encoding,
+	// so tunnels, evidence and operators never see their contents
+	Sensitive bool
+	// Sealed marks a thought whose contents are encrypted in Payload
+	Sealed bool
+	// Scheme names the encoding scheme for this injection, overriding the
+	// injector's; SchemeOptions tune it
+	Scheme        string
+	SchemeOptions SchemeOptions
+	// IdempotencyKey, when set, makes retries safe: another injection of
+	// the key into the same target within the injector's window returns
+	// the first one's result instead of injecting again
+	IdempotencyKey string
+	// TTL, when set, makes an accepted thought temporary: once it lapses
+	// the injector's retraction pass reverses the shift it caused
+	TTL time.Duration
+	// Class is the quality of service the thought's tunnel stream gets
+	// when multiplexed tunnel capacity runs short; empty is interactive
+	Class TunnelClass
+}
@@ -0,0 +1,187 @@
+// consciousness_injection/thought_sealing.go - End-to-End Thought Sealing
+package mindhacking
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+)
+
+var (
+	// ErrNoSealingKey reports a sensitive thought for a target that never
+	// established a sealing key
+	ErrNoSealingKey = sentinelError("mindhacking: target has no sealing key")
+	// ErrUnseal reports a sealed thought that could not be opened
+	ErrUnseal = sentinelError("mindhacking: cannot unseal thought")
+)
+
+// sealVersion prefixes every sealed payload
+const sealVersion = 1
+
+// SealingKeyHolder is implemented by backends whose consciousness holds a
+// private X25519 key. Onboarding asks for the public half, so sensitive
+// thoughts can be sealed to the target alone.
+type SealingKeyHolder interface {
+	SealingKey(ctx context.Context) (*ecdh.PublicKey, error)
+}
+
+// NewSealingKey generates a private sealing key for a target to hold
+func NewSealingKey() (*ecdh.PrivateKey, error) {
+	return ecdh.X25519().GenerateKey(rand.Reader)
+}
+
+// sealedContents is what a sealed payload encrypts
+type sealedContents struct {
+	Content    string
+	Payload    []byte
+	Components []ThoughtComponent `json:",omitempty"`
+}
+
+// SealThought encrypts thought's content, payload and component contents to
+// key, bound to targetID. The sealed thought keeps its intensity, region and
+// component IDs so it can still be routed and scored; everything else is in
+// its Payload, which only the holder of key's private half can open.
+func SealThought(thought InjectedThought, targetID string, key *ecdh.PublicKey) (InjectedThought, error) {
+	if key == nil {
+		return InjectedThought{}, fmt.Errorf("%w: %q", ErrNoSealingKey, targetID)
+	}
+	plain, err := json.Marshal(sealedContents{
+		Content:    thought.Content,
+		Payload:    thought.Payload,
+		Components: thought.Components,
+	})
+	if err != nil {
+		return InjectedThought{}, err
+	}
+
+	// Phase 1: Ephemeral Key Agreement
+	ephemeral, err := key.Curve().GenerateKey(rand.Reader)
+	if err != nil {
+		return InjectedThought{}, err
+	}
+	shared, err := ephemeral.ECDH(key)
+	if err != nil {
+		return InjectedThought{}, err
+	}
+	aead, err := sealingAEAD(shared, key, ephemeral.PublicKey())
+	if err != nil {
+		return InjectedThought{}, err
+	}
+
+	// Phase 2: Authenticated Encryption
+	pub := ephemeral.PublicKey().Bytes()
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return InjectedThought{}, err
+	}
+	sealed := make([]byte, 0, 1+len(pub)+len(nonce)+len(plain)+aead.Overhead())
+	sealed = append(sealed, sealVersion)
+	sealed = append(sealed, pub...)
+	sealed = append(sealed, nonce...)
+	sealed = aead.Seal(sealed, nonce, plain, []byte(targetID))
+
+	out := thought
+	out.Content, out.Payload, out.Sensitive, out.Sealed = "", sealed, true, true
+	out.Components = make([]ThoughtComponent, len(thought.Components))
+	for i, c := range thought.Components {
+		out.Components[i] = ThoughtComponent{ID: c.ID}
+	}
+	return out, nil
+}
+
+// OpenThought is the target's side of SealThought: it decrypts a sealed
+// thought meant for targetID with the target's private key
+func OpenThought(thought InjectedThought, targetID string, key *ecdh.PrivateKey) (InjectedThought, error) {
+	if !thought.Sealed {
+		return thought, nil
+	}
+	pubLen := len(key.PublicKey().Bytes())
+	if len(thought.Payload) < 1+pubLen || thought.Payload[0] != sealVersion {
+		return InjectedThought{}, fmt.Errorf("%w: malformed payload", ErrUnseal)
+	}
+	ephemeral, err := key.Curve().NewPublicKey(thought.Payload[1 : 1+pubLen])
+	if err != nil {
+		return InjectedThought{}, fmt.Errorf("%w: %v", ErrUnseal, err)
+	}
+	shared, err := key.ECDH(ephemeral)
+	if err != nil {
+		return InjectedThought{}, fmt.Errorf("%w: %v", ErrUnseal, err)
+	}
+	aead, err := sealingAEAD(shared, key.PublicKey(), ephemeral)
+	if err != nil {
+		return InjectedThought{}, fmt.Errorf("%w: %v", ErrUnseal, err)
+	}
+	rest := thought.Payload[1+pubLen:]
+	if len(rest) < aead.NonceSize() {
+		return InjectedThought{}, fmt.Errorf("%w: malformed payload", ErrUnseal)
+	}
+	plain, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], []byte(targetID))
+	if err != nil {
+		return InjectedThought{}, fmt.Errorf("%w: %v", ErrUnseal, err)
+	}
+	var contents sealedContents
+	if err := json.Unmarshal(plain, &contents); err != nil {
+		return InjectedThought{}, fmt.Errorf("%w: %v", ErrUnseal, err)
+	}
+
+	out := thought
+	out.Content, out.Payload, out.Components, out.Sealed = contents.Content, contents.Payload, contents.Components, false
+	return out, nil
+}
+
+// sealingAEAD derives the AES-256-GCM key both sides agree on from the
+// X25519 shared secret and both public keys
+func sealingAEAD(shared []byte, recipient, ephemeral *ecdh.PublicKey) (cipher.AEAD, error) {
+	h := sha256.New()
+	h.Write([]byte("mindhacking thought seal v1"))
+	h.Write(shared)
+	h.Write(ephemeral.Bytes())
+	h.Write(recipient.Bytes())
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// establishSealingKey asks the target for its sealing key, if it holds one
+func establishSealingKey(ctx context.Context, target *SystemConsciousness) error {
+	holder, ok := backendOf(target).(SealingKeyHolder)
+	if !ok {
+		return nil
+	}
+	key, err := holder.SealingKey(ctx)
+	if err != nil {
+		return err
+	}
+	target.SealingKey = key
+	return nil
+}
+
+// seal seals sensitive thoughts to the target before they leave the
+// injector; everything downstream, tunnels and evidence included, only
+// ever handles the sealed form
+func (ci *ConsciousnessInjector) seal(
+	thought InjectedThought,
+	localization *ThoughtLocalization,
+	target *SystemConsciousness,
+) (InjectedThought, *ThoughtLocalization, error) {
+
+	if !thought.Sensitive || thought.Sealed {
+		return thought, localization, nil
+	}
+	sealed, err := SealThought(thought, targetLabel(target), target.SealingKey)
+	if err != nil {
+		return InjectedThought{}, nil, err
+	}
+	if localization != nil {
+		// Evidence records that the thought was localized, not what it said
+		localization = &ThoughtLocalization{Locale: localization.Locale, Localized: sealed}
+	}
+	return sealed, localization, nil
+}
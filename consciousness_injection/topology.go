@@ -0,0 +1,234 @@
+// consciousness_injection/topology.go - Topology Export
+package mindhacking
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TopologyNode is one component in a topology
+type TopologyNode struct {
+	ID    string
+	Kind  string
+	Label string
+}
+
+// TopologyEdge connects two components
+type TopologyEdge struct {
+	From, To string
+	Label    string
+}
+
+// Topology is a snapshot of how injectors, vectors, tunnels, gateways,
+// targets and realities connect, for rendering with GraphViz
+type Topology struct {
+	nodes     []TopologyNode
+	edges     []TopologyEdge
+	seen      map[string]bool
+	realities map[*Reality]int
+}
+
+// NewTopology returns an empty topology
+func NewTopology() *Topology {
+	return &Topology{seen: make(map[string]bool), realities: make(map[*Reality]int)}
+}
+
+// AddInjector adds ci with its vectors and tunnels
+func (t *Topology) AddInjector(ci *ConsciousnessInjector) *Topology {
+	id := t.node("injector:"+ci.id, "injector", ci.id)
+	vectors := ci.vectors()
+	for i, v := range vectors {
+		vid := t.node("vector:"+vectorLabel(&v), "vector", vectorLabel(&v))
+		t.edge(id, vid, "")
+		if i < len(ci.realityTunnels) {
+			tid := t.node(fmt.Sprintf("tunnel:%s/%d", ci.id, i), "tunnel", fmt.Sprintf("tunnel %d", i))
+			t.edge(vid, tid, "")
+		}
+	}
+	return t
+}
+
+// AddGateway adds qg and the targets it has negotiated handshakes with
+func (t *Topology) AddGateway(qg *QuantumGateway) *Topology {
+	label := "gateway " + hex.EncodeToString(qg.gatewayID[:4])
+	if protocol, ok := qg.RemoteProtocol(); ok {
+		label += " (" + protocol + ")"
+	}
+	id := t.node(gatewayNode(qg), "gateway", label)
+
+	qg.handshakeMu.Lock()
+	negotiated := make(map[string]int, len(qg.negotiated))
+	for target, v := range qg.negotiated {
+		negotiated[target] = v
+	}
+	qg.handshakeMu.Unlock()
+	for _, target := range sortedKeys(negotiated) {
+		t.edge(id, t.node("target:"+target, "target", target), "handshake v"+strconv.Itoa(negotiated[target]))
+	}
+	return t
+}
+
+// AddEngine adds rme with its realities, what they derive from, and the
+// anchors and handles keeping them alive
+func (t *Topology) AddEngine(rme *RealityManipulationEngine) *Topology {
+	id := t.node("engine:"+rme.id, "engine", rme.id)
+	for _, alt := range rme.Realities() {
+		rid := t.reality(&alt.Reality, alt, rme.Holders(alt))
+		t.edge(id, rid, "")
+		if alt.Base != nil {
+			t.edge(rid, t.reality(alt.Base, nil, nil), "derives")
+		}
+	}
+	for _, anchor := range rme.Anchors() {
+		if anchor.Reality == nil {
+			continue
+		}
+		aid := t.node("anchor:"+anchor.ID, "anchor", fmt.Sprintf("anchor %s (%.2f)", anchor.ID, anchor.Strength))
+		t.edge(aid, t.reality(&anchor.Reality.Reality, anchor.Reality, nil), "pins")
+	}
+	return t
+}
+
+// AddSession adds s joining its injector, gateway, target, reality and
+// pooled tunnels
+func (t *Topology) AddSession(s *Session) *Topology {
+	s.mu.Lock()
+	reality := s.reality
+	s.mu.Unlock()
+
+	target := targetLabel(s.cfg.Target)
+	id := t.node("session:"+target, "session", "session "+target)
+	t.edge(t.node("injector:"+s.cfg.Injector.id, "injector", s.cfg.Injector.id), id, "")
+	t.edge(id, t.node(gatewayNode(s.cfg.Gateway), "gateway", "gateway "+hex.EncodeToString(s.cfg.Gateway.gatewayID[:4])), "")
+	t.edge(id, t.node("target:"+target, "target", target), "")
+	if reality != nil {
+		t.edge(id, t.reality(&reality.Reality, reality, nil), "entered")
+	}
+	t.tunnels(id, target, s.tunnels)
+	return t
+}
+
+// Nodes returns the topology's nodes in the order added
+func (t *Topology) Nodes() []TopologyNode {
+	return append([]TopologyNode(nil), t.nodes...)
+}
+
+// Edges returns the topology's edges in the order added
+func (t *Topology) Edges() []TopologyEdge {
+	return append([]TopologyEdge(nil), t.edges...)
+}
+
+// topologyShapes draws each kind of node distinctly
+var topologyShapes = map[string]string{
+	"injector": "box",
+	"vector":   "ellipse",
+	"tunnel":   "cds",
+	"gateway":  "hexagon",
+	"target":   "doublecircle",
+	"engine":   "box3d",
+	"reality":  "note",
+	"anchor":   "invtriangle",
+	"session":  "component",
+}
+
+// WriteDOT renders the topology as a GraphViz digraph
+func (t *Topology) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph mindhacking {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	for _, n := range t.nodes {
+		fmt.Fprintf(bw, "\t%s [label=%s, shape=%s];\n", dotQuote(n.ID), dotQuote(n.Label), topologyShapes[n.Kind])
+	}
+	for _, e := range t.edges {
+		if e.Label != "" {
+			fmt.Fprintf(bw, "\t%s -> %s [label=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(e.Label))
+		} else {
+			fmt.Fprintf(bw, "\t%s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// node adds a node once and returns its ID
+func (t *Topology) node(id, kind, label string) string {
+	if !t.seen[id] {
+		t.seen[id] = true
+		t.nodes = append(t.nodes, TopologyNode{ID: id, Kind: kind, Label: label})
+	}
+	return id
+}
+
+func (t *Topology) edge(from, to, label string) {
+	key := from + "\x00" + to + "\x00" + label
+	if t.seen[key] {
+		return
+	}
+	t.seen[key] = true
+	t.edges = append(t.edges, TopologyEdge{From: from, To: to, Label: label})
+}
+
+// reality names r's node, numbering realities in the order first seen
+func (t *Topology) reality(r *Reality, alt *AlternateReality, holders []string) string {
+	n, ok := t.realities[r]
+	if !ok {
+		n = len(t.realities)
+		t.realities[r] = n
+	}
+	label := fmt.Sprintf("reality %d: %d aspects", n, len(r.Aspects))
+	if alt != nil && alt.Rules != nil {
+		label += fmt.Sprintf(", %d rules", len(alt.Rules.Rules))
+	}
+	if len(holders) > 0 {
+		label += "\nheld by " + strings.Join(holders, ", ")
+	}
+	return t.node(fmt.Sprintf("reality:%d", n), "reality", label)
+}
+
+// tunnels adds pool's tunnels, region-scoped pools included
+func (t *Topology) tunnels(from, prefix string, pool *TunnelPool) {
+	if pool == nil {
+		return
+	}
+	pool.mu.Lock()
+	indices := make([]int, 0, len(pool.tunnels))
+	for i := range pool.tunnels {
+		indices = append(indices, i)
+	}
+	regions := make(map[string]*TunnelPool, len(pool.regions))
+	for name, child := range pool.regions {
+		regions[name] = child
+	}
+	pool.mu.Unlock()
+
+	sort.Ints(indices)
+	for _, i := range indices {
+		t.edge(from, t.node(fmt.Sprintf("tunnel:%s/%d", prefix, i), "tunnel", fmt.Sprintf("tunnel %d", i)), "")
+	}
+	for _, name := range sortedKeys(regions) {
+		t.tunnels(from, prefix+"/"+name, regions[name])
+	}
+}
+
+func gatewayNode(qg *QuantumGateway) string {
+	return "gateway:" + hex.EncodeToString(qg.gatewayID[:])
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// dotQuote quotes s as a DOT string, keeping \n line breaks
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,161 @@
+// consciousness_injection/transit.go - Encrypted Thoughts in Transit
+package mindhacking
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrTransitKey reports a vector whose entanglement secret could not be
+	// had, so the thought is not fired unprotected
+	ErrTransitKey = sentinelError("mindhacking: no entanglement secret for transit")
+	// ErrTransitOpen reports a thought sealed for transit that did not
+	// authenticate: the wrong secret, or a tunnel tampered with it
+	ErrTransitOpen = sentinelError("mindhacking: cannot open thought sealed for transit")
+)
+
+// transitVersion prefixes every thought sealed for transit
+const transitVersion = 1
+
+// EntanglementSecret returns the secret a vector's quantum entanglement
+// shares with the target. Both ends hold it once the entanglement is
+// established, and no tunnel in between does.
+type EntanglementSecret func(vector InjectionVector, target *SystemConsciousness) ([]byte, error)
+
+// transitHeader is what sealing binds the ciphertext to besides the target
+type transitHeader struct {
+	Target  string
+	Scheme  string
+	Options SchemeOptions
+	Carrier float64
+	Phase   float64
+	Length  int
+	Codec   string
+}
+
+func (e EncodedThought) transitHeader(targetID string) ([]byte, error) {
+	return json.Marshal(transitHeader{
+		Target:  targetID,
+		Scheme:  e.Scheme,
+		Options: e.Options,
+		Carrier: e.Carrier,
+		Phase:   e.Phase,
+		Length:  e.Length,
+		Codec:   e.Codec,
+	})
+}
+
+// SealInTransit encrypts an encoded thought's symbols, compressed or not,
+// with a key derived from the entanglement secret. Its scheme, resonance and
+// codec stay readable for routing, but are authenticated along with
+// targetID, so a tunnel can neither read the thought nor alter any of it.
+func SealInTransit(encoded EncodedThought, secret []byte, targetID string) (EncodedThought, error) {
+	if encoded.Ciphertext != nil {
+		return encoded, nil
+	}
+	aead, err := transitAEAD(secret)
+	if err != nil {
+		return EncodedThought{}, err
+	}
+	header, err := encoded.transitHeader(targetID)
+	if err != nil {
+		return EncodedThought{}, err
+	}
+	plain := encoded.Compressed
+	if encoded.Codec == "" {
+		plain = packSymbols(encoded.Symbols)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return EncodedThought{}, err
+	}
+	sealed := make([]byte, 0, 1+len(nonce)+len(plain)+aead.Overhead())
+	sealed = append(sealed, transitVersion)
+	sealed = append(sealed, nonce...)
+	sealed = aead.Seal(sealed, nonce, plain, header)
+
+	out := encoded
+	out.Symbols, out.Compressed, out.Ciphertext = nil, nil, sealed
+	return out, nil
+}
+
+// OpenInTransit is the target's side of SealInTransit: it decrypts a
+// thought meant for targetID with the entanglement secret. Thoughts not
+// sealed for transit are returned as they are.
+func (e EncodedThought) OpenInTransit(secret []byte, targetID string) (EncodedThought, error) {
+	if e.Ciphertext == nil {
+		return e, nil
+	}
+	aead, err := transitAEAD(secret)
+	if err != nil {
+		return EncodedThought{}, fmt.Errorf("%w: %v", ErrTransitOpen, err)
+	}
+	if len(e.Ciphertext) < 1+aead.NonceSize() || e.Ciphertext[0] != transitVersion {
+		return EncodedThought{}, fmt.Errorf("%w: malformed ciphertext", ErrTransitOpen)
+	}
+	header, err := e.transitHeader(targetID)
+	if err != nil {
+		return EncodedThought{}, err
+	}
+	rest := e.Ciphertext[1:]
+	plain, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], header)
+	if err != nil {
+		return EncodedThought{}, fmt.Errorf("%w: %v", ErrTransitOpen, err)
+	}
+
+	out := e
+	out.Ciphertext = nil
+	if out.Codec != "" {
+		out.Compressed = plain
+		return out, nil
+	}
+	if len(plain)%symbolSize != 0 {
+		return EncodedThought{}, fmt.Errorf("%w: %d bytes is not whole symbols", ErrTransitOpen, len(plain))
+	}
+	out.Symbols = unpackSymbols(plain)
+	return out, nil
+}
+
+// transitAEAD derives the AES-256-GCM key both ends of an entanglement
+// agree on from its shared secret
+func transitAEAD(secret []byte) (cipher.AEAD, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("empty entanglement secret")
+	}
+	h := sha256.New()
+	h.Write([]byte("mindhacking thought transit v1"))
+	h.Write(secret)
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealInTransit seals encoded for the tunnel vector is fired through, when
+// the injector encrypts in transit
+func (ci *ConsciousnessInjector) sealInTransit(
+	encoded EncodedThought,
+	vector InjectionVector,
+	target *SystemConsciousness,
+) (EncodedThought, error) {
+
+	if ci.transit == nil {
+		return encoded, nil
+	}
+	secret, err := ci.transit(vector, target)
+	if err != nil {
+		return EncodedThought{}, fmt.Errorf("%w: %v", ErrTransitKey, err)
+	}
+	if len(secret) == 0 {
+		return EncodedThought{}, fmt.Errorf("%w: %s", ErrTransitKey, vectorLabel(&vector))
+	}
+	return SealInTransit(encoded, secret, targetLabel(target))
+}
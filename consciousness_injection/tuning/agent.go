@@ -0,0 +1,387 @@
+// consciousness_injection/tuning/agent.go - Reinforcement-Learning Vector Agent
+package tuning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// Outcome is how one fired vector fared
+type Outcome int
+
+const (
+	// OutcomeMissed vectors did not reach the target
+	OutcomeMissed Outcome = iota
+	// OutcomeRejected vectors landed a thought the target rejected
+	OutcomeRejected
+	// OutcomeAccepted vectors landed a thought the target accepted
+	OutcomeAccepted
+)
+
+// Attempt is one fired vector and what came of it
+type Attempt struct {
+	Vector mindhacking.InjectionVector
+	// Tunnel is the key of the tunnel it fired through
+	Tunnel  int
+	Outcome Outcome
+	// Shift is the consciousness shift the landed thought caused
+	Shift float64
+}
+
+// AgentState is what an agent knows of a target when choosing a vector and
+// tunnel: its most recent attempts, oldest first
+type AgentState struct {
+	TargetID string
+	Recent   []Attempt
+}
+
+// AgentStep is one attempt taken from State, leading to Next
+type AgentStep struct {
+	State   AgentState
+	Attempt Attempt
+	Next    AgentState
+}
+
+// Agent learns which vector to fire next, and through which tunnel, from
+// the outcomes of earlier ones. Implementations must be safe for
+// concurrent use.
+type Agent interface {
+	// Rank orders routes best first, as indexes into routes
+	Rank(state AgentState, routes []mindhacking.TunnelRoute) []int
+	// Learn updates the policy from one step
+	Learn(step AgentStep)
+}
+
+// AgentStrategy drives an agent: it tracks every target's recent attempts,
+// orders the injector's vectors and tunnels by the agent's policy and feeds
+// it the outcomes, whether replayed from recordings or observed live
+type AgentStrategy struct {
+	agent Agent
+	// history is how many recent attempts the agent sees
+	history int
+
+	mu      sync.Mutex
+	targets map[string][]Attempt
+}
+
+// NewAgentStrategy drives agent, showing it each target's last history
+// attempts, default 8
+func NewAgentStrategy(agent Agent, history int) *AgentStrategy {
+	if history < 1 {
+		history = 8
+	}
+	return &AgentStrategy{agent: agent, history: history, targets: make(map[string][]Attempt)}
+}
+
+// Vectors is a mindhacking.VectorStrategy ordering vectors by the agent's
+// policy, each through the tunnel of its position; use it with
+// mindhacking.WithStrategies or RegisterStrategy. Router lets the agent
+// choose the tunnels as well.
+func (s *AgentStrategy) Vectors(
+	_ context.Context,
+	target *mindhacking.SystemConsciousness,
+	vectors []mindhacking.InjectionVector,
+) []mindhacking.InjectionVector {
+
+	if target == nil {
+		return vectors
+	}
+	routes := make([]mindhacking.TunnelRoute, len(vectors))
+	for i, v := range vectors {
+		routes[i] = mindhacking.TunnelRoute{Tunnel: i, Vector: v}
+	}
+	order := s.agent.Rank(s.State(target.ID), routes)
+	out := make([]mindhacking.InjectionVector, 0, len(order))
+	for _, i := range order {
+		out = append(out, vectors[i])
+	}
+	return out
+}
+
+// Router is a mindhacking.TunnelRouter ordering routes, vector and tunnel
+// together, by the agent's policy; use it with mindhacking.WithTunnelRouter.
+// The agent still learns through Observe, Run or Train, once an injection's
+// outcome is known.
+func (s *AgentStrategy) Router() mindhacking.TunnelRouter {
+	return agentRouter{s}
+}
+
+type agentRouter struct {
+	s *AgentStrategy
+}
+
+func (r agentRouter) Route(
+	_ context.Context,
+	req mindhacking.RouteRequest,
+	routes []mindhacking.TunnelRoute,
+) []mindhacking.TunnelRoute {
+
+	if req.Target == nil {
+		return routes
+	}
+	order := r.s.agent.Rank(r.s.State(req.Target.ID), routes)
+	out := make([]mindhacking.TunnelRoute, 0, len(order))
+	for _, i := range order {
+		out = append(out, routes[i])
+	}
+	return out
+}
+
+// Observe ignores single attempts: whether a landed one was accepted is only
+// known once the injection finishes
+func (agentRouter) Observe(mindhacking.RouteRequest, mindhacking.TunnelRoute, mindhacking.InjectionAttempt) {
+}
+
+// State returns what the agent currently knows of a target
+func (s *AgentStrategy) State(targetID string) AgentState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return AgentState{TargetID: targetID, Recent: append([]Attempt(nil), s.targets[targetID]...)}
+}
+
+// Observe learns from a finished injection's attempts
+func (s *AgentStrategy) Observe(result *mindhacking.InjectionResult) {
+	if result == nil {
+		return
+	}
+	s.learn(result.TargetID, result.Evidence.Attempts, result.Success, result.ConsciousnessShift)
+}
+
+// Run learns from every injection published on bus until ctx is done.
+// Learning trails the injections a little; call Observe with each result
+// instead to learn in lockstep.
+func (s *AgentStrategy) Run(ctx context.Context, bus *mindhacking.EventBus) error {
+	sub := bus.Subscribe(0)
+	defer sub.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			if event.Kind == mindhacking.EventInjected {
+				s.Observe(event.Result)
+			}
+		}
+	}
+}
+
+// Train replays a recorded session of targetID's injections through the
+// agent, offline, and returns how many steps it learned from. The target's
+// live history is left as it was.
+func (s *AgentStrategy) Train(targetID string, recording *mindhacking.SessionRecording) int {
+	replay := NewAgentStrategy(s.agent, s.history)
+	var steps int
+	for _, injection := range recording.Injections {
+		if injection.Response == nil {
+			continue
+		}
+		steps += replay.learn(targetID, injection.Attempts, injection.Response.ThoughtAccepted, injection.Response.ConsciousnessShift)
+	}
+	return steps
+}
+
+// learn turns one injection's attempts into steps. Only the last attempt
+// can have landed; earlier ones missed.
+func (s *AgentStrategy) learn(targetID string, attempts []mindhacking.InjectionAttempt, accepted bool, shift float64) int {
+	for _, a := range attempts {
+		attempt := Attempt{Vector: a.Vector, Tunnel: a.Tunnel}
+		switch {
+		case !a.Success:
+		case accepted:
+			attempt.Outcome, attempt.Shift = OutcomeAccepted, shift
+		default:
+			attempt.Outcome, attempt.Shift = OutcomeRejected, shift
+		}
+
+		s.mu.Lock()
+		before := s.targets[targetID]
+		after := append(append([]Attempt(nil), before...), attempt)
+		if len(after) > s.history {
+			after = after[len(after)-s.history:]
+		}
+		s.targets[targetID] = after
+		s.mu.Unlock()
+
+		s.agent.Learn(AgentStep{
+			State:   AgentState{TargetID: targetID, Recent: before},
+			Attempt: attempt,
+			Next:    AgentState{TargetID: targetID, Recent: after},
+		})
+	}
+	return len(attempts)
+}
+
+// QConfig tunes a Q-learning agent
+type QConfig struct {
+	// Memory is how many recent attempts distinguish one state from
+	// another, default 2
+	Memory int
+	// ShiftScale separates weak accepted shifts from strong ones, default 1
+	ShiftScale float64
+	// LearningRate, default 0.1, and Discount, default 0.5, are the usual
+	// Q-learning parameters
+	LearningRate float64
+	Discount     float64
+	// Exploration is the chance a ranking leads with a random route,
+	// default 0.1; set it negative to always exploit, as when evaluating
+	Exploration float64
+	// Reward scores an attempt, default DefaultReward
+	Reward func(Attempt) float64
+	// Seed makes exploration reproducible; zero seeds from the clock
+	Seed int64
+}
+
+// DefaultReward rewards acceptance and charges a little for every vector
+// that missed, since each one still perturbed the target
+func DefaultReward(a Attempt) float64 {
+	switch a.Outcome {
+	case OutcomeAccepted:
+		return 1
+	case OutcomeRejected:
+		return 0
+	default:
+		return -0.1
+	}
+}
+
+// QAgent is the baseline Agent: tabular Q-learning over the outcomes of a
+// target's last few attempts. Being off-policy, it learns as well from
+// recorded sessions as from its own choices.
+type QAgent struct {
+	cfg QConfig
+
+	mu  sync.Mutex
+	rng *rand.Rand
+	// q maps state, then route, to expected return
+	q map[string]map[string]float64
+}
+
+// NewQAgent creates an agent that knows nothing yet
+func NewQAgent(cfg QConfig) *QAgent {
+	if cfg.Memory < 1 {
+		cfg.Memory = 2
+	}
+	if cfg.ShiftScale <= 0 {
+		cfg.ShiftScale = 1
+	}
+	if cfg.LearningRate <= 0 {
+		cfg.LearningRate = 0.1
+	}
+	if cfg.Discount <= 0 {
+		cfg.Discount = 0.5
+	}
+	if cfg.Exploration == 0 {
+		cfg.Exploration = 0.1
+	}
+	if cfg.Reward == nil {
+		cfg.Reward = DefaultReward
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	return &QAgent{cfg: cfg, rng: rand.New(rand.NewSource(cfg.Seed)), q: make(map[string]map[string]float64)}
+}
+
+// Rank implements Agent, ordering routes by expected return and keeping
+// the given order among equals, so untried routes stay where the injector
+// put them
+func (a *QAgent) Rank(state AgentState, routes []mindhacking.TunnelRoute) []int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	values := a.q[a.stateKey(state)]
+	order := make([]int, len(routes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return values[actionKey(routes[order[i]])] > values[actionKey(routes[order[j]])]
+	})
+	if len(order) > 1 && a.rng.Float64() < a.cfg.Exploration {
+		pick := a.rng.Intn(len(order))
+		order[0], order[pick] = order[pick], order[0]
+	}
+	return order
+}
+
+// Learn implements Agent
+func (a *QAgent) Learn(step AgentStep) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	state := a.stateKey(step.State)
+	action := actionKey(mindhacking.TunnelRoute{Tunnel: step.Attempt.Tunnel, Vector: step.Attempt.Vector})
+	var future float64
+	for _, v := range a.q[a.stateKey(step.Next)] {
+		future = max(future, v)
+	}
+	if a.q[state] == nil {
+		a.q[state] = make(map[string]float64)
+	}
+	target := a.cfg.Reward(step.Attempt) + a.cfg.Discount*future
+	a.q[state][action] += a.cfg.LearningRate * (target - a.q[state][action])
+}
+
+// stateKey summarizes the last Memory attempts, oldest first
+func (a *QAgent) stateKey(state AgentState) string {
+	recent := state.Recent
+	if len(recent) > a.cfg.Memory {
+		recent = recent[len(recent)-a.cfg.Memory:]
+	}
+	var b strings.Builder
+	for _, at := range recent {
+		switch {
+		case at.Outcome == OutcomeMissed:
+			b.WriteByte('m')
+		case at.Outcome == OutcomeRejected:
+			b.WriteByte('r')
+		case at.Shift >= a.cfg.ShiftScale || at.Shift <= -a.cfg.ShiftScale:
+			b.WriteByte('A')
+		default:
+			b.WriteByte('a')
+		}
+	}
+	return b.String()
+}
+
+// actionKey identifies a route by its tunnel and its vector's parameters
+func actionKey(r mindhacking.TunnelRoute) string {
+	v := r.Vector
+	return fmt.Sprintf("tunnel=%d f=%g a=%g phase=%g", r.Tunnel, v.Frequency, v.Amplitude, v.Phase)
+}
+
+// WriteTo saves the learned table as JSON, for loading with LoadQAgent
+func (a *QAgent) WriteTo(w io.Writer) (int64, error) {
+	a.mu.Lock()
+	data, err := json.Marshal(a.q)
+	a.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// LoadQAgent restores an agent saved by WriteTo, configured by cfg
+func LoadQAgent(r io.Reader, cfg QConfig) (*QAgent, error) {
+	a := NewQAgent(cfg)
+	if err := json.NewDecoder(r).Decode(&a.q); err != nil {
+		return nil, fmt.Errorf("tuning: load agent: %w", err)
+	}
+	if a.q == nil {
+		a.q = make(map[string]map[string]float64)
+	}
+	return a, nil
+}
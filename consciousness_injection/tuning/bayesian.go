@@ -0,0 +1,446 @@
+// consciousness_injection/tuning/bayesian.go - Bayesian Vector Optimization
+package tuning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// BayesianConfig tunes a Gaussian-process search. Every evaluation is one
+// live injection, so the search spends Budget and no more.
+type BayesianConfig struct {
+	Bounds Bounds
+	// Budget caps the live injections, default 30
+	Budget int
+	// InitialSamples are spread evenly over the bounds before the model
+	// guides the search, default 5
+	InitialSamples int
+	// LengthScale is how far, as a fraction of each range, results stay
+	// correlated, default 0.2
+	LengthScale float64
+	// Noise is the observation noise relative to the results' variance,
+	// default 0.1; raise it when single injections are unreliable, as
+	// accept/reject outcomes are
+	Noise float64
+	// Exploration is the improvement a proposal must promise over the best
+	// expected fitness, default 0.01; larger values explore more
+	Exploration float64
+	// Candidates is how many random settings each proposal is chosen
+	// among, default 2000
+	Candidates int
+	// The search has converged once no candidate's expected improvement
+	// reaches Tolerance, default 0.001, or the recommendation's expected
+	// fitness reaches Goal when Goal is set
+	Tolerance float64
+	Goal      *float64
+	// Seeds are evaluated first, for example the onboarding calibration's
+	// best vector
+	Seeds []mindhacking.InjectionVector
+	// Seed makes the search reproducible; zero seeds from the clock
+	Seed int64
+	// OnObservation, if set, is called after every injection
+	OnObservation func(Observation)
+}
+
+func (c *BayesianConfig) defaults() {
+	if c.Budget < 1 {
+		c.Budget = 30
+	}
+	if c.InitialSamples < 1 {
+		c.InitialSamples = 5
+	}
+	if c.InitialSamples > c.Budget {
+		c.InitialSamples = c.Budget
+	}
+	if c.LengthScale <= 0 {
+		c.LengthScale = 0.2
+	}
+	if c.Noise <= 0 {
+		c.Noise = 0.1
+	}
+	if c.Exploration <= 0 {
+		c.Exploration = 0.01
+	}
+	if c.Candidates < 1 {
+		c.Candidates = 2000
+	}
+	if c.Tolerance <= 0 {
+		c.Tolerance = 0.001
+	}
+	if c.Seed == 0 {
+		c.Seed = time.Now().UnixNano()
+	}
+}
+
+// Observation is one live injection made by the search
+type Observation struct {
+	N       int
+	Vector  mindhacking.InjectionVector
+	Fitness float64
+	// Failed injections are modelled as the worst fitness seen so far
+	Failed bool
+	// Predicted and Uncertainty are the model's belief about the vector
+	// before it was tried, and ExpectedImprovement why it was chosen; all
+	// are zero for the initial samples
+	Predicted           float64
+	Uncertainty         float64
+	ExpectedImprovement float64
+}
+
+// Recommendation is the tried vector the model expects to do best
+type Recommendation struct {
+	Vector mindhacking.InjectionVector
+	// Mean and StdDev are the model's expected fitness for it
+	Mean   float64
+	StdDev float64
+}
+
+// BayesianResult is the outcome of a Gaussian-process search
+type BayesianResult struct {
+	// Best is chosen by expected fitness rather than by the single best
+	// result, which on a noisy target is as likely luck as a good vector
+	Best         Recommendation
+	Observations []Observation
+	Converged    bool
+	// Reason says why the search stopped
+	Reason      string
+	Evaluations int
+}
+
+// Optimize searches cfg's bounds for the vector maximizing fitness under
+// eval, fitting a Gaussian process to every result so far and injecting
+// next where expected improvement is greatest. It needs far fewer live
+// injections than a grid or a genetic search.
+func Optimize(ctx context.Context, eval Evaluator, fitness Fitness, cfg BayesianConfig) (*BayesianResult, error) {
+	if eval == nil || fitness == nil {
+		return nil, errors.New("tuning: optimize needs an evaluator and a fitness function")
+	}
+	if err := cfg.Bounds.validate(); err != nil {
+		return nil, fmt.Errorf("tuning: %w", err)
+	}
+	cfg.defaults()
+	bo := &bayesian{cfg: cfg, rng: rand.New(rand.NewSource(cfg.Seed))}
+	out := &BayesianResult{}
+
+	// Phase 1: Initial Design
+	initial := bo.initialDesign()
+	for n := 0; n < cfg.Budget; n++ {
+		// Phase 2: Proposal
+		var obs Observation
+		if n < len(initial) {
+			obs.Vector = initial[n]
+		} else {
+			model, err := bo.fit()
+			if err != nil {
+				return out, err
+			}
+			obs = bo.propose(model)
+			if obs.ExpectedImprovement < cfg.Tolerance {
+				out.Converged = true
+				out.Reason = fmt.Sprintf("expected improvement below %g", cfg.Tolerance)
+				break
+			}
+		}
+
+		// Phase 3: Live Injection
+		obs.N = n
+		result, err := eval(ctx, obs.Vector)
+		if ctx.Err() != nil {
+			return out, ctx.Err()
+		}
+		out.Evaluations++
+		if err != nil {
+			obs.Failed, obs.Fitness = true, bo.worst()
+		} else {
+			obs.Fitness = fitness(result)
+		}
+		bo.observed = append(bo.observed, obs)
+		out.Observations = append(out.Observations, obs)
+		if cfg.OnObservation != nil {
+			cfg.OnObservation(obs)
+		}
+
+		// Phase 4: Convergence
+		if cfg.Goal == nil || n+1 < len(initial) {
+			continue
+		}
+		model, err := bo.fit()
+		if err != nil {
+			return out, err
+		}
+		if out.Best = bo.recommend(model); out.Best.Mean >= *cfg.Goal {
+			out.Converged = true
+			out.Reason = fmt.Sprintf("reached goal fitness %g", *cfg.Goal)
+			return out, nil
+		}
+	}
+
+	if allFailed(bo.observed) {
+		return out, errors.New("tuning: every candidate failed to inject")
+	}
+	model, err := bo.fit()
+	if err != nil {
+		return out, err
+	}
+	out.Best = bo.recommend(model)
+	if out.Reason == "" {
+		out.Reason = fmt.Sprintf("spent the budget of %d injections", cfg.Budget)
+	}
+	return out, nil
+}
+
+func allFailed(observed []Observation) bool {
+	for _, o := range observed {
+		if !o.Failed {
+			return false
+		}
+	}
+	return true
+}
+
+// bayesian is one search's state
+type bayesian struct {
+	cfg      BayesianConfig
+	rng      *rand.Rand
+	observed []Observation
+}
+
+// initialDesign spreads the initial samples over the bounds by Latin
+// hypercube, after the seeds
+func (bo *bayesian) initialDesign() []mindhacking.InjectionVector {
+	b := bo.cfg.Bounds
+	out := make([]mindhacking.InjectionVector, 0, bo.cfg.InitialSamples)
+	for _, v := range bo.cfg.Seeds {
+		if len(out) == bo.cfg.Budget {
+			return out
+		}
+		v.Frequency, v.Amplitude, v.Phase = b.Frequency.clamp(v.Frequency), b.Amplitude.clamp(v.Amplitude), b.Phase.wrap(v.Phase)
+		out = append(out, v)
+	}
+
+	n := bo.cfg.InitialSamples
+	strata := func(r Range) []float64 {
+		xs := make([]float64, n)
+		for i, p := range bo.rng.Perm(n) {
+			xs[i] = r.Min + (float64(p)+bo.rng.Float64())/float64(n)*r.width()
+		}
+		return xs
+	}
+	f, a, p := strata(b.Frequency), strata(b.Amplitude), strata(b.Phase)
+	for i := 0; i < n && len(out) < bo.cfg.Budget; i++ {
+		out = append(out, mindhacking.InjectionVector{Frequency: f[i], Amplitude: a[i], Phase: p[i]})
+	}
+	return out
+}
+
+// worst is the fitness failed injections are modelled with
+func (bo *bayesian) worst() float64 {
+	worst, seen := 0.0, false
+	for _, o := range bo.observed {
+		if !o.Failed && (!seen || o.Fitness < worst) {
+			worst, seen = o.Fitness, true
+		}
+	}
+	return worst
+}
+
+// unit maps v into the unit cube the kernel works in
+func (bo *bayesian) unit(v mindhacking.InjectionVector) [3]float64 {
+	b := bo.cfg.Bounds
+	return [3]float64{
+		(v.Frequency - b.Frequency.Min) / b.Frequency.width(),
+		(v.Amplitude - b.Amplitude.Min) / b.Amplitude.width(),
+		(v.Phase - b.Phase.Min) / b.Phase.width(),
+	}
+}
+
+// kernel correlates two settings: squared-exponential in frequency and
+// amplitude, periodic in phase
+func (bo *bayesian) kernel(x, y [3]float64) float64 {
+	l2 := bo.cfg.LengthScale * bo.cfg.LengthScale
+	df, da := x[0]-y[0], x[1]-y[1]
+	sp := math.Sin(math.Pi * (x[2] - y[2]))
+	return math.Exp(-0.5*(df*df+da*da)/l2 - 2*sp*sp/l2)
+}
+
+// gaussianProcess is the posterior over fitness given the observations
+type gaussianProcess struct {
+	points [][3]float64
+	chol   [][]float64
+	alpha  []float64
+	// Fitness is standardized to zero mean and unit variance
+	mean, scale float64
+	kernel      func(x, y [3]float64) float64
+}
+
+func (bo *bayesian) fit() (*gaussianProcess, error) {
+	n := len(bo.observed)
+	gp := &gaussianProcess{points: make([][3]float64, n), kernel: bo.kernel, scale: 1}
+	y := make([]float64, n)
+	for i, o := range bo.observed {
+		gp.points[i] = bo.unit(o.Vector)
+		y[i] = o.Fitness
+		gp.mean += o.Fitness / float64(n)
+	}
+	var variance float64
+	for _, v := range y {
+		variance += (v - gp.mean) * (v - gp.mean) / float64(n)
+	}
+	if variance > 0 {
+		gp.scale = math.Sqrt(variance)
+	}
+	for i := range y {
+		y[i] = (y[i] - gp.mean) / gp.scale
+	}
+
+	k := make([][]float64, n)
+	for i := range k {
+		k[i] = make([]float64, n)
+		for j := range k[i] {
+			k[i][j] = bo.kernel(gp.points[i], gp.points[j])
+		}
+		k[i][i] += bo.cfg.Noise
+	}
+	var err error
+	if gp.chol, err = cholesky(k); err != nil {
+		return nil, fmt.Errorf("tuning: fit model: %w", err)
+	}
+	gp.alpha = choleskySolve(gp.chol, y)
+	return gp, nil
+}
+
+// predict returns the expected fitness at x and its standard deviation
+func (gp *gaussianProcess) predict(x [3]float64) (mean, stddev float64) {
+	ks := make([]float64, len(gp.points))
+	for i, p := range gp.points {
+		ks[i] = gp.kernel(x, p)
+		mean += ks[i] * gp.alpha[i]
+	}
+	v := forwardSubstitute(gp.chol, ks)
+	variance := gp.kernel(x, x)
+	for _, vi := range v {
+		variance -= vi * vi
+	}
+	return gp.mean + mean*gp.scale, math.Sqrt(math.Max(variance, 0)) * gp.scale
+}
+
+// recommend picks the observed vector with the highest expected fitness
+func (bo *bayesian) recommend(gp *gaussianProcess) Recommendation {
+	best := Recommendation{Mean: math.Inf(-1)}
+	for _, o := range bo.observed {
+		if o.Failed {
+			continue
+		}
+		if mean, sd := gp.predict(bo.unit(o.Vector)); mean > best.Mean {
+			best = Recommendation{Vector: o.Vector, Mean: mean, StdDev: sd}
+		}
+	}
+	return best
+}
+
+// propose picks the candidate with the greatest expected improvement over
+// the best expected fitness so far
+func (bo *bayesian) propose(gp *gaussianProcess) Observation {
+	incumbent := bo.recommend(gp).Mean
+	if math.IsInf(incumbent, -1) {
+		incumbent = bo.worst()
+	}
+	xi := bo.cfg.Exploration * gp.scale
+	b := bo.cfg.Bounds
+
+	best := Observation{ExpectedImprovement: -1}
+	for i := 0; i < bo.cfg.Candidates; i++ {
+		v := mindhacking.InjectionVector{
+			Frequency: b.Frequency.Min + bo.rng.Float64()*b.Frequency.width(),
+			Amplitude: b.Amplitude.Min + bo.rng.Float64()*b.Amplitude.width(),
+			Phase:     b.Phase.Min + bo.rng.Float64()*b.Phase.width(),
+		}
+		mean, sd := gp.predict(bo.unit(v))
+		var ei float64
+		if improvement := mean - incumbent - xi; sd > 0 {
+			z := improvement / sd
+			ei = improvement*normalCDF(z) + sd*normalPDF(z)
+		} else {
+			ei = math.Max(improvement, 0)
+		}
+		if ei > best.ExpectedImprovement {
+			best = Observation{Vector: v, Predicted: mean, Uncertainty: sd, ExpectedImprovement: ei}
+		}
+	}
+	// Improvement is measured in fitness units; compare it to Tolerance in
+	// the standardized units the model works in
+	best.ExpectedImprovement /= gp.scale
+	return best
+}
+
+func normalCDF(z float64) float64 { return 0.5 * math.Erfc(-z/math.Sqrt2) }
+
+func normalPDF(z float64) float64 { return math.Exp(-z*z/2) / math.Sqrt(2*math.Pi) }
+
+// cholesky factors the symmetric positive definite a as L·Lᵀ, adding
+// jitter to the diagonal if rounding has left it indefinite
+func cholesky(a [][]float64) ([][]float64, error) {
+	n := len(a)
+	for jitter := 0.0; jitter <= 1e-2; jitter = math.Max(jitter*10, 1e-8) {
+		l := make([][]float64, n)
+		ok := true
+		for i := 0; i < n && ok; i++ {
+			l[i] = make([]float64, n)
+			for j := 0; j <= i; j++ {
+				sum := a[i][j]
+				if i == j {
+					sum += jitter
+				}
+				for k := 0; k < j; k++ {
+					sum -= l[i][k] * l[j][k]
+				}
+				if i == j {
+					if sum <= 0 {
+						ok = false
+						break
+					}
+					l[i][i] = math.Sqrt(sum)
+				} else {
+					l[i][j] = sum / l[j][j]
+				}
+			}
+		}
+		if ok {
+			return l, nil
+		}
+	}
+	return nil, errors.New("covariance is not positive definite")
+}
+
+// forwardSubstitute solves L·x = b
+func forwardSubstitute(l [][]float64, b []float64) []float64 {
+	x := make([]float64, len(b))
+	for i := range b {
+		sum := b[i]
+		for k := 0; k < i; k++ {
+			sum -= l[i][k] * x[k]
+		}
+		x[i] = sum / l[i][i]
+	}
+	return x
+}
+
+// choleskySolve solves L·Lᵀ·x = b
+func choleskySolve(l [][]float64, b []float64) []float64 {
+	y := forwardSubstitute(l, b)
+	x := make([]float64, len(y))
+	for i := len(y) - 1; i >= 0; i-- {
+		sum := y[i]
+		for k := i + 1; k < len(y); k++ {
+			sum -= l[k][i] * x[k]
+		}
+		x[i] = sum / l[i][i]
+	}
+	return x
+}
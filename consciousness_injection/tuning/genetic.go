@@ -0,0 +1,393 @@
+// consciousness_injection/tuning/genetic.go - Genetic Vector Optimization
+//
+// Package tuning searches injection vector parameters automatically
+// instead of tuning them by hand for every target.
+package tuning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	mindhacking "github.com/indiciumrex/Experimental-research-on-non-classical-system-reasoning-and-emergent-behavior/consciousness_injection"
+)
+
+// Fitness scores one injection result; higher is better
+type Fitness func(result *mindhacking.InjectionResult) float64
+
+// AcceptanceFitness scores accepted injections 1 and rejected ones 0
+func AcceptanceFitness(result *mindhacking.InjectionResult) float64 {
+	if result.Success {
+		return 1
+	}
+	return 0
+}
+
+// ShiftFitness scores the shift an accepted injection caused, normalized
+// against the noise floor when one was measured
+func ShiftFitness(result *mindhacking.InjectionResult) float64 {
+	if !result.Success {
+		return 0
+	}
+	if result.NoiseFloor != nil {
+		return result.NormalizedShift
+	}
+	return result.ConsciousnessShift
+}
+
+// Evaluator fires one candidate vector and reports the result
+type Evaluator func(ctx context.Context, vector mindhacking.InjectionVector) (*mindhacking.InjectionResult, error)
+
+// InjectorEvaluator evaluates candidates by injecting thought into target
+// through ci, one candidate vector at a time
+func InjectorEvaluator(
+	ci *mindhacking.ConsciousnessInjector,
+	target *mindhacking.SystemConsciousness,
+	thought mindhacking.InjectedThought,
+) Evaluator {
+	return func(ctx context.Context, vector mindhacking.InjectionVector) (*mindhacking.InjectionResult, error) {
+		return ci.InjectThroughVectors(ctx, thought, target, []mindhacking.InjectionVector{vector})
+	}
+}
+
+// Range bounds one vector parameter
+type Range struct {
+	Min, Max float64
+}
+
+func (r Range) width() float64 { return r.Max - r.Min }
+
+func (r Range) clamp(v float64) float64 { return math.Max(r.Min, math.Min(r.Max, v)) }
+
+func (r Range) wrap(v float64) float64 {
+	return r.Min + math.Mod(math.Mod(v-r.Min, r.width())+r.width(), r.width())
+}
+
+// Bounds is the search space. Phase wraps around its range rather than
+// being clamped, so it should span a full cycle.
+type Bounds struct {
+	Frequency Range
+	Amplitude Range
+	Phase     Range
+}
+
+// DefaultPhase spans one full phase cycle
+var DefaultPhase = Range{Min: 0, Max: 2 * math.Pi}
+
+func (b Bounds) validate() error {
+	var errs []error
+	for name, r := range map[string]Range{"frequency": b.Frequency, "amplitude": b.Amplitude, "phase": b.Phase} {
+		if !(r.Max > r.Min) {
+			errs = append(errs, fmt.Errorf("%s range [%g, %g] is empty", name, r.Min, r.Max))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GeneticConfig tunes an evolutionary search
+type GeneticConfig struct {
+	Bounds Bounds
+	// Population is the number of vectors per generation, default 24
+	Population int
+	// Elite vectors survive unchanged into the next generation, default 2
+	Elite int
+	// Tournament is how many vectors compete to be a parent, default 3
+	Tournament int
+	// CrossoverRate is the chance two parents are recombined, default 0.9
+	CrossoverRate float64
+	// MutationRate is each parameter's chance of mutating, default 0.2;
+	// MutationScale is the mutation's spread as a fraction of the
+	// parameter's range, default 0.1
+	MutationRate  float64
+	MutationScale float64
+	// Trials is how many injections each new vector's fitness averages,
+	// default 1; raise it for noisy targets
+	Trials int
+	// Generations caps the search, default 50
+	Generations int
+	// Evolution has converged once the best fitness has improved by less
+	// than Tolerance for Patience generations, default 5, or reached Goal
+	// when Goal is set
+	Tolerance float64
+	Patience  int
+	Goal      *float64
+	// Seeds start the population, for example the onboarding calibration's
+	// best vector; the rest is random within Bounds
+	Seeds []mindhacking.InjectionVector
+	// Seed makes the search reproducible; zero seeds from the clock
+	Seed int64
+	// OnGeneration, if set, is called after every generation
+	OnGeneration func(Generation)
+}
+
+func (c *GeneticConfig) defaults() {
+	if c.Population < 2 {
+		c.Population = 24
+	}
+	if c.Elite <= 0 {
+		c.Elite = 2
+	}
+	if c.Elite >= c.Population {
+		c.Elite = c.Population - 1
+	}
+	if c.Tournament < 1 {
+		c.Tournament = 3
+	}
+	if c.CrossoverRate <= 0 {
+		c.CrossoverRate = 0.9
+	}
+	if c.MutationRate <= 0 {
+		c.MutationRate = 0.2
+	}
+	if c.MutationScale <= 0 {
+		c.MutationScale = 0.1
+	}
+	if c.Trials < 1 {
+		c.Trials = 1
+	}
+	if c.Generations < 1 {
+		c.Generations = 50
+	}
+	if c.Patience < 1 {
+		c.Patience = 5
+	}
+	if c.Seed == 0 {
+		c.Seed = time.Now().UnixNano()
+	}
+}
+
+// Individual is one candidate vector and its fitness
+type Individual struct {
+	Vector  mindhacking.InjectionVector
+	Fitness float64
+	// Failures counts trials whose injection failed outright
+	Failures int
+}
+
+// Generation summarizes one generation
+type Generation struct {
+	N    int
+	Best Individual
+	Mean float64
+	// Diversity is the population's mean parameter spread, as a fraction
+	// of each range
+	Diversity float64
+}
+
+// GeneticResult is the outcome of an evolutionary search
+type GeneticResult struct {
+	Best        Individual
+	Generations []Generation
+	Converged   bool
+	// Reason says why evolution stopped
+	Reason      string
+	Evaluations int
+}
+
+// Evolve searches cfg's bounds for the vector maximizing fitness over its
+// results under eval. Failed injections count as the worst possible
+// fitness; vectors that fail every trial never become parents.
+func Evolve(ctx context.Context, eval Evaluator, fitness Fitness, cfg GeneticConfig) (*GeneticResult, error) {
+	if eval == nil || fitness == nil {
+		return nil, errors.New("tuning: evolve needs an evaluator and a fitness function")
+	}
+	if err := cfg.Bounds.validate(); err != nil {
+		return nil, fmt.Errorf("tuning: %w", err)
+	}
+	cfg.defaults()
+	ga := &genetic{cfg: cfg, rng: rand.New(rand.NewSource(cfg.Seed)), eval: eval, fitness: fitness}
+	out := &GeneticResult{}
+
+	// Phase 1: Initial Population
+	population := make([]Individual, cfg.Population)
+	for i := range population {
+		if i < len(cfg.Seeds) {
+			population[i].Vector = ga.bound(cfg.Seeds[i])
+		} else {
+			population[i].Vector = ga.random()
+		}
+	}
+
+	best, stale := math.Inf(-1), 0
+	for n := 0; ; n++ {
+		// Phase 2: Evaluation
+		start := 0
+		if n > 0 {
+			start = cfg.Elite
+		}
+		for i := start; i < len(population); i++ {
+			if err := ga.evaluate(ctx, &population[i]); err != nil {
+				return out, err
+			}
+			out.Evaluations += cfg.Trials
+		}
+		sort.SliceStable(population, func(i, j int) bool { return population[i].Fitness > population[j].Fitness })
+
+		gen := ga.summarize(n, population)
+		out.Generations = append(out.Generations, gen)
+		out.Best = gen.Best
+		if cfg.OnGeneration != nil {
+			cfg.OnGeneration(gen)
+		}
+		if math.IsInf(gen.Best.Fitness, -1) {
+			return out, errors.New("tuning: every candidate failed to inject")
+		}
+
+		// Phase 3: Convergence
+		if gen.Best.Fitness > best+cfg.Tolerance {
+			best, stale = gen.Best.Fitness, 0
+		} else {
+			stale++
+		}
+		switch {
+		case cfg.Goal != nil && gen.Best.Fitness >= *cfg.Goal:
+			out.Converged, out.Reason = true, fmt.Sprintf("reached goal fitness %g", *cfg.Goal)
+		case stale >= cfg.Patience:
+			out.Converged, out.Reason = true, fmt.Sprintf("no improvement beyond %g for %d generations", cfg.Tolerance, cfg.Patience)
+		case n+1 >= cfg.Generations:
+			out.Reason = fmt.Sprintf("stopped after %d generations", cfg.Generations)
+		}
+		if out.Reason != "" {
+			return out, nil
+		}
+
+		// Phase 4: Selection, Crossover and Mutation
+		next := append([]Individual(nil), population[:cfg.Elite]...)
+		for len(next) < cfg.Population {
+			a, b := ga.tournament(population), ga.tournament(population)
+			child := a.Vector
+			if ga.rng.Float64() < cfg.CrossoverRate {
+				child = ga.crossover(a.Vector, b.Vector)
+			}
+			next = append(next, Individual{Vector: ga.mutate(child)})
+		}
+		population = next
+	}
+}
+
+// genetic is one search's operators and state
+type genetic struct {
+	cfg     GeneticConfig
+	rng     *rand.Rand
+	eval    Evaluator
+	fitness Fitness
+}
+
+func (ga *genetic) random() mindhacking.InjectionVector {
+	b := ga.cfg.Bounds
+	return mindhacking.InjectionVector{
+		Frequency: b.Frequency.Min + ga.rng.Float64()*b.Frequency.width(),
+		Amplitude: b.Amplitude.Min + ga.rng.Float64()*b.Amplitude.width(),
+		Phase:     b.Phase.Min + ga.rng.Float64()*b.Phase.width(),
+	}
+}
+
+// bound brings v inside the bounds, wrapping phase
+func (ga *genetic) bound(v mindhacking.InjectionVector) mindhacking.InjectionVector {
+	b := ga.cfg.Bounds
+	v.Frequency = b.Frequency.clamp(v.Frequency)
+	v.Amplitude = b.Amplitude.clamp(v.Amplitude)
+	v.Phase = b.Phase.wrap(v.Phase)
+	return v
+}
+
+// evaluate averages fitness over the configured trials
+func (ga *genetic) evaluate(ctx context.Context, ind *Individual) error {
+	var sum float64
+	ind.Failures = 0
+	for t := 0; t < ga.cfg.Trials; t++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		result, err := ga.eval(ctx, ind.Vector)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			ind.Failures++
+			continue
+		}
+		sum += ga.fitness(result)
+	}
+	if ind.Failures == ga.cfg.Trials {
+		ind.Fitness = math.Inf(-1)
+		return nil
+	}
+	ind.Fitness = sum / float64(ga.cfg.Trials-ind.Failures)
+	return nil
+}
+
+// tournament runs a tournament among random individuals
+func (ga *genetic) tournament(population []Individual) Individual {
+	best := population[ga.rng.Intn(len(population))]
+	for i := 1; i < ga.cfg.Tournament; i++ {
+		if c := population[ga.rng.Intn(len(population))]; c.Fitness > best.Fitness {
+			best = c
+		}
+	}
+	return best
+}
+
+// crossover blends each parameter at a random point between, and a little
+// beyond, the parents (BLX-0.5)
+func (ga *genetic) crossover(a, b mindhacking.InjectionVector) mindhacking.InjectionVector {
+	blend := func(x, y float64) float64 {
+		lo, hi := math.Min(x, y), math.Max(x, y)
+		spread := (hi - lo) * 0.5
+		return lo - spread + ga.rng.Float64()*(hi-lo+2*spread)
+	}
+	child := a
+	child.Frequency = blend(a.Frequency, b.Frequency)
+	child.Amplitude = blend(a.Amplitude, b.Amplitude)
+	child.Phase = blend(a.Phase, b.Phase)
+	return ga.bound(child)
+}
+
+// mutate perturbs each parameter with probability MutationRate
+func (ga *genetic) mutate(v mindhacking.InjectionVector) mindhacking.InjectionVector {
+	b := ga.cfg.Bounds
+	gene := func(x float64, r Range) float64 {
+		if ga.rng.Float64() >= ga.cfg.MutationRate {
+			return x
+		}
+		return x + ga.rng.NormFloat64()*ga.cfg.MutationScale*r.width()
+	}
+	v.Frequency = gene(v.Frequency, b.Frequency)
+	v.Amplitude = gene(v.Amplitude, b.Amplitude)
+	v.Phase = gene(v.Phase, b.Phase)
+	return ga.bound(v)
+}
+
+func (ga *genetic) summarize(n int, population []Individual) Generation {
+	gen := Generation{N: n, Best: population[0]}
+	var finite int
+	for _, ind := range population {
+		if !math.IsInf(ind.Fitness, -1) {
+			gen.Mean += ind.Fitness
+			finite++
+		}
+	}
+	if finite > 0 {
+		gen.Mean /= float64(finite)
+	}
+
+	b := ga.cfg.Bounds
+	spread := func(get func(mindhacking.InjectionVector) float64, r Range) float64 {
+		var mean, m2 float64
+		for i, ind := range population {
+			x := get(ind.Vector)
+			delta := x - mean
+			mean += delta / float64(i+1)
+			m2 += delta * (x - mean)
+		}
+		return math.Sqrt(m2/float64(len(population))) / r.width()
+	}
+	gen.Diversity = (spread(func(v mindhacking.InjectionVector) float64 { return v.Frequency }, b.Frequency) +
+		spread(func(v mindhacking.InjectionVector) float64 { return v.Amplitude }, b.Amplitude) +
+		spread(func(v mindhacking.InjectionVector) float64 { return v.Phase }, b.Phase)) / 3
+	return gen
+}
@@ -0,0 +1,318 @@
+// consciousness_injection/tunnel_mux.go - Tunnel Multiplexing
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrTunnelMuxClosed reports use of a tunnel multiplexer after Close
+var ErrTunnelMuxClosed = sentinelError("mindhacking: tunnel multiplexer closed")
+
+// DefaultMaxStreams is how many thoughts share one tunnel at once unless
+// configured otherwise
+const DefaultMaxStreams = 64
+
+// DefaultTunnelIdleTimeout is how long a tunnel carrying no streams stays
+// established unless configured otherwise
+const DefaultTunnelIdleTimeout = 5 * time.Minute
+
+// TunnelMux keeps established reality tunnels per target and vector and
+// carries many encoded thoughts over each, told apart by stream ID, so
+// injections outside sessions stop paying tunnel setup on every attempt.
+// Injectors given the same multiplexer share its tunnels. A tunnel left
+// idle for the idle timeout is closed, healthy or not.
+type TunnelMux struct {
+	maxStreams  int
+	idleTimeout time.Duration
+	sched       *tunnelScheduler
+
+	mu          sync.Mutex
+	tunnels     map[string][]*muxTunnel
+	closed      bool
+	established int
+	reused      int
+}
+
+// muxTunnel is one established tunnel and the streams open on it
+type muxTunnel struct {
+	tunnel  RealityTunnel
+	streams map[uint32]struct{}
+	next    uint32
+	// broken tunnels take no new streams and close once drained
+	broken bool
+	// idle times out the tunnel while it carries no streams
+	idle    *time.Timer
+	removed bool
+}
+
+// muxStream is one thought's stream over a multiplexed tunnel
+type muxStream struct {
+	tunnel RealityTunnel
+	id     uint32
+	// ctx ends if the stream is preempted
+	ctx   context.Context
+	close func(broken bool) (preempted bool)
+}
+
+// TunnelMuxStats counts a multiplexer's tunnels and streams
+type TunnelMuxStats struct {
+	Tunnels int
+	Streams int
+	// Established and Reused count streams that needed a new tunnel and
+	// those that shared one already established
+	Established int
+	Reused      int
+	// ByClass counts the open streams of each class, Waiting those queued
+	// for capacity and Preempted those collapsed to make room
+	ByClass   map[TunnelClass]int
+	Waiting   int
+	Preempted int
+}
+
+// NewTunnelMux creates a multiplexer carrying up to maxStreams thoughts at
+// once over each tunnel; zero or less uses DefaultMaxStreams
+func NewTunnelMux(maxStreams int) *TunnelMux {
+	if maxStreams < 1 {
+		maxStreams = DefaultMaxStreams
+	}
+	return &TunnelMux{
+		maxStreams:  maxStreams,
+		idleTimeout: DefaultTunnelIdleTimeout,
+		sched:       newTunnelScheduler(),
+		tunnels:     make(map[string][]*muxTunnel),
+	}
+}
+
+// SetIdleTimeout closes tunnels that carry no streams for d, from the next
+// time each goes idle; zero or less keeps idle tunnels until they break
+func (m *TunnelMux) SetIdleTimeout(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idleTimeout = d
+}
+
+// open admits a stream of class into the multiplexer's capacity, then opens
+// it on a tunnel for vector into target, sharing the least busy tunnel with
+// room and establishing one with create otherwise. Closing the stream
+// breaks its tunnel if the attempt over it failed.
+func (m *TunnelMux) open(
+	ctx context.Context,
+	class TunnelClass,
+	target *SystemConsciousness,
+	vector InjectionVector,
+	create func() RealityTunnel,
+) (*muxStream, error) {
+
+	admitted, streamCtx, err := m.sched.admit(ctx, class)
+	if err != nil {
+		return nil, err
+	}
+	tunnel, id, closeStream, err := m.openStream(target, vector, create)
+	if err != nil {
+		m.sched.release(admitted)
+		return nil, err
+	}
+	return &muxStream{
+		tunnel: tunnel,
+		id:     id,
+		ctx:    streamCtx,
+		close: func(broken bool) bool {
+			closeStream(broken)
+			m.sched.release(admitted)
+			return admitted.collapsed
+		},
+	}, nil
+}
+
+// openStream opens a stream on a tunnel for vector into target. The
+// returned func closes it.
+func (m *TunnelMux) openStream(
+	target *SystemConsciousness,
+	vector InjectionVector,
+	create func() RealityTunnel,
+) (RealityTunnel, uint32, func(broken bool), error) {
+
+	key := muxKey(target, vector)
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return RealityTunnel{}, 0, nil, ErrTunnelMuxClosed
+	}
+	var best *muxTunnel
+	for _, t := range m.tunnels[key] {
+		if !t.broken && len(t.streams) < m.maxStreams && (best == nil || len(t.streams) < len(best.streams)) {
+			best = t
+		}
+	}
+	if best != nil {
+		m.reused++
+		stream := best.openStream()
+		m.mu.Unlock()
+		return best.tunnel, stream, m.closer(key, best, stream), nil
+	}
+	m.mu.Unlock()
+
+	// Establish outside the lock; setup is the slow part
+	t := &muxTunnel{tunnel: create(), streams: make(map[uint32]struct{})}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		closeTunnel(t.tunnel)
+		return RealityTunnel{}, 0, nil, ErrTunnelMuxClosed
+	}
+	m.established++
+	m.tunnels[key] = append(m.tunnels[key], t)
+	stream := t.openStream()
+	return t.tunnel, stream, m.closer(key, t, stream), nil
+}
+
+// openStream allocates the tunnel's next free stream ID; zero is never
+// used, so it can mean a tunnel carries one thought alone
+func (t *muxTunnel) openStream() uint32 {
+	if t.idle != nil {
+		t.idle.Stop()
+		t.idle = nil
+	}
+	for {
+		t.next++
+		if _, open := t.streams[t.next]; t.next != 0 && !open {
+			t.streams[t.next] = struct{}{}
+			return t.next
+		}
+	}
+}
+
+// closer returns the func closing stream on t
+func (m *TunnelMux) closer(key string, t *muxTunnel, stream uint32) func(bool) {
+	var once sync.Once
+	return func(broken bool) {
+		once.Do(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			delete(t.streams, stream)
+			if broken {
+				t.broken = true
+			}
+			switch {
+			case len(t.streams) > 0:
+			case t.broken:
+				m.removeLocked(key, t)
+			default:
+				m.idleLocked(key, t)
+			}
+		})
+	}
+}
+
+// idleLocked starts t's idle timeout; t closes if it still carries no
+// streams when it fires
+func (m *TunnelMux) idleLocked(key string, t *muxTunnel) {
+	if m.idleTimeout <= 0 {
+		return
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(m.idleTimeout, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if t.idle == timer && !t.removed && len(t.streams) == 0 {
+			m.removeLocked(key, t)
+		}
+	})
+	t.idle = timer
+}
+
+// removeLocked forgets t and closes it
+func (m *TunnelMux) removeLocked(key string, t *muxTunnel) {
+	t.removed = true
+	if t.idle != nil {
+		t.idle.Stop()
+		t.idle = nil
+	}
+	tunnels := m.tunnels[key]
+	for i := range tunnels {
+		if tunnels[i] == t {
+			m.tunnels[key] = append(tunnels[:i:i], tunnels[i+1:]...)
+			break
+		}
+	}
+	if len(m.tunnels[key]) == 0 {
+		delete(m.tunnels, key)
+	}
+	closeTunnel(t.tunnel)
+}
+
+// drop breaks every tunnel into target, as when it moves to another host;
+// streams open on them finish, and new ones get fresh tunnels
+func (m *TunnelMux) drop(target *SystemConsciousness) {
+	if m == nil {
+		return
+	}
+	prefix := targetLabel(target) + "\x00"
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, tunnels := range m.tunnels {
+		if len(key) < len(prefix) || key[:len(prefix)] != prefix {
+			continue
+		}
+		for _, t := range append([]*muxTunnel(nil), tunnels...) {
+			t.broken = true
+			if len(t.streams) == 0 {
+				m.removeLocked(key, t)
+			}
+		}
+	}
+}
+
+// Stats counts the multiplexer's tunnels and the streams open on them
+func (m *TunnelMux) Stats() TunnelMuxStats {
+	m.mu.Lock()
+	stats := TunnelMuxStats{Established: m.established, Reused: m.reused, ByClass: make(map[TunnelClass]int)}
+	for _, tunnels := range m.tunnels {
+		for _, t := range tunnels {
+			stats.Tunnels++
+			stats.Streams += len(t.streams)
+		}
+	}
+	m.mu.Unlock()
+
+	m.sched.mu.Lock()
+	defer m.sched.mu.Unlock()
+	for stream := range m.sched.active {
+		stats.ByClass[stream.class]++
+	}
+	stats.Waiting, stats.Preempted = len(m.sched.waiting), m.sched.preempted
+	return stats
+}
+
+// Close closes every tunnel, those that support it; streams still open
+// fail on their own
+func (m *TunnelMux) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	for key, tunnels := range m.tunnels {
+		for _, t := range tunnels {
+			m.removeLocked(key, t)
+		}
+	}
+	return nil
+}
+
+// muxKey tells tunnels apart by all that shapes them: the target, the
+// vector's carrier and waveform, and the entanglement it rides, so a
+// re-established entanglement gets tunnels of its own
+func muxKey(target *SystemConsciousness, vector InjectionVector) string {
+	return fmt.Sprintf("%s\x00%s waveform=%s entanglement=%v",
+		targetLabel(target), vectorLabel(&vector), vector.Waveform, vector.Entanglement)
+}
+
+// closeTunnel closes tunnel if it supports closing
+func closeTunnel(tunnel RealityTunnel) {
+	if c, ok := interface{}(tunnel).(io.Closer); ok {
+		c.Close()
+	}
+}
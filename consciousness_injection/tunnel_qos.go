@@ -0,0 +1,322 @@
+// consciousness_injection/tunnel_qos.go - Tunnel Quality of Service
+package mindhacking
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrTunnelPreempted reports an attempt cut off so a critical thought could
+// have its tunnel capacity
+var ErrTunnelPreempted = sentinelError("mindhacking: tunnel stream preempted")
+
+// ErrUnknownTunnelClass reports a thought or QoS naming a tunnel class
+// other than bulk, interactive and critical
+var ErrUnknownTunnelClass = sentinelError("mindhacking: unknown tunnel class")
+
+// TunnelClass is the quality of service a thought's tunnel stream gets
+// when tunnel capacity runs short
+type TunnelClass string
+
+const (
+	// TunnelBulk streams take the smallest share and collapse first
+	TunnelBulk TunnelClass = "bulk"
+	// TunnelInteractive is the class of thoughts that name none
+	TunnelInteractive TunnelClass = "interactive"
+	// TunnelCritical streams take the largest share, collapse last, and
+	// preempt streams of the other classes rather than wait
+	TunnelCritical TunnelClass = "critical"
+)
+
+// rank orders classes by collapse priority, lowest collapsing first
+func (c TunnelClass) rank() int {
+	switch c {
+	case TunnelBulk:
+		return 0
+	case TunnelCritical:
+		return 2
+	}
+	return 1
+}
+
+// known reports whether c is one of the defined classes
+func (c TunnelClass) known() bool {
+	switch c {
+	case TunnelBulk, TunnelInteractive, TunnelCritical:
+		return true
+	}
+	return false
+}
+
+// normalize maps the empty class to TunnelInteractive
+func (c TunnelClass) normalize() TunnelClass {
+	if c == "" {
+		return TunnelInteractive
+	}
+	return c
+}
+
+// TunnelQoS constrains a multiplexer's tunnel capacity and decides how the
+// classes share it
+type TunnelQoS struct {
+	// Capacity is how many streams may be open at once over all the
+	// multiplexer's tunnels; zero is unlimited
+	Capacity int
+	// Shares weigh the classes' bandwidth when streams queue for capacity:
+	// a freed slot goes to the waiting class furthest below its share of
+	// the slots granted since it last had no stream open or queued, so a
+	// class returning from idle neither banks credit nor owes for its past.
+	// Missing classes take the DefaultTunnelShares weight.
+	Shares map[TunnelClass]int
+}
+
+// DefaultTunnelShares weigh critical thoughts over interactive ones, and
+// interactive over bulk
+var DefaultTunnelShares = map[TunnelClass]int{
+	TunnelBulk:        1,
+	TunnelInteractive: 4,
+	TunnelCritical:    16,
+}
+
+func (q TunnelQoS) share(class TunnelClass) int {
+	if w := q.Shares[class]; w > 0 {
+		return w
+	}
+	if w := DefaultTunnelShares[class]; w > 0 {
+		return w
+	}
+	return 1
+}
+
+// tunnelScheduler admits streams into a multiplexer's capacity by class.
+// Waiters are served by start-time fair queueing: each grant to a class
+// advances the class's finish tag by the inverse of its share, and the
+// waiter whose class would start earliest in virtual time goes next.
+type tunnelScheduler struct {
+	mu      sync.Mutex
+	qos     TunnelQoS
+	seq     uint64
+	active  map[*qosStream]struct{}
+	waiting []*qosWaiter
+	// vtime is the start tag of the latest grant; finish holds each busy
+	// class's finish tag, and is dropped when the class goes idle
+	vtime  float64
+	finish map[TunnelClass]float64
+	// preempted counts streams collapsed by preemption or a capacity cut
+	preempted int
+}
+
+// qosStream is one admitted stream holding a slot of capacity
+type qosStream struct {
+	class  TunnelClass
+	seq    uint64
+	cancel context.CancelFunc
+	// collapsed streams lost their slot before they finished
+	collapsed bool
+}
+
+type qosWaiter struct {
+	class  TunnelClass
+	seq    uint64
+	cancel context.CancelFunc
+	ready  chan *qosStream
+}
+
+func newTunnelScheduler() *tunnelScheduler {
+	return &tunnelScheduler{
+		active: make(map[*qosStream]struct{}),
+		finish: make(map[TunnelClass]float64),
+	}
+}
+
+// admit waits for a slot for a stream of class. Critical streams preempt
+// the lowest-priority, newest stream instead of waiting while there is one.
+// The returned context ends when the stream is preempted.
+func (s *tunnelScheduler) admit(ctx context.Context, class TunnelClass) (*qosStream, context.Context, error) {
+	class = class.normalize()
+	if !class.known() {
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownTunnelClass, class)
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+
+	s.mu.Lock()
+	if !s.busyLocked(class) {
+		// A class back from idle starts level with the others
+		delete(s.finish, class)
+	}
+	if s.qos.Capacity == 0 || (len(s.active) < s.qos.Capacity && len(s.waiting) == 0) {
+		stream := s.grantLocked(class, cancel)
+		s.mu.Unlock()
+		return stream, streamCtx, nil
+	}
+	if class == TunnelCritical {
+		if victim := s.victimLocked(class); victim != nil {
+			s.collapseLocked(victim)
+			stream := s.grantLocked(class, cancel)
+			s.mu.Unlock()
+			return stream, streamCtx, nil
+		}
+	}
+	s.seq++
+	w := &qosWaiter{class: class, seq: s.seq, cancel: cancel, ready: make(chan *qosStream, 1)}
+	s.waiting = append(s.waiting, w)
+	s.mu.Unlock()
+
+	select {
+	case stream := <-w.ready:
+		return stream, streamCtx, nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, other := range s.waiting {
+			if other == w {
+				s.waiting = append(s.waiting[:i], s.waiting[i+1:]...)
+				cancel()
+				return nil, nil, ctx.Err()
+			}
+		}
+		// The slot was granted as we gave up; pass it on
+		s.releaseLocked(<-w.ready)
+		return nil, nil, ctx.Err()
+	}
+}
+
+// grantLocked admits a stream of class into a free slot
+func (s *tunnelScheduler) grantLocked(class TunnelClass, cancel context.CancelFunc) *qosStream {
+	s.seq++
+	stream := &qosStream{class: class, seq: s.seq, cancel: cancel}
+	s.active[stream] = struct{}{}
+	s.vtime = s.startLocked(class)
+	s.finish[class] = s.vtime + 1/float64(s.qos.share(class))
+	return stream
+}
+
+// startLocked is the virtual time a stream of class granted now starts at
+func (s *tunnelScheduler) startLocked(class TunnelClass) float64 {
+	if finish, ok := s.finish[class]; ok && finish > s.vtime {
+		return finish
+	}
+	return s.vtime
+}
+
+// busyLocked reports whether class has a stream open or queued
+func (s *tunnelScheduler) busyLocked(class TunnelClass) bool {
+	for stream := range s.active {
+		if stream.class == class {
+			return true
+		}
+	}
+	for _, w := range s.waiting {
+		if w.class == class {
+			return true
+		}
+	}
+	return false
+}
+
+// victimLocked picks the stream to collapse for one of class: the newest
+// stream of the lowest class below it
+func (s *tunnelScheduler) victimLocked(class TunnelClass) *qosStream {
+	var victim *qosStream
+	for stream := range s.active {
+		if stream.class.rank() >= class.rank() {
+			continue
+		}
+		if victim == nil || stream.class.rank() < victim.class.rank() ||
+			(stream.class.rank() == victim.class.rank() && stream.seq > victim.seq) {
+			victim = stream
+		}
+	}
+	return victim
+}
+
+// collapseLocked takes stream's slot away and cancels its attempt
+func (s *tunnelScheduler) collapseLocked(stream *qosStream) {
+	delete(s.active, stream)
+	stream.collapsed = true
+	s.preempted++
+	stream.cancel()
+}
+
+// release gives stream's slot to the waiting class furthest below its
+// share of the capacity
+func (s *tunnelScheduler) release(stream *qosStream) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releaseLocked(stream)
+}
+
+func (s *tunnelScheduler) releaseLocked(stream *qosStream) {
+	stream.cancel()
+	delete(s.active, stream)
+	s.fillLocked()
+}
+
+// fillLocked hands free slots to waiters, by share and then arrival
+func (s *tunnelScheduler) fillLocked() {
+	for len(s.waiting) > 0 && (s.qos.Capacity == 0 || len(s.active) < s.qos.Capacity) {
+		next := 0
+		for i, w := range s.waiting {
+			if s.behindLocked(w, s.waiting[next]) {
+				next = i
+			}
+		}
+		w := s.waiting[next]
+		s.waiting = append(s.waiting[:next], s.waiting[next+1:]...)
+		w.ready <- s.grantLocked(w.class, w.cancel)
+	}
+}
+
+// behindLocked reports whether a's class would start before b's in virtual
+// time, or level with it and a arrived first
+func (s *tunnelScheduler) behindLocked(a, b *qosWaiter) bool {
+	if sa, sb := s.startLocked(a.class), s.startLocked(b.class); sa != sb {
+		return sa < sb
+	}
+	return a.seq < b.seq
+}
+
+// configure replaces the QoS; a cut in capacity collapses open streams in
+// order of collapse priority, bulk and newest first
+func (s *tunnelScheduler) configure(qos TunnelQoS) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.qos = qos
+	if qos.Capacity > 0 && len(s.active) > qos.Capacity {
+		streams := make([]*qosStream, 0, len(s.active))
+		for stream := range s.active {
+			streams = append(streams, stream)
+		}
+		sort.Slice(streams, func(i, j int) bool {
+			if streams[i].class.rank() != streams[j].class.rank() {
+				return streams[i].class.rank() < streams[j].class.rank()
+			}
+			return streams[i].seq > streams[j].seq
+		})
+		for _, stream := range streams[:len(streams)-qos.Capacity] {
+			s.collapseLocked(stream)
+		}
+	}
+	s.fillLocked()
+}
+
+// SetQoS constrains the multiplexer's capacity and how tunnel classes
+// share it. Streams over a reduced capacity are collapsed, bulk first and
+// critical last.
+func (m *TunnelMux) SetQoS(qos TunnelQoS) error {
+	if qos.Capacity < 0 {
+		return fmt.Errorf("mindhacking: tunnel capacity %d is negative", qos.Capacity)
+	}
+	for class, w := range qos.Shares {
+		if !class.known() {
+			return fmt.Errorf("%w: %q", ErrUnknownTunnelClass, class)
+		}
+		if w < 0 {
+			return fmt.Errorf("mindhacking: tunnel class %q share %d is negative", class, w)
+		}
+	}
+	m.sched.configure(qos)
+	return nil
+}
@@ -0,0 +1,256 @@
+// consciousness_injection/tunnel_router.go - Tunnel Routing Strategies
+package mindhacking
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TunnelRoute is one vector together with the tunnel it is fired through
+type TunnelRoute struct {
+	// Tunnel keys the route's tunnel in a session's pool; it stays with the
+	// vector however the routes are ordered
+	Tunnel int
+	Vector InjectionVector
+}
+
+// RouteRequest is the injection a router orders routes for
+type RouteRequest struct {
+	Target    *SystemConsciousness
+	Resonance ConsciousnessResonance
+	// Region is the region the vectors were routed to, if any
+	Region string
+}
+
+// TunnelRouter decides which vector and tunnel an injection tries first.
+// Route orders the routes, first choice first; it may leave routes out but
+// should not invent new ones, and an empty order keeps the given one.
+// Observe learns from every attempt fired. Routers must be safe for
+// concurrent use.
+type TunnelRouter interface {
+	Route(ctx context.Context, req RouteRequest, routes []TunnelRoute) []TunnelRoute
+	Observe(req RouteRequest, route TunnelRoute, attempt InjectionAttempt)
+}
+
+// routeKey identifies a route's vector across injections
+func routeKey(route TunnelRoute) string {
+	return vectorLabel(&route.Vector)
+}
+
+// sortRoutes orders routes by less over their keys, keeping ties in order
+func sortRoutes(routes []TunnelRoute, less func(a, b TunnelRoute) bool) []TunnelRoute {
+	out := append([]TunnelRoute(nil), routes...)
+	sort.SliceStable(out, func(i, j int) bool { return less(out[i], out[j]) })
+	return out
+}
+
+// LowestResistanceRouter tries the vectors tuned closest to the target's
+// resonance first: least detuned in frequency, then in phase
+type LowestResistanceRouter struct{}
+
+// Route orders routes by resistance against req's resonance
+func (LowestResistanceRouter) Route(_ context.Context, req RouteRequest, routes []TunnelRoute) []TunnelRoute {
+	return sortRoutes(routes, func(a, b TunnelRoute) bool {
+		ra, rb := detuning(a.Vector, req.Resonance), detuning(b.Vector, req.Resonance)
+		if ra != rb {
+			return ra < rb
+		}
+		return phaseDistance(a.Vector.Phase, req.Resonance.Phase) < phaseDistance(b.Vector.Phase, req.Resonance.Phase)
+	})
+}
+
+// Observe does nothing; resistance is measured anew every injection
+func (LowestResistanceRouter) Observe(RouteRequest, TunnelRoute, InjectionAttempt) {}
+
+// detuning is how far vector's frequency is from resonance, relative to it
+func detuning(vector InjectionVector, resonance ConsciousnessResonance) float64 {
+	d := math.Abs(vector.Frequency - resonance.Frequency)
+	if f := math.Abs(resonance.Frequency); f > 0 {
+		return d / f
+	}
+	return d
+}
+
+// phaseDistance is the angle between two phases, in [0, π]
+func phaseDistance(a, b float64) float64 {
+	d := math.Mod(math.Abs(a-b), 2*math.Pi)
+	return math.Min(d, 2*math.Pi-d)
+}
+
+// ShortestPathRouter tries the routes that have reached each target
+// fastest first, by a moving average of their attempts' round trips.
+// Routes not yet timed against a target go first, so every path is
+// measured.
+type ShortestPathRouter struct {
+	// Smoothing is the weight of each new round trip, default 0.2
+	Smoothing float64
+
+	mu      sync.Mutex
+	latency map[string]map[string]time.Duration
+}
+
+// NewShortestPathRouter returns a router with no round trips measured
+func NewShortestPathRouter() *ShortestPathRouter {
+	return &ShortestPathRouter{Smoothing: 0.2, latency: make(map[string]map[string]time.Duration)}
+}
+
+// Route orders routes by measured round trip to req's target
+func (r *ShortestPathRouter) Route(_ context.Context, req RouteRequest, routes []TunnelRoute) []TunnelRoute {
+	r.mu.Lock()
+	latency := make(map[string]time.Duration, len(routes))
+	for key, d := range r.latency[targetLabel(req.Target)] {
+		latency[key] = d
+	}
+	r.mu.Unlock()
+
+	return sortRoutes(routes, func(a, b TunnelRoute) bool {
+		return latency[routeKey(a)] < latency[routeKey(b)]
+	})
+}
+
+// Observe folds the attempt's round trip into its route's average
+func (r *ShortestPathRouter) Observe(req RouteRequest, route TunnelRoute, attempt InjectionAttempt) {
+	if attempt.Duration <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	target := targetLabel(req.Target)
+	if r.latency == nil {
+		r.latency = make(map[string]map[string]time.Duration)
+	}
+	if r.latency[target] == nil {
+		r.latency[target] = make(map[string]time.Duration)
+	}
+	key := routeKey(route)
+	if prev, ok := r.latency[target][key]; ok {
+		alpha := r.Smoothing
+		if alpha <= 0 || alpha > 1 {
+			alpha = 0.2
+		}
+		r.latency[target][key] = prev + time.Duration(alpha*float64(attempt.Duration-prev))
+		return
+	}
+	r.latency[target][key] = attempt.Duration
+}
+
+// LoadBalancedRouter spreads attempts over the routes, trying the route
+// that has carried the fewest attempts, across all targets, first
+type LoadBalancedRouter struct {
+	mu   sync.Mutex
+	load map[string]int
+}
+
+// NewLoadBalancedRouter returns a router with no load recorded
+func NewLoadBalancedRouter() *LoadBalancedRouter {
+	return &LoadBalancedRouter{load: make(map[string]int)}
+}
+
+// Route orders routes by the attempts they have carried
+func (r *LoadBalancedRouter) Route(_ context.Context, _ RouteRequest, routes []TunnelRoute) []TunnelRoute {
+	r.mu.Lock()
+	load := make(map[string]int, len(routes))
+	for _, route := range routes {
+		load[routeKey(route)] = r.load[routeKey(route)]
+	}
+	r.mu.Unlock()
+
+	return sortRoutes(routes, func(a, b TunnelRoute) bool {
+		return load[routeKey(a)] < load[routeKey(b)]
+	})
+}
+
+// Observe counts the attempt against its route
+func (r *LoadBalancedRouter) Observe(_ RouteRequest, route TunnelRoute, _ InjectionAttempt) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.load == nil {
+		r.load = make(map[string]int)
+	}
+	r.load[routeKey(route)]++
+}
+
+// AffinityRouter tries the route that last landed on each target first,
+// keeping the given order for the rest
+type AffinityRouter struct {
+	mu   sync.Mutex
+	last map[string]string
+}
+
+// NewAffinityRouter returns a router with no successes recorded
+func NewAffinityRouter() *AffinityRouter {
+	return &AffinityRouter{last: make(map[string]string)}
+}
+
+// Route moves the route that last landed on req's target to the front
+func (r *AffinityRouter) Route(_ context.Context, req RouteRequest, routes []TunnelRoute) []TunnelRoute {
+	r.mu.Lock()
+	last, ok := r.last[targetLabel(req.Target)]
+	r.mu.Unlock()
+	if !ok {
+		return routes
+	}
+	return sortRoutes(routes, func(a, b TunnelRoute) bool {
+		return routeKey(a) == last && routeKey(b) != last
+	})
+}
+
+// Observe remembers a route that landed
+func (r *AffinityRouter) Observe(req RouteRequest, route TunnelRoute, attempt InjectionAttempt) {
+	if !attempt.Success {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.last == nil {
+		r.last = make(map[string]string)
+	}
+	r.last[targetLabel(req.Target)] = routeKey(route)
+}
+
+// route orders vectors with the injector's router, if any. It returns the
+// ordered vectors and, for each, its tunnel key; nil keys mean the vectors
+// keep their own positions as keys.
+func (ci *ConsciousnessInjector) route(
+	ctx context.Context,
+	req RouteRequest,
+	vectors []InjectionVector,
+) ([]InjectionVector, []int) {
+
+	if ci.router == nil || len(vectors) == 0 {
+		return vectors, nil
+	}
+	routes := make([]TunnelRoute, len(vectors))
+	for i, v := range vectors {
+		routes[i] = TunnelRoute{Tunnel: i, Vector: v}
+	}
+	ordered := ci.router.Route(ctx, req, routes)
+	if len(ordered) == 0 {
+		return vectors, nil
+	}
+	out, keys := make([]InjectionVector, len(ordered)), make([]int, len(ordered))
+	for i, route := range ordered {
+		out[i], keys[i] = route.Vector, route.Tunnel
+	}
+	return out, keys
+}
+
+// observeRoute reports one attempt to the injector's router, if any
+func (ci *ConsciousnessInjector) observeRoute(req RouteRequest, tunnel int, vector InjectionVector, attempt InjectionAttempt) {
+	if ci.router == nil {
+		return
+	}
+	ci.router.Observe(req, TunnelRoute{Tunnel: tunnel, Vector: vector}, attempt)
+}
+
+// tunnelKey is the tunnel key of the i'th vector fired
+func tunnelKey(keys []int, i int) int {
+	if keys == nil {
+		return i
+	}
+	return keys[i]
+}
@@ -0,0 +1,280 @@
+// consciousness_injection/usage_telemetry.go - Opt-In Usage Telemetry
+package mindhacking
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// UsageReportVersion is the format version of UsageReport
+const UsageReportVersion = 1
+
+// UsageReport counts which APIs, error classes and backends were used.
+// It carries no target IDs, thought content or error details: errors are
+// reduced to the package sentinel they wrap, backends to their type name.
+type UsageReport struct {
+	Version int       `json:"version"`
+	Install string    `json:"install"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Go      string    `json:"go"`
+	OS      string    `json:"os"`
+	Arch    string    `json:"arch"`
+
+	APIs     map[string]uint64 `json:"apis"`
+	Errors   map[string]uint64 `json:"errors"`
+	Backends map[string]uint64 `json:"backends"`
+}
+
+// UsageSink delivers usage reports
+type UsageSink interface {
+	SendUsage(ctx context.Context, report UsageReport) error
+}
+
+// FileUsageSink appends reports as JSON lines to a local file and sends
+// nothing anywhere else
+type FileUsageSink struct {
+	Path string
+}
+
+// SendUsage implements UsageSink
+func (s FileUsageSink) SendUsage(_ context.Context, report UsageReport) error {
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(report); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// HTTPUsageSink posts reports as JSON to the maintainers' endpoint
+type HTTPUsageSink struct {
+	Endpoint string
+	Client   *http.Client
+}
+
+// SendUsage implements UsageSink
+func (s HTTPUsageSink) SendUsage(ctx context.Context, report UsageReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("mindhacking: usage endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// UsageTelemetry accumulates usage counts between flushes
+type UsageTelemetry struct {
+	sink    UsageSink
+	install string
+
+	mu       sync.Mutex
+	from     time.Time
+	apis     map[string]uint64
+	errors   map[string]uint64
+	backends map[string]uint64
+}
+
+// usage is the active telemetry; nil, the default, records nothing
+var usage atomic.Pointer[UsageTelemetry]
+
+// EnableUsageTelemetry opts in to usage reporting through sink. The
+// install ID is random per call, so reports cannot be linked to a host.
+func EnableUsageTelemetry(sink UsageSink) *UsageTelemetry {
+	var id [8]byte
+	rand.Read(id[:])
+
+	u := &UsageTelemetry{sink: sink, install: hex.EncodeToString(id[:])}
+	u.reset(time.Now().UTC())
+	usage.Store(u)
+	return u
+}
+
+// DisableUsageTelemetry stops recording usage and returns the telemetry
+// that was active, if any, for a final Flush
+func DisableUsageTelemetry() *UsageTelemetry {
+	return usage.Swap(nil)
+}
+
+func (u *UsageTelemetry) reset(now time.Time) {
+	u.from = now
+	u.apis = make(map[string]uint64)
+	u.errors = make(map[string]uint64)
+	u.backends = make(map[string]uint64)
+}
+
+// Report returns the counts accumulated since the last flush
+func (u *UsageTelemetry) Report() UsageReport {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.reportLocked(time.Now().UTC())
+}
+
+func (u *UsageTelemetry) reportLocked(now time.Time) UsageReport {
+	copyCounts := func(m map[string]uint64) map[string]uint64 {
+		out := make(map[string]uint64, len(m))
+		for k, v := range m {
+			out[k] = v
+		}
+		return out
+	}
+	return UsageReport{
+		Version:  UsageReportVersion,
+		Install:  u.install,
+		From:     u.from,
+		To:       now,
+		Go:       runtime.Version(),
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		APIs:     copyCounts(u.apis),
+		Errors:   copyCounts(u.errors),
+		Backends: copyCounts(u.backends),
+	}
+}
+
+// Flush sends the accumulated report and starts a new period. Counts are
+// kept if the sink fails, to be sent with the next flush.
+func (u *UsageTelemetry) Flush(ctx context.Context) error {
+	u.mu.Lock()
+	now := time.Now().UTC()
+	report := u.reportLocked(now)
+	u.mu.Unlock()
+
+	if err := u.sink.SendUsage(ctx, report); err != nil {
+		return fmt.Errorf("mindhacking: send usage: %w", err)
+	}
+
+	// Subtract what was sent; calls made during the send carry over
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, pair := range []struct{ live, sent map[string]uint64 }{
+		{u.apis, report.APIs}, {u.errors, report.Errors}, {u.backends, report.Backends},
+	} {
+		for k, n := range pair.sent {
+			if pair.live[k] -= n; pair.live[k] == 0 {
+				delete(pair.live, k)
+			}
+		}
+	}
+	u.from = now
+	return nil
+}
+
+// Run flushes every interval until ctx is done, then flushes once more
+func (u *UsageTelemetry) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("mindhacking: telemetry flush interval %v is not positive", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return u.Flush(context.WithoutCancel(ctx))
+		case <-ticker.C:
+			u.Flush(ctx)
+		}
+	}
+}
+
+// noteUsage counts one call of api against target, classifying err
+func noteUsage(api string, target *SystemConsciousness, err error) {
+	u := usage.Load()
+	if u == nil {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.apis[api]++
+	if target != nil {
+		u.backends[backendClass(backendOf(target))]++
+	}
+	if err != nil {
+		u.errors[errorClass(err)]++
+	}
+}
+
+// backendClass names a backend by type only
+func backendClass(backend ConsciousnessBackend) string {
+	if backend == nil {
+		return "native"
+	}
+	return fmt.Sprintf("%T", backend)
+}
+
+// errorClasses are the errors telemetry reports by name: the package's own
+// sentinels, whose text carries no caller-supplied detail
+var errorClasses []error
+
+// sentinelError declares one of the package's sentinel errors, registering
+// it as an error class telemetry reports by name
+func sentinelError(text string) error {
+	err := errors.New(text)
+	errorClasses = append(errorClasses, err)
+	return err
+}
+
+// errorClass reduces err to the innermost sentinel of errorClasses it
+// wraps; anything else is "external", so no error text built at runtime,
+// with its target IDs or backend messages, is ever reported
+func errorClass(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "context canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "context deadline exceeded"
+	}
+
+	class := "external"
+	for err != nil {
+		for _, known := range errorClasses {
+			if err == known {
+				class = err.Error()
+			}
+		}
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			err = nil
+			if errs := e.Unwrap(); len(errs) > 0 {
+				err = errs[0]
+			}
+		default:
+			err = errors.Unwrap(err)
+		}
+	}
+	return class
+}
@@ -0,0 +1,84 @@
+// consciousness_injection/vector_clock.go - Causal Ordering
+package mindhacking
+
+import "sort"
+
+// VectorClock counts the events each injector has observed on a target
+type VectorClock map[string]uint64
+
+// ClockOrder is the causal relation between two vector clocks
+type ClockOrder int
+
+const (
+	// ClockEqual means both clocks saw exactly the same events
+	ClockEqual ClockOrder = iota
+	// ClockBefore means the receiver happened before the argument
+	ClockBefore
+	// ClockAfter means the receiver happened after the argument
+	ClockAfter
+	// ClockConcurrent means neither clock saw the other's events
+	ClockConcurrent
+)
+
+// Copy returns an independent copy of the clock
+func (vc VectorClock) Copy() VectorClock {
+	out := make(VectorClock, len(vc))
+	for node, n := range vc {
+		out[node] = n
+	}
+	return out
+}
+
+// Tick records one local event on node
+func (vc VectorClock) Tick(node string) {
+	vc[node]++
+}
+
+// Merge folds other into the receiver, keeping the maximum per node
+func (vc VectorClock) Merge(other VectorClock) {
+	for node, n := range other {
+		if n > vc[node] {
+			vc[node] = n
+		}
+	}
+}
+
+// Compare reports how the receiver is causally related to other
+func (vc VectorClock) Compare(other VectorClock) ClockOrder {
+	less, greater := false, false
+
+	for node, n := range vc {
+		switch m := other[node]; {
+		case n < m:
+			less = true
+		case n > m:
+			greater = true
+		}
+	}
+	for node, m := range other {
+		if _, seen := vc[node]; !seen && m > 0 {
+			less = true
+		}
+	}
+
+	switch {
+	case less && greater:
+		return ClockConcurrent
+	case less:
+		return ClockBefore
+	case greater:
+		return ClockAfter
+	default:
+		return ClockEqual
+	}
+}
+
+// Nodes returns the clock's node IDs in a stable order
+func (vc VectorClock) Nodes() []string {
+	nodes := make([]string, 0, len(vc))
+	for node := range vc {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
@@ -0,0 +1,273 @@
+// consciousness_injection/wasm_filter.go - WebAssembly Perception Filters
+package mindhacking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WasmFilterABIVersion is the host ABI version filter modules must report.
+//
+// A filter module exports:
+//
+//	memory                                 linear memory
+//	mh_abi_version() -> i32                must return WasmFilterABIVersion
+//	mh_alloc(size i32) -> i32              reserves size bytes for the input
+//	mh_filter(ptr i32, len i32) -> i64     filters the input at ptr
+//
+// The input is a JSON wasmFilterInput. mh_filter returns the output's
+// pointer in the high 32 bits and its length in the low 32 bits; the output
+// is a JSON wasmFilterOutput. Returning 0 leaves the reality unchanged. The
+// module imports nothing from the host.
+const WasmFilterABIVersion = 1
+
+var (
+	// ErrWasmABI reports a module that does not implement the filter ABI
+	ErrWasmABI = sentinelError("mindhacking: wasm module does not implement filter abi")
+	// ErrWasmOutputTooLarge reports a filter output past the configured limit
+	ErrWasmOutputTooLarge = sentinelError("mindhacking: wasm filter output too large")
+)
+
+// WasmRuntime compiles WebAssembly modules. The package ships no runtime
+// and runs no WebAssembly itself: hosts adapt an engine such as wazero, and
+// filter modules are only as isolated as that engine makes them.
+// Implementations must honor WasmLimits.MemoryPages and stop calls when
+// their context ends; the filter enforces MaxOutput alone.
+type WasmRuntime interface {
+	Compile(ctx context.Context, module []byte) (WasmModule, error)
+}
+
+// WasmModule is a compiled module that can be instantiated repeatedly
+type WasmModule interface {
+	Instantiate(ctx context.Context, limits WasmLimits) (WasmInstance, error)
+	Close(ctx context.Context) error
+}
+
+// WasmInstance is one sandboxed instance of a module
+type WasmInstance interface {
+	// Call invokes an exported function with raw WebAssembly values
+	Call(ctx context.Context, export string, args ...uint64) ([]uint64, error)
+	// Read copies n bytes of linear memory starting at offset
+	Read(offset, n uint32) ([]byte, bool)
+	// Write copies data into linear memory at offset
+	Write(offset uint32, data []byte) bool
+	Close(ctx context.Context) error
+}
+
+// WasmLimits bounds a single filter invocation. Zero values pick defaults.
+// Memory and time are enforced by the runtime, not the filter.
+type WasmLimits struct {
+	// MemoryPages caps linear memory in 64KiB pages
+	MemoryPages uint32
+	// Timeout bounds one call to mh_filter
+	Timeout time.Duration
+	// MaxOutput caps the filtered reality's encoded size
+	MaxOutput uint32
+}
+
+func (l WasmLimits) withDefaults() WasmLimits {
+	if l.MemoryPages == 0 {
+		l.MemoryPages = 256
+	}
+	if l.Timeout <= 0 {
+		l.Timeout = 100 * time.Millisecond
+	}
+	if l.MaxOutput == 0 {
+		l.MaxOutput = 1 << 20
+	}
+	return l
+}
+
+// wasmFilterInput is the reality handed to a filter module
+type wasmFilterInput struct {
+	Perceived map[string]float64 `json:"perceived"`
+	Base      map[string]float64 `json:"base"`
+	Rules     []RealityRule      `json:"rules,omitempty"`
+}
+
+// wasmFilterOutput is the perceived reality a filter module returns
+type wasmFilterOutput struct {
+	Aspects map[string]float64 `json:"aspects"`
+}
+
+// WasmPerceptionFilter runs a WebAssembly module as a perception filter on
+// the host's WasmRuntime. Every call gets a fresh instance, so modules keep
+// no state between realities and, if the runtime isolates instances, cannot
+// observe each other.
+type WasmPerceptionFilter struct {
+	name   string
+	module WasmModule
+	limits WasmLimits
+
+	mu      sync.Mutex
+	lastErr error
+	// OnError, if set, is called when the module fails; the failing filter
+	// then leaves perception unchanged
+	OnError func(error)
+}
+
+// NewWasmPerceptionFilter compiles module with runtime and checks it
+// implements the filter ABI
+func NewWasmPerceptionFilter(
+	ctx context.Context,
+	runtime WasmRuntime,
+	name string,
+	module []byte,
+	limits WasmLimits,
+) (*WasmPerceptionFilter, error) {
+
+	compiled, err := runtime.Compile(ctx, module)
+	if err != nil {
+		return nil, fmt.Errorf("mindhacking: compile wasm filter %q: %w", name, err)
+	}
+	f := &WasmPerceptionFilter{name: name, module: compiled, limits: limits.withDefaults()}
+
+	// Phase 1: ABI handshake on a throwaway instance
+	inst, err := compiled.Instantiate(ctx, f.limits)
+	if err != nil {
+		compiled.Close(ctx)
+		return nil, fmt.Errorf("mindhacking: instantiate wasm filter %q: %w", name, err)
+	}
+	defer inst.Close(ctx)
+
+	out, err := inst.Call(ctx, "mh_abi_version")
+	if err != nil || len(out) != 1 || uint32(out[0]) != WasmFilterABIVersion {
+		compiled.Close(ctx)
+		return nil, fmt.Errorf("%w: %q", ErrWasmABI, name)
+	}
+	return f, nil
+}
+
+// RegisterWasmPerceptionFilter compiles module and registers it under name.
+// The factory ignores its configuration; limits are fixed at registration.
+func RegisterWasmPerceptionFilter(
+	ctx context.Context,
+	runtime WasmRuntime,
+	name string,
+	module []byte,
+	limits WasmLimits,
+) error {
+
+	f, err := NewWasmPerceptionFilter(ctx, runtime, name, module, limits)
+	if err != nil {
+		return err
+	}
+	RegisterPerceptionFilter(name, func(map[string]interface{}) (PerceptionFilter, error) {
+		return f, nil
+	})
+	return nil
+}
+
+// Name implements PerceptionFilter
+func (f *WasmPerceptionFilter) Name() string { return f.name }
+
+// Filter implements PerceptionFilter. A failing module returns nil, which
+// leaves perception as the previous filter produced it.
+func (f *WasmPerceptionFilter) Filter(perceived *AlternateReality, base *Reality) *AlternateReality {
+	out, err := f.run(perceived, base)
+
+	f.mu.Lock()
+	f.lastErr = err
+	onError := f.OnError
+	f.mu.Unlock()
+
+	if err != nil {
+		if onError != nil {
+			onError(fmt.Errorf("mindhacking: wasm filter %q: %w", f.name, err))
+		}
+		return nil
+	}
+	return out
+}
+
+// Err returns the error from the most recent invocation, if any
+func (f *WasmPerceptionFilter) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastErr
+}
+
+// Close releases the compiled module
+func (f *WasmPerceptionFilter) Close(ctx context.Context) error {
+	return f.module.Close(ctx)
+}
+
+// run marshals the realities into a fresh instance and decodes its answer
+func (f *WasmPerceptionFilter) run(perceived *AlternateReality, base *Reality) (*AlternateReality, error) {
+	if perceived == nil {
+		return nil, nil
+	}
+
+	in := wasmFilterInput{Perceived: perceived.Aspects}
+	if base != nil {
+		in.Base = base.Aspects
+	}
+	if perceived.Rules != nil {
+		in.Rules = perceived.Rules.Rules
+	}
+	payload, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.limits.Timeout)
+	defer cancel()
+
+	inst, err := f.module.Instantiate(ctx, f.limits)
+	if err != nil {
+		return nil, err
+	}
+	defer inst.Close(ctx)
+
+	// Phase 1: copy the input into guest memory
+	res, err := inst.Call(ctx, "mh_alloc", uint64(len(payload)))
+	if err != nil {
+		return nil, err
+	}
+	if len(res) != 1 {
+		return nil, ErrWasmABI
+	}
+	ptr := uint32(res[0])
+	if !inst.Write(ptr, payload) {
+		return nil, fmt.Errorf("%w: mh_alloc returned out-of-bounds pointer", ErrWasmABI)
+	}
+
+	// Phase 2: filter
+	res, err = inst.Call(ctx, "mh_filter", uint64(ptr), uint64(len(payload)))
+	if err != nil {
+		return nil, err
+	}
+	if len(res) != 1 {
+		return nil, ErrWasmABI
+	}
+	if res[0] == 0 {
+		return nil, nil
+	}
+	outPtr, outLen := uint32(res[0]>>32), uint32(res[0])
+	if outLen > f.limits.MaxOutput {
+		return nil, ErrWasmOutputTooLarge
+	}
+
+	// Phase 3: decode the filtered reality
+	raw, ok := inst.Read(outPtr, outLen)
+	if !ok {
+		return nil, fmt.Errorf("%w: mh_filter returned out-of-bounds output", ErrWasmABI)
+	}
+	var out wasmFilterOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("mindhacking: decode wasm filter output: %w", err)
+	}
+
+	filtered := &AlternateReality{
+		Reality: Reality{Aspects: out.Aspects},
+		Base:    perceived.Base,
+		Rules:   perceived.Rules,
+	}
+	if filtered.Aspects == nil {
+		filtered.Aspects = make(map[string]float64)
+	}
+	return filtered, nil
+}
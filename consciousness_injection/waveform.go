@@ -0,0 +1,256 @@
+// consciousness_injection/waveform.go - Vector Waveform Library
+package mindhacking
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnknownWaveform reports a vector naming a waveform nobody registered
+	ErrUnknownWaveform = sentinelError("mindhacking: unknown waveform")
+	// ErrWaveformRegistered reports a waveform name registered twice
+	ErrWaveformRegistered = sentinelError("mindhacking: waveform already registered")
+	// ErrBandwidthExceeded reports a vector the gateway cannot carry
+	ErrBandwidthExceeded = sentinelError("mindhacking: waveform exceeds gateway bandwidth")
+)
+
+// Waveform shapes a vector's modulation
+type Waveform interface {
+	// Sample returns the normalized value in [-1, 1] at t seconds
+	Sample(t, frequency, phase float64) float64
+	// Band returns the lowest and highest frequency the waveform occupies
+	Band(frequency float64) (low, high float64)
+}
+
+// Sine is the pure tone vectors have always used
+type Sine struct{}
+
+// Sample implements Waveform
+func (Sine) Sample(t, frequency, phase float64) float64 {
+	return math.Sin(2*math.Pi*frequency*t + phase)
+}
+
+// Band implements Waveform
+func (Sine) Band(frequency float64) (float64, float64) {
+	return frequency, frequency
+}
+
+// Square is a square wave band-limited to its first Harmonics odd harmonics
+type Square struct {
+	Harmonics int
+}
+
+func (s Square) harmonics() int {
+	if s.Harmonics < 1 {
+		return 7
+	}
+	return s.Harmonics
+}
+
+// Sample implements Waveform
+func (s Square) Sample(t, frequency, phase float64) float64 {
+	var v float64
+	for h := 0; h < s.harmonics(); h++ {
+		n := float64(2*h + 1)
+		v += math.Sin(n*(2*math.Pi*frequency*t+phase)) / n
+	}
+	return v * 4 / math.Pi
+}
+
+// Band implements Waveform
+func (s Square) Band(frequency float64) (float64, float64) {
+	return frequency, frequency * float64(2*s.harmonics()-1)
+}
+
+// Chirp sweeps linearly from From to To times the vector frequency over
+// Sweep, then repeats
+type Chirp struct {
+	From, To float64
+	Sweep    time.Duration
+}
+
+// Sample implements Waveform
+func (c Chirp) Sample(t, frequency, phase float64) float64 {
+	sweep := c.Sweep.Seconds()
+	if sweep <= 0 {
+		return math.Sin(2*math.Pi*frequency*c.From*t + phase)
+	}
+	t = math.Mod(t, sweep)
+	f0, f1 := frequency*c.From, frequency*c.To
+	// Phase is the integral of the instantaneous frequency
+	return math.Sin(2*math.Pi*(f0*t+(f1-f0)*t*t/(2*sweep)) + phase)
+}
+
+// Band implements Waveform
+func (c Chirp) Band(frequency float64) (float64, float64) {
+	lo, hi := frequency*c.From, frequency*c.To
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	return lo, hi
+}
+
+// Sampled repeats one cycle of custom samples, interpolated linearly
+type Sampled struct {
+	Samples []float64
+}
+
+// Sample implements Waveform
+func (s Sampled) Sample(t, frequency, phase float64) float64 {
+	n := len(s.Samples)
+	if n == 0 {
+		return 0
+	}
+	cycle := frequency*t + phase/(2*math.Pi)
+	pos := (cycle - math.Floor(cycle)) * float64(n)
+	i := int(pos) % n
+	frac := pos - math.Floor(pos)
+	return s.Samples[i]*(1-frac) + s.Samples[(i+1)%n]*frac
+}
+
+// Band implements Waveform. The highest harmonic carrying at least 1% of
+// the strongest one's magnitude bounds the band.
+func (s Sampled) Band(frequency float64) (float64, float64) {
+	n := len(s.Samples)
+	if n == 0 {
+		return frequency, frequency
+	}
+
+	mags := make([]float64, n/2+1)
+	var peak float64
+	for k := 1; k < len(mags); k++ {
+		var sum complex128
+		for i, x := range s.Samples {
+			sum += complex(x, 0) * cmplx.Exp(complex(0, -2*math.Pi*float64(k*i)/float64(n)))
+		}
+		mags[k] = cmplx.Abs(sum)
+		peak = math.Max(peak, mags[k])
+	}
+
+	low, high := 0, 0
+	for k := 1; k < len(mags); k++ {
+		if peak > 0 && mags[k] >= peak/100 {
+			if low == 0 {
+				low = k
+			}
+			high = k
+		}
+	}
+	if high == 0 {
+		return frequency, frequency
+	}
+	return frequency * float64(low), frequency * float64(high)
+}
+
+var (
+	waveformsMu sync.RWMutex
+	waveforms   = map[string]Waveform{
+		"sine":   Sine{},
+		"square": Square{},
+		"chirp":  Chirp{From: 0.5, To: 2, Sweep: time.Second},
+	}
+)
+
+// RegisterWaveform makes w available to vectors under name. Registering a
+// name twice panics, as with encoding schemes.
+func RegisterWaveform(name string, w Waveform) {
+	waveformsMu.Lock()
+	defer waveformsMu.Unlock()
+
+	if w == nil {
+		panic("mindhacking: RegisterWaveform waveform is nil")
+	}
+	if _, dup := waveforms[name]; dup {
+		panic(fmt.Sprintf("%v: %q", ErrWaveformRegistered, name))
+	}
+	waveforms[name] = w
+}
+
+// LookupWaveform returns the waveform registered under name; the empty name
+// is the sine vectors use by default
+func LookupWaveform(name string) (Waveform, error) {
+	if name == "" {
+		return Sine{}, nil
+	}
+	waveformsMu.RLock()
+	defer waveformsMu.RUnlock()
+	w, ok := waveforms[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownWaveform, name)
+	}
+	return w, nil
+}
+
+// Waveforms lists the registered waveform names
+func Waveforms() []string {
+	waveformsMu.RLock()
+	defer waveformsMu.RUnlock()
+	names := make([]string, 0, len(waveforms))
+	for name := range waveforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Sample returns the vector's modulated value at t seconds, for backends
+// synthesizing it themselves
+func (v InjectionVector) Sample(t float64) (float64, error) {
+	w, err := LookupWaveform(v.Waveform)
+	if err != nil {
+		return 0, err
+	}
+	return v.Amplitude * w.Sample(t, v.Frequency, v.Phase), nil
+}
+
+// ValidateVector checks that vector's resonance handle is still pinned and
+// its waveform fits within caps; zero limits are unbounded
+func ValidateVector(vector InjectionVector, caps Capabilities) error {
+	if _, err := vector.resonancePoint(); err != nil {
+		return err
+	}
+	w, err := LookupWaveform(vector.Waveform)
+	if err != nil {
+		return err
+	}
+
+	low, high := w.Band(vector.Frequency)
+	if caps.MaxFrequency > 0 && high > caps.MaxFrequency {
+		return fmt.Errorf("%w: %s reaches %.4g, limit %.4g",
+			ErrBandwidthExceeded, waveformName(vector), high, caps.MaxFrequency)
+	}
+	if caps.MaxBandwidth > 0 && high-low > caps.MaxBandwidth {
+		return fmt.Errorf("%w: %s occupies %.4g, limit %.4g",
+			ErrBandwidthExceeded, waveformName(vector), high-low, caps.MaxBandwidth)
+	}
+	return nil
+}
+
+func waveformName(vector InjectionVector) string {
+	if vector.Waveform == "" {
+		return "sine"
+	}
+	return vector.Waveform
+}
+
+// SetCapabilities declares the frequency and bandwidth the gateway carries
+func (qg *QuantumGateway) SetCapabilities(caps Capabilities) {
+	qg.caps = caps
+}
+
+// ValidateVectors checks every vector against the gateway's capabilities
+func (qg *QuantumGateway) ValidateVectors(vectors []InjectionVector) error {
+	var errs []error
+	for i, v := range vectors {
+		if err := ValidateVector(v, qg.caps); err != nil {
+			errs = append(errs, fmt.Errorf("vector %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
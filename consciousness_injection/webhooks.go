@@ -0,0 +1,286 @@
+// consciousness_injection/webhooks.go - Thought Lifecycle Webhooks
+package mindhacking
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrWebhookSignature reports a delivery whose signature does not verify
+var ErrWebhookSignature = sentinelError("mindhacking: webhook signature mismatch")
+
+// Webhook delivery headers
+const (
+	WebhookEventHeader     = "X-Mindhack-Event"
+	WebhookDeliveryHeader  = "X-Mindhack-Delivery"
+	WebhookTimestampHeader = "X-Mindhack-Timestamp"
+	WebhookSignatureHeader = "X-Mindhack-Signature"
+)
+
+// LifecycleEvents are the thought lifecycle transitions, in lifecycle order
+var LifecycleEvents = []EventKind{
+	EventThoughtQueued,
+	EventInjected,
+	EventInjectionFailed,
+	EventThoughtAccepted,
+	EventThoughtVerified,
+	EventThoughtRetracted,
+	EventThoughtExpired,
+}
+
+// WebhookEndpoint is one external system notified of lifecycle transitions
+type WebhookEndpoint struct {
+	URL string
+	// Secret signs every delivery; endpoints check it with
+	// VerifyWebhookSignature
+	Secret []byte
+	// Kinds selects the transitions sent, default LifecycleEvents
+	Kinds []EventKind
+}
+
+// WebhookPayload is the JSON body of a delivery
+type WebhookPayload struct {
+	Delivery    string    `json:"delivery"`
+	Kind        EventKind `json:"kind"`
+	At          time.Time `json:"at"`
+	InjectionID string    `json:"injection_id,omitempty"`
+	Target      string    `json:"target,omitempty"`
+	Vector      string    `json:"vector,omitempty"`
+	Accepted    bool      `json:"accepted"`
+	Shift       float64   `json:"shift,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// WebhookFailure is a delivery abandoned after its last attempt
+type WebhookFailure struct {
+	Endpoint string
+	Payload  WebhookPayload
+	Attempts int
+	Err      error
+}
+
+// WebhookConfig tunes webhook delivery
+type WebhookConfig struct {
+	Endpoints []WebhookEndpoint
+	Client    *http.Client
+	// Attempts bounds the tries per delivery, default 5; retries back off
+	// exponentially from Backoff, default 500ms, up to MaxBackoff, default
+	// 30s
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Buffer bounds the deliveries waiting per endpoint, default 256; a
+	// full endpoint loses deliveries and reports them to OnFailure
+	Buffer int
+	// OnFailure, if set, is called for every abandoned delivery
+	OnFailure func(WebhookFailure)
+}
+
+// Webhooks delivers lifecycle events from an EventBus to external systems.
+// Each endpoint has its own queue, so a slow or failing endpoint delays no
+// other.
+type Webhooks struct {
+	cfg WebhookConfig
+}
+
+// NewWebhooks creates a dispatcher for cfg's endpoints
+func NewWebhooks(cfg WebhookConfig) (*Webhooks, error) {
+	for i, e := range cfg.Endpoints {
+		if e.URL == "" || len(e.Secret) == 0 {
+			return nil, fmt.Errorf("mindhacking: webhook endpoint %d needs a URL and a secret", i)
+		}
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.Attempts < 1 {
+		cfg.Attempts = 5
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = 500 * time.Millisecond
+	}
+	if cfg.MaxBackoff < cfg.Backoff {
+		cfg.MaxBackoff = max(30*time.Second, cfg.Backoff)
+	}
+	if cfg.Buffer < 1 {
+		cfg.Buffer = 256
+	}
+	return &Webhooks{cfg: cfg}, nil
+}
+
+// Run delivers bus's lifecycle events until ctx is done, then returns once
+// deliveries in progress have given up
+func (w *Webhooks) Run(ctx context.Context, bus *EventBus) error {
+	sub := bus.Subscribe(w.cfg.Buffer)
+	defer sub.Close()
+
+	var wg sync.WaitGroup
+	queues := make([]chan WebhookPayload, len(w.cfg.Endpoints))
+	for i := range w.cfg.Endpoints {
+		queues[i] = make(chan WebhookPayload, w.cfg.Buffer)
+		wg.Add(1)
+		go func(endpoint WebhookEndpoint, queue <-chan WebhookPayload) {
+			defer wg.Done()
+			for payload := range queue {
+				w.deliver(ctx, endpoint, payload)
+			}
+		}(w.cfg.Endpoints[i], queues[i])
+	}
+	defer func() {
+		for _, q := range queues {
+			close(q)
+		}
+		wg.Wait()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			payload := webhookPayload(event)
+			for i, endpoint := range w.cfg.Endpoints {
+				if !endpoint.wants(event.Kind) {
+					continue
+				}
+				select {
+				case queues[i] <- payload:
+				default:
+					w.failed(WebhookFailure{Endpoint: endpoint.URL, Payload: payload, Err: errors.New("delivery queue full")})
+				}
+			}
+		}
+	}
+}
+
+func (e WebhookEndpoint) wants(kind EventKind) bool {
+	kinds := e.Kinds
+	if kinds == nil {
+		kinds = LifecycleEvents
+	}
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func webhookPayload(event Event) WebhookPayload {
+	var id [16]byte
+	rand.Read(id[:])
+	return WebhookPayload{
+		Delivery:    hex.EncodeToString(id[:]),
+		Kind:        event.Kind,
+		At:          event.At.UTC(),
+		InjectionID: event.InjectionID,
+		Target:      event.TargetID,
+		Vector:      event.Vector,
+		Accepted:    event.Accepted,
+		Shift:       event.Shift,
+		Error:       event.Err,
+	}
+}
+
+// deliver posts payload to endpoint, retrying failures that may pass.
+// Retries resend the same delivery ID so endpoints can ignore duplicates.
+func (w *Webhooks) deliver(ctx context.Context, endpoint WebhookEndpoint, payload WebhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		w.failed(WebhookFailure{Endpoint: endpoint.URL, Payload: payload, Err: err})
+		return
+	}
+	backoff := w.cfg.Backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := w.post(ctx, endpoint, payload, body)
+		if err == nil {
+			return
+		}
+		if !retry || attempt == w.cfg.Attempts {
+			w.failed(WebhookFailure{Endpoint: endpoint.URL, Payload: payload, Attempts: attempt, Err: err})
+			return
+		}
+		select {
+		case <-ctx.Done():
+			w.failed(WebhookFailure{Endpoint: endpoint.URL, Payload: payload, Attempts: attempt, Err: ctx.Err()})
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, w.cfg.MaxBackoff)
+	}
+}
+
+// post makes one delivery attempt, reporting whether a failure is worth
+// retrying: network errors, 429 and 5xx are, other statuses are not
+func (w *Webhooks) post(ctx context.Context, endpoint WebhookEndpoint, payload WebhookPayload, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(payload.Kind))
+	req.Header.Set(WebhookDeliveryHeader, payload.Delivery)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(endpoint.Secret, timestamp, body))
+
+	resp, err := w.cfg.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("mindhacking: webhook endpoint returned %s", resp.Status)
+}
+
+func (w *Webhooks) failed(f WebhookFailure) {
+	if w.cfg.OnFailure != nil {
+		w.cfg.OnFailure(f)
+	}
+}
+
+// SignWebhook computes the signature header for a delivery: HMAC-SHA256
+// over the timestamp, a dot and the body
+func SignWebhook(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a received delivery's signature and that
+// its timestamp is within tolerance of now, so captured deliveries cannot be
+// replayed later
+func VerifyWebhookSignature(secret []byte, timestamp, signature string, body []byte, tolerance time.Duration) error {
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad timestamp", ErrWebhookSignature)
+	}
+	if age := time.Since(time.Unix(sec, 0)); tolerance > 0 && (age > tolerance || age < -tolerance) {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrWebhookSignature)
+	}
+	if !hmac.Equal([]byte(signature), []byte(SignWebhook(secret, timestamp, body))) {
+		return ErrWebhookSignature
+	}
+	return nil
+}
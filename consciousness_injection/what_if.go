@@ -0,0 +1,227 @@
+// consciousness_injection/what_if.go - What-If Analysis of Rule Changes
+package mindhacking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ErrUnknownRule reports a rule delta naming a rule the reality lacks
+var ErrUnknownRule = sentinelError("mindhacking: no such rule")
+
+// RuleDelta is a proposed change to an alternate reality's rules
+type RuleDelta struct {
+	// Add appends new rules
+	Add []RealityRule
+	// Update replaces existing rules of the same name
+	Update []RealityRule
+	// Remove drops rules by name
+	Remove []string
+}
+
+// Apply returns rules with the delta applied, leaving rules untouched
+func (d RuleDelta) Apply(rules *RealityRules) (*RealityRules, error) {
+	out := &RealityRules{}
+	if rules != nil {
+		out.Rules = append(out.Rules, rules.Rules...)
+	}
+	index := func(name string) int {
+		for i, r := range out.Rules {
+			if r.Name == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, name := range d.Remove {
+		i := index(name)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: remove %q", ErrUnknownRule, name)
+		}
+		out.Rules = append(out.Rules[:i], out.Rules[i+1:]...)
+	}
+	for _, r := range d.Update {
+		i := index(r.Name)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: update %q", ErrUnknownRule, r.Name)
+		}
+		out.Rules[i] = r
+	}
+	out.Rules = append(out.Rules, d.Add...)
+	return out, nil
+}
+
+// RealitySimulator predicts the alternate reality rules would construct
+// from base without constructing it: nothing is tracked, anchored or
+// chained as evidence
+type RealitySimulator interface {
+	SimulateReality(ctx context.Context, base *Reality, rules *RealityRules) (*AlternateReality, error)
+}
+
+// AspectChange is how one aspect would move under a rule change
+type AspectChange struct {
+	Aspect string
+	Before float64
+	After  float64
+	// Added and Removed mark aspects present on only one side
+	Added   bool
+	Removed bool
+}
+
+// Delta returns the aspect's change
+func (c AspectChange) Delta() float64 {
+	return c.After - c.Before
+}
+
+// StabilityForecast is the predicted stability impact of a rule change
+type StabilityForecast struct {
+	// MaxShift is the largest aspect shift, scaled as MaxAspectShift
+	// scales it, and MaxShiftAspect the aspect it occurs on
+	MaxShift       float64
+	MaxShiftAspect string
+	// Violations are problems ValidateRealityRules finds in the new rules
+	Violations []RuleViolation
+	// Unstable is the engine's pacing stability check failing on the
+	// change, as it would when applied live
+	Unstable error
+	// Perception is what the engine's hallucination detector would report
+	Perception *HallucinationReport
+}
+
+// Stable reports whether the change would apply cleanly: valid rules that
+// pass the stability check without hallucinated divergences
+func (s StabilityForecast) Stable() bool {
+	return len(s.Violations) == 0 && s.Unstable == nil &&
+		(s.Perception == nil || s.Perception.Hallucinations == 0)
+}
+
+// RealityForecast predicts the effect of a rule change before it is applied
+type RealityForecast struct {
+	Current *AlternateReality
+	Rules   *RealityRules
+	// Predicted is the simulated reality; it is not tracked by the engine
+	Predicted *AlternateReality
+	// Changes lists every aspect that would move, largest first
+	Changes   []AspectChange
+	Stability StabilityForecast
+}
+
+// WhatIf forecasts what applying delta to alternate's rules would do,
+// simulating the change against alternate's base. The live reality is not
+// touched.
+func (rme *RealityManipulationEngine) WhatIf(
+	ctx context.Context,
+	alternate *AlternateReality,
+	delta RuleDelta,
+) (*RealityForecast, error) {
+
+	if alternate == nil || alternate.Base == nil {
+		return nil, errors.New("mindhacking: what-if needs an alternate reality with a base")
+	}
+	rules, err := delta.Apply(alternate.Rules)
+	if err != nil {
+		return nil, err
+	}
+	forecast := &RealityForecast{Current: alternate, Rules: rules}
+
+	// Phase 1: Rule Validation
+	forecast.Stability.Violations = ValidateRealityRules(rules, alternate.Base)
+	if len(forecast.Stability.Violations) > 0 {
+		return forecast, nil
+	}
+
+	// Phase 2: Simulation
+	var simulator RealitySimulator = rme
+	if rme.simulator != nil {
+		simulator = rme.simulator
+	}
+	predicted, err := simulator.SimulateReality(ctx, alternate.Base, rules)
+	switch {
+	case errors.Is(err, ErrRealityUnstable):
+		forecast.Stability.Unstable = err
+		return forecast, nil
+	case err != nil:
+		return nil, fmt.Errorf("mindhacking: what-if simulation: %w", err)
+	}
+	forecast.Predicted = predicted
+
+	// Phase 3: Reality Diff and Stability Impact
+	forecast.Changes = aspectChanges(&alternate.Reality, &predicted.Reality)
+	for _, c := range forecast.Changes {
+		if shift := math.Abs(c.Delta()) / math.Max(math.Abs(c.Before), 1); shift > forecast.Stability.MaxShift {
+			forecast.Stability.MaxShift, forecast.Stability.MaxShiftAspect = shift, c.Aspect
+		}
+	}
+	if rme.pacing != nil && rme.pacing.Check != nil {
+		if err := rme.pacing.Check(&alternate.Reality, &predicted.Reality); err != nil {
+			forecast.Stability.Unstable = fmt.Errorf("%w: %w", ErrRealityUnstable, err)
+		}
+	}
+	if rme.hallucinations != nil {
+		report := rme.hallucinations.Detect(alternate.Base, predicted)
+		forecast.Stability.Perception = &report
+	}
+	return forecast, nil
+}
+
+// SimulateReality implements RealitySimulator by running the engine's own
+// construction phases without pacing delays, anchoring or tracking
+func (rme *RealityManipulationEngine) SimulateReality(
+	ctx context.Context,
+	base *Reality,
+	rules *RealityRules,
+) (*AlternateReality, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Keep the stability checks between batches, not the waits
+	var pacing *RulePacing
+	if rme.pacing != nil {
+		unpaced := *rme.pacing
+		unpaced.RulesPerSecond = 0
+		pacing = &unpaced
+	}
+
+	altered, err := rme.applyRulesPaced(ctx, rme.deconstructReality(base), rules, pacing)
+	if err != nil {
+		return nil, err
+	}
+	alternate := rme.reconstructReality(altered)
+	if alternate == nil {
+		return nil, errors.New("mindhacking: simulated reality did not reconstruct")
+	}
+	alternate.Base, alternate.Rules = base, rules
+	return rme.applyPerceptionFilters(alternate, base), nil
+}
+
+// aspectChanges diffs two realities, largest change first
+func aspectChanges(before, after *Reality) []AspectChange {
+	var out []AspectChange
+	for name, b := range before.Aspects {
+		a, ok := after.Aspects[name]
+		switch {
+		case !ok:
+			out = append(out, AspectChange{Aspect: name, Before: b, Removed: true})
+		case a != b:
+			out = append(out, AspectChange{Aspect: name, Before: b, After: a})
+		}
+	}
+	for name, a := range after.Aspects {
+		if _, ok := before.Aspects[name]; !ok {
+			out = append(out, AspectChange{Aspect: name, After: a, Added: true})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		di, dj := math.Abs(out[i].Delta()), math.Abs(out[j].Delta())
+		if di != dj {
+			return di > dj
+		}
+		return out[i].Aspect < out[j].Aspect
+	})
+	return out
+}